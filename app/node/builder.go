@@ -3,6 +3,7 @@ package node
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"time"
 
 	"github.com/filecoin-project/venus/app/submodule/dagservice"
@@ -20,20 +21,27 @@ import (
 	"github.com/filecoin-project/venus/app/submodule/chain"
 	"github.com/filecoin-project/venus/app/submodule/common"
 	config2 "github.com/filecoin-project/venus/app/submodule/config"
+	"github.com/filecoin-project/venus/app/submodule/disputer"
 	"github.com/filecoin-project/venus/app/submodule/market"
 	"github.com/filecoin-project/venus/app/submodule/mining"
 	"github.com/filecoin-project/venus/app/submodule/mpool"
+	"github.com/filecoin-project/venus/app/submodule/multisig"
 	"github.com/filecoin-project/venus/app/submodule/paych"
 	"github.com/filecoin-project/venus/app/submodule/storagenetworking"
 	"github.com/filecoin-project/venus/app/submodule/syncer"
 	"github.com/filecoin-project/venus/app/submodule/wallet"
 	chain2 "github.com/filecoin-project/venus/pkg/chain"
+	"github.com/filecoin-project/venus/pkg/chainsync/slashfilter"
 	"github.com/filecoin-project/venus/pkg/clock"
+	"github.com/filecoin-project/venus/pkg/config"
+	"github.com/filecoin-project/venus/pkg/events/sink"
+	"github.com/filecoin-project/venus/pkg/follower"
 	"github.com/filecoin-project/venus/pkg/journal"
 	"github.com/filecoin-project/venus/pkg/paychmgr"
 	"github.com/filecoin-project/venus/pkg/repo"
 	"github.com/filecoin-project/venus/pkg/util/ffiwrapper"
 	"github.com/filecoin-project/venus/pkg/util/ffiwrapper/impl"
+	v1api "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
 	"github.com/filecoin-project/venus/venus-shared/types"
 	"github.com/ipfs-force-community/metrics/ratelimit"
 )
@@ -52,6 +60,7 @@ type Builder struct {
 	genBlk         types.BlockHeader
 	walletPassword []byte
 	authURL        string
+	follower       *follower.Follower
 }
 
 // New creates a new node.
@@ -86,6 +95,14 @@ func (b *Builder) build(ctx context.Context) (*Node, error) {
 		b.journal = journal.NewNoopJournal()
 	}
 
+	if fCfg := b.repo.Config().Follower; fCfg != nil && fCfg.Enable {
+		// a follower reads the chain it misses from the leader through its repo
+		if b.follower, err = follower.New(ctx, b.repo, fCfg); err != nil {
+			return nil, errors.Wrap(err, "failed to follow the leader")
+		}
+		b.repo = b.follower.Repo()
+	}
+
 	b.genBlk, err = chain2.GenesisBlock(ctx, b.repo.ChainDatastore(), b.repo.Datastore())
 	if err != nil {
 		return nil, err
@@ -144,6 +161,8 @@ func (b *Builder) build(ctx context.Context) (*Node, error) {
 		return nil, errors.Wrap(err, "failed to build node.mpool")
 	}
 
+	nd.multiSig = multisig.NewMultiSigSubmodule(nd.chain.API(), nd.blockstore.Blockstore)
+
 	nd.storageNetworking, err = storagenetworking.NewStorgeNetworkingSubmodule(ctx, nd.network)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to build node.storageNetworking")
@@ -176,6 +195,43 @@ func (b *Builder) build(ctx context.Context) (*Node, error) {
 		return nil, err
 	}
 
+	disputerAPI := struct {
+		v1api.IWallet
+		v1api.IChain
+		v1api.IMessagePool
+	}{nd.wallet.API(), nd.chain.API(), nd.mpool.API()}
+	if nd.disputer, err = disputer.NewDisputerSubmodule(ctx, disputerAPI, b.repo.MetaDatastore(), func() *config.DisputerConfig {
+		return b.repo.Config().Disputer
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to build node.disputer")
+	}
+
+	if frCfg := b.repo.Config().FaultReporter; frCfg != nil && frCfg.EnableConsensusFaultReporter {
+		dataDir := frCfg.ConsensusFaultReporterDataDir
+		if dataDir == "" {
+			repoPath, err := b.repo.Path()
+			if err != nil {
+				return nil, err
+			}
+			dataDir = filepath.Join(repoPath, "fault-reporter")
+		}
+		faultReporterAPI := struct {
+			v1api.IWallet
+			v1api.IChain
+			v1api.IMessagePool
+			v1api.ISyncer
+		}{nd.wallet.API(), nd.chain.API(), nd.mpool.API(), nd.syncer.API()}
+		if nd.faultReporter, err = slashfilter.NewConsensusFaultReporter(frCfg, dataDir, faultReporterAPI); err != nil {
+			return nil, errors.Wrap(err, "failed to build the consensus fault reporter")
+		}
+	}
+
+	if sinksCfg := b.repo.Config().EventSinks; sinksCfg != nil && len(sinksCfg.Sinks) > 0 {
+		if nd.eventSinks, err = sink.NewService(nd.chain.API(), b.repo.MetaDatastore(), sinksCfg); err != nil {
+			return nil, errors.Wrap(err, "failed to build the event sinks")
+		}
+	}
+
 	apiBuilder := NewBuilder()
 	apiBuilder.NameSpace("Filecoin")
 
@@ -189,11 +245,13 @@ func (b *Builder) build(ctx context.Context) (*Node, error) {
 		nd.storageNetworking,
 		nd.mining,
 		nd.mpool,
+		nd.multiSig,
 		nd.paychan,
 		nd.market,
 		nd.common,
 		nd.eth,
 		nd.actorEvent,
+		nd.disputer,
 	)
 
 	if err != nil {
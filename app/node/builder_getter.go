@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/filecoin-project/venus/pkg/clock"
+	"github.com/filecoin-project/venus/pkg/follower"
 	"github.com/filecoin-project/venus/pkg/journal"
 	"github.com/filecoin-project/venus/pkg/repo"
 	"github.com/filecoin-project/venus/pkg/util/ffiwrapper"
@@ -66,6 +67,11 @@ func (b builder) OfflineMode() bool {
 	return b.offlineMode
 }
 
+// Follower get the follower of the leader, nil when the node is not a follower
+func (b builder) Follower() *follower.Follower {
+	return b.follower
+}
+
 // Verify export ffi verify
 func (b builder) Verifier() ffiwrapper.Verifier {
 	return b.verifier
@@ -21,11 +21,13 @@ type Env struct {
 	WalletAPI            v1api.IWallet
 	MingingAPI           v1api.IMining
 	MessagePoolAPI       v1api.IMessagePool
+	MultiSigAPI          v1api.IMultiSig
+	DisputerAPI          v1api.IDisputer
 
 	MarketAPI v1api.IMarket
 	PaychAPI  v1api.IPaychan
 	CommonAPI v1api.ICommon
-	EthAPI    v1api.IETH
+	EthAPI    v1api.FullETH
 }
 
 var _ cmds.Environment = (*Env)(nil)
@@ -17,21 +17,26 @@ import (
 	"github.com/filecoin-project/venus/app/submodule/common"
 	configModule "github.com/filecoin-project/venus/app/submodule/config"
 	"github.com/filecoin-project/venus/app/submodule/dagservice"
+	"github.com/filecoin-project/venus/app/submodule/disputer"
 	"github.com/filecoin-project/venus/app/submodule/eth"
 	"github.com/filecoin-project/venus/app/submodule/market"
 	"github.com/filecoin-project/venus/app/submodule/mining"
 	"github.com/filecoin-project/venus/app/submodule/mpool"
+	"github.com/filecoin-project/venus/app/submodule/multisig"
 	network2 "github.com/filecoin-project/venus/app/submodule/network"
 	"github.com/filecoin-project/venus/app/submodule/paych"
 	"github.com/filecoin-project/venus/app/submodule/storagenetworking"
 	syncer2 "github.com/filecoin-project/venus/app/submodule/syncer"
 	"github.com/filecoin-project/venus/app/submodule/wallet"
 	"github.com/filecoin-project/venus/pkg/chain"
+	"github.com/filecoin-project/venus/pkg/chainsync/slashfilter"
 	"github.com/filecoin-project/venus/pkg/clock"
 	"github.com/filecoin-project/venus/pkg/config"
 	_ "github.com/filecoin-project/venus/pkg/crypto/bls"       // enable bls signatures
 	_ "github.com/filecoin-project/venus/pkg/crypto/delegated" // enable delegated signatures
 	_ "github.com/filecoin-project/venus/pkg/crypto/secp"      // enable secp signatures
+	"github.com/filecoin-project/venus/pkg/events/sink"
+	"github.com/filecoin-project/venus/pkg/follower"
 	metricsPKG "github.com/filecoin-project/venus/pkg/metrics"
 	"github.com/filecoin-project/venus/pkg/repo"
 	"github.com/ipfs-force-community/metrics"
@@ -93,6 +98,7 @@ type Node struct {
 	//
 	wallet            *wallet.WalletSubmodule
 	mpool             *mpool.MessagePoolSubmodule
+	multiSig          *multisig.MultiSigSubmodule
 	storageNetworking *storagenetworking.StorageNetworkingSubmodule
 
 	// paychannel and market
@@ -103,6 +109,12 @@ type Node struct {
 
 	eth        *eth.EthSubModule
 	actorEvent *actorevent.ActorEventSubModule
+	disputer   *disputer.DisputerSubmodule
+
+	// faultReporter reports the consensus faults seen in the incoming blocks, when enabled
+	faultReporter *slashfilter.ConsensusFaultReporter
+	// eventSinks pushes the chain to the configured event sinks, when any
+	eventSinks *sink.Service
 
 	//
 	// Jsonrpc
@@ -203,17 +215,47 @@ func (node *Node) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to start eth module %v", err)
 	}
 
+	if err := node.disputer.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start disputer %v", err)
+	}
+
+	if node.faultReporter != nil {
+		if err := node.faultReporter.Start(syncCtx); err != nil {
+			return fmt.Errorf("failed to start consensus fault reporter %v", err)
+		}
+	}
+
+	if node.eventSinks != nil {
+		if err := node.eventSinks.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start event sinks %v", err)
+		}
+	}
+
 	return nil
 }
 
 // Stop initiates the shutdown of the node.
 func (node *Node) Stop(ctx context.Context) {
+	if node.faultReporter != nil {
+		log.Infof("shutting down consensus fault reporter...")
+		node.faultReporter.Stop()
+	}
+
+	if node.eventSinks != nil {
+		log.Infof("shutting down event sinks...")
+		node.eventSinks.Stop()
+	}
+
 	// stop eth submodule
 	log.Infof("closing eth ...")
 	if err := node.eth.Close(ctx); err != nil {
 		log.Warnf("error closing eth: %s", err)
 	}
 
+	// stop disputer submodule
+	log.Infof("shutting down disputer...")
+	node.disputer.Stop()
+
 	// stop mpool submodule
 	log.Infof("shutting down mpool...")
 	node.mpool.Stop(ctx)
@@ -279,6 +321,8 @@ func (node *Node) RunRPCAndWait(ctx context.Context, rootCmdDaemon *cmds.Command
 	if err != nil {
 		return err
 	}
+	mux.Handle(chain2.ExportRangePath, node.chain.ExportRangeHandler())
+	mux.Handle(follower.BlockstorePath, node.blockstore.NetBlockstoreHandler(ctx))
 
 	localVerifer, token, err := jwtclient.NewLocalAuthClient()
 	if err != nil {
@@ -377,6 +421,8 @@ func (node *Node) createServerEnv(ctx context.Context) *Env {
 		WalletAPI:            node.wallet.API(),
 		MingingAPI:           node.mining.API(),
 		MessagePoolAPI:       node.mpool.API(),
+		MultiSigAPI:          node.multiSig.API(),
+		DisputerAPI:          node.disputer.API(),
 		PaychAPI:             node.paychan.API(),
 		MarketAPI:            node.market.API(),
 		CommonAPI:            node.common,
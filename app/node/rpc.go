@@ -6,7 +6,9 @@ import (
 
 	"github.com/filecoin-project/go-jsonrpc"
 	"github.com/filecoin-project/venus/app/submodule/actorevent"
+	"github.com/filecoin-project/venus/app/submodule/disputer"
 	"github.com/filecoin-project/venus/app/submodule/eth"
+	"github.com/filecoin-project/venus/app/submodule/multisig"
 	v0api "github.com/filecoin-project/venus/venus-shared/api/chain/v0"
 	v1api "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
 	"github.com/filecoin-project/venus/venus-shared/api/permission"
@@ -42,6 +44,8 @@ func (builder *RPCBuilder) AddServices(services ...RPCService) error {
 
 var ethSubModuleTyp = reflect.TypeOf(&eth.EthSubModule{}).Elem()
 var actorEventSubModuleTyp = reflect.TypeOf(&actorevent.ActorEventSubModule{}).Elem()
+var multiSigSubModuleTyp = reflect.TypeOf(&multisig.MultiSigSubmodule{}).Elem()
+var disputerSubModuleTyp = reflect.TypeOf(&disputer.DisputerSubmodule{}).Elem()
 
 func skipV0API(in interface{}) bool {
 	inT := reflect.TypeOf(in)
@@ -49,7 +53,8 @@ func skipV0API(in interface{}) bool {
 		inT = inT.Elem()
 	}
 
-	return inT.AssignableTo(ethSubModuleTyp) || inT.AssignableTo(actorEventSubModuleTyp)
+	return inT.AssignableTo(ethSubModuleTyp) || inT.AssignableTo(actorEventSubModuleTyp) ||
+		inT.AssignableTo(multiSigSubModuleTyp) || inT.AssignableTo(disputerSubModuleTyp)
 }
 
 func (builder *RPCBuilder) AddV0API(service RPCService) error {
@@ -182,6 +187,7 @@ func aliasETHAPI(rpcServer *jsonrpc.RPCServer) {
 	rpcServer.AliasMethod("eth_getMessageCidByTransactionHash", "Filecoin.EthGetMessageCidByTransactionHash")
 	rpcServer.AliasMethod("eth_getTransactionCount", "Filecoin.EthGetTransactionCount")
 	rpcServer.AliasMethod("eth_getTransactionReceipt", "Filecoin.EthGetTransactionReceipt")
+	rpcServer.AliasMethod("eth_getBlockReceipts", "Filecoin.EthGetBlockReceipts")
 	rpcServer.AliasMethod("eth_getTransactionByBlockHashAndIndex", "Filecoin.EthGetTransactionByBlockHashAndIndex")
 	rpcServer.AliasMethod("eth_getTransactionByBlockNumberAndIndex", "Filecoin.EthGetTransactionByBlockNumberAndIndex")
 
@@ -210,6 +216,11 @@ func aliasETHAPI(rpcServer *jsonrpc.RPCServer) {
 
 	rpcServer.AliasMethod("trace_block", "Filecoin.EthTraceBlock")
 	rpcServer.AliasMethod("trace_replayBlockTransactions", "Filecoin.EthTraceReplayBlockTransactions")
+	rpcServer.AliasMethod("trace_transaction", "Filecoin.EthTraceTransaction")
+	rpcServer.AliasMethod("trace_filter", "Filecoin.EthTraceFilter")
+
+	rpcServer.AliasMethod("debug_traceTransaction", "Filecoin.EthDebugTraceTransaction")
+	rpcServer.AliasMethod("debug_traceCall", "Filecoin.EthDebugTraceCall")
 
 	rpcServer.AliasMethod("net_version", "Filecoin.NetVersion")
 	rpcServer.AliasMethod("net_listening", "Filecoin.NetListening")
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/filecoin-project/venus/pkg/splitstore"
 	"github.com/filecoin-project/venus/venus-shared/types"
 
 	"github.com/ipfs/boxo/blockservice"
@@ -92,6 +93,32 @@ func (blockstoreAPI *blockstoreAPI) ChainPutObj(ctx context.Context, blk blocks.
 	return blockstoreAPI.blockstore.Blockstore.Put(ctx, blk)
 }
 
+func (blockstoreAPI *blockstoreAPI) ChainSplitStoreInfo(ctx context.Context) (*types.SplitStoreInfo, error) {
+	ss, err := blockstoreAPI.splitStore()
+	if err != nil {
+		return nil, err
+	}
+	info := ss.Info()
+	return &info, nil
+}
+
+func (blockstoreAPI *blockstoreAPI) ChainSplitStoreCompact(ctx context.Context) error {
+	ss, err := blockstoreAPI.splitStore()
+	if err != nil {
+		return err
+	}
+	// the compaction outlives the request, it stops when the splitstore is closed
+	return ss.Compact(context.Background())
+}
+
+func (blockstoreAPI *blockstoreAPI) splitStore() (*splitstore.SplitStore, error) {
+	ss, ok := blockstoreAPI.blockstore.Blockstore.(*splitstore.SplitStore)
+	if !ok {
+		return nil, fmt.Errorf("splitstore is not enabled, datastore type must be splitstore")
+	}
+	return ss, nil
+}
+
 func (blockstoreAPI *blockstoreAPI) PutMany(ctx context.Context, blocks []blocks.Block) error {
 	return blockstoreAPI.blockstore.Blockstore.PutMany(ctx, blocks)
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/filecoin-project/venus/pkg/repo"
 	v0api "github.com/filecoin-project/venus/venus-shared/api/chain/v0"
+	v1api "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
 	blockstoreutil "github.com/filecoin-project/venus/venus-shared/blockstore"
 )
 
@@ -30,7 +31,7 @@ func NewBlockstoreSubmodule(ctx context.Context, repo blockstoreRepo) (*Blocksto
 	}, nil
 }
 
-func (bsm *BlockstoreSubmodule) API() v0api.IBlockStore {
+func (bsm *BlockstoreSubmodule) API() v1api.IBlockStore {
 	return &blockstoreAPI{blockstore: bsm}
 }
 
@@ -0,0 +1,64 @@
+package blockstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"github.com/ipfs-force-community/sophon-auth/core"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	logging "github.com/ipfs/go-log/v2"
+
+	blockstoreutil "github.com/filecoin-project/venus/venus-shared/blockstore"
+)
+
+var log = logging.Logger("blockstore_module")
+
+var errReadOnly = errors.New("the blockstore is served read only")
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// NetBlockstoreHandler serves the blockstore read only over websocket with the protocol of the
+// NetworkStore, for follower nodes to read the chain from. Each connection is served until it
+// closes or ctx is done.
+func (bsm *BlockstoreSubmodule) NetBlockstoreHandler(ctx context.Context) http.Handler {
+	bs := readOnlyBlockstore{Blockstore: bsm.Blockstore}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !core.HasPerm(r.Context(), []core.Permission{core.PermRead}, core.PermRead) {
+			http.Error(w, "missing permission to read the blockstore (need 'read')", http.StatusUnauthorized)
+			return
+		}
+
+		wc, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Warnf("upgrading blockstore connection from %s: %s", r.RemoteAddr, err)
+			return
+		}
+		blockstoreutil.HandleNetBstoreWS(ctx, bs, wc)
+	})
+}
+
+// readOnlyBlockstore rejects the writes of the nodes the blockstore is served to.
+type readOnlyBlockstore struct {
+	blockstoreutil.Blockstore
+}
+
+func (readOnlyBlockstore) Put(context.Context, blocks.Block) error {
+	return errReadOnly
+}
+
+func (readOnlyBlockstore) PutMany(context.Context, []blocks.Block) error {
+	return errReadOnly
+}
+
+func (readOnlyBlockstore) DeleteBlock(context.Context, cid.Cid) error {
+	return errReadOnly
+}
+
+func (readOnlyBlockstore) DeleteMany(context.Context, []cid.Cid) error {
+	return errReadOnly
+}
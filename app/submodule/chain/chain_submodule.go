@@ -13,8 +13,10 @@ import (
 	"github.com/filecoin-project/venus/pkg/consensus"
 	"github.com/filecoin-project/venus/pkg/consensus/chainselector"
 	"github.com/filecoin-project/venus/pkg/consensusfault"
+	"github.com/filecoin-project/venus/pkg/follower"
 	"github.com/filecoin-project/venus/pkg/fork"
 	"github.com/filecoin-project/venus/pkg/repo"
+	"github.com/filecoin-project/venus/pkg/splitstore"
 	"github.com/filecoin-project/venus/pkg/statemanger"
 	"github.com/filecoin-project/venus/pkg/util/ffiwrapper"
 	"github.com/filecoin-project/venus/pkg/vm"
@@ -47,6 +49,7 @@ type chainConfig interface {
 	BlockTime() time.Duration
 	Repo() repo.Repo
 	Verifier() ffiwrapper.Verifier
+	Follower() *follower.Follower
 }
 
 // NewChainSubmodule creates a new chain submodule.
@@ -57,6 +60,10 @@ func NewChainSubmodule(ctx context.Context,
 	repo := config.Repo()
 	// initialize chain store
 	chainStore := chain.NewStore(repo.ChainDatastore(), repo.Datastore(), config.GenesisCid(), circulatiingSupplyCalculator, chainselector.Weight)
+	if f := config.Follower(); f != nil {
+		// a follower loads the states of the chain from the leader instead of executing it
+		chainStore.SetTipSetMetadataLoader(f.LoadTipSetMetadata)
+	}
 	// drand
 	genBlk, err := chainStore.GetGenesisBlock(context.TODO())
 	if err != nil {
@@ -99,6 +106,11 @@ func NewChainSubmodule(ctx context.Context,
 
 // Start loads the chain from disk.
 func (chain *ChainSubmodule) Start(ctx context.Context) error {
+	if ss, ok := chain.config.Repo().Datastore().(*splitstore.SplitStore); ok {
+		ss.Start(chain.ChainReader)
+		chain.ChainReader.SubscribeHeadChanges(ss.HeadChange)
+	}
+
 	return chain.Fork.Start(ctx)
 }
 
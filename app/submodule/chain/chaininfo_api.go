@@ -31,6 +31,7 @@ import (
 	"github.com/filecoin-project/venus/pkg/statemanger"
 	"github.com/filecoin-project/venus/venus-shared/actors"
 	v1api "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	blockstoreutil "github.com/filecoin-project/venus/venus-shared/blockstore"
 	"github.com/filecoin-project/venus/venus-shared/types"
 	"github.com/filecoin-project/venus/venus-shared/utils"
 )
@@ -325,6 +326,28 @@ func (cia *chainInfoAPI) ChainNotify(ctx context.Context) (<-chan []*types.HeadC
 	return cia.chain.ChainReader.SubHeadChanges(ctx), nil
 }
 
+// ChainNotifySince subscribe to chain head change event, starting with the path from the tipset tsk to the head
+func (cia *chainInfoAPI) ChainNotifySince(ctx context.Context, tsk types.TipSetKey) (<-chan []*types.HeadChange, error) {
+	from, err := cia.chain.ChainReader.GetTipSet(ctx, tsk)
+	if err != nil {
+		return nil, fmt.Errorf("loading tipset %s: %w", tsk, err)
+	}
+	return cia.chain.ChainReader.SubHeadChangesSince(ctx, from)
+}
+
+// ChainGetTipSetState returns the stored state root and receipts root after the execution of the tipset tsk
+func (cia *chainInfoAPI) ChainGetTipSetState(ctx context.Context, tsk types.TipSetKey) (*types.TipSetState, error) {
+	ts, err := cia.chain.ChainReader.GetTipSet(ctx, tsk)
+	if err != nil {
+		return nil, fmt.Errorf("loading tipset %s: %w", tsk, err)
+	}
+	tsm, err := cia.chain.ChainReader.GetTipsetMetadata(ctx, ts)
+	if err != nil {
+		return nil, fmt.Errorf("tipset %s has not been executed: %w", tsk, err)
+	}
+	return &types.TipSetState{StateRoot: tsm.TipSetStateRoot, Receipts: tsm.TipSetReceipts}, nil
+}
+
 //************Drand****************//
 
 // GetEntry retrieves an entry from the drand server
@@ -533,6 +556,32 @@ func (cia *chainInfoAPI) StateSearchMsg(ctx context.Context, from types.TipSetKe
 	if err != nil {
 		return nil, err
 	}
+	return searchLookup(msgResult, found), nil
+}
+
+// StateSearchMsgFinality searches for a message in the chain, and returns it once the reorg
+// probability of the tipset where it was executed is at most reorgProbability
+func (cia *chainInfoAPI) StateSearchMsgFinality(ctx context.Context, from types.TipSetKey, mCid cid.Cid, reorgProbability float64, lookbackLimit abi.ChainEpoch, allowReplaced bool) (*types.MsgLookup, error) {
+	reorgProbability, err := finalityThreshold(reorgProbability)
+	if err != nil {
+		return nil, err
+	}
+	chainMsg, err := cia.chain.MessageStore.LoadMessage(ctx, mCid)
+	if err != nil {
+		return nil, err
+	}
+	head, err := cia.chain.ChainReader.GetTipSet(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	msgResult, found, err := cia.chain.Waiter.FindFinal(ctx, chainMsg, reorgProbability, lookbackLimit, head, allowReplaced)
+	if err != nil {
+		return nil, err
+	}
+	return searchLookup(msgResult, found), nil
+}
+
+func searchLookup(msgResult *types.ChainMessage, found bool) *types.MsgLookup {
 
 	if found {
 		return &types.MsgLookup{
@@ -540,9 +589,9 @@ func (cia *chainInfoAPI) StateSearchMsg(ctx context.Context, from types.TipSetKe
 			Receipt: *msgResult.Receipt,
 			TipSet:  msgResult.TS.Key(),
 			Height:  msgResult.TS.Height(),
-		}, nil
+		}
 	}
-	return nil, nil
+	return nil
 }
 
 var ErrMetadataNotFound = errors.New("actor metadata not found")
@@ -577,6 +626,28 @@ func (cia *chainInfoAPI) StateWaitMsg(ctx context.Context, mCid cid.Cid, confide
 	if err != nil {
 		return nil, err
 	}
+	return cia.waitLookup(ctx, chainMsg, msgResult)
+}
+
+// StateWaitMsgFinality looks back in the chain for a message. If not found, it blocks until the
+// message arrives on chain, and the reorg probability of its tipset is at most reorgProbability.
+func (cia *chainInfoAPI) StateWaitMsgFinality(ctx context.Context, mCid cid.Cid, reorgProbability float64, lookbackLimit abi.ChainEpoch, allowReplaced bool) (*types.MsgLookup, error) {
+	reorgProbability, err := finalityThreshold(reorgProbability)
+	if err != nil {
+		return nil, err
+	}
+	chainMsg, err := cia.chain.MessageStore.LoadMessage(ctx, mCid)
+	if err != nil {
+		return nil, err
+	}
+	msgResult, err := cia.chain.Waiter.WaitPredicate(ctx, chainMsg, reorgProbability, lookbackLimit, allowReplaced)
+	if err != nil {
+		return nil, err
+	}
+	return cia.waitLookup(ctx, chainMsg, msgResult)
+}
+
+func (cia *chainInfoAPI) waitLookup(ctx context.Context, chainMsg types.ChainMsg, msgResult *types.ChainMessage) (*types.MsgLookup, error) {
 	if msgResult != nil {
 		var returndec interface{}
 		recpt := msgResult.Receipt
@@ -616,18 +687,53 @@ func (cia *chainInfoAPI) ChainExport(ctx context.Context, nroots abi.ChainEpoch,
 	if err != nil {
 		return nil, fmt.Errorf("loading tipset %s: %v", tsk, err)
 	}
+	return exportStream(ctx, func(w io.Writer) error {
+		return cia.chain.ChainReader.Export(ctx, ts, nroots, skipoldmsgs, w)
+	}), nil
+}
+
+// ChainExportRange streams a car of the tipsets from head down to the height tail
+func (cia *chainInfoAPI) ChainExportRange(ctx context.Context, head types.TipSetKey, tail abi.ChainEpoch, opts types.ChainExportRangeOpts) (<-chan []byte, error) {
+	ts, err := cia.chain.ChainReader.GetTipSet(ctx, head)
+	if err != nil {
+		return nil, fmt.Errorf("loading tipset %s: %v", head, err)
+	}
+	if tail > ts.Height() {
+		return nil, fmt.Errorf("tail %d is above head %d", tail, ts.Height())
+	}
+	return exportStream(ctx, func(w io.Writer) error {
+		return cia.chain.ChainReader.ExportRange(ctx, w, ts, tail, opts)
+	}), nil
+}
+
+// ChainSnapshotImportStatus returns the status of the last snapshot import
+func (cia *chainInfoAPI) ChainSnapshotImportStatus(ctx context.Context) (*types.SnapshotImportStatus, error) {
+	status, err := cia.chain.ChainReader.GetSnapshotImportStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if status == nil {
+		return nil, fmt.Errorf("no snapshot was imported")
+	}
+	return status, nil
+}
+
+// exportStream streams what export writes in chunks, ending with an empty chunk when it succeeded.
+func exportStream(ctx context.Context, export func(w io.Writer) error) <-chan []byte {
 	r, w := io.Pipe()
 	out := make(chan []byte)
 	go func() {
 		bw := bufio.NewWriterSize(w, 1<<20)
 
-		err := cia.chain.ChainReader.Export(ctx, ts, nroots, skipoldmsgs, bw)
+		err := export(bw)
 		bw.Flush()            //nolint:errcheck // it is a write to a pipe
 		w.CloseWithError(err) //nolint:errcheck // it is a pipe
 	}()
 
 	go func() {
 		defer close(out)
+		// unblock the export when the stream is abandoned
+		defer r.Close() //nolint:errcheck // it is a pipe
 		for {
 			buf := make([]byte, 1<<20)
 			n, err := r.Read(buf)
@@ -657,7 +763,7 @@ func (cia *chainInfoAPI) ChainExport(ctx context.Context, nroots abi.ChainEpoch,
 		}
 	}()
 
-	return out, nil
+	return out
 }
 
 // ChainGetPath returns a set of revert/apply operations needed to get from
@@ -704,6 +810,45 @@ func (cia *chainInfoAPI) ChainGetPath(ctx context.Context, from types.TipSetKey,
 	return path, nil
 }
 
+// ChainGetFinality returns the reorg probability of the tipset at height and the highest safe height.
+func (cia *chainInfoAPI) ChainGetFinality(ctx context.Context, height abi.ChainEpoch, threshold float64) (*types.ChainFinalityStatus, error) {
+	threshold, err := finalityThreshold(threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	head := cia.chain.ChainReader.GetHead()
+	fc := chain.NewFinalityCalculator(cia.chain.ChainReader)
+	p, err := fc.ReorgProbability(ctx, head, height)
+	if err != nil {
+		return nil, err
+	}
+	safe, err := fc.SafeHeight(ctx, head, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.ChainFinalityStatus{
+		Head:             head.Height(),
+		Height:           height,
+		ReorgProbability: p,
+		Threshold:        threshold,
+		SafeHeight:       safe,
+		SafeDepth:        head.Height() - safe,
+	}, nil
+}
+
+// finalityThreshold checks a reorg probability, zero selects the default one.
+func finalityThreshold(p float64) (float64, error) {
+	if p < 0 || p >= 1 {
+		return 0, fmt.Errorf("reorg probability %v out of range [0, 1)", p)
+	}
+	if p == 0 {
+		return chain.DefaultFinalityThreshold, nil
+	}
+	return p, nil
+}
+
 // StateGetNetworkParams returns current network params
 func (cia *chainInfoAPI) StateGetNetworkParams(ctx context.Context) (*types.NetworkParams, error) {
 	networkName, err := cia.getNetworkName(ctx)
@@ -926,3 +1071,26 @@ func (cia *chainInfoAPI) StateCompute(ctx context.Context, height abi.ChainEpoch
 		Trace: t,
 	}, nil
 }
+
+// StateSimulateBundle applies msgs in order on top of the state computed by tsk
+func (cia *chainInfoAPI) StateSimulateBundle(ctx context.Context, msgs []*types.Message, tsk types.TipSetKey, opts types.SimulateBundleOpts) (*types.SimulateBundleResult, error) {
+	ts, err := cia.ChainGetTipSet(ctx, tsk)
+	if err != nil {
+		return nil, fmt.Errorf("loading tipset %s: %w", tsk, err)
+	}
+	return cia.chain.Stmgr.SimulateBundle(ctx, msgs, ts, opts)
+}
+
+// ChainPrune deletes the objects that are not reachable from the head
+func (cia *chainInfoAPI) ChainPrune(ctx context.Context, opts types.ChainPruneOpts) (*types.ChainPruneResult, error) {
+	return cia.chain.ChainReader.Prune(ctx, opts)
+}
+
+// ChainHotGC reclaims the disk space of deleted objects
+func (cia *chainInfoAPI) ChainHotGC(ctx context.Context, opts types.HotGCOpts) error {
+	gc, ok := cia.chain.ChainReader.Blockstore().(blockstoreutil.BlockstoreGC)
+	if !ok {
+		return fmt.Errorf("blockstore does not support garbage collection")
+	}
+	return gc.CollectGarbage(blockstoreutil.WithThreshold(opts.Threshold))
+}
@@ -0,0 +1,174 @@
+package chain
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus/pkg/chain"
+	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/pkg/state/tree"
+	"github.com/filecoin-project/venus/pkg/statemanger"
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	"github.com/filecoin-project/venus/venus-shared/actors/builtin"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// appendTipSet stores and sets as head a child of parent with the expected
+// number of blocks on top of the state root, the first block carries the
+// messages msgs. The fake state of the builder does not allow to build on a
+// tipset with messages.
+func appendTipSet(ctx context.Context, t *testing.T, builder *chain.Builder, parent *types.TipSet, root, msgs, empty cid.Cid) *types.TipSet {
+	var blks []*types.BlockHeader
+	for i := 0; i < int(constants.ExpectedLeadersPerEpoch); i++ {
+		blk := *parent.At(0)
+		miner, err := address.NewIDAddress(uint64(1000 + i))
+		require.NoError(t, err)
+		blk.Miner = miner
+		blk.ParentStateRoot = root
+		blk.Parents = parent.Cids()
+		blk.Height = parent.Height() + 1
+		blk.Messages = empty
+		if i == 0 {
+			blk.Messages = msgs
+		}
+		blk.Ticket = &types.Ticket{VRFProof: binary.BigEndian.AppendUint64(nil, uint64(int(blk.Height)*100+i))}
+		_, err = builder.Cstore().Put(ctx, &blk)
+		require.NoError(t, err)
+		blks = append(blks, &blk)
+	}
+
+	ts, err := types.NewTipSet(blks)
+	require.NoError(t, err)
+	require.NoError(t, builder.Store().SetHead(ctx, ts))
+	return ts
+}
+
+func TestStateMsgFinality(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	builder := chain.NewBuilder(t, address.Undef)
+	eval := builder.FakeStateEvaluator()
+	stmgr, err := statemanger.NewStateManager(builder.Store(), builder.MessageStore(), eval, nil, nil, nil, nil, false)
+	require.NoError(t, err)
+	waiter := chain.NewWaiter(builder.Store(), builder.MessageStore(), builder.BlockStore(), builder.Cstore())
+	waiter.Stmgr = stmgr
+	api := &chainInfoAPI{chain: &ChainSubmodule{
+		ChainReader:  builder.Store(),
+		MessageStore: builder.MessageStore(),
+		Stmgr:        stmgr,
+		Waiter:       waiter,
+	}}
+
+	// the sender only exists in the state after the message
+	from, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+	msg := &types.Message{From: from, To: builtin.BurntFundsActorAddr, Method: 1 << 20, Value: abi.NewTokenAmount(0)}
+	genesisRoot := builder.Genesis().At(0).ParentStateRoot
+	st, err := tree.LoadState(ctx, builder.Cstore(), genesisRoot)
+	require.NoError(t, err)
+	act, found, err := st.GetActor(ctx, builtin.BurntFundsActorAddr)
+	require.NoError(t, err)
+	require.True(t, found)
+	act.Nonce = 1
+	require.NoError(t, st.SetActor(ctx, from, act))
+	root, err := st.Flush(ctx)
+	require.NoError(t, err)
+	meta, err := builder.MessageStore().StoreMessages(ctx, nil, []*types.Message{msg})
+	require.NoError(t, err)
+	empty, err := builder.MessageStore().StoreMessages(ctx, nil, nil)
+	require.NoError(t, err)
+
+	head := builder.Genesis()
+	for i := 0; i < 5; i++ {
+		head = appendTipSet(ctx, t, builder, head, genesisRoot, empty, empty)
+	}
+	included := appendTipSet(ctx, t, builder, head, genesisRoot, meta, empty)
+	receipts, err := builder.MessageStore().StoreReceipts(ctx, []types.MessageReceipt{{GasUsed: 3}})
+	require.NoError(t, err)
+	require.NoError(t, builder.Store().PutTipSetMetadata(ctx, &chain.TipSetMetadata{
+		TipSetStateRoot: root,
+		TipSet:          included,
+		TipSetReceipts:  receipts,
+	}))
+	executed := appendTipSet(ctx, t, builder, included, root, empty, empty)
+	head = appendTipSet(ctx, t, builder, executed, root, empty, empty)
+
+	// the tipset executing the message was just mined, it is far from final
+	lookup, err := api.StateSearchMsgFinality(ctx, head.Key(), msg.Cid(), 0, constants.LookbackNoLimit, true)
+	require.NoError(t, err)
+	assert.Nil(t, lookup)
+
+	lookup, err = api.StateSearchMsgFinality(ctx, head.Key(), msg.Cid(), 0.999, constants.LookbackNoLimit, true)
+	require.NoError(t, err)
+	require.NotNil(t, lookup)
+	assert.Equal(t, msg.Cid(), lookup.Message)
+	assert.Equal(t, executed.Key(), lookup.TipSet)
+
+	_, err = api.StateSearchMsgFinality(ctx, head.Key(), msg.Cid(), 1, constants.LookbackNoLimit, true)
+	require.Error(t, err)
+
+	// the wait returns once enough tipsets are mined on top of the message
+	type result struct {
+		lookup *types.MsgLookup
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		lookup, err := api.StateWaitMsgFinality(ctx, msg.Cid(), 0, constants.LookbackNoLimit, true)
+		done <- result{lookup, err}
+	}()
+
+	fc := chain.NewFinalityCalculator(builder.Store())
+	for {
+		p, err := fc.ReorgProbability(ctx, head, executed.Height())
+		require.NoError(t, err)
+		if p <= chain.DefaultFinalityThreshold {
+			break
+		}
+		select {
+		case r := <-done:
+			t.Fatalf("wait returned before the message is final: %v %v", r.lookup, r.err)
+		default:
+		}
+		head = appendTipSet(ctx, t, builder, head, root, empty, empty)
+	}
+
+	select {
+	case r := <-done:
+		require.NoError(t, r.err)
+		require.NotNil(t, r.lookup)
+		assert.Equal(t, msg.Cid(), r.lookup.Message)
+		assert.Equal(t, executed.Key(), r.lookup.TipSet)
+	case <-time.After(10 * time.Second):
+		t.Fatal("wait did not return once the message is final")
+	}
+}
+
+func TestChainGetTipSetState(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	builder := chain.NewBuilder(t, address.Undef)
+	api := &chainInfoAPI{chain: &ChainSubmodule{ChainReader: builder.Store()}}
+
+	// the state of the genesis is stored
+	genesis := builder.Genesis()
+	state, err := api.ChainGetTipSetState(ctx, genesis.Key())
+	require.NoError(t, err)
+	require.Equal(t, genesis.At(0).ParentStateRoot, state.StateRoot)
+	require.Equal(t, genesis.At(0).ParentMessageReceipts, state.Receipts)
+
+	// a tipset the node has not executed is not executed by the call
+	ts := builder.AppendOn(ctx, genesis, 1)
+	_, err = api.ChainGetTipSetState(ctx, ts.Key())
+	require.ErrorContains(t, err, "has not been executed")
+}
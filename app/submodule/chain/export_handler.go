@@ -0,0 +1,120 @@
+package chain
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs-force-community/sophon-auth/core"
+	"github.com/ipfs/go-cid"
+
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// ExportRangePath is the http path range exports of the chain are served at.
+const ExportRangePath = "/chain/export"
+
+// maxExportRangeWorkers bounds the walkers of an export requested over http.
+const maxExportRangeWorkers = 16
+
+// ExportRangeHandler serves range exports of the chain as car files, for other nodes to download
+// partial history with a plain http client:
+//
+//	GET /chain/export?tail=<height>[&head=<cid>,<cid>][&workers=<n>][&messages=false][&receipts=false][&stateroots=false][&skipheader=true]
+//
+// The head defaults to the chain head, and the messages, receipts and states are included unless
+// disabled.
+func (chain *ChainSubmodule) ExportRangeHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !core.HasPerm(r.Context(), []core.Permission{core.PermRead}, core.PermRead) {
+			http.Error(w, "missing permission to export the chain (need 'read')", http.StatusUnauthorized)
+			return
+		}
+
+		head, tail, opts, err := parseExportRangeQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+		ts := chain.ChainReader.GetHead()
+		if !head.IsEmpty() {
+			if ts, err = chain.ChainReader.GetTipSet(ctx, head); err != nil {
+				http.Error(w, fmt.Sprintf("loading tipset %s: %s", head, err), http.StatusNotFound)
+				return
+			}
+		}
+		if tail > ts.Height() {
+			http.Error(w, fmt.Sprintf("tail %d is above head %d", tail, ts.Height()), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/vnd.ipld.car")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"chain-%d-%d.car\"", tail, ts.Height()))
+		if err := chain.ChainReader.ExportRange(ctx, w, ts, tail, opts); err != nil {
+			// the status is sent already, the client sees a truncated car it can resume
+			log.Errorf("range export of %d to %d failed: %s", ts.Height(), tail, err)
+		}
+	})
+}
+
+func parseExportRangeQuery(r *http.Request) (types.TipSetKey, abi.ChainEpoch, types.ChainExportRangeOpts, error) {
+	q := r.URL.Query()
+	opts := types.ChainExportRangeOpts{
+		Workers:    1,
+		Messages:   true,
+		Receipts:   true,
+		StateRoots: true,
+	}
+
+	var head types.TipSetKey
+	if v := q.Get("head"); v != "" {
+		var cids []cid.Cid
+		for _, s := range strings.Split(v, ",") {
+			c, err := cid.Decode(strings.TrimSpace(s))
+			if err != nil {
+				return head, 0, opts, fmt.Errorf("invalid head: %w", err)
+			}
+			cids = append(cids, c)
+		}
+		head = types.NewTipSetKey(cids...)
+	}
+
+	if q.Get("tail") == "" {
+		return head, 0, opts, fmt.Errorf("missing tail")
+	}
+	tail, err := strconv.ParseInt(q.Get("tail"), 10, 64)
+	if err != nil {
+		return head, 0, opts, fmt.Errorf("invalid tail: %w", err)
+	}
+
+	if v := q.Get("workers"); v != "" {
+		if opts.Workers, err = strconv.Atoi(v); err != nil {
+			return head, 0, opts, fmt.Errorf("invalid workers: %w", err)
+		}
+		if opts.Workers > maxExportRangeWorkers {
+			opts.Workers = maxExportRangeWorkers
+		}
+	}
+	for name, opt := range map[string]*bool{
+		"messages":   &opts.Messages,
+		"receipts":   &opts.Receipts,
+		"stateroots": &opts.StateRoots,
+		"skipheader": &opts.SkipHeader,
+	} {
+		if v := q.Get(name); v != "" {
+			if *opt, err = strconv.ParseBool(v); err != nil {
+				return head, 0, opts, fmt.Errorf("invalid %s: %w", name, err)
+			}
+		}
+	}
+
+	return head, abi.ChainEpoch(tail), opts, nil
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"sort"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-bitfield"
@@ -1225,6 +1226,162 @@ func (msa *minerStateAPI) StateChangedActors(ctx context.Context, old cid.Cid, n
 	return tree.Diff(oldTree, newTree)
 }
 
+// StateDiff returns the actors added, removed and modified between the parent states of tipsets from and to
+func (msa *minerStateAPI) StateDiff(ctx context.Context, from, to types.TipSetKey, opts types.StateDiffOpts) (*types.StateDiff, error) {
+	fromTS, err := msa.ChainReader.GetTipSet(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("loading tipset %s: %w", from, err)
+	}
+	toTS, err := msa.ChainReader.GetTipSet(ctx, to)
+	if err != nil {
+		return nil, fmt.Errorf("loading tipset %s: %w", to, err)
+	}
+
+	store := msa.ChainReader.Store(ctx)
+	fromTree, err := tree.LoadState(ctx, store, fromTS.ParentState())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load old state tree: %w", err)
+	}
+	toTree, err := tree.LoadState(ctx, store, toTS.ParentState())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load new state tree: %w", err)
+	}
+
+	changes, err := tree.DiffActors(ctx, fromTree, toTree)
+	if err != nil {
+		return nil, fmt.Errorf("diffing state trees: %w", err)
+	}
+
+	out := &types.StateDiff{
+		From: fromTS.ParentState(),
+		To:   toTS.ParentState(),
+	}
+	for _, c := range changes {
+		diff := &types.ActorDiff{
+			Address:      c.Address,
+			From:         c.From,
+			To:           c.To,
+			BalanceDelta: big.Zero(),
+		}
+		if c.To != nil {
+			diff.BalanceDelta = big.Add(diff.BalanceDelta, c.To.Balance)
+			diff.NonceDelta += int64(c.To.Nonce)
+		}
+		if c.From != nil {
+			diff.BalanceDelta = big.Sub(diff.BalanceDelta, c.From.Balance)
+			diff.NonceDelta -= int64(c.From.Nonce)
+		}
+
+		switch {
+		case c.From == nil:
+			out.Added = append(out.Added, diff)
+		case c.To == nil:
+			out.Removed = append(out.Removed, diff)
+		default:
+			if opts.DecodeStates {
+				if err := msa.decodeStateDiff(ctx, diff); err != nil {
+					return nil, fmt.Errorf("diffing the state of %s: %w", c.Address, err)
+				}
+			}
+			out.Modified = append(out.Modified, diff)
+		}
+	}
+
+	for _, diffs := range [][]*types.ActorDiff{out.Added, out.Removed, out.Modified} {
+		sort.Slice(diffs, func(i, j int) bool {
+			return diffs[i].Address.String() < diffs[j].Address.String()
+		})
+	}
+
+	return out, nil
+}
+
+// decodeStateDiff diffs the states of the modified miner, market and power actors.
+func (msa *minerStateAPI) decodeStateDiff(ctx context.Context, diff *types.ActorDiff) error {
+	if diff.From.Head == diff.To.Head {
+		return nil
+	}
+	store := msa.ChainReader.Store(ctx)
+
+	switch {
+	case builtin.IsStorageMinerActor(diff.From.Code) && builtin.IsStorageMinerActor(diff.To.Code):
+		pre, err := lminer.Load(store, diff.From)
+		if err != nil {
+			return err
+		}
+		cur, err := lminer.Load(store, diff.To)
+		if err != nil {
+			return err
+		}
+
+		md := &types.MinerStateDiff{}
+		if md.Sectors, err = lminer.DiffSectors(pre, cur); err != nil {
+			return err
+		}
+		if md.PreCommits, err = lminer.DiffPreCommits(pre, cur); err != nil {
+			return err
+		}
+		if md.Deadlines, err = lminer.DiffDeadlines(pre, cur); err != nil {
+			return err
+		}
+		diff.Miner = md
+	case diff.Address == market.Address:
+		pre, err := market.Load(store, diff.From)
+		if err != nil {
+			return err
+		}
+		cur, err := market.Load(store, diff.To)
+		if err != nil {
+			return err
+		}
+
+		md := &types.MarketStateDiff{}
+		preProposals, err := pre.Proposals()
+		if err != nil {
+			return err
+		}
+		curProposals, err := cur.Proposals()
+		if err != nil {
+			return err
+		}
+		if md.Proposals, err = market.DiffDealProposals(preProposals, curProposals); err != nil {
+			return err
+		}
+
+		preStates, err := pre.States()
+		if err != nil {
+			return err
+		}
+		curStates, err := cur.States()
+		if err != nil {
+			return err
+		}
+		states, err := market.DiffDealStates(preStates, curStates)
+		if err != nil {
+			return err
+		}
+		md.States = types.MakeMarketDealStateChanges(states)
+		diff.Market = md
+	case diff.Address == power.Address:
+		pre, err := power.Load(store, diff.From)
+		if err != nil {
+			return err
+		}
+		cur, err := power.Load(store, diff.To)
+		if err != nil {
+			return err
+		}
+
+		claims, err := power.DiffClaims(pre, cur)
+		if err != nil {
+			return err
+		}
+		diff.Power = &types.PowerStateDiff{Claims: claims}
+	}
+
+	return nil
+}
+
 func (msa *minerStateAPI) StateReadState(ctx context.Context, actor address.Address, tsk types.TipSetKey) (*types.ActorState, error) {
 	_, view, err := msa.Stmgr.ParentStateViewTsk(ctx, tsk)
 	if err != nil {
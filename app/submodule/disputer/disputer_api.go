@@ -0,0 +1,35 @@
+package disputer
+
+import (
+	"context"
+
+	"github.com/filecoin-project/venus/pkg/disputer"
+	v1api "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+var _ v1api.IDisputer = &disputerAPI{}
+
+type disputerAPI struct {
+	disputer *disputer.Disputer
+}
+
+// DisputerStart starts the window post disputer with the disputer config of the node
+func (da *disputerAPI) DisputerStart(ctx context.Context) error {
+	return da.disputer.Start(ctx)
+}
+
+// DisputerStop stops the window post disputer
+func (da *disputerAPI) DisputerStop(ctx context.Context) error {
+	return da.disputer.Stop(ctx)
+}
+
+// DisputerStatus returns the progress of the window post disputer
+func (da *disputerAPI) DisputerStatus(ctx context.Context) (*types.DisputerStatus, error) {
+	return da.disputer.Status(ctx), nil
+}
+
+// DisputerHistory returns the DisputeWindowedPoSt messages sent by the disputer
+func (da *disputerAPI) DisputerHistory(ctx context.Context) ([]*types.DisputeRecord, error) {
+	return da.disputer.History(ctx)
+}
@@ -0,0 +1,39 @@
+package disputer
+
+import (
+	"context"
+
+	"github.com/ipfs/go-datastore"
+
+	"github.com/filecoin-project/venus/pkg/config"
+	"github.com/filecoin-project/venus/pkg/disputer"
+	v1api "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+)
+
+// DisputerSubmodule enhances the `Node` with a window post disputer.
+type DisputerSubmodule struct { //nolint
+	disputer *disputer.Disputer
+}
+
+// NewDisputerSubmodule create new disputer module, which keeps its progress in ds
+func NewDisputerSubmodule(ctx context.Context, api disputer.API, ds datastore.Batching, cfg func() *config.DisputerConfig) (*DisputerSubmodule, error) {
+	d, err := disputer.NewDisputer(ctx, api, ds, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &DisputerSubmodule{disputer: d}, nil
+}
+
+// Start resumes the disputer when it is enabled or was running
+func (ds *DisputerSubmodule) Start(ctx context.Context) error {
+	return ds.disputer.Resume(ctx)
+}
+
+func (ds *DisputerSubmodule) Stop() {
+	ds.disputer.Close()
+}
+
+// API create a new disputer implement
+func (ds *DisputerSubmodule) API() v1api.IDisputer {
+	return &disputerAPI{disputer: ds.disputer}
+}
@@ -77,6 +77,14 @@ func (e *ethAPIDummy) EthGetTransactionReceiptLimited(ctx context.Context, txHas
 	return nil, ErrModuleDisabled
 }
 
+func (e *ethAPIDummy) EthGetBlockReceipts(ctx context.Context, blkParam types.EthBlockNumberOrHash) ([]*types.EthTxReceipt, error) {
+	return nil, ErrModuleDisabled
+}
+
+func (e *ethAPIDummy) EthGetBlockReceiptsLimited(ctx context.Context, blkParam types.EthBlockNumberOrHash, limit abi.ChainEpoch) ([]*types.EthTxReceipt, error) {
+	return nil, ErrModuleDisabled
+}
+
 func (e *ethAPIDummy) EthGetTransactionByBlockHashAndIndex(ctx context.Context, blkHash types.EthHash, txIndex types.EthUint64) (types.EthTx, error) {
 	return types.EthTx{}, ErrModuleDisabled
 }
@@ -153,6 +161,22 @@ func (e *ethAPIDummy) EthTraceReplayBlockTransactions(ctx context.Context, blkNu
 	return nil, ErrModuleDisabled
 }
 
+func (e *ethAPIDummy) EthTraceTransaction(ctx context.Context, txHash string) ([]*types.EthTraceTransaction, error) {
+	return nil, ErrModuleDisabled
+}
+
+func (e *ethAPIDummy) EthTraceFilter(ctx context.Context, filter types.EthTraceFilterCriteria) ([]*types.EthTraceFilterResult, error) {
+	return nil, ErrModuleDisabled
+}
+
+func (e *ethAPIDummy) EthDebugTraceTransaction(ctx context.Context, txHash string, config types.EthTraceConfig) (interface{}, error) {
+	return nil, ErrModuleDisabled
+}
+
+func (e *ethAPIDummy) EthDebugTraceCall(ctx context.Context, tx types.EthCall, blkParam types.EthBlockNumberOrHash, config types.EthTraceConfig) (interface{}, error) {
+	return nil, ErrModuleDisabled
+}
+
 func (e *ethAPIDummy) start(_ context.Context) error {
 	return nil
 }
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strconv"
 
 	"github.com/filecoin-project/go-address"
@@ -94,7 +93,7 @@ func (a *ethAPI) start(ctx context.Context) error {
 	// Tipset listener
 	_ = ev.Observe(a.ethTxHashManager)
 
-	ch, err := a.em.mpoolModule.MPool.Updates(ctx)
+	ch, err := a.mpool.MpoolSub(ctx)
 	if err != nil {
 		return err
 	}
@@ -338,12 +337,14 @@ func (a *ethAPI) EthGetTransactionCount(ctx context.Context, sender types.EthAdd
 	}
 
 	// First, handle the case where the "sender" is an EVM actor.
-	if actor, err := a.em.chainModule.Stmgr.GetActorAt(ctx, addr, ts); err != nil {
+	actor, err := a.em.chainModule.Stmgr.GetActorAt(ctx, addr, ts)
+	if err != nil {
 		if errors.Is(err, types.ErrActorNotFound) {
 			return 0, nil
 		}
 		return 0, fmt.Errorf("failed to lookup contract %s: %w", sender, err)
-	} else if builtinactors.IsEvmActor(actor.Code) {
+	}
+	if builtinactors.IsEvmActor(actor.Code) {
 		evmState, err := builtinevm.Load(a.em.chainModule.ChainReader.Store(ctx), actor)
 		if err != nil {
 			return 0, fmt.Errorf("failed to load evm state: %w", err)
@@ -357,7 +358,11 @@ func (a *ethAPI) EthGetTransactionCount(ctx context.Context, sender types.EthAdd
 		return types.EthUint64(nonce), err
 	}
 
-	nonce, err := a.em.mpoolModule.MPool.GetNonce(ctx, addr, ts.Key())
+	// the pending nonce counts the messages of the message pool, the others are read from the state
+	if blkParam.PredefinedBlock == nil || *blkParam.PredefinedBlock != "pending" {
+		return types.EthUint64(actor.Nonce), nil
+	}
+	nonce, err := a.mpool.MpoolGetNonce(ctx, addr)
 	if err != nil {
 		return types.EthUint64(0), err
 	}
@@ -405,6 +410,74 @@ func (a *ethAPI) EthGetTransactionReceiptLimited(ctx context.Context, txHash typ
 	return &receipt, nil
 }
 
+func (a *ethAPI) EthGetBlockReceipts(ctx context.Context, blkParam types.EthBlockNumberOrHash) ([]*types.EthTxReceipt, error) {
+	return a.EthGetBlockReceiptsLimited(ctx, blkParam, constants.LookbackNoLimit)
+}
+
+func (a *ethAPI) EthGetBlockReceiptsLimited(ctx context.Context, blkParam types.EthBlockNumberOrHash, limit abi.ChainEpoch) ([]*types.EthTxReceipt, error) {
+	ts, err := getTipsetByEthBlockNumberOrHash(ctx, a.em.chainModule.ChainReader, blkParam)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tipset: %w", err)
+	}
+
+	head := a.em.chainModule.ChainReader.GetHead()
+	if limit > constants.LookbackNoLimit && ts.Height() < head.Height()-limit {
+		return nil, fmt.Errorf("tipset %s is older than the allowed lookback limit", ts.Key())
+	}
+	// the receipts of the messages in the head are only known once a child is mined
+	if ts.Height() >= head.Height() {
+		return nil, fmt.Errorf("tipset %s at the head has not been executed yet", ts.Key())
+	}
+
+	msgs, rcpts, err := messagesAndReceipts(ctx, ts, a.em.chainModule.MessageStore, a.em.chainModule.Stmgr)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return []*types.EthTxReceipt{}, nil
+	}
+
+	// the receipts of the messages in ts are included in its child
+	child, err := a.chain.ChainGetTipSetAfterHeight(ctx, ts.Height()+1, head.Key())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get child tipset of %s: %w", ts.Key(), err)
+	}
+	if !child.Parents().Equals(ts.Key()) {
+		return nil, fmt.Errorf("tipset %s is not on the current chain", ts.Key())
+	}
+
+	receipts := make([]*types.EthTxReceipt, 0, len(msgs))
+	for i, msg := range msgs {
+		lookup := &types.MsgLookup{
+			Message: msg.Cid(),
+			Receipt: rcpts[i],
+			TipSet:  child.Key(),
+			Height:  child.Height(),
+		}
+
+		tx, err := newEthTxFromMessageLookup(ctx, lookup, i, a.em.chainModule.MessageStore, a.chain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert msg %s to eth tx: %w", msg.Cid(), err)
+		}
+
+		var events []types.Event
+		if rcpts[i].EventsRoot != nil {
+			events, err = a.chain.ChainGetEvents(ctx, *rcpts[i].EventsRoot)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load events of msg %s: %w", msg.Cid(), err)
+			}
+		}
+
+		receipt, err := newEthTxReceipt(ctx, tx, lookup, events, a.chain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build receipt of msg %s: %w", msg.Cid(), err)
+		}
+		receipts = append(receipts, &receipt)
+	}
+
+	return receipts, nil
+}
+
 func (a *ethAPI) EthGetTransactionByBlockHashAndIndex(ctx context.Context, blkHash types.EthHash, txIndex types.EthUint64) (types.EthTx, error) {
 	return types.EthTx{}, ErrUnsupported
 }
@@ -686,63 +759,35 @@ func (a *ethAPI) EthFeeHistory(ctx context.Context, p jsonrpc.RawParams) (types.
 		return types.EthFeeHistory{}, fmt.Errorf("bad block parameter %s: %s", params.NewestBlkNum, err)
 	}
 
+	history, err := a.mpool.GasEstimateFeeHistory(ctx, uint64(params.BlkCount), rewardPercentiles, ts.Key())
+	if err != nil {
+		return types.EthFeeHistory{}, fmt.Errorf("failed to get the fee history: %w", err)
+	}
+
 	var (
-		basefee         = ts.Blocks()[0].ParentBaseFee
-		oldestBlkHeight = uint64(1)
-
-		// NOTE: baseFeePerGas should include the next block after the newest of the returned range,
-		//  because the next base fee can be inferred from the messages in the newest block.
-		//  However, this is NOT the case in Filecoin due to deferred execution, so the best
-		//  we can do is duplicate the last value.
-		baseFeeArray      = []types.EthBigInt{types.EthBigInt(basefee)}
-		rewardsArray      = make([][]types.EthBigInt, 0)
-		gasUsedRatioArray = []float64{}
-		blocksIncluded    int
+		oldestBlkHeight   = uint64(1)
+		baseFeeArray      = make([]types.EthBigInt, 0, len(history.Epochs)+1)
+		rewardsArray      = make([][]types.EthBigInt, 0, len(history.Epochs))
+		gasUsedRatioArray = make([]float64, 0, len(history.Epochs))
 	)
+	if len(history.Epochs) > 0 {
+		oldestBlkHeight = uint64(history.Epochs[0].Epoch)
+	}
+	for _, epoch := range history.Epochs {
+		baseFeeArray = append(baseFeeArray, types.EthBigInt(epoch.BaseFee))
+		gasUsedRatioArray = append(gasUsedRatioArray, epoch.GasUsedRatio)
 
-	for blocksIncluded < int(params.BlkCount) && ts.Height() > 0 {
-		msgs, rcpts, err := messagesAndReceipts(ctx, ts, a.em.chainModule.MessageStore, a.em.chainModule.Stmgr)
-		if err != nil {
-			return types.EthFeeHistory{}, fmt.Errorf("failed to retrieve messages and receipts for height %d: %w", ts.Height(), err)
-		}
-
-		txGasRewards := gasRewardSorter{}
-		for i, msg := range msgs {
-			effectivePremium := msg.VMMessage().EffectiveGasPremium(basefee)
-			txGasRewards = append(txGasRewards, gasRewardTuple{
-				premium: effectivePremium,
-				gasUsed: rcpts[i].GasUsed,
-			})
+		rewards := make([]types.EthBigInt, 0, len(epoch.Premiums))
+		for _, premium := range epoch.Premiums {
+			rewards = append(rewards, types.EthBigInt(premium))
 		}
-
-		rewards, totalGasUsed := calculateRewardsAndGasUsed(rewardPercentiles, txGasRewards)
-		maxGas := constants.BlockGasLimit * int64(len(ts.Blocks()))
-
-		// arrays should be reversed at the end
-		baseFeeArray = append(baseFeeArray, types.EthBigInt(basefee))
-		gasUsedRatioArray = append(gasUsedRatioArray, float64(totalGasUsed)/float64(maxGas))
-
 		rewardsArray = append(rewardsArray, rewards)
-		oldestBlkHeight = uint64(ts.Height())
-		blocksIncluded++
-
-		parentTSKey := ts.Parents()
-		ts, err = a.chain.ChainGetTipSet(ctx, parentTSKey)
-		if err != nil {
-			return types.EthFeeHistory{}, fmt.Errorf("cannot load tipset key: %v", parentTSKey)
-		}
-	}
-
-	// Reverse the arrays; we collected them newest to oldest; the client expects oldest to newest.
-	for i, j := 0, len(baseFeeArray)-1; i < j; i, j = i+1, j-1 {
-		baseFeeArray[i], baseFeeArray[j] = baseFeeArray[j], baseFeeArray[i]
-	}
-	for i, j := 0, len(gasUsedRatioArray)-1; i < j; i, j = i+1, j-1 {
-		gasUsedRatioArray[i], gasUsedRatioArray[j] = gasUsedRatioArray[j], gasUsedRatioArray[i]
-	}
-	for i, j := 0, len(rewardsArray)-1; i < j; i, j = i+1, j-1 {
-		rewardsArray[i], rewardsArray[j] = rewardsArray[j], rewardsArray[i]
 	}
+	// NOTE: baseFeePerGas should include the next block after the newest of the returned range,
+	//  because the next base fee can be inferred from the messages in the newest block.
+	//  However, this is NOT the case in Filecoin due to deferred execution, so the best
+	//  we can do is duplicate the last value.
+	baseFeeArray = append(baseFeeArray, types.EthBigInt(ts.Blocks()[0].ParentBaseFee))
 
 	ret := types.EthFeeHistory{
 		OldestBlock:   types.EthUint64(oldestBlkHeight),
@@ -818,6 +863,20 @@ func (a *ethAPI) EthSendRawTransaction(ctx context.Context, rawTx types.EthBytes
 }
 
 func (a *ethAPI) applyMessage(ctx context.Context, msg *types.Message, tsk types.TipSetKey) (*types.InvocResult, error) {
+	res, err := a.callMessage(ctx, msg, tsk)
+	if err != nil {
+		return nil, err
+	}
+	if res.MsgRct.ExitCode.IsError() {
+		reason := parseEthRevert(res.MsgRct.Return)
+		return nil, fmt.Errorf("message execution failed: exit %s, revert reason: %s, vm error: %s", res.MsgRct.ExitCode, reason, res.Error)
+	}
+
+	return res, nil
+}
+
+// callMessage executes msg on top of the tipset tsk, the execution failures are left to the caller.
+func (a *ethAPI) callMessage(ctx context.Context, msg *types.Message, tsk types.TipSetKey) (*types.InvocResult, error) {
 	ts, err := a.chain.ChainGetTipSet(ctx, tsk)
 	if err != nil {
 		return nil, fmt.Errorf("failed to got tipset %v", err)
@@ -846,15 +905,16 @@ func (a *ethAPI) applyMessage(ctx context.Context, msg *types.Message, tsk types
 	if res.MsgRct == nil {
 		return nil, fmt.Errorf("no message receipt")
 	}
-	if res.MsgRct.ExitCode.IsError() {
-		reason := parseEthRevert(res.MsgRct.Return)
-		return nil, fmt.Errorf("message execution failed: exit %s, revert reason: %s, vm error: %s", res.MsgRct.ExitCode, reason, res.Error)
-	}
 
 	return res, nil
 }
 
 func (a *ethAPI) EthEstimateGas(ctx context.Context, p jsonrpc.RawParams) (types.EthUint64, error) {
+	if leader := a.em.mpoolModule.Leader(); leader != nil {
+		// a follower has no message pool, the leader estimates the gas with its pending messages
+		return leader.EthEstimateGas(ctx, p)
+	}
+
 	params, err := jsonrpc.DecodeParams[types.EthEstimateGasParams](p)
 	if err != nil {
 		return types.EthUint64(0), fmt.Errorf("decoding params: %w", err)
@@ -1063,11 +1123,6 @@ func (a *ethAPI) EthTraceBlock(ctx context.Context, blkNum string) ([]*types.Eth
 		return nil, fmt.Errorf("failed to get tipset: %w", err)
 	}
 
-	_, trace, err := a.em.chainModule.Stmgr.ExecutionTrace(ctx, ts)
-	if err != nil {
-		return nil, fmt.Errorf("failed when calling ExecutionTrace: %w", err)
-	}
-
 	head, err := a.chain.ChainHead(ctx)
 	if err != nil {
 		return nil, err
@@ -1081,6 +1136,21 @@ func (a *ethAPI) EthTraceBlock(ctx context.Context, blkNum string) ([]*types.Eth
 	if err != nil {
 		return nil, fmt.Errorf("failed to get parent messages: %w", err)
 	}
+	msgCids := make([]cid.Cid, 0, len(msgs))
+	for _, msg := range msgs {
+		msgCids = append(msgCids, msg.Message.Cid())
+	}
+
+	return a.traceTipSet(ctx, ts, msgCids)
+}
+
+// traceTipSet returns the traces of all the messages included in ts, msgs are the cids of its
+// messages in execution order.
+func (a *ethAPI) traceTipSet(ctx context.Context, ts *types.TipSet, msgs []cid.Cid) ([]*types.EthTraceBlock, error) {
+	_, trace, err := a.em.chainModule.Stmgr.ExecutionTrace(ctx, ts)
+	if err != nil {
+		return nil, fmt.Errorf("failed when calling ExecutionTrace: %w", err)
+	}
 
 	cid, err := ts.Key().Cid()
 	if err != nil {
@@ -1102,7 +1172,7 @@ func (a *ethAPI) EthTraceBlock(ctx context.Context, blkNum string) ([]*types.Eth
 
 		// as we include TransactionPosition in the results, lets do sanity checking that the
 		// traces are indeed in the message execution order
-		if ir.Msg.Cid() != msgs[msgIdx].Message.Cid() {
+		if msgIdx >= len(msgs) || ir.Msg.Cid() != msgs[msgIdx] {
 			return nil, fmt.Errorf("traces are not in message execution order")
 		}
 		msgIdx++
@@ -1201,35 +1271,170 @@ func (a *ethAPI) EthTraceReplayBlockTransactions(ctx context.Context, blkNum str
 	return allTraces, nil
 }
 
-func calculateRewardsAndGasUsed(rewardPercentiles []float64, txGasRewards gasRewardSorter) ([]types.EthBigInt, int64) {
-	var gasUsedTotal int64
-	for _, tx := range txGasRewards {
-		gasUsedTotal += tx.gasUsed
+func (a *ethAPI) EthTraceTransaction(ctx context.Context, txHash string) ([]*types.EthTraceTransaction, error) {
+	ethTxHash, err := types.ParseEthHash(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse eth hash: %w", err)
+	}
+
+	tx, err := a.EthGetTransactionByHash(ctx, &ethTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get transaction by hash: %w", err)
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("transaction not found")
+	}
+	// tx.BlockNumber is nil when the transaction is still in the mpool
+	if tx.BlockNumber == nil {
+		return nil, fmt.Errorf("no trace for pending transactions")
+	}
+
+	blockTraces, err := a.EthTraceBlock(ctx, tx.BlockNumber.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get trace for block: %w", err)
 	}
 
-	rewards := make([]types.EthBigInt, len(rewardPercentiles))
-	for i := range rewards {
-		rewards[i] = types.EthBigInt(types.NewInt(messagepool.MinGasPremium))
+	txTraces := make([]*types.EthTraceTransaction, 0, len(blockTraces))
+	for _, blockTrace := range blockTraces {
+		if blockTrace.TransactionHash == ethTxHash {
+			txTraces = append(txTraces, &types.EthTraceTransaction{
+				EthTrace:            blockTrace.EthTrace,
+				BlockHash:           blockTrace.BlockHash,
+				BlockNumber:         blockTrace.BlockNumber,
+				TransactionHash:     blockTrace.TransactionHash,
+				TransactionPosition: blockTrace.TransactionPosition,
+			})
+		}
 	}
 
-	if len(txGasRewards) == 0 {
-		return rewards, gasUsedTotal
+	return txTraces, nil
+}
+
+func (a *ethAPI) EthTraceFilter(ctx context.Context, filter types.EthTraceFilterCriteria) ([]*types.EthTraceFilterResult, error) {
+	maxResults := a.em.cfg.FevmConfig.EthTraceFilterMaxResults
+	if filter.Count != nil {
+		// a zero count asks for nothing
+		if *filter.Count == 0 {
+			return []*types.EthTraceFilterResult{}, nil
+		}
+		if uint64(*filter.Count) > maxResults {
+			return nil, fmt.Errorf("invalid response count, requested %d, maximum supported is %d", *filter.Count, maxResults)
+		}
 	}
 
-	sort.Stable(txGasRewards)
+	fromTS, err := a.traceFilterTipSet(ctx, filter.FromBlock)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse fromBlock: %w", err)
+	}
+	toTS, err := a.traceFilterTipSet(ctx, filter.ToBlock)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse toBlock: %w", err)
+	}
+	if fromTS.Height() > toTS.Height() {
+		return nil, fmt.Errorf("fromBlock %d is after toBlock %d", fromTS.Height(), toTS.Height())
+	}
 
-	var idx int
-	var sum int64
-	for i, percentile := range rewardPercentiles {
-		threshold := int64(float64(gasUsedTotal) * percentile / 100)
-		for sum < threshold && idx < len(txGasRewards)-1 {
-			sum += txGasRewards[idx].gasUsed
-			idx++
+	// collect the non null tipsets of the range, oldest first
+	var tipsets []*types.TipSet
+	for ts := toTS; ts.Height() >= fromTS.Height(); {
+		tipsets = append(tipsets, ts)
+		if ts.Height() == 0 {
+			break
+		}
+		ts, err = a.chain.ChainGetTipSet(ctx, ts.Parents())
+		if err != nil {
+			return nil, fmt.Errorf("cannot get parent tipset: %w", err)
 		}
-		rewards[i] = types.EthBigInt(txGasRewards[idx].premium)
 	}
 
-	return rewards, gasUsedTotal
+	results := []*types.EthTraceFilterResult{}
+	traceCounter := types.EthUint64(0)
+	for i := len(tipsets) - 1; i >= 0; i-- {
+		// the head has no child yet, so the messages are read from the tipset itself
+		msgs, err := a.em.chainModule.MessageStore.MessagesForTipset(tipsets[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get messages of tipset: %w", err)
+		}
+		msgCids := make([]cid.Cid, 0, len(msgs))
+		for _, msg := range msgs {
+			msgCids = append(msgCids, msg.VMMessage().Cid())
+		}
+
+		blockTraces, err := a.traceTipSet(ctx, tipsets[i], msgCids)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get trace for block %d: %w", tipsets[i].Height(), err)
+		}
+
+		for _, blockTrace := range blockTraces {
+			if !matchTraceFilter(blockTrace, filter.FromAddress, filter.ToAddress) {
+				continue
+			}
+
+			traceCounter++
+			if filter.After != nil && traceCounter <= *filter.After {
+				continue
+			}
+
+			results = append(results, &types.EthTraceFilterResult{
+				EthTrace:            blockTrace.EthTrace,
+				BlockHash:           blockTrace.BlockHash,
+				BlockNumber:         blockTrace.BlockNumber,
+				TransactionHash:     blockTrace.TransactionHash,
+				TransactionPosition: blockTrace.TransactionPosition,
+			})
+
+			if filter.Count != nil && types.EthUint64(len(results)) >= *filter.Count {
+				return results, nil
+			} else if filter.Count == nil && uint64(len(results)) > maxResults {
+				return nil, fmt.Errorf("too many results, maximum supported is %d, try paginating requests with After and Count", maxResults)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// traceFilterTipSet resolves a trace_filter block param, defaulting to "latest".
+func (a *ethAPI) traceFilterTipSet(ctx context.Context, blkParam *string) (*types.TipSet, error) {
+	if blkParam == nil {
+		return getTipsetByBlockNumber(ctx, a.em.chainModule.ChainReader, "latest", false)
+	}
+	return getTipsetByBlockNumber(ctx, a.em.chainModule.ChainReader, *blkParam, false)
+}
+
+// matchTraceFilter checks whether a trace originates from one of fromAddrs and goes to one of
+// toAddrs, an empty list matching any address.
+func matchTraceFilter(trace *types.EthTraceBlock, fromAddrs, toAddrs types.EthAddressList) bool {
+	var from, to *types.EthAddress
+	switch action := trace.Action.(type) {
+	case *types.EthCallTraceAction:
+		from, to = &action.From, &action.To
+	case *types.EthCreateTraceAction:
+		from = &action.From
+		// the created address is only known when the creation succeeded
+		if result, ok := trace.Result.(*types.EthCreateTraceResult); ok {
+			to = result.Address
+		}
+	default:
+		return false
+	}
+
+	return matchTraceAddress(from, fromAddrs) && matchTraceAddress(to, toAddrs)
+}
+
+func matchTraceAddress(addr *types.EthAddress, addrs types.EthAddressList) bool {
+	if len(addrs) == 0 {
+		return true
+	}
+	if addr == nil {
+		return false
+	}
+	for _, a := range addrs {
+		if a == *addr {
+			return true
+		}
+	}
+	return false
 }
 
 func getSignedMessage(ctx context.Context, ms *chain.MessageStore, msgCid cid.Cid) (*types.SignedMessage, error) {
@@ -1251,21 +1456,5 @@ func getSignedMessage(ctx context.Context, ms *chain.MessageStore, msgCid cid.Ci
 	return smsg, nil
 }
 
-type gasRewardTuple struct {
-	gasUsed int64
-	premium abi.TokenAmount
-}
-
-// sorted in ascending order
-type gasRewardSorter []gasRewardTuple
-
-func (g gasRewardSorter) Len() int { return len(g) }
-func (g gasRewardSorter) Swap(i, j int) {
-	g[i], g[j] = g[j], g[i]
-}
-func (g gasRewardSorter) Less(i, j int) bool {
-	return g[i].premium.Int.Cmp(g[j].premium.Int) == -1
-}
-
 var _ v1.IETH = &ethAPI{}
 var _ ethAPIAdapter = &ethAPI{}
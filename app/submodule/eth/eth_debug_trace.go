@@ -0,0 +1,527 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+
+	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/pkg/state/tree"
+	"github.com/filecoin-project/venus/venus-shared/actors/adt"
+	builtinactors "github.com/filecoin-project/venus/venus-shared/actors/builtin"
+	builtinevm "github.com/filecoin-project/venus/venus-shared/actors/builtin/evm"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+const (
+	ethCallTracer     = "callTracer"
+	ethPrestateTracer = "prestateTracer"
+)
+
+func (a *ethAPI) EthDebugTraceTransaction(ctx context.Context, txHash string, config types.EthTraceConfig) (interface{}, error) {
+	if err := checkTraceConfig(config); err != nil {
+		return nil, err
+	}
+
+	ethTxHash, err := types.ParseEthHash(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse eth hash: %w", err)
+	}
+	msgCid, err := a.EthGetMessageCidByTransactionHash(ctx, &ethTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get message cid: %w", err)
+	}
+	if msgCid == nil {
+		return nil, fmt.Errorf("transaction not found")
+	}
+
+	lookup, err := a.chain.StateSearchMsg(ctx, types.EmptyTSK, *msgCid, constants.LookbackNoLimit, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search message %s: %w", msgCid, err)
+	}
+	if lookup == nil {
+		return nil, fmt.Errorf("no trace for pending transactions")
+	}
+
+	// the message is included in the parent of the tipset holding its receipt
+	execTS, err := a.chain.ChainGetTipSet(ctx, lookup.TipSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tipset %s: %w", lookup.TipSet, err)
+	}
+	ts, err := a.chain.ChainGetTipSet(ctx, execTS.Parents())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tipset %s: %w", execTS.Parents(), err)
+	}
+
+	replay, err := a.em.chainModule.Stmgr.ReplayStates(ctx, ts, lookup.Message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay message %s: %w", lookup.Message, err)
+	}
+
+	traces, err := a.buildEthTraces(ctx, replay.Msg.From, &replay.Ret.GasTracker.ExecutionTrace)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Tracer == ethCallTracer {
+		return buildCallFrame(traces, config.TracerConfig.OnlyTopCall)
+	}
+
+	store := adt.WrapStore(ctx, cbor.NewCborStore(replay.Store))
+	before, err := tree.LoadState(ctx, store, replay.PreStateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state before message %s: %w", lookup.Message, err)
+	}
+	after, err := tree.LoadState(ctx, store, replay.PostStateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state after message %s: %w", lookup.Message, err)
+	}
+	if config.TracerConfig.DiffMode {
+		return prestateDiff(ctx, traces, store, before, after)
+	}
+	return prestate(ctx, traces, store, before, after)
+}
+
+func (a *ethAPI) EthDebugTraceCall(ctx context.Context, tx types.EthCall, blkParam types.EthBlockNumberOrHash, config types.EthTraceConfig) (interface{}, error) {
+	if err := checkTraceConfig(config); err != nil {
+		return nil, err
+	}
+	// the state modified by a call is dropped, it can't be compared
+	if config.Tracer == ethPrestateTracer && config.TracerConfig.DiffMode {
+		return nil, fmt.Errorf("diffMode is not supported when tracing calls")
+	}
+
+	msg, err := ethCallToFilecoinMessage(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert ethcall to filecoin message: %w", err)
+	}
+	ts, err := getTipsetByEthBlockNumberOrHash(ctx, a.em.chainModule.ChainReader, blkParam)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process block param: %v, %w", blkParam, err)
+	}
+
+	res, err := a.callMessage(ctx, msg, ts.Key())
+	if err != nil {
+		return nil, err
+	}
+
+	traces, err := a.buildEthTraces(ctx, msg.From, &res.ExecutionTrace)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Tracer == ethCallTracer {
+		return buildCallFrame(traces, config.TracerConfig.OnlyTopCall)
+	}
+
+	_, before, err := a.em.chainModule.Stmgr.ParentState(ctx, ts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load parent state of %s: %w", ts.Key(), err)
+	}
+	return prestate(ctx, traces, a.em.chainModule.ChainReader.Store(ctx), before, nil)
+}
+
+func checkTraceConfig(config types.EthTraceConfig) error {
+	switch config.Tracer {
+	case ethCallTracer, ethPrestateTracer:
+		return nil
+	default:
+		return fmt.Errorf("unsupported tracer %q, must be one of %s, %s", config.Tracer, ethCallTracer, ethPrestateTracer)
+	}
+}
+
+// buildEthTraces flattens the execution trace of a message sent by from into eth traces.
+func (a *ethAPI) buildEthTraces(ctx context.Context, from address.Address, et *types.ExecutionTrace) ([]*types.EthTrace, error) {
+	env, err := baseEnvironment(ctx, from, a.chain)
+	if err != nil {
+		return nil, err
+	}
+	if err := buildTraces(env, []int{}, et); err != nil {
+		return nil, fmt.Errorf("failed building traces: %w", err)
+	}
+	return env.traces, nil
+}
+
+// buildCallFrame nests the traces, ordered depth first as built by buildTraces, into the
+// call frames of the callTracer.
+func buildCallFrame(traces []*types.EthTrace, onlyTopCall bool) (*types.EthCallFrame, error) {
+	var root *types.EthCallFrame
+	// stack[i] is the last frame seen at depth i
+	var stack []*types.EthCallFrame
+	for _, trace := range traces {
+		depth := len(trace.TraceAddress)
+		if depth > 0 && onlyTopCall {
+			continue
+		}
+
+		frame, err := newEthCallFrame(trace)
+		if err != nil {
+			return nil, err
+		}
+
+		if depth == 0 {
+			if root != nil {
+				return nil, fmt.Errorf("more than one top level call")
+			}
+			root = frame
+			stack = append(stack[:0], frame)
+			continue
+		}
+		if depth > len(stack) {
+			return nil, fmt.Errorf("trace %v has no parent call", trace.TraceAddress)
+		}
+		parent := stack[depth-1]
+		parent.Calls = append(parent.Calls, frame)
+		stack = append(stack[:depth], frame)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("no top level call")
+	}
+
+	return root, nil
+}
+
+func newEthCallFrame(trace *types.EthTrace) (*types.EthCallFrame, error) {
+	var frame types.EthCallFrame
+	switch action := trace.Action.(type) {
+	case *types.EthCallTraceAction:
+		to := action.To
+		frame = types.EthCallFrame{
+			Type:  strings.ToUpper(action.CallType),
+			From:  action.From,
+			To:    &to,
+			Value: action.Value,
+			Gas:   action.Gas,
+			Input: action.Input,
+		}
+		if result, ok := trace.Result.(*types.EthCallTraceResult); ok {
+			frame.GasUsed = result.GasUsed
+			frame.Output = result.Output
+		}
+	case *types.EthCreateTraceAction:
+		frame = types.EthCallFrame{
+			Type:  "CREATE",
+			From:  action.From,
+			Value: action.Value,
+			Gas:   action.Gas,
+			Input: action.Init,
+		}
+		if result, ok := trace.Result.(*types.EthCreateTraceResult); ok {
+			frame.To = result.Address
+			frame.GasUsed = result.GasUsed
+			frame.Output = result.Code
+		}
+	default:
+		return nil, fmt.Errorf("unexpected trace action %T", trace.Action)
+	}
+
+	switch trace.Error {
+	case "":
+	case "Reverted":
+		frame.Error = "execution reverted"
+		frame.RevertReason = revertReason(frame.Output)
+	default:
+		frame.Error = trace.Error
+	}
+
+	return &frame, nil
+}
+
+// revertReason returns the message of a revert with a `Error(string)` payload.
+func revertReason(output types.EthBytes) string {
+	buf := new(bytes.Buffer)
+	payload := abi.CborBytes(output)
+	if err := payload.MarshalCBOR(buf); err != nil {
+		return ""
+	}
+	reason := parseEthRevert(buf.Bytes())
+	if strings.HasPrefix(reason, "Error(") && strings.HasSuffix(reason, ")") {
+		return reason[len("Error(") : len(reason)-1]
+	}
+	return ""
+}
+
+// prestate returns the accounts touched by traces in the state before. When the state after
+// the traced message is known, it includes the storage slots modified by the message.
+func prestate(ctx context.Context, traces []*types.EthTrace, store adt.Store, before, after *tree.State) (map[string]*types.EthPrestateAccount, error) {
+	accounts := make(map[string]*types.EthPrestateAccount)
+	for _, addr := range touchedAddresses(traces) {
+		acc, _, err := prestateAccounts(ctx, addr, store, before, after)
+		if err != nil {
+			return nil, err
+		}
+		if acc != nil {
+			accounts[addr.String()] = acc
+		}
+	}
+	return accounts, nil
+}
+
+// prestateDiff returns the accounts touched by traces that were modified between the
+// states before and after the traced message.
+func prestateDiff(ctx context.Context, traces []*types.EthTrace, store adt.Store, before, after *tree.State) (*types.EthPrestateDiff, error) {
+	diff := &types.EthPrestateDiff{
+		Pre:  make(map[string]*types.EthPrestateAccount),
+		Post: make(map[string]*types.EthPrestateAccount),
+	}
+	for _, addr := range touchedAddresses(traces) {
+		accBefore, accAfter, err := prestateAccounts(ctx, addr, store, before, after)
+		if err != nil {
+			return nil, err
+		}
+
+		pre, post := diffPrestateAccounts(accBefore, accAfter)
+		if pre != nil {
+			diff.Pre[addr.String()] = pre
+		}
+		if post != nil {
+			diff.Post[addr.String()] = post
+		}
+	}
+	return diff, nil
+}
+
+// diffPrestateAccounts returns nothing when the account is unchanged, otherwise the
+// account before its modification and its modified fields.
+func diffPrestateAccounts(before, after *types.EthPrestateAccount) (*types.EthPrestateAccount, *types.EthPrestateAccount) {
+	if before == nil || after == nil {
+		return before, after
+	}
+
+	changed := &types.EthPrestateAccount{}
+	modified := false
+	if big.Cmp(big.Int(*before.Balance), big.Int(*after.Balance)) != 0 {
+		changed.Balance = after.Balance
+		modified = true
+	}
+	if before.Nonce != after.Nonce {
+		changed.Nonce = after.Nonce
+		modified = true
+	}
+	if !bytes.Equal(before.Code, after.Code) {
+		changed.Code = after.Code
+		modified = true
+	}
+	// the storage only holds the modified slots
+	if len(after.Storage) > 0 {
+		changed.Storage = after.Storage
+		modified = true
+	}
+	if !modified {
+		return nil, nil
+	}
+	return before, changed
+}
+
+// prestateAccounts returns the account in the states before and after, nil if it doesn't
+// exist. When after is set, the storage of the accounts holds the slots whose value differs
+// between the two states.
+func prestateAccounts(ctx context.Context, addr types.EthAddress, store adt.Store, before, after *tree.State) (*types.EthPrestateAccount, *types.EthPrestateAccount, error) {
+	faddr, err := addr.ToFilecoinAddress()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot get Filecoin address of %s: %w", addr, err)
+	}
+
+	accBefore, storageBefore, err := prestateAccount(ctx, faddr, store, before)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load account %s: %w", addr, err)
+	}
+	if after == nil {
+		return accBefore, nil, nil
+	}
+	accAfter, storageAfter, err := prestateAccount(ctx, faddr, store, after)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load account %s: %w", addr, err)
+	}
+
+	slotsBefore, slotsAfter, err := evmStorageChanges(ctx, store, storageBefore, storageAfter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to diff the storage of %s: %w", addr, err)
+	}
+	if accBefore != nil && len(slotsBefore) > 0 {
+		accBefore.Storage = slotsBefore
+	}
+	if accAfter != nil && len(slotsAfter) > 0 {
+		accAfter.Storage = slotsAfter
+	}
+	return accBefore, accAfter, nil
+}
+
+// prestateAccount returns the account in the state st, nil if it doesn't exist, and the root
+// of its contract storage, undefined if it isn't an EVM actor.
+func prestateAccount(ctx context.Context, addr address.Address, store adt.Store, st *tree.State) (*types.EthPrestateAccount, cid.Cid, error) {
+	actor, found, err := st.GetActor(ctx, addr)
+	if err != nil {
+		return nil, cid.Undef, err
+	}
+	if !found {
+		return nil, cid.Undef, nil
+	}
+
+	balance := types.EthBigInt(actor.Balance)
+	acc := &types.EthPrestateAccount{
+		Balance: &balance,
+		Nonce:   actor.Nonce,
+	}
+	if !builtinactors.IsEvmActor(actor.Code) {
+		return acc, cid.Undef, nil
+	}
+
+	evmState, err := builtinevm.Load(store, actor)
+	if err != nil {
+		return nil, cid.Undef, fmt.Errorf("failed to load evm state: %w", err)
+	}
+	if acc.Nonce, err = evmState.Nonce(); err != nil {
+		return nil, cid.Undef, err
+	}
+	if acc.Code, err = evmState.GetBytecode(); err != nil {
+		return nil, cid.Undef, fmt.Errorf("failed to load bytecode: %w", err)
+	}
+	storage, err := evmState.GetContractStateCID()
+	if err != nil {
+		return nil, cid.Undef, err
+	}
+
+	return acc, storage, nil
+}
+
+// evmStorageChanges returns the values before and after of the slots that differ between the
+// contract storages rooted at before and after, an undefined root being an empty storage.
+// The storage is a KAMT of 32 bytes integers, its nodes are walked generically: the subtrees
+// shared by both storages are skipped and the key value pairs, encoded as two byte strings,
+// are collected from the other nodes.
+func evmStorageChanges(ctx context.Context, store cbor.IpldStore, before, after cid.Cid) (map[string]types.EthHash, map[string]types.EthHash, error) {
+	if before.Equals(after) {
+		return nil, nil, nil
+	}
+
+	var nodesBefore, nodesAfter []cid.Cid
+	if before.Defined() {
+		nodesBefore = append(nodesBefore, before)
+	}
+	if after.Defined() {
+		nodesAfter = append(nodesAfter, after)
+	}
+	slotsBefore := make(map[types.EthHash]types.EthHash)
+	slotsAfter := make(map[types.EthHash]types.EthHash)
+	for len(nodesBefore) > 0 || len(nodesAfter) > 0 {
+		seen := make(map[cid.Cid]struct{}, len(nodesBefore))
+		for _, c := range nodesBefore {
+			seen[c] = struct{}{}
+		}
+		shared := make(map[cid.Cid]struct{})
+		for _, c := range nodesAfter {
+			if _, ok := seen[c]; ok {
+				shared[c] = struct{}{}
+			}
+		}
+
+		var err error
+		if nodesBefore, err = readKamtNodes(ctx, store, nodesBefore, shared, slotsBefore); err != nil {
+			return nil, nil, err
+		}
+		if nodesAfter, err = readKamtNodes(ctx, store, nodesAfter, shared, slotsAfter); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	pre := make(map[string]types.EthHash)
+	post := make(map[string]types.EthHash)
+	for slot, value := range slotsBefore {
+		if valueAfter := slotsAfter[slot]; valueAfter != value {
+			pre[slot.String()] = value
+			post[slot.String()] = valueAfter
+		}
+	}
+	for slot, value := range slotsAfter {
+		if _, ok := slotsBefore[slot]; !ok && value != (types.EthHash{}) {
+			pre[slot.String()] = types.EthHash{}
+			post[slot.String()] = value
+		}
+	}
+	return pre, post, nil
+}
+
+// readKamtNodes collects into slots the key value pairs held by nodes, except the skipped
+// ones, and returns the links to their children.
+func readKamtNodes(ctx context.Context, store cbor.IpldStore, nodes []cid.Cid, skip map[cid.Cid]struct{}, slots map[types.EthHash]types.EthHash) ([]cid.Cid, error) {
+	var children []cid.Cid
+	for _, c := range nodes {
+		if _, ok := skip[c]; ok {
+			continue
+		}
+		var node interface{}
+		if err := store.Get(ctx, c, &node); err != nil {
+			return nil, fmt.Errorf("failed to load storage node %s: %w", c, err)
+		}
+		children = walkKamtNode(node, slots, children)
+	}
+	return children, nil
+}
+
+func walkKamtNode(node interface{}, slots map[types.EthHash]types.EthHash, links []cid.Cid) []cid.Cid {
+	switch node := node.(type) {
+	case cid.Cid:
+		links = append(links, node)
+	case []interface{}:
+		if len(node) == 2 {
+			key, isKey := node[0].([]byte)
+			value, isValue := node[1].([]byte)
+			if isKey && isValue {
+				slots[uint256Hash(key)] = uint256Hash(value)
+				return links
+			}
+		}
+		for _, elem := range node {
+			links = walkKamtNode(elem, slots, links)
+		}
+	case map[string]interface{}:
+		for _, elem := range node {
+			links = walkKamtNode(elem, slots, links)
+		}
+	}
+	return links
+}
+
+// uint256Hash left pads a big endian integer, encoded without its leading zeros, to 32 bytes.
+func uint256Hash(b []byte) types.EthHash {
+	var h types.EthHash
+	if len(b) > len(h) {
+		b = b[len(b)-len(h):]
+	}
+	copy(h[len(h)-len(b):], b)
+	return h
+}
+
+// touchedAddresses returns the senders and the recipients of traces, in order of appearance.
+func touchedAddresses(traces []*types.EthTrace) []types.EthAddress {
+	var addrs []types.EthAddress
+	seen := make(map[types.EthAddress]struct{})
+	add := func(addr types.EthAddress) {
+		if _, ok := seen[addr]; !ok {
+			seen[addr] = struct{}{}
+			addrs = append(addrs, addr)
+		}
+	}
+
+	for _, trace := range traces {
+		switch action := trace.Action.(type) {
+		case *types.EthCallTraceAction:
+			add(action.From)
+			add(action.To)
+		case *types.EthCreateTraceAction:
+			add(action.From)
+			if result, ok := trace.Result.(*types.EthCreateTraceResult); ok && result.Address != nil {
+				add(*result.Address)
+			}
+		}
+	}
+	return addrs
+}
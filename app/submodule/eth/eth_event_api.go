@@ -52,17 +52,22 @@ func newEthEventAPI(ctx context.Context, em *EthSubModule) (*ethEventAPI, error)
 	ee.FilterStore = filter.NewMemFilterStore(cfg.Event.MaxFilters)
 
 	// Enable indexing of actor events
-	var eventIndex *filter.EventIndex
+	var eventIndex filter.EventIndex
 	if !cfg.Event.DisableHistoricFilterAPI {
-		var dbPath string
-		if len(cfg.Event.DatabasePath) == 0 {
-			dbPath = filepath.Join(ee.em.sqlitePath, "events.db")
-		} else {
-			dbPath = cfg.Event.DatabasePath
-		}
-
 		var err error
-		eventIndex, err = filter.NewEventIndex(ctx, dbPath, em.chainModule.ChainReader)
+		switch cfg.Event.DatabaseType {
+		case "", filter.EventIndexSqlite:
+			var dbPath string
+			if len(cfg.Event.DatabasePath) == 0 {
+				dbPath = filepath.Join(ee.em.sqlitePath, "events.db")
+			} else {
+				dbPath = cfg.Event.DatabasePath
+			}
+
+			eventIndex, err = filter.NewEventIndex(ctx, dbPath, em.chainModule.ChainReader)
+		default:
+			eventIndex, err = filter.NewSQLEventIndex(ctx, cfg.Event.DatabaseType, cfg.Event.Database)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -129,7 +134,7 @@ func (e *ethEventAPI) Start(ctx context.Context) error {
 	_ = ev.Observe(e.EventFilterManager)
 	_ = ev.Observe(e.TipSetFilterManager)
 
-	ch, err := e.em.mpoolModule.MPool.Updates(ctx)
+	ch, err := e.em.mpoolModule.API().MpoolSub(ctx)
 	if err != nil {
 		return err
 	}
@@ -146,6 +151,123 @@ func (e *ethEventAPI) Close(ctx context.Context) error {
 	return nil
 }
 
+func (e *ethEventAPI) EthIndexBackfill(ctx context.Context, from, to abi.ChainEpoch) (*types.EthIndexBackfillResult, error) {
+	head, err := e.indexRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// both indexes are fed by a single walk of the chain
+	res := &types.EthIndexBackfillResult{From: from, To: to}
+	var visit func(*types.TipSet) error
+	if txHashes := e.em.txHashManager(); txHashes != nil {
+		visit = func(ts *types.TipSet) error {
+			added, err := txHashes.checkTipSet(ctx, ts, true)
+			if err != nil {
+				return fmt.Errorf("index eth transactions of %s: %w", ts.Key(), err)
+			}
+			res.TxHashes += len(added)
+			return nil
+		}
+	}
+
+	if e.hasEventIndex() {
+		res.TipSets, res.Events, err = e.EventFilterManager.Backfill(ctx, e.em.chainModule.ChainReader, head, from, to, visit)
+	} else {
+		res.TipSets, err = e.walkTipSets(ctx, head, from, to, visit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (e *ethEventAPI) EthIndexValidate(ctx context.Context, from, to abi.ChainEpoch, fix bool) (*types.EthIndexValidateResult, error) {
+	head, err := e.indexRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &types.EthIndexValidateResult{From: from, To: to, Fixed: fix}
+	var visit func(*types.TipSet) error
+	if txHashes := e.em.txHashManager(); txHashes != nil {
+		visit = func(ts *types.TipSet) error {
+			missing, err := txHashes.checkTipSet(ctx, ts, fix)
+			if err != nil {
+				return fmt.Errorf("check eth transactions of %s: %w", ts.Key(), err)
+			}
+			res.MissingTxHashes = append(res.MissingTxHashes, missing...)
+			return nil
+		}
+	}
+
+	if e.hasEventIndex() {
+		validation, err := e.EventFilterManager.Validate(ctx, e.em.chainModule.ChainReader, head, from, to, fix, visit)
+		if err != nil {
+			return nil, err
+		}
+		res.TipSets = validation.TipSets
+		res.MissingEvents = validation.Missing
+		res.UnrevertedEvents = validation.Unreverted
+	} else if res.TipSets, err = e.walkTipSets(ctx, head, from, to, visit); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (e *ethEventAPI) hasEventIndex() bool {
+	return e.EventFilterManager != nil && e.EventFilterManager.EventIndex != nil
+}
+
+// indexRange checks the range of a backfill or a validation, and returns the current head.
+func (e *ethEventAPI) indexRange(from, to abi.ChainEpoch) (*types.TipSet, error) {
+	if !e.hasEventIndex() && e.em.txHashManager() == nil {
+		return nil, api.ErrNotSupported
+	}
+
+	head := e.em.chainModule.ChainReader.GetHead()
+	// the messages of the head are not executed yet
+	if to >= head.Height() {
+		return nil, fmt.Errorf("to %d must be below the head height %d", to, head.Height())
+	}
+	if from < 0 || from > to {
+		return nil, fmt.Errorf("invalid range from %d to %d", from, to)
+	}
+
+	return head, nil
+}
+
+// walkTipSets calls cb with the tipsets of the chain ending at head whose height is within
+// [from, to], from the highest to the lowest, and returns the number of tipsets visited.
+func (e *ethEventAPI) walkTipSets(ctx context.Context, head *types.TipSet, from, to abi.ChainEpoch, cb func(*types.TipSet) error) (int, error) {
+	var tipsets int
+	ts := head
+	for ts.Height() > from {
+		if err := ctx.Err(); err != nil {
+			return tipsets, err
+		}
+
+		parent, err := e.em.chainModule.ChainReader.GetTipSet(ctx, ts.Parents())
+		if err != nil {
+			return tipsets, fmt.Errorf("load parent of %s: %w", ts.Key(), err)
+		}
+		if parent.Height() < from {
+			break
+		}
+		if parent.Height() <= to {
+			if err := cb(parent); err != nil {
+				return tipsets, err
+			}
+			tipsets++
+		}
+		ts = parent
+	}
+
+	return tipsets, nil
+}
+
 func (e *ethEventAPI) EthGetLogs(ctx context.Context, filterSpec *types.EthFilterSpec) (*types.EthFilterResult, error) {
 	if e.EventFilterManager == nil {
 		return nil, api.ErrNotSupported
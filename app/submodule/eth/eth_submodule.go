@@ -97,6 +97,14 @@ func (em *EthSubModule) GetEventFilterManager() *filter.EventFilterManager {
 	return em.ethEventAPI.EventFilterManager
 }
 
+// txHashManager returns the manager of the eth transaction hash mappings, nil when the eth rpc is disabled.
+func (em *EthSubModule) txHashManager() *ethTxHashManager {
+	if a, ok := em.ethAPIAdapter.(*ethAPI); ok {
+		return a.ethTxHashManager
+	}
+	return nil
+}
+
 type ethAPIAdapter interface {
 	v1api.IETH
 	start(ctx context.Context) error
@@ -2,18 +2,23 @@ package eth
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"fmt"
 	"testing"
 
 	"github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
 	"github.com/multiformats/go-multicodec"
 	"github.com/stretchr/testify/require"
 	cbg "github.com/whyrusleeping/cbor-gen"
 
+	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
-	"github.com/filecoin-project/venus/pkg/messagepool"
+	chainsubmodule "github.com/filecoin-project/venus/app/submodule/chain"
+	"github.com/filecoin-project/venus/pkg/chain"
+	blockstoreutil "github.com/filecoin-project/venus/venus-shared/blockstore"
 	"github.com/filecoin-project/venus/venus-shared/types"
 )
 
@@ -203,45 +208,6 @@ func TestReward(t *testing.T) {
 	}
 }
 
-func TestRewardPercentiles(t *testing.T) {
-	testcases := []struct {
-		percentiles  []float64
-		txGasRewards gasRewardSorter
-		answer       []int64
-	}{
-		{
-			percentiles:  []float64{25, 50, 75},
-			txGasRewards: []gasRewardTuple{},
-			answer:       []int64{messagepool.MinGasPremium, messagepool.MinGasPremium, messagepool.MinGasPremium},
-		},
-		{
-			percentiles: []float64{25, 50, 75, 100},
-			txGasRewards: []gasRewardTuple{
-				{gasUsed: int64(0), premium: big.NewInt(300)},
-				{gasUsed: int64(100), premium: big.NewInt(200)},
-				{gasUsed: int64(350), premium: big.NewInt(100)},
-				{gasUsed: int64(500), premium: big.NewInt(600)},
-				{gasUsed: int64(300), premium: big.NewInt(700)},
-			},
-			answer: []int64{200, 700, 700, 700},
-		},
-	}
-	for _, tc := range testcases {
-		rewards, totalGasUsed := calculateRewardsAndGasUsed(tc.percentiles, tc.txGasRewards)
-		var gasUsed int64
-		for _, tx := range tc.txGasRewards {
-			gasUsed += tx.gasUsed
-		}
-		ans := []types.EthBigInt{}
-		for _, bi := range tc.answer {
-			ans = append(ans, types.EthBigInt(big.NewInt(bi)))
-		}
-		require.Equal(t, totalGasUsed, gasUsed)
-		require.Equal(t, len(ans), len(tc.percentiles))
-		require.Equal(t, ans, rewards)
-	}
-}
-
 func TestABIEncoding(t *testing.T) {
 	// Generated from https://abi.hashex.org/
 	const expected = "000000000000000000000000000000000000000000000000000000000000001600000000000000000000000000000000000000000000000000000000000000510000000000000000000000000000000000000000000000000000000000000060000000000000000000000000000000000000000000000000000000000000001b1111111111111111111020200301000000044444444444444444010000000000"
@@ -292,3 +258,154 @@ func TestDecodePayload(t *testing.T) {
 	_, err = decodePayload(w.Bytes(), 42)
 	require.Error(t, err)
 }
+
+func TestMatchTraceFilter(t *testing.T) {
+	addr := func(b byte) types.EthAddress { return types.EthAddress{b} }
+	a1, a2, a3 := addr(1), addr(2), addr(3)
+
+	call := &types.EthTraceBlock{EthTrace: &types.EthTrace{
+		Action: &types.EthCallTraceAction{From: a1, To: a2},
+	}}
+	create := &types.EthTraceBlock{EthTrace: &types.EthTrace{
+		Action: &types.EthCreateTraceAction{From: a1},
+		Result: &types.EthCreateTraceResult{Address: &a3},
+	}}
+	failedCreate := &types.EthTraceBlock{EthTrace: &types.EthTrace{
+		Action: &types.EthCreateTraceAction{From: a1},
+		Result: &types.EthCreateTraceResult{},
+	}}
+
+	require.True(t, matchTraceFilter(call, nil, nil))
+	require.True(t, matchTraceFilter(call, types.EthAddressList{a1}, types.EthAddressList{a3, a2}))
+	require.False(t, matchTraceFilter(call, types.EthAddressList{a2}, nil))
+	require.False(t, matchTraceFilter(call, nil, types.EthAddressList{a1}))
+
+	require.True(t, matchTraceFilter(create, nil, types.EthAddressList{a3}))
+	require.False(t, matchTraceFilter(create, types.EthAddressList{a3}, nil))
+	require.True(t, matchTraceFilter(failedCreate, types.EthAddressList{a1}, nil))
+	require.False(t, matchTraceFilter(failedCreate, nil, types.EthAddressList{a3}))
+}
+
+func TestBuildCallFrame(t *testing.T) {
+	call := func(addr []int, from, to byte, errMsg string) *types.EthTrace {
+		return &types.EthTrace{
+			Type:         "call",
+			TraceAddress: addr,
+			Error:        errMsg,
+			Action:       &types.EthCallTraceAction{CallType: "call", From: types.EthAddress{from}, To: types.EthAddress{to}},
+			Result:       &types.EthCallTraceResult{GasUsed: 10},
+		}
+	}
+	created := types.EthAddress{9}
+	traces := []*types.EthTrace{
+		call([]int{}, 1, 2, ""),
+		call([]int{0}, 2, 3, "Reverted"),
+		call([]int{0, 0}, 3, 4, ""),
+		{
+			Type:         "create",
+			TraceAddress: []int{1},
+			Action:       &types.EthCreateTraceAction{From: types.EthAddress{2}},
+			Result:       &types.EthCreateTraceResult{Address: &created},
+		},
+	}
+
+	root, err := buildCallFrame(traces, false)
+	require.NoError(t, err)
+	require.Equal(t, "CALL", root.Type)
+	require.Len(t, root.Calls, 2)
+	require.Equal(t, "execution reverted", root.Calls[0].Error)
+	require.Len(t, root.Calls[0].Calls, 1)
+	require.Equal(t, types.EthAddress{4}, *root.Calls[0].Calls[0].To)
+	require.Equal(t, "CREATE", root.Calls[1].Type)
+	require.Equal(t, created, *root.Calls[1].To)
+
+	root, err = buildCallFrame(traces, true)
+	require.NoError(t, err)
+	require.Empty(t, root.Calls)
+
+	_, err = buildCallFrame(traces[2:3], false)
+	require.Error(t, err)
+}
+
+func TestDiffPrestateAccounts(t *testing.T) {
+	balance := func(v int64) *types.EthBigInt {
+		b := types.EthBigInt(big.NewInt(v))
+		return &b
+	}
+
+	before := &types.EthPrestateAccount{Balance: balance(10), Nonce: 1}
+	pre, post := diffPrestateAccounts(before, &types.EthPrestateAccount{Balance: balance(10), Nonce: 1})
+	require.Nil(t, pre)
+	require.Nil(t, post)
+
+	pre, post = diffPrestateAccounts(before, &types.EthPrestateAccount{Balance: balance(10), Nonce: 2})
+	require.Equal(t, before, pre)
+	require.Equal(t, &types.EthPrestateAccount{Nonce: 2}, post)
+
+	slot := types.EthHash{1}.String()
+	stored := &types.EthPrestateAccount{Balance: balance(10), Nonce: 1, Storage: map[string]types.EthHash{slot: {}}}
+	pre, post = diffPrestateAccounts(stored, &types.EthPrestateAccount{Balance: balance(10), Nonce: 1, Storage: map[string]types.EthHash{slot: {2}}})
+	require.Equal(t, stored, pre)
+	require.Equal(t, &types.EthPrestateAccount{Storage: map[string]types.EthHash{slot: {2}}}, post)
+
+	created := &types.EthPrestateAccount{Balance: balance(5), Code: []byte{1}}
+	pre, post = diffPrestateAccounts(nil, created)
+	require.Nil(t, pre)
+	require.Equal(t, created, post)
+}
+
+func TestEvmStorageChanges(t *testing.T) {
+	ctx := context.Background()
+	store := cbor.NewCborStore(blockstoreutil.NewMemory())
+
+	// node builds a KAMT node holding the pointers, a pointer being either a link to a
+	// child or a bucket of key value pairs
+	node := func(pointers ...interface{}) cid.Cid {
+		c, err := store.Put(ctx, []interface{}{[]byte{0xff}, pointers})
+		require.NoError(t, err)
+		return c
+	}
+	kv := func(key, value byte) []interface{} {
+		return []interface{}{[]byte{key}, []byte{value}}
+	}
+	slot := func(key byte) string {
+		return uint256Hash([]byte{key}).String()
+	}
+	value := func(v byte) types.EthHash {
+		return uint256Hash([]byte{v})
+	}
+
+	shared := node([]interface{}{kv(1, 1), kv(2, 2)})
+	before := node(shared, []interface{}{kv(3, 3)}, node([]interface{}{kv(4, 4), kv(5, 5)}))
+	after := node(shared, []interface{}{kv(3, 3), kv(6, 6)}, node([]interface{}{kv(4, 7)}))
+
+	pre, post, err := evmStorageChanges(ctx, store, before, after)
+	require.NoError(t, err)
+	require.Equal(t, map[string]types.EthHash{slot(4): value(4), slot(5): value(5), slot(6): {}}, pre)
+	require.Equal(t, map[string]types.EthHash{slot(4): value(7), slot(5): {}, slot(6): value(6)}, post)
+
+	// a created contract has no storage before
+	pre, post, err = evmStorageChanges(ctx, store, cid.Undef, shared)
+	require.NoError(t, err)
+	require.Equal(t, map[string]types.EthHash{slot(1): {}, slot(2): {}}, pre)
+	require.Equal(t, map[string]types.EthHash{slot(1): value(1), slot(2): value(2)}, post)
+
+	pre, post, err = evmStorageChanges(ctx, store, before, before)
+	require.NoError(t, err)
+	require.Empty(t, pre)
+	require.Empty(t, post)
+}
+
+func TestEthGetBlockReceiptsOfHead(t *testing.T) {
+	ctx := context.Background()
+	builder := chain.NewBuilder(t, address.Undef)
+	head := builder.AppendManyOn(ctx, 3, builder.Genesis())
+	require.NoError(t, builder.Store().SetHead(ctx, head))
+
+	a := &ethAPI{em: &EthSubModule{chainModule: &chainsubmodule.ChainSubmodule{ChainReader: builder.Store()}}}
+
+	// the head has no child holding its receipts yet
+	pending := "pending"
+	_, err := a.EthGetBlockReceipts(ctx, types.EthBlockNumberOrHash{PredefinedBlock: &pending})
+	require.ErrorContains(t, err, "has not been executed yet")
+}
@@ -2,6 +2,8 @@ package eth
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/filecoin-project/go-state-types/abi"
@@ -11,6 +13,7 @@ import (
 	"github.com/filecoin-project/venus/pkg/ethhashlookup"
 	v1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
 	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/ipfs/go-cid"
 )
 
 type ethTxHashManager struct {
@@ -84,6 +87,52 @@ func (m *ethTxHashManager) PopulateExistingMappings(ctx context.Context, minHeig
 	return nil
 }
 
+// checkTipSet returns the eth transactions included in ts that have no tx hash mapping, the
+// mappings are added when fix is set.
+func (m *ethTxHashManager) checkTipSet(ctx context.Context, ts *types.TipSet, fix bool) ([]cid.Cid, error) {
+	var missing []cid.Cid
+	seen := make(map[cid.Cid]struct{})
+	for _, blk := range ts.Blocks() {
+		msgs, err := m.messageStore.SecpkMessagesForBlock(ctx, blk)
+		if err != nil {
+			return nil, fmt.Errorf("load messages of block %s: %w", blk.Cid(), err)
+		}
+
+		for _, smsg := range msgs {
+			if smsg.Signature.Type != crypto.SigTypeDelegated {
+				continue
+			}
+			// a message can be included by several blocks of the tipset
+			if _, ok := seen[smsg.Cid()]; ok {
+				continue
+			}
+			seen[smsg.Cid()] = struct{}{}
+
+			hash, err := ethTxHashFromSignedMessage(ctx, smsg, m.chainAPI)
+			if err != nil {
+				return nil, err
+			}
+
+			c, err := m.TransactionHashLookup.GetCidFromHash(hash)
+			if err == nil && c == smsg.Cid() {
+				continue
+			}
+			if err != nil && !errors.Is(err, ethhashlookup.ErrNotFound) {
+				return nil, err
+			}
+
+			missing = append(missing, smsg.Cid())
+			if fix {
+				if err := m.TransactionHashLookup.UpsertHash(hash, smsg.Cid()); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return missing, nil
+}
+
 func (m *ethTxHashManager) ProcessSignedMessage(ctx context.Context, msg *types.SignedMessage) {
 	if msg.Signature.Type != crypto.SigTypeDelegated {
 		return
@@ -51,6 +51,8 @@ func (a *MessagePoolAPI) MpoolGetConfig(context.Context) (*types.MpoolConfig, er
 		ReplaceByFeeRatio:      cfg.ReplaceByFeeRatio,
 		PruneCooldown:          cfg.PruneCooldown,
 		GasLimitOverestimation: cfg.GasLimitOverestimation,
+		SelectionStrategy:      cfg.SelectionStrategy,
+		MaxSenderGasLimit:      cfg.MaxSenderGasLimit,
 	}, nil
 }
 
@@ -63,6 +65,8 @@ func (a *MessagePoolAPI) MpoolSetConfig(ctx context.Context, cfg *types.MpoolCon
 		ReplaceByFeeRatio:      cfg.ReplaceByFeeRatio,
 		PruneCooldown:          cfg.PruneCooldown,
 		GasLimitOverestimation: cfg.GasLimitOverestimation,
+		SelectionStrategy:      cfg.SelectionStrategy,
+		MaxSenderGasLimit:      cfg.MaxSenderGasLimit,
 	})
 }
 
@@ -314,6 +318,30 @@ func (a *MessagePoolAPI) GasEstimateGasPremium(ctx context.Context, nblocksincl
 	return a.mp.MPool.GasEstimateGasPremium(ctx, nblocksincl, sender, gaslimit, tsk, a.mp.MPool.PriceCache)
 }
 
+// GasEstimateFeeHistory returns the fees paid by the messages of the last blocks tipsets
+// executed up to tsk, the messages of the head are only executed by its children.
+func (a *MessagePoolAPI) GasEstimateFeeHistory(ctx context.Context, blocks uint64, percentiles []float64, tsk types.TipSetKey) (*types.FeeHistory, error) {
+	var ts *types.TipSet
+	if tsk.IsEmpty() {
+		head, err := a.mp.chain.API().ChainHead(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("loading head: %w", err)
+		}
+		ts, err = a.mp.chain.API().ChainGetTipSet(ctx, head.Parents())
+		if err != nil {
+			return nil, fmt.Errorf("loading parent %s of head: %w", head.Parents(), err)
+		}
+	} else {
+		var err error
+		ts, err = a.mp.chain.API().ChainGetTipSet(ctx, tsk)
+		if err != nil {
+			return nil, fmt.Errorf("loading tipset %s: %w", tsk, err)
+		}
+	}
+
+	return a.mp.MPool.FeeOracle.FeeHistory(ctx, ts, int(blocks), percentiles)
+}
+
 func (a *MessagePoolAPI) MpoolCheckMessages(ctx context.Context, protos []*types.MessagePrototype) ([][]types.MessageCheckStatus, error) {
 	return a.mp.MPool.CheckMessages(ctx, protos)
 }
@@ -325,3 +353,13 @@ func (a *MessagePoolAPI) MpoolCheckPendingMessages(ctx context.Context, addr add
 func (a *MessagePoolAPI) MpoolCheckReplaceMessages(ctx context.Context, msg []*types.Message) ([][]types.MessageCheckStatus, error) {
 	return a.mp.MPool.CheckReplaceMessages(ctx, msg)
 }
+
+// MpoolHistory returns the journaled events of the messages sent by addr, or only of its message with nonce.
+func (a *MessagePoolAPI) MpoolHistory(ctx context.Context, addr address.Address, nonce *uint64) ([]*types.MpoolJournalEntry, error) {
+	return a.mp.MPool.History(ctx, addr, nonce)
+}
+
+// MpoolGetMessageStatus returns whether the message is in the mpool, or why it left it.
+func (a *MessagePoolAPI) MpoolGetMessageStatus(ctx context.Context, c cid.Cid) (*types.MpoolMessageStatus, error) {
+	return a.mp.MPool.MessageStatus(ctx, c)
+}
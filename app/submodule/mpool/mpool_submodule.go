@@ -14,6 +14,7 @@ import (
 	"github.com/libp2p/go-libp2p/core/peer"
 
 	"github.com/filecoin-project/go-address"
+	"github.com/ipfs/go-cid"
 	logging "github.com/ipfs/go-log"
 
 	"github.com/filecoin-project/venus/app/submodule/chain"
@@ -22,6 +23,7 @@ import (
 	chainpkg "github.com/filecoin-project/venus/pkg/chain"
 	"github.com/filecoin-project/venus/pkg/config"
 	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/pkg/follower"
 	"github.com/filecoin-project/venus/pkg/messagepool"
 	"github.com/filecoin-project/venus/pkg/messagepool/journal"
 	"github.com/filecoin-project/venus/pkg/repo"
@@ -47,6 +49,7 @@ var log = logging.Logger("mpool")
 
 type messagepoolConfig interface {
 	Repo() repo.Repo
+	Follower() *follower.Follower
 }
 
 // MessagingSubmodule enhances the `Node` with internal message capabilities.
@@ -61,6 +64,8 @@ type MessagePoolSubmodule struct { //nolint
 	walletAPI    v1api.IWallet
 	networkCfg   *config.NetworkParamsConfig
 	bootstrapper bool
+	// leader is the api of the leader a follower forwards its message pool api to, MPool is nil then
+	leader v1api.FullNode
 }
 
 func OpenFilesystemJournal(lr repo.Repo) (journal.Journal, error) {
@@ -72,15 +77,42 @@ func OpenFilesystemJournal(lr repo.Repo) (journal.Journal, error) {
 	return jrnl, err
 }
 
+// OpenJournal opens the message pool journal selected by the config of the repo, the sqlite
+// journal records every event unless disabled by the environment.
+func OpenJournal(lr repo.Repo) (journal.Journal, error) {
+	cfg := lr.Config().Mpool
+	switch cfg.JournalType {
+	case config.MpoolJournalSqlite:
+		return journal.OpenSqliteJournal(lr, journal.EnvDisabledEventsOr(nil), time.Duration(cfg.JournalRetention))
+	case config.MpoolJournalFS, "":
+		return OpenFilesystemJournal(lr)
+	case config.MpoolJournalNone:
+		return journal.NilJournal(), nil
+	default:
+		return nil, fmt.Errorf("unknown mpool journal type %q", cfg.JournalType)
+	}
+}
+
 func NewMpoolSubmodule(ctx context.Context,
 	cfg messagepoolConfig,
 	network *network.NetworkSubmodule,
 	chain *chain.ChainSubmodule,
 	wallet *wallet.WalletSubmodule,
 ) (*MessagePoolSubmodule, error) {
+	if f := cfg.Follower(); f != nil {
+		// a follower has no message pool, its messages go to the one of the leader
+		return &MessagePoolSubmodule{
+			chain:      chain,
+			walletAPI:  wallet.API(),
+			network:    network,
+			networkCfg: cfg.Repo().Config().NetworkParams,
+			leader:     f.Leader(),
+		}, nil
+	}
+
 	mpp := messagepool.NewProvider(chain.Stmgr, chain.ChainReader, chain.MessageStore, cfg.Repo().Config().NetworkParams, network.Pubsub)
 
-	j, err := OpenFilesystemJournal(cfg.Repo())
+	j, err := OpenJournal(cfg.Repo())
 	if err != nil {
 		return nil, err
 	}
@@ -89,6 +121,16 @@ func NewMpoolSubmodule(ctx context.Context,
 	if err != nil {
 		return nil, fmt.Errorf("constructing mpool: %s", err)
 	}
+	// pruning the chain keeps the pending messages, the unsigned message of a bls message is the
+	// one stored
+	chain.ChainReader.AddPruneRoots(func(ctx context.Context) ([]cid.Cid, error) {
+		pending, _ := mp.Pending(ctx)
+		cids := make([]cid.Cid, 0, 2*len(pending))
+		for _, m := range pending {
+			cids = append(cids, m.Cid(), m.Message.Cid())
+		}
+		return cids, nil
+	})
 
 	return &MessagePoolSubmodule{
 		MPool:        mp,
@@ -193,6 +235,11 @@ func (mp *MessagePoolSubmodule) validateLocalMessage(ctx context.Context, msg *p
 
 // Start to the message pubsub topic to learn about messages to mine into blocks.
 func (mp *MessagePoolSubmodule) Start(ctx context.Context) error {
+	if mp.leader != nil {
+		// the messages of a follower go to the message pool of the leader
+		return nil
+	}
+
 	topicName := types.MessageTopic(mp.network.NetworkName)
 	var err error
 	if err = mp.network.Pubsub.RegisterTopicValidator(topicName, mp.Validate); err != nil {
@@ -265,6 +312,9 @@ func (mp *MessagePoolSubmodule) waitForSync(epochs int, subscribe func()) {
 }
 
 func (mp *MessagePoolSubmodule) Stop(ctx context.Context) {
+	if mp.MPool == nil {
+		return
+	}
 	err := mp.MPool.Close()
 	if err != nil {
 		log.Errorf("failed to close mpool: %s", err)
@@ -274,13 +324,31 @@ func (mp *MessagePoolSubmodule) Stop(ctx context.Context) {
 	}
 }
 
+// Leader returns the api of the leader of a follower, nil when the node is not a follower.
+func (mp *MessagePoolSubmodule) Leader() v1api.FullNode {
+	return mp.leader
+}
+
 // API create a new mpool api implement
 func (mp *MessagePoolSubmodule) API() v1api.IMessagePool {
+	if mp.leader != nil {
+		return &leaderMessagePool{IMessagePool: mp.leader}
+	}
 	pushLocks := messagepool.NewMpoolLocker()
 	return &MessagePoolAPI{mp: mp, pushLocks: pushLocks}
 }
 
 func (mp *MessagePoolSubmodule) V0API() v0api.IMessagePool {
+	if mp.leader != nil {
+		return &leaderMessagePool{IMessagePool: mp.leader}
+	}
 	pushLocks := messagepool.NewMpoolLocker()
 	return &MessagePoolAPI{mp: mp, pushLocks: pushLocks}
 }
+
+// leaderMessagePool forwards the message pool api of a follower to the leader, the v1 api of the
+// leader also serves the v0 api. The leader holds the keys: MpoolPushMessage and
+// MpoolBatchPushMessage are signed by the wallet of the leader, not the one of the follower.
+type leaderMessagePool struct {
+	v1api.IMessagePool
+}
@@ -0,0 +1,60 @@
+package mpool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	v1api "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// fakeLeader records the messages a follower forwards to it, and signs the ones it pushes.
+type fakeLeader struct {
+	v1api.FullNode
+	pushed []*types.SignedMessage
+}
+
+func (l *fakeLeader) MpoolPush(_ context.Context, smsg *types.SignedMessage) (cid.Cid, error) {
+	l.pushed = append(l.pushed, smsg)
+	return smsg.Cid(), nil
+}
+
+func (l *fakeLeader) MpoolPushMessage(_ context.Context, msg *types.Message, _ *types.MessageSendSpec) (*types.SignedMessage, error) {
+	smsg := &types.SignedMessage{Message: *msg}
+	l.pushed = append(l.pushed, smsg)
+	return smsg, nil
+}
+
+func TestFollowerForwardsMessages(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	leader := &fakeLeader{}
+	mp := &MessagePoolSubmodule{leader: leader}
+	// a follower has no message pool to start or stop
+	require.NoError(t, mp.Start(ctx))
+	defer mp.Stop(ctx)
+
+	from, err := address.NewIDAddress(100)
+	require.NoError(t, err)
+	msg := &types.Message{From: from, To: from, Value: types.NewInt(1), GasFeeCap: types.NewInt(0), GasPremium: types.NewInt(0)}
+
+	smsg := &types.SignedMessage{Message: *msg}
+	c, err := mp.API().MpoolPush(ctx, smsg)
+	require.NoError(t, err)
+	require.Equal(t, smsg.Cid(), c)
+
+	// the leader signs the messages it pushes for the follower
+	_, err = mp.V0API().MpoolPushMessage(ctx, msg, nil)
+	require.NoError(t, err)
+
+	require.Len(t, leader.pushed, 2)
+	require.Equal(t, smsg, leader.pushed[0])
+	require.Equal(t, *msg, leader.pushed[1].Message)
+	require.Equal(t, leader, mp.Leader())
+}
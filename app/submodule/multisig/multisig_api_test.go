@@ -0,0 +1,74 @@
+package multisig
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/network"
+	"github.com/stretchr/testify/require"
+
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	"github.com/filecoin-project/venus/venus-shared/actors/builtin/multisig"
+	v1api "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+type fakeChain struct {
+	v1api.IChain
+}
+
+func (fakeChain) StateNetworkVersion(context.Context, types.TipSetKey) (network.Version, error) {
+	return network.Version21, nil
+}
+
+func (fakeChain) StateLookupID(_ context.Context, addr address.Address, _ types.TipSetKey) (address.Address, error) {
+	return address.NewIDAddress(1000)
+}
+
+func TestMsigSignerFlows(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	api := NewMultiSigSubmodule(fakeChain{}, nil).API()
+	msig, _ := address.NewIDAddress(100)
+	src, _ := address.NewIDAddress(101)
+	signer, _ := address.NewIDAddress(102)
+
+	proto, err := api.MsigAddPropose(ctx, msig, src, signer, true)
+	require.NoError(t, err)
+	require.Equal(t, msig, proto.Message.To)
+	require.Equal(t, src, proto.Message.From)
+	require.Equal(t, multisig.Methods.Propose, proto.Message.Method)
+
+	var propose types.ProposeParams
+	require.NoError(t, propose.UnmarshalCBOR(bytes.NewReader(proto.Message.Params)))
+	require.Equal(t, msig, propose.To)
+	require.Equal(t, multisig.Methods.AddSigner, propose.Method)
+	require.True(t, big.Zero().Equals(propose.Value))
+
+	var add types.AddSignerParams
+	require.NoError(t, add.UnmarshalCBOR(bytes.NewReader(propose.Params)))
+	require.Equal(t, signer, add.Signer)
+	require.True(t, add.Increase)
+
+	proto, err = api.MsigSwapApprove(ctx, msig, src, 3, signer, src, signer)
+	require.NoError(t, err)
+	require.Equal(t, multisig.Methods.Approve, proto.Message.Method)
+
+	var approve types.TxnIDParams
+	require.NoError(t, approve.UnmarshalCBOR(bytes.NewReader(proto.Message.Params)))
+	require.EqualValues(t, 3, approve.ID)
+	require.NotEmpty(t, approve.ProposalHash)
+
+	_, err = api.MsigApprove(ctx, address.Undef, 3, src)
+	require.Error(t, err)
+	_, err = api.MsigCancel(ctx, msig, 3, address.Undef)
+	require.Error(t, err)
+
+	_, err = api.MsigCreate(ctx, 1, []address.Address{signer}, abi.ChainEpoch(0), big.Zero(), src, types.NewInt(1))
+	require.NoError(t, err)
+}
@@ -0,0 +1,22 @@
+package multisig
+
+import (
+	v1api "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	blockstoreutil "github.com/filecoin-project/venus/venus-shared/blockstore"
+)
+
+// MultiSigSubmodule enhances the `Node` with multisig capabilities.
+type MultiSigSubmodule struct { //nolint
+	state v1api.IChain
+	bs    blockstoreutil.Blockstore
+}
+
+// NewMultiSigSubmodule create new multisig module
+func NewMultiSigSubmodule(chainState v1api.IChain, bs blockstoreutil.Blockstore) *MultiSigSubmodule {
+	return &MultiSigSubmodule{state: chainState, bs: bs}
+}
+
+// API create a new multisig implement
+func (sb *MultiSigSubmodule) API() v1api.IMultiSig {
+	return newMultiSig(sb)
+}
@@ -125,6 +125,36 @@ func (na *networkAPI) NetConnectedness(ctx context.Context, p peer.ID) (network.
 	return na.network.Network.Connectedness(p)
 }
 
+// NetBlockAdd blocks the given peers, ip addresses and subnets, and closes their connections
+func (na *networkAPI) NetBlockAdd(ctx context.Context, acl types.NetBlockList) error {
+	return na.network.Network.BlockAdd(acl)
+}
+
+// NetBlockRemove unblocks the given peers, ip addresses and subnets
+func (na *networkAPI) NetBlockRemove(ctx context.Context, acl types.NetBlockList) error {
+	return na.network.Network.BlockRemove(acl)
+}
+
+// NetBlockList returns the peers, ip addresses and subnets that are blocked
+func (na *networkAPI) NetBlockList(ctx context.Context) (types.NetBlockList, error) {
+	return na.network.Network.BlockList()
+}
+
+// NetStat returns the resources used by a scope of the libp2p resource manager
+func (na *networkAPI) NetStat(ctx context.Context, scope string) (types.NetStat, error) {
+	return na.network.Network.ResourceStat(scope)
+}
+
+// NetLimit returns the limit of a scope of the libp2p resource manager
+func (na *networkAPI) NetLimit(ctx context.Context, scope string) (types.NetLimit, error) {
+	return na.network.Network.ResourceLimit(scope)
+}
+
+// NetSetLimit changes the limit of a scope of the libp2p resource manager
+func (na *networkAPI) NetSetLimit(ctx context.Context, scope string, limit types.NetLimit) error {
+	return na.network.Network.SetResourceLimit(scope, limit)
+}
+
 // NetAutoNatStatus return a struct with current NAT status and public dial address
 func (na *networkAPI) NetAutoNatStatus(context.Context) (types.NatInfo, error) {
 	return na.network.Network.AutoNatStatus()
@@ -155,6 +155,19 @@ func NewNetworkSubmodule(ctx context.Context,
 	}
 	libP2pOpts = append(libP2pOpts, libp2p.ConnectionManager(cm))
 
+	rm, err := resourceManager(swarmCfg.ResourceManager)
+	if err != nil {
+		return nil, err
+	}
+	libP2pOpts = append(libP2pOpts, libp2p.ResourceManager(rm))
+
+	sk := net.NewScoreKeeper()
+	gater, err := net.NewConnGater(config.Repo().MetaDatastore(), sk)
+	if err != nil {
+		return nil, err
+	}
+	libP2pOpts = append(libP2pOpts, libp2p.ConnectionGater(gater))
+
 	// set up host
 	rawHost, err := buildHost(ctx, config, libP2pOpts, cfg)
 	if err != nil {
@@ -177,7 +190,6 @@ func NewNetworkSubmodule(ctx context.Context,
 		return nil, err
 	}
 
-	sk := net.NewScoreKeeper()
 	gsub, err := net.NewGossipSub(ctx, peerHost, sk, networkName, cfg.NetworkParams.DrandSchedule, bootNodes, cfg.PubsubConfig.Bootstrapper)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to set up network")
@@ -209,7 +221,7 @@ func NewNetworkSubmodule(ctx context.Context,
 		return nil, err
 	}
 	// build network
-	network := net.New(peerHost, rawHost, net.NewRouter(router), bandwidthTracker)
+	network := net.New(peerHost, rawHost, net.NewRouter(router), bandwidthTracker, gater)
 	exchangeClient := filexchange.NewClient(peerHost, peerMgr)
 	exchangeServer := filexchange.NewServer(chainStore, messageStore, peerHost)
 	helloHandler := helloprotocol.NewHelloProtocolHandler(peerHost, peerMgr, exchangeClient, chainStore, messageStore, config.GenesisCid(), time.Duration(config.Repo().Config().NetworkParams.BlockDelay)*time.Second)
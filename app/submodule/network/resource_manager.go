@@ -0,0 +1,51 @@
+package network
+
+import (
+	"fmt"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	rcmgr "github.com/libp2p/go-libp2p/p2p/host/resource-manager"
+
+	"github.com/filecoin-project/venus/pkg/config"
+	"github.com/filecoin-project/venus/pkg/net"
+)
+
+// resourceManager builds the libp2p resource manager from the limits of cfg, on top of the
+// default limits scaled to the memory and the file descriptors of the host.
+func resourceManager(cfg *config.ResourceManagerConfig) (network.ResourceManager, error) {
+	if cfg == nil || !cfg.Enable {
+		networkLogger.Warn("libp2p resource manager disabled")
+		return &network.NullResourceManager{}, nil
+	}
+
+	scalingLimits := rcmgr.DefaultLimits
+	libp2p.SetDefaultServiceLimits(&scalingLimits)
+
+	limits := rcmgr.PartialLimitConfig{
+		System:          net.ResourceLimits(cfg.System),
+		Transient:       net.ResourceLimits(cfg.Transient),
+		ProtocolDefault: net.ResourceLimits(cfg.ProtocolDefault),
+		PeerDefault:     net.ResourceLimits(cfg.PeerDefault),
+	}
+	if len(cfg.Protocols) > 0 {
+		limits.Protocol = make(map[protocol.ID]rcmgr.ResourceLimits, len(cfg.Protocols))
+		for proto, l := range cfg.Protocols {
+			limits.Protocol[protocol.ID(proto)] = net.ResourceLimits(l)
+		}
+	}
+	if len(cfg.Peers) > 0 {
+		limits.Peer = make(map[peer.ID]rcmgr.ResourceLimits, len(cfg.Peers))
+		for p, l := range cfg.Peers {
+			pid, err := peer.Decode(p)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse peer ID in resource manager limits: %w", err)
+			}
+			limits.Peer[pid] = net.ResourceLimits(l)
+		}
+	}
+
+	return rcmgr.NewResourceManager(rcmgr.NewFixedLimiter(limits.Build(scalingLimits.AutoScale())))
+}
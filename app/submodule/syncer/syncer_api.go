@@ -12,6 +12,7 @@ import (
 	"github.com/filecoin-project/venus/pkg/fvm"
 	v1api "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
 	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/ipfs/go-cid"
 	logging "github.com/ipfs/go-log/v2"
 )
 
@@ -96,6 +97,11 @@ func (sa *syncerAPI) ChainSyncHandleNewTipSet(ctx context.Context, ci *types.Cha
 // SyncSubmitBlock can be used to submit a newly created block to the.
 // network through this node
 func (sa *syncerAPI) SyncSubmitBlock(ctx context.Context, blk *types.BlockMsg) error {
+	if sa.syncer.Follower != nil {
+		// the leader checks and publishes the blocks of a follower
+		return sa.syncer.Follower.Leader().SyncSubmitBlock(ctx, blk)
+	}
+
 	// todo many dot. how to get directly
 	chainModule := sa.syncer.ChainModule
 	parent, err := chainModule.ChainReader.GetBlock(ctx, blk.Header.Parents[0])
@@ -214,3 +220,37 @@ func (sa *syncerAPI) SyncState(ctx context.Context) (*types.SyncState, error) {
 func (sa *syncerAPI) SyncIncomingBlocks(ctx context.Context) (<-chan *types.BlockHeader, error) {
 	return sa.syncer.ChainSyncManager.BlockProposer().IncomingBlocks(ctx)
 }
+
+// SyncCheckpoint switches the chain to the given tipset and keeps it as checkpoint.
+func (sa *syncerAPI) SyncCheckpoint(ctx context.Context, tsk types.TipSetKey) error {
+	syncAPILog.Warnf("setting checkpoint %s", tsk)
+	return sa.syncer.ChainSyncManager.SyncCheckpoint(ctx, tsk)
+}
+
+// SyncMarkBad marks a block as bad, the syncer won't ever sync it or its descendants.
+func (sa *syncerAPI) SyncMarkBad(ctx context.Context, bcid cid.Cid) error {
+	syncAPILog.Warnf("marking block %s as bad", bcid)
+	sa.syncer.ChainSyncManager.BadTipSets().Add(bcid, "manually marked bad")
+	return nil
+}
+
+// SyncUnmarkBad removes a block from the bad block cache.
+func (sa *syncerAPI) SyncUnmarkBad(ctx context.Context, bcid cid.Cid) error {
+	syncAPILog.Warnf("unmarking block %s as bad", bcid)
+	sa.syncer.ChainSyncManager.BadTipSets().Remove(bcid)
+	return nil
+}
+
+// SyncUnmarkAllBad purges the bad block cache.
+func (sa *syncerAPI) SyncUnmarkAllBad(ctx context.Context) error {
+	syncAPILog.Warnf("dropping bad block cache")
+	sa.syncer.ChainSyncManager.BadTipSets().Purge()
+	return nil
+}
+
+// SyncCheckBad returns the reason a block was marked bad, or an empty string
+// if it is not.
+func (sa *syncerAPI) SyncCheckBad(ctx context.Context, bcid cid.Cid) (string, error) {
+	reason, _ := sa.syncer.ChainSyncManager.BadTipSets().Has(bcid)
+	return reason, nil
+}
@@ -3,6 +3,7 @@ package syncer
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"reflect"
 	"runtime"
 	"time"
@@ -27,6 +28,7 @@ import (
 	"github.com/filecoin-project/venus/pkg/chainsync"
 	"github.com/filecoin-project/venus/pkg/chainsync/slashfilter"
 	"github.com/filecoin-project/venus/pkg/consensus"
+	"github.com/filecoin-project/venus/pkg/follower"
 	"github.com/filecoin-project/venus/pkg/net/blocksub"
 	"github.com/filecoin-project/venus/pkg/net/pubsub"
 	"github.com/filecoin-project/venus/pkg/repo"
@@ -55,6 +57,8 @@ type SyncerSubmodule struct { //nolint
 	SyncProvider     ChainSyncProvider
 	SlashFilter      slashfilter.ISlashFilter
 	BlockValidator   *consensus.BlockValidator
+	// Follower follows the head of the leader instead of syncing, when the node is a follower
+	Follower *follower.Follower
 
 	// cancelChainSync cancels the context for chain sync subscriptions and handlers.
 	CancelChainSync context.CancelFunc
@@ -66,6 +70,7 @@ type syncerConfig interface {
 	ChainClock() clock.ChainEpochClock
 	Repo() repo.Repo
 	Verifier() ffiwrapper.Verifier
+	Follower() *follower.Follower
 }
 
 // NewSyncerSubmodule creates a new chain submodule.
@@ -126,7 +131,7 @@ func NewSyncerSubmodule(ctx context.Context,
 	chn.Waiter.Stmgr = stmgr
 
 	chainSyncManager, err := chainsync.NewManager(stmgr, blkValid, chn,
-		blockstore.Blockstore, network.ExchangeClient, config.ChainClock(), chn.Fork)
+		blockstore.Blockstore, config.Repo().ChainDatastore(), network.ExchangeClient, config.ChainClock(), chn.Fork)
 	if err != nil {
 		return nil, err
 	}
@@ -141,14 +146,16 @@ func NewSyncerSubmodule(ctx context.Context,
 		}
 	}
 
-	if err := network.HelloHandler.Register(ctx, func(ci *types.ChainInfo) {
-		err := chainSyncManager.BlockProposer().SendHello(ci)
-		if err != nil {
-			log.Errorf("error receiving chain info from hello %s: %s", ci, err)
-			return
+	if config.Follower() == nil {
+		if err := network.HelloHandler.Register(ctx, func(ci *types.ChainInfo) {
+			err := chainSyncManager.BlockProposer().SendHello(ci)
+			if err != nil {
+				log.Errorf("error receiving chain info from hello %s: %s", ci, err)
+				return
+			}
+		}); err != nil {
+			return nil, err
 		}
-	}); err != nil {
-		return nil, err
 	}
 
 	return &SyncerSubmodule{
@@ -161,6 +168,7 @@ func NewSyncerSubmodule(ctx context.Context,
 		Drand:            chn.Drand,
 		SyncProvider:     *NewChainSyncProvider(&chainSyncManager),
 		BlockValidator:   blkValid,
+		Follower:         config.Follower(),
 	}, nil
 }
 
@@ -234,6 +242,15 @@ func (syncer *SyncerSubmodule) handleIncomingBlocks(ctx context.Context, msg pub
 
 // Start starts the syncer submodule for a node.
 func (syncer *SyncerSubmodule) Start(ctx context.Context) error {
+	if syncer.Follower != nil {
+		// a follower neither receives blocks nor syncs, it follows the head of the leader
+		if err := syncer.ChainModule.Start(ctx); err != nil {
+			return err
+		}
+		syncer.Follower.Start(ctx, syncer.ChainModule.ChainReader)
+		return nil
+	}
+
 	// setup topic
 	topicName := types.BlockTopic(syncer.NetworkModule.NetworkName)
 	topic, err := syncer.NetworkModule.Pubsub.Join(topicName)
@@ -274,9 +291,83 @@ func (syncer *SyncerSubmodule) Start(ctx context.Context) error {
 		return err
 	}
 
+	if err := syncer.checkSnapshotImport(ctx); err != nil {
+		return err
+	}
+	// the verification takes hours on a full snapshot, it runs while the node syncs and serves
+	// the API, which reports its progress
+	go func() {
+		if err := syncer.verifySnapshotStates(ctx); err != nil {
+			log.Errorf("failed to verify the snapshot states: %s", err)
+		}
+	}()
+
 	return syncer.ChainSyncManager.Start(ctx)
 }
 
+// checkSnapshotImport refuses a snapshot whose import did not complete or failed its verification.
+func (syncer *SyncerSubmodule) checkSnapshotImport(ctx context.Context) error {
+	status, err := syncer.ChainModule.ChainReader.GetSnapshotImportStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("loading snapshot import status: %w", err)
+	}
+	if status == nil || status.State == types.SnapshotImported || status.State == types.SnapshotVerifyingStates {
+		return nil
+	}
+	return fmt.Errorf("the import of snapshot %s did not complete (%s: %s), import it again in a new repo", status.Source, status.State, status.Error)
+}
+
+// verifySnapshotStates recomputes the state roots of the imported snapshot that the import left to
+// verify, resuming after the ones verified before the last shutdown. The progress is saved in the
+// snapshot import status.
+func (syncer *SyncerSubmodule) verifySnapshotStates(ctx context.Context) error {
+	cs := syncer.ChainModule.ChainReader
+	status, err := cs.GetSnapshotImportStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("loading snapshot import status: %w", err)
+	}
+	if status == nil || status.State != types.SnapshotVerifyingStates {
+		return nil
+	}
+
+	head, err := cs.GetTipSet(ctx, status.Head)
+	if err != nil {
+		return fmt.Errorf("loading snapshot head %s: %w", status.Head, err)
+	}
+
+	log.Infof("verifying the last %d state roots of snapshot %s, %d already verified", status.StateRoots, status.Source, status.StateRootsVerified)
+	start := time.Now()
+	resumed := status.StateRootsVerified
+	err = syncer.Stmgr.VerifyStateRoots(ctx, head, resumed, status.StateRoots, func(verified int, ts *types.TipSet) {
+		status.StateRootsVerified = verified
+		status.UpdatedAt = time.Now()
+		if err := cs.PutSnapshotImportStatus(ctx, status); err != nil {
+			log.Warnf("failed to save snapshot import status: %s", err)
+		}
+
+		perState := time.Since(start) / time.Duration(verified-resumed)
+		log.Infow("verified snapshot state", "height", ts.Height(), "verified", verified, "total", status.StateRoots,
+			"eta", perState*time.Duration(status.StateRoots-verified))
+	})
+	status.UpdatedAt = time.Now()
+	if err != nil {
+		if ctx.Err() != nil {
+			log.Infof("interrupted the verification of snapshot %s after %d state roots, it resumes at the next start", status.Source, status.StateRootsVerified)
+			return nil
+		}
+		status.State = types.SnapshotImportFailed
+		status.Error = err.Error()
+		if err := cs.PutSnapshotImportStatus(ctx, status); err != nil {
+			log.Warnf("failed to save snapshot import status: %s", err)
+		}
+		return fmt.Errorf("verifying the state roots of snapshot %s: %w", status.Source, err)
+	}
+
+	status.State = types.SnapshotImported
+	log.Infof("verified %d state roots of snapshot %s in %s", status.StateRootsVerified, status.Source, time.Since(start))
+	return cs.PutSnapshotImportStatus(ctx, status)
+}
+
 func (syncer *SyncerSubmodule) Stop(ctx context.Context) {
 	if syncer.CancelChainSync != nil {
 		syncer.CancelChainSync()
@@ -284,6 +375,9 @@ func (syncer *SyncerSubmodule) Stop(ctx context.Context) {
 	if syncer.BlockSub != nil {
 		syncer.BlockSub.Cancel()
 	}
+	if syncer.Follower != nil {
+		syncer.Follower.Stop()
+	}
 	if syncer.Stmgr != nil {
 		syncer.Stmgr.Close(ctx)
 	}
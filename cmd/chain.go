@@ -7,7 +7,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,6 +20,7 @@ import (
 	cmds "github.com/ipfs/go-ipfs-cmds"
 
 	"github.com/filecoin-project/venus/app/node"
+	"github.com/filecoin-project/venus/pkg/chain"
 	"github.com/filecoin-project/venus/pkg/constants"
 	v1api "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
 	"github.com/filecoin-project/venus/venus-shared/types"
@@ -37,7 +40,120 @@ var chainCmd = &cmds.Command{
 		"get-receipts":       chainGetReceiptsCmd,
 		"disputer":           chainDisputeSetCmd,
 		"export":             chainExportCmd,
+		"export-range":       chainExportRangeCmd,
+		"import-status":      chainImportStatusCmd,
 		"read-obj":           chainReadObjCmd,
+		"splitstore":         chainSplitStoreCmd,
+		"prune":              chainPruneCmd,
+		"finality":           chainFinalityCmd,
+		"index":              chainIndexCmd,
+	},
+}
+
+var chainIndexCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Manage the indexes of the chain",
+	},
+	Subcommands: map[string]*cmds.Command{
+		"backfill": chainIndexBackfillCmd,
+		"validate": chainIndexValidateCmd,
+	},
+}
+
+var chainIndexRangeOptions = []cmds.Option{
+	cmds.Int64Option("from", "lowest height, defaults to 2880 epochs below --to"),
+	cmds.Int64Option("to", "highest height, defaults to the last executed tipset"),
+}
+
+// chainIndexRange returns the range of heights selected by the --from and --to options.
+func chainIndexRange(req *cmds.Request, env cmds.Environment) (abi.ChainEpoch, abi.ChainEpoch, error) {
+	head, err := env.(*node.Env).ChainAPI.ChainHead(req.Context)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	to := head.Height() - 1
+	if v, ok := req.Options["to"].(int64); ok {
+		to = abi.ChainEpoch(v)
+	}
+	from := to - 2880
+	if v, ok := req.Options["from"].(int64); ok {
+		from = abi.ChainEpoch(v)
+	}
+	if from < 0 {
+		from = 0
+	}
+
+	return from, to, nil
+}
+
+var chainIndexBackfillCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Index the actor events and eth transaction hashes of a range of tipsets",
+		ShortDescription: `Read the actor events of the tipsets between --from and --to from the message receipts
+and write them to the event index configured in Fevm.Event, e.g. after importing a snapshot or
+to fill a new postgres or mysql index. The eth transaction hashes of the messages are indexed too.`,
+	},
+	Options: chainIndexRangeOptions,
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		from, to, err := chainIndexRange(req, env)
+		if err != nil {
+			return err
+		}
+
+		res, err := env.(*node.Env).EthAPI.EthIndexBackfill(req.Context, from, to)
+		if err != nil {
+			return err
+		}
+
+		return printOneString(re, fmt.Sprintf("indexed %d events and %d eth transaction hashes of %d tipsets from %d to %d",
+			res.Events, res.TxHashes, res.TipSets, res.From, res.To))
+	},
+}
+
+var chainIndexValidateCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Check the indexes of a range of tipsets against the chain",
+		ShortDescription: `Report the tipsets between --from and --to whose actor events are missing from the event
+index, the tipsets no longer on the chain whose events are not marked as reverted, and the eth
+transactions without a transaction hash mapping. Use --fix to repair them.
+Mappings older than Fevm.EthTxHashMappingLifetimeDays are garbage collected and reported as missing.`,
+	},
+	Options: append([]cmds.Option{
+		cmds.BoolOption("fix", "repair the inconsistencies found"),
+	}, chainIndexRangeOptions...),
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		from, to, err := chainIndexRange(req, env)
+		if err != nil {
+			return err
+		}
+		fix, _ := req.Options["fix"].(bool)
+
+		res, err := env.(*node.Env).EthAPI.EthIndexValidate(req.Context, from, to, fix)
+		if err != nil {
+			return err
+		}
+
+		buf := new(bytes.Buffer)
+		writer := NewSilentWriter(buf)
+		writer.Printf("Checked %d tipsets from %d to %d\n", res.TipSets, res.From, res.To)
+		writer.Printf("Tipsets with missing events: %d\n", len(res.MissingEvents))
+		for _, height := range res.MissingEvents {
+			writer.Printf("\t%d\n", height)
+		}
+		writer.Printf("Forked tipsets with events not reverted: %d\n", len(res.UnrevertedEvents))
+		for _, height := range res.UnrevertedEvents {
+			writer.Printf("\t%d\n", height)
+		}
+		writer.Printf("Eth transactions without hash mapping: %d\n", len(res.MissingTxHashes))
+		for _, c := range res.MissingTxHashes {
+			writer.Printf("\t%s\n", c)
+		}
+		if res.Fixed && len(res.MissingEvents)+len(res.UnrevertedEvents)+len(res.MissingTxHashes) > 0 {
+			writer.Println("The inconsistencies were fixed")
+		}
+
+		return re.Emit(buf)
 	},
 }
 
@@ -74,6 +190,49 @@ var chainReadObjCmd = &cmds.Command{
 	},
 }
 
+var chainFinalityCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Show the reorg probability of a height and the current safe height",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("height", false, false, "height to inspect, defaults to the chain head"),
+	},
+	Options: []cmds.Option{
+		cmds.FloatOption("threshold", "reorg probability under which a height is considered safe").WithDefault(1e-6),
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		ctx := req.Context
+		head, err := env.(*node.Env).ChainAPI.ChainHead(ctx)
+		if err != nil {
+			return err
+		}
+
+		height := head.Height()
+		if len(req.Arguments) > 0 {
+			h, err := strconv.ParseInt(req.Arguments[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid height: %w", err)
+			}
+			height = abi.ChainEpoch(h)
+		}
+		threshold, _ := req.Options["threshold"].(float64)
+
+		f, err := env.(*node.Env).ChainAPI.ChainGetFinality(ctx, height, threshold)
+		if err != nil {
+			return err
+		}
+
+		buf := new(bytes.Buffer)
+		writer := NewSilentWriter(buf)
+		writer.Printf("Head: %d\n", f.Head)
+		writer.Printf("Height: %d (depth %d)\n", f.Height, f.Head-f.Height)
+		writer.Printf("Reorg probability: %g\n", f.ReorgProbability)
+		writer.Printf("Safe height: %d (depth %d, threshold %g)\n", f.SafeHeight, f.SafeDepth, f.Threshold)
+
+		return re.Emit(buf)
+	},
+}
+
 var chainHeadCmd = &cmds.Command{
 	Helptext: cmds.HelpText{
 		Tagline: "Get heaviest tipset info",
@@ -410,6 +569,201 @@ var chainExportCmd = &cmds.Command{
 	},
 }
 
+var chainExportRangeCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Export the tipsets between two heights to a car file",
+		ShortDescription: `Export the tipsets from --head down to the height --tail, with their messages,
+receipts and states, walking --workers tipsets in parallel. With --resume, an interrupted
+export in the output file is continued from the last tipset it completed.`,
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("outputPath", true, false, "the car file to write"),
+	},
+	Options: []cmds.Option{
+		cmds.StringOption("head", "the highest tipset, as comma separated cids, @<height> or @head").WithDefault("@head"),
+		cmds.Int64Option("tail", "the lowest height to export"),
+		cmds.IntOption("workers", "number of tipsets walked in parallel").WithDefault(1),
+		cmds.BoolOption("messages", "include the messages").WithDefault(true),
+		cmds.BoolOption("receipts", "include the message receipts").WithDefault(true),
+		cmds.BoolOption("stateroots", "include the states").WithDefault(true),
+		cmds.BoolOption("resume", "continue the interrupted export in the output file").WithDefault(false),
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		ctx := req.Context
+		chainAPI := env.(*node.Env).ChainAPI
+
+		tailOpt, ok := req.Options["tail"].(int64)
+		if !ok {
+			return errors.New("must specify the tail height with --tail")
+		}
+		tail := abi.ChainEpoch(tailOpt)
+		opts := types.ChainExportRangeOpts{
+			Workers:    req.Options["workers"].(int),
+			Messages:   req.Options["messages"].(bool),
+			Receipts:   req.Options["receipts"].(bool),
+			StateRoots: req.Options["stateroots"].(bool),
+		}
+
+		head, err := ParseTipSetRef(ctx, chainAPI, req.Options["head"].(string))
+		if err != nil {
+			return err
+		}
+
+		buf := new(bytes.Buffer)
+		writer := NewSilentWriter(buf)
+
+		resume := req.Options["resume"].(bool)
+		flags := os.O_CREATE | os.O_RDWR
+		if !resume {
+			flags |= os.O_TRUNC
+		}
+		fi, err := os.OpenFile(req.Arguments[0], flags, 0o644)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := fi.Close()
+			if err != nil {
+				fmt.Printf("error closing output file: %+v", err)
+			}
+		}()
+
+		if resume {
+			st, err := fi.Stat()
+			if err != nil {
+				return err
+			}
+			if st.Size() > 0 {
+				progress, err := chain.ScanExportRange(fi)
+				if err != nil {
+					return fmt.Errorf("reading the export to resume: %w", err)
+				}
+				if progress != nil {
+					if progress.Height <= tail {
+						writer.Printf("Export complete down to %d\n", progress.Height)
+						return re.Emit(buf)
+					}
+					if head, err = chainAPI.ChainGetTipSet(ctx, progress.Next); err != nil {
+						return err
+					}
+					if err := fi.Truncate(progress.Offset); err != nil {
+						return err
+					}
+					opts.SkipHeader = true
+					writer.Printf("Resuming the export from %d\n", head.Height())
+				} else if err := fi.Truncate(0); err != nil {
+					return err
+				}
+			}
+			if _, err := fi.Seek(0, io.SeekEnd); err != nil {
+				return err
+			}
+		}
+
+		stream, err := chainAPI.ChainExportRange(ctx, head.Key(), tail, opts)
+		if err != nil {
+			return err
+		}
+
+		var last bool
+		for b := range stream {
+			last = len(b) == 0
+
+			_, err := fi.Write(b)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !last {
+			return fmt.Errorf("incomplete export (remote connection lost?), continue it with --resume")
+		}
+
+		writer.Printf("Exported %d to %d\n", head.Height(), tail)
+		return re.Emit(buf)
+	},
+}
+
+var chainImportStatusCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Show the progress and the verification of the snapshot imported by the daemon",
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		st, err := env.(*node.Env).ChainAPI.ChainSnapshotImportStatus(req.Context)
+		if err != nil {
+			return err
+		}
+
+		buf := new(bytes.Buffer)
+		writer := NewSilentWriter(buf)
+		writer.Printf("Source: %s\n", st.Source)
+		writer.Printf("State: %s\n", st.State)
+		if st.Error != "" {
+			writer.Printf("Error: %s\n", st.Error)
+		}
+		writer.Printf("Started: %s, updated: %s\n", st.StartedAt.Format(time.RFC3339), st.UpdatedAt.Format(time.RFC3339))
+		writer.Printf("Read: %s of %s, %d blocks\n", types.SizeStr(types.NewInt(st.Bytes)), types.SizeStr(types.NewInt(st.TotalBytes)), st.Blocks)
+		writer.Printf("Speed: %s/s, %.0f blocks/s\n", types.SizeStr(types.NewInt(uint64(st.BytesPerSecond))), st.BlocksPerSecond)
+		if st.State == types.SnapshotImporting {
+			writer.Printf("ETA: %s\n", st.ETA.Truncate(time.Second))
+		}
+		if !st.Head.IsEmpty() {
+			writer.Printf("Head: %d %s\n", st.Height, st.Head)
+		}
+		writer.Printf("Tipset links verified: %d\n", st.LinksVerified)
+		if st.StateRoots > 0 {
+			writer.Printf("State roots verified: %d of %d\n", st.StateRootsVerified, st.StateRoots)
+		}
+
+		return re.Emit(buf)
+	},
+}
+
+var chainPruneCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Delete the objects that are not reachable from the chain head",
+		ShortDescription: `Marks the chain headers down to genesis and the state, messages and receipts of the
+last --retain-state epochs, then deletes everything else from the blockstore. The node keeps
+syncing while pruning. Use --dry-run to see how much would be freed.`,
+	},
+	Options: []cmds.Option{
+		cmds.Int64Option("retain-state", "number of epochs of state, messages and receipts to keep").WithDefault(int64(2 * constants.Finality)),
+		cmds.BoolOption("dry-run", "only report how many blocks and bytes would be freed").WithDefault(false),
+		cmds.BoolOption("gc", "run the value log garbage collection after pruning to reclaim disk space").WithDefault(false),
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		ctx := ReqContext(req.Context)
+		chainAPI := env.(*node.Env).ChainAPI
+
+		dryRun := req.Options["dry-run"].(bool)
+		res, err := chainAPI.ChainPrune(ctx, types.ChainPruneOpts{
+			RetainState: abi.ChainEpoch(req.Options["retain-state"].(int64)),
+			DryRun:      dryRun,
+		})
+		if err != nil {
+			return err
+		}
+
+		buf := new(bytes.Buffer)
+		writer := NewSilentWriter(buf)
+		verb := "Pruned"
+		if dryRun {
+			verb = "Would prune"
+		}
+		writer.Printf("Head: %d, marked %d reachable objects\n", res.Head, res.Marked)
+		writer.Printf("%s %d blocks, %s, took %s\n", verb, res.Pruned, types.SizeStr(types.NewInt(res.PrunedBytes)), res.Duration)
+
+		if !dryRun && req.Options["gc"].(bool) {
+			if err := chainAPI.ChainHotGC(ctx, types.HotGCOpts{}); err != nil {
+				return fmt.Errorf("garbage collection: %w", err)
+			}
+			writer.Println("Garbage collection finished")
+		}
+
+		return re.Emit(buf)
+	},
+}
+
 // LoadTipSet gets the tipset from the context, or the head from the API.
 //
 // It always gets the head from the API so commands use a consistent tipset even if time pases.
@@ -11,7 +11,6 @@ import (
 	types2 "github.com/filecoin-project/venus/venus-shared/actors/types"
 	"github.com/filecoin-project/venus/venus-shared/utils"
 
-	"github.com/filecoin-project/venus/pkg/chainsync/slashfilter"
 	"github.com/filecoin-project/venus/pkg/util/ulimit"
 
 	paramfetch "github.com/filecoin-project/go-paramfetch"
@@ -54,6 +53,7 @@ var daemonCmd = &cmds.Command{
 		cmds.StringsOption(BootstrapPeers, "set the bootstrap peers"),
 		cmds.BoolOption(IsRelay, "advertise and allow venus network traffic to be relayed through this node"),
 		cmds.StringOption(ImportSnapshot, "import chain state from a given chain export file or url"),
+		cmds.IntOption(ImportVerifyStates, "number of state roots below the head of the imported snapshot to recompute in the background, while syncing").WithDefault(0),
 		cmds.StringOption(GenesisFile, "path of file or HTTP(S) URL containing archive of genesis block DAG data"),
 		cmds.StringOption(Network, "when set, populates config with network specific parameters, eg. mainnet,2k,calibrationnet,interopnet,butterflynet").WithDefault("mainnet"),
 		cmds.StringOption(Password, "set wallet password"),
@@ -182,7 +182,8 @@ func initRun(req *cmds.Request, repoDir string) error {
 	// import snapshot argument only work when init
 	importPath, _ := req.Options[ImportSnapshot].(string)
 	if len(importPath) != 0 {
-		err := Import(req.Context, rep, importPath)
+		verifyStates, _ := req.Options[ImportVerifyStates].(int)
+		err := Import(req.Context, rep, importPath, verifyStates)
 		if err != nil {
 			log.Errorf("failed to import snapshot, import path: %s, error: %s", importPath, err.Error())
 			return err
@@ -297,13 +298,6 @@ func daemonRun(req *cmds.Request, re cmds.ResponseEmitter) error {
 		_ = re.Emit("--" + ELStdout + " option is deprecated\n")
 	}
 
-	if config.FaultReporter.EnableConsensusFaultReporter {
-		if err := slashfilter.SlashConsensus(req.Context, config.FaultReporter, fcn.Wallet().API(),
-			fcn.Chain().API(), fcn.Mpool().API(), fcn.Sync().API()); err != nil {
-			return fmt.Errorf("run consensus fault reporter failed: %v", err)
-		}
-	}
-
 	// Start the node.
 	if err := fcn.Start(req.Context); err != nil {
 		return err
@@ -6,33 +6,21 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
-	"time"
+	"text/tabwriter"
 
 	builtin3 "github.com/filecoin-project/specs-actors/v3/actors/builtin"
 	miner3 "github.com/filecoin-project/specs-actors/v3/actors/builtin/miner"
 
 	"github.com/filecoin-project/go-address"
-	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 	cmds "github.com/ipfs/go-ipfs-cmds"
 
-	logging "github.com/ipfs/go-log/v2"
-
 	"github.com/filecoin-project/venus/app/node"
 	"github.com/filecoin-project/venus/venus-shared/actors"
 	v1api "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
 	"github.com/filecoin-project/venus/venus-shared/types"
 )
 
-var disputeLog = logging.Logger("disputer")
-
-const Confidence = 10
-
-type minerDeadline struct {
-	miner address.Address
-	index uint64
-}
-
 var chainDisputeSetCmd = &cmds.Command{
 	Helptext: cmds.HelpText{
 		Tagline:          "interact with the window post disputer",
@@ -44,6 +32,9 @@ var chainDisputeSetCmd = &cmds.Command{
 	},
 	Subcommands: map[string]*cmds.Command{
 		"start":   disputerStartCmd,
+		"stop":    disputerStopCmd,
+		"status":  disputerStatusCmd,
+		"history": disputerHistoryCmd,
 		"dispute": disputerMsgCmd,
 	},
 }
@@ -131,268 +122,86 @@ var disputerMsgCmd = &cmds.Command{
 
 var disputerStartCmd = &cmds.Command{
 	Helptext: cmds.HelpText{
-		Tagline:          "Start the window post disputer",
-		ShortDescription: `[minerAddress]`,
-	},
-	Arguments: []cmds.Argument{
-		cmds.StringArg("minerAddress", true, false, "address for miner"),
-	},
-	Options: []cmds.Option{
-		cmds.Uint64Option("start-epoch", "only start disputing PoSts after this epoch").WithDefault(uint64(0)),
-		cmds.Uint64Option("height", ""),
+		Tagline: "Start the window post disputer in the daemon",
+		ShortDescription: `The disputer is configured by the disputer section of the config, and keeps running
+across restarts of the daemon until it is stopped.`,
 	},
 	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
-		ctx := req.Context
-
-		fromStr := req.Options["from"].(string)
-		fromAddr, err := getSender(ctx, env.(*node.Env).WalletAPI, fromStr)
-		if err != nil {
+		if err := env.(*node.Env).DisputerAPI.DisputerStart(req.Context); err != nil {
 			return err
 		}
+		return re.Emit("window post disputer started")
+	},
+}
 
-		mss, err := getMaxFee(req.Options["max-fee"].(string))
-		if err != nil {
+var disputerStopCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Stop the window post disputer",
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		if err := env.(*node.Env).DisputerAPI.DisputerStop(req.Context); err != nil {
 			return err
 		}
+		return re.Emit("window post disputer stopped")
+	},
+}
 
-		startEpoch := abi.ChainEpoch(0)
-		height := req.Options["height"].(uint64)
-		if height > 0 {
-			startEpoch = abi.ChainEpoch(height)
-		}
-
-		disputeLog.Info("setting up window post disputer")
-
-		// subscribe to head changes and validate the current value
-
-		headChanges, err := env.(*node.Env).ChainAPI.ChainNotify(ctx)
+var disputerStatusCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Show the progress of the window post disputer",
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		status, err := env.(*node.Env).DisputerAPI.DisputerStatus(req.Context)
 		if err != nil {
 			return err
 		}
-		head, ok := <-headChanges
-		if !ok {
-			return fmt.Errorf("notify stream was invalid")
-		}
 
-		if len(head) != 1 {
-			return fmt.Errorf("notify first entry should have been one item")
+		buf := new(bytes.Buffer)
+		writer := NewSilentWriter(buf)
+		writer.Printf("Running:            %t\n", status.Running)
+		if status.Running {
+			writer.Printf("From:               %s\n", status.From)
 		}
-
-		if head[0].Type != types.HCCurrent {
-			return fmt.Errorf("expected current head on Notify stream (got %s)", head[0].Type)
+		writer.Printf("Next epoch:         %d\n", status.NextEpoch)
+		writer.Printf("Miners watched:     %d\n", status.Miners)
+		writer.Printf("Proofs checked:     %d\n", status.ProofsChecked)
+		writer.Printf("Disputes sent:      %d\n", status.DisputesSent)
+		writer.Printf("Disputes succeeded: %d\n", status.DisputesSucceeded)
+		writer.Printf("Disputes failed:    %d\n", status.DisputesFailed)
+		writer.Printf("Rewards earned:     %s\n", types.FIL(status.RewardsEarned))
+		if status.Error != "" {
+			writer.Printf("Error:              %s\n", status.Error)
 		}
 
-		lastEpoch := head[0].Val.Height()
-		lastStatusCheckEpoch := lastEpoch
-
-		// build initial deadlineMap
+		return re.Emit(buf)
+	},
+}
 
-		minerList, err := env.(*node.Env).ChainAPI.StateListMiners(ctx, types.EmptyTSK)
+var disputerHistoryCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "List the disputes sent by the window post disputer",
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		history, err := env.(*node.Env).DisputerAPI.DisputerHistory(req.Context)
 		if err != nil {
 			return err
 		}
 
-		knownMiners := make(map[address.Address]struct{})
-		deadlineMap := make(map[abi.ChainEpoch][]minerDeadline)
-		for _, miner := range minerList {
-			dClose, dl, err := makeMinerDeadline(ctx, env.(*node.Env).ChainAPI, miner)
-			if err != nil {
-				return fmt.Errorf("making deadline: %w", err)
-			}
-
-			deadlineMap[dClose+Confidence] = append(deadlineMap[dClose+Confidence], *dl)
-
-			knownMiners[miner] = struct{}{}
-		}
-
-		// when this fires, check for newly created miners, and purge any "missed" epochs from deadlineMap
-		statusCheckTicker := time.NewTicker(time.Hour)
-		defer statusCheckTicker.Stop()
-
-		disputeLog.Info("starting up window post disputer")
-
-		applyTsk := func(tsk types.TipSetKey) error {
-			disputeLog.Infow("last checked epoch", "epoch", lastEpoch)
-			dls, ok := deadlineMap[lastEpoch]
-			delete(deadlineMap, lastEpoch)
-			if !ok || startEpoch >= lastEpoch {
-				// no deadlines closed at this epoch - Confidence, or we haven't reached the start cutoff yet
-				return nil
-			}
-
-			dpmsgs := make([]*types.Message, 0)
-
-			startTime := time.Now()
-			proofsChecked := uint64(0)
-
-			// TODO: Parallelizeable
-			for _, dl := range dls {
-				fullDeadlines, err := env.(*node.Env).ChainAPI.StateMinerDeadlines(ctx, dl.miner, tsk)
-				if err != nil {
-					return fmt.Errorf("failed to load deadlines: %w", err)
-				}
-
-				if int(dl.index) >= len(fullDeadlines) {
-					return fmt.Errorf("deadline index %d not found in deadlines", dl.index)
-				}
-
-				disputableProofs := fullDeadlines[dl.index].DisputableProofCount
-				proofsChecked += disputableProofs
-
-				ms, err := makeDisputeWindowedPosts(ctx, env.(*node.Env).ChainAPI, dl, disputableProofs, fromAddr)
-				if err != nil {
-					return fmt.Errorf("failed to check for disputes: %w", err)
-				}
-
-				dpmsgs = append(dpmsgs, ms...)
-
-				dClose, dl, err := makeMinerDeadline(ctx, env.(*node.Env).ChainAPI, dl.miner)
-				if err != nil {
-					return fmt.Errorf("making deadline: %w", err)
-				}
-
-				deadlineMap[dClose+Confidence] = append(deadlineMap[dClose+Confidence], *dl)
-			}
-
-			disputeLog.Infow("checked proofs", "count", proofsChecked, "duration", time.Since(startTime))
-
-			// TODO: Parallelizeable / can be integrated into the previous deadline-iterating for loop
-			for _, dpmsg := range dpmsgs {
-				disputeLog.Infow("disputing a PoSt", "miner", dpmsg.To)
-				m, err := env.(*node.Env).MessagePoolAPI.MpoolPushMessage(ctx, dpmsg, mss)
-				if err != nil {
-					disputeLog.Errorw("failed to dispute post message", "err", err.Error(), "miner", dpmsg.To)
-				} else {
-					disputeLog.Infow("submited dispute", "mcid", m.Cid(), "miner", dpmsg.To)
-				}
-			}
-
-			return nil
-		}
-
-		disputeLoop := func() error {
-			select {
-			case notif, ok := <-headChanges:
-				if !ok {
-					return fmt.Errorf("head change channel errored")
-				}
-
-				for _, val := range notif {
-					switch val.Type {
-					case types.HCApply:
-						for ; lastEpoch <= val.Val.Height(); lastEpoch++ {
-							err := applyTsk(val.Val.Key())
-							if err != nil {
-								return err
-							}
-						}
-					case types.HCRevert:
-						// do nothing
-					default:
-						return fmt.Errorf("unexpected head change type %s", val.Type)
-					}
-				}
-			case <-statusCheckTicker.C:
-				disputeLog.Infof("running status check")
-
-				minerList, err = env.(*node.Env).ChainAPI.StateListMiners(ctx, types.EmptyTSK)
-				if err != nil {
-					return fmt.Errorf("getting miner list: %w", err)
-				}
-
-				for _, m := range minerList {
-					_, ok := knownMiners[m]
-					if !ok {
-						dClose, dl, err := makeMinerDeadline(ctx, env.(*node.Env).ChainAPI, m)
-						if err != nil {
-							return fmt.Errorf("making deadline: %w", err)
-						}
-
-						deadlineMap[dClose+Confidence] = append(deadlineMap[dClose+Confidence], *dl)
-
-						knownMiners[m] = struct{}{}
-					}
-				}
-
-				for ; lastStatusCheckEpoch < lastEpoch; lastStatusCheckEpoch++ {
-					// if an epoch got "skipped" from the deadlineMap somehow, just fry it now instead of letting it sit around forever
-					_, ok := deadlineMap[lastStatusCheckEpoch]
-					if ok {
-						disputeLog.Infow("epoch skipped during execution, deleting it from deadlineMap", "epoch", lastStatusCheckEpoch)
-						delete(deadlineMap, lastStatusCheckEpoch)
-					}
-				}
-
-				log.Infof("status check complete")
-			case <-ctx.Done():
-				return ctx.Err()
-			}
-
-			return nil
+		buf := new(bytes.Buffer)
+		tw := tabwriter.NewWriter(buf, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "Epoch\tMiner\tDeadline\tPoSt\tMessage\tState\tExit\tReward")
+		for _, rec := range history {
+			_, _ = fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%s\t%s\t%d\t%s\n", rec.SentAt, rec.Miner, rec.Deadline,
+				rec.PoStIndex, rec.Message, rec.State, rec.ExitCode, types.FIL(rec.Reward))
 		}
-
-		for {
-			err := disputeLoop()
-			if err == context.Canceled {
-				disputeLog.Info("disputer shutting down")
-				break
-			}
-			if err != nil {
-				disputeLog.Errorw("disputer shutting down", "err", err)
-				return err
-			}
+		if err := tw.Flush(); err != nil {
+			return err
 		}
 
-		return nil
+		return re.Emit(buf)
 	},
 }
 
-// for a given miner, index, and maxPostIndex, tries to dispute posts from 0...postsSnapshotted-1
-// returns a list of DisputeWindowedPoSt msgs that are expected to succeed if sent
-func makeDisputeWindowedPosts(ctx context.Context, api v1api.IChain, dl minerDeadline, postsSnapshotted uint64, sender address.Address) ([]*types.Message, error) {
-	disputes := make([]*types.Message, 0)
-
-	for i := uint64(0); i < postsSnapshotted; i++ {
-
-		dpp, aerr := actors.SerializeParams(&miner3.DisputeWindowedPoStParams{
-			Deadline:  dl.index,
-			PoStIndex: i,
-		})
-
-		if aerr != nil {
-			return nil, fmt.Errorf("failed to serailize params: %w", aerr)
-		}
-
-		dispute := &types.Message{
-			To:     dl.miner,
-			From:   sender,
-			Value:  big.Zero(),
-			Method: builtin3.MethodsMiner.DisputeWindowedPoSt,
-			Params: dpp,
-		}
-
-		rslt, err := api.StateCall(ctx, dispute, types.EmptyTSK)
-		if err == nil && rslt.MsgRct.ExitCode == 0 {
-			disputes = append(disputes, dispute)
-		}
-
-	}
-
-	return disputes, nil
-}
-
-func makeMinerDeadline(ctx context.Context, api v1api.IChain, mAddr address.Address) (abi.ChainEpoch, *minerDeadline, error) {
-	dl, err := api.StateMinerProvingDeadline(ctx, mAddr, types.EmptyTSK)
-	if err != nil {
-		return -1, nil, fmt.Errorf("getting proving index list: %w", err)
-	}
-
-	return dl.Close, &minerDeadline{
-		miner: mAddr,
-		index: dl.Index,
-	}, nil
-}
-
 func getSender(ctx context.Context, api v1api.IWallet, fromStr string) (address.Address, error) {
 	if fromStr == "" {
 		return api.WalletDefaultAddress(ctx)
@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/filecoin-project/venus/pkg/consensus/chainselector"
 	"github.com/filecoin-project/venus/pkg/httpreader"
@@ -14,6 +15,7 @@ import (
 	"github.com/DataDog/zstd"
 	"github.com/filecoin-project/venus/pkg/chain"
 	"github.com/filecoin-project/venus/pkg/repo"
+	"github.com/filecoin-project/venus/venus-shared/types"
 	"github.com/ipfs/go-cid"
 	logging "github.com/ipfs/go-log/v2"
 	"github.com/mitchellh/go-homedir"
@@ -22,13 +24,18 @@ import (
 
 var logImport = logging.Logger("commands/import")
 
+// importProgressInterval is the interval the progress of an import is logged at.
+const importProgressInterval = 30 * time.Second
+
 // Import cache tipset cids to store.
-// The value of the cached tipset CIDS is used as the check-point when running `venus daemon`
-func Import(ctx context.Context, r repo.Repo, fileName string) error {
-	return importChain(ctx, r, fileName)
+// The value of the cached tipset CIDS is used as the check-point when running `venus daemon`.
+// The links of the imported chain are verified, and the daemon recomputes the last verifyStates
+// state roots in the background once started.
+func Import(ctx context.Context, r repo.Repo, fileName string, verifyStates int) error {
+	return importChain(ctx, r, fileName, verifyStates)
 }
 
-func importChain(ctx context.Context, r repo.Repo, fname string) error {
+func importChain(ctx context.Context, r repo.Repo, fname string, verifyStates int) error {
 	var rd io.Reader
 	var l int64
 	if strings.HasPrefix(fname, "http://") || strings.HasPrefix(fname, "https://") {
@@ -64,11 +71,53 @@ func importChain(ctx context.Context, r repo.Repo, fname string) error {
 	// setup a ipldCbor on top of the local store
 	chainStore := chain.NewStore(r.ChainDatastore(), bs, cid.Undef, chain.NewMockCirculatingSupplyCalculator(), chainselector.Weight)
 
-	bufr := bufio.NewReaderSize(rd, 1<<20)
+	// the genesis the repo was initialized with, that the snapshot must end at
+	var genesis *types.BlockHeader
+	if blk, err := chain.GenesisBlock(ctx, r.ChainDatastore(), bs); err == nil {
+		genesis = &blk
+	}
+
+	progress := chain.NewImportProgress(fname, uint64(l))
+	saveStatus := func() {
+		if err := chainStore.PutSnapshotImportStatus(ctx, progress.Status()); err != nil {
+			logImport.Warnf("failed to save snapshot import status: %s", err)
+		}
+	}
+	fail := func(err error) error {
+		progress.Update(func(status *types.SnapshotImportStatus) {
+			status.State = types.SnapshotImportFailed
+			status.Error = err.Error()
+		})
+		saveStatus()
+		return err
+	}
+	saveStatus()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(importProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				st := progress.Status()
+				logImport.Infow("importing snapshot", "state", st.State, "bytes", types.SizeStr(types.NewInt(st.Bytes)),
+					"total", types.SizeStr(types.NewInt(st.TotalBytes)), "blocks", st.Blocks,
+					"blocks/s", int64(st.BlocksPerSecond), "speed", types.SizeStr(types.NewInt(uint64(st.BytesPerSecond)))+"/s",
+					"eta", st.ETA.Truncate(time.Second), "links", st.LinksVerified)
+				saveStatus()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	bufr := bufio.NewReaderSize(progress.Reader(rd), 1<<20)
 
 	header, err := bufr.Peek(4)
 	if err != nil {
-		return fmt.Errorf("peek header: %w", err)
+		return fail(fmt.Errorf("peek header: %w", err))
 	}
 
 	bar := pb.New64(l)
@@ -90,26 +139,57 @@ func importChain(ctx context.Context, r repo.Repo, fname string) error {
 	}
 
 	bar.Start()
-	tip, genesisBlk, err := chainStore.Import(ctx, ir)
+	tip, genesisBlk, err := chainStore.Import(ctx, ir, progress)
 	if err != nil {
-		return fmt.Errorf("importing chain failed: %s", err)
+		return fail(fmt.Errorf("importing chain failed: %s", err))
 	}
 	bar.Finish()
 
+	progress.Update(func(status *types.SnapshotImportStatus) {
+		status.State = types.SnapshotVerifyingLinks
+		status.Head = tip.Key()
+		status.Height = tip.Height()
+	})
+	if genesis != nil && genesis.Cid() != genesisBlk.Cid() {
+		return fail(fmt.Errorf("snapshot genesis %s is not the genesis %s of the repo", genesisBlk.Cid(), genesis.Cid()))
+	}
+	logImport.Infof("verifying the links of the %d tipsets of the snapshot", tip.Height())
+	err = chainStore.VerifyChainLinks(ctx, tip, genesisBlk, func(checked uint64, _ *types.TipSet) {
+		progress.Update(func(status *types.SnapshotImportStatus) {
+			status.LinksVerified = checked
+		})
+	})
+	if err != nil {
+		return fail(fmt.Errorf("verifying the snapshot chain: %w", err))
+	}
+
 	err = chainStore.SetHead(context.TODO(), tip)
 	if err != nil {
-		return fmt.Errorf("importing chain failed: %s", err)
+		return fail(fmt.Errorf("importing chain failed: %s", err))
 	}
 	logImport.Infof("accepting %s as new head", tip.Key().String())
 
 	if err := chainStore.PersistGenesisCID(ctx, genesisBlk); err != nil {
-		return fmt.Errorf("persist genesis failed: %v", err)
+		return fail(fmt.Errorf("persist genesis failed: %v", err))
 	}
 
 	err = chainStore.WriteCheckPoint(context.TODO(), tip.Key())
 	if err != nil {
 		logImport.Errorf("set check point error: %s", err.Error())
+		return fail(err)
 	}
 
-	return err
+	// the state roots are recomputed by the daemon, which has a state manager
+	progress.Update(func(status *types.SnapshotImportStatus) {
+		status.State = types.SnapshotImported
+		if verifyStates > 0 {
+			status.State = types.SnapshotVerifyingStates
+			status.StateRoots = verifyStates
+		}
+	})
+	saveStatus()
+	st := progress.Status()
+	logImport.Infof("imported %d blocks (%s) in %s", st.Blocks, types.SizeStr(types.NewInt(st.Bytes)), st.UpdatedAt.Sub(st.StartedAt).Truncate(time.Second))
+
+	return nil
 }
@@ -71,7 +71,8 @@ const (
 
 	Size = "size"
 
-	ImportSnapshot = "import-snapshot"
+	ImportSnapshot     = "import-snapshot"
+	ImportVerifyStates = "import-verify-states"
 
 	// wallet password
 	Password = "password"
@@ -126,6 +127,9 @@ State COMMANDS
 Paych COMMANDS 
   paych                  - Manage payment channels
 
+Multisig COMMANDS
+  msig                   - Interact with a multisig wallet
+
 Cid COMMANDS
   manifest-cid-from-car  - Get the manifest CID from a car file
 
@@ -180,6 +184,7 @@ var rootSubcmdsDaemon = map[string]*cmds.Command{
 	"state":   stateCmd,
 	"miner":   minerCmd,
 	"paych":   paychCmd,
+	"msig":    msigCmd,
 	"info":    infoCmd,
 	"evm":     evmCmd,
 }
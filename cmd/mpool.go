@@ -1,12 +1,16 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	stdbig "math/big"
 
@@ -28,17 +32,19 @@ var mpoolCmd = &cmds.Command{
 		Tagline: "Manage message pool",
 	},
 	Subcommands: map[string]*cmds.Command{
-		"pending":  mpoolPending,
-		"clear":    mpoolClear,
-		"sub":      mpoolSub,
-		"stat":     mpoolStat,
-		"replace":  mpoolReplaceCmd,
-		"find":     mpoolFindCmd,
-		"config":   mpoolConfig,
-		"gas-perf": mpoolGasPerfCmd,
-		"publish":  mpoolPublish,
-		"delete":   mpoolDeleteAddress,
-		"select":   mpoolSelect,
+		"pending":     mpoolPending,
+		"clear":       mpoolClear,
+		"sub":         mpoolSub,
+		"stat":        mpoolStat,
+		"replace":     mpoolReplaceCmd,
+		"find":        mpoolFindCmd,
+		"history":     mpoolHistoryCmd,
+		"fee-history": mpoolFeeHistoryCmd,
+		"config":      mpoolConfig,
+		"gas-perf":    mpoolGasPerfCmd,
+		"publish":     mpoolPublish,
+		"delete":      mpoolDeleteAddress,
+		"select":      mpoolSelect,
 	},
 }
 
@@ -74,8 +80,11 @@ var mpoolDeleteAddress = &cmds.Command{
 
 var mpoolSelect = &cmds.Command{
 	Helptext: cmds.HelpText{
-		Tagline:          "select",
-		ShortDescription: "select message from mpool",
+		Tagline: "select",
+		ShortDescription: `
+select message from mpool with the selection strategy of the mpool config, set
+its SelectionStrategy to compare the strategies: optimal, fee, local or sender-gas-cap
+`,
 	},
 	Options: []cmds.Option{
 		cmds.FloatOption("quality", "optionally specify the wallet for publish message").WithDefault(float64(0.5)),
@@ -88,6 +97,10 @@ var mpoolSelect = &cmds.Command{
 		if err != nil {
 			return err
 		}
+		cfg, err := env.(*node.Env).MessagePoolAPI.MpoolGetConfig(ctx)
+		if err != nil {
+			return err
+		}
 		msgs, err := env.(*node.Env).MessagePoolAPI.MpoolSelect(ctx, head.Key(), quality)
 		if err != nil {
 			return err
@@ -97,7 +110,16 @@ var mpoolSelect = &cmds.Command{
 			return err
 		}
 
-		return printOneString(re, string(selectMsg))
+		strategy := cfg.SelectionStrategy
+		if strategy == "" {
+			strategy = messagepool.DefaultSelectionStrategy
+		}
+		var gasLimit int64
+		for _, m := range msgs {
+			gasLimit += m.Message.GasLimit
+		}
+
+		return printOneString(re, fmt.Sprintf("strategy: %s, messages: %d, gas limit: %d\n%s", strategy, len(msgs), gasLimit, selectMsg))
 	},
 }
 
@@ -142,15 +164,50 @@ var mpoolPublish = &cmds.Command{
 
 var mpoolFindCmd = &cmds.Command{
 	Helptext: cmds.HelpText{
-		Tagline:          "find",
-		ShortDescription: "find a message in the mempool",
+		Tagline: "find",
+		ShortDescription: `
+find a message in the mempool, with --cid print whether the message is still pending
+or why it was removed from the mempool
+`,
 	},
 	Options: []cmds.Option{
 		cmds.StringOption("from", "search for messages with given 'from' address"),
 		cmds.StringOption("to", "search for messages with given 'to' address"),
 		cmds.Int64Option("method", "search for messages with given method"),
+		cmds.StringOption("cid", "print the status of the message with given cid"),
 	},
 	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		if c, ok := req.Options["cid"].(string); ok {
+			mc, err := cid.Decode(c)
+			if err != nil {
+				return fmt.Errorf("'cid' was invalid: %w", err)
+			}
+
+			status, err := env.(*node.Env).MessagePoolAPI.MpoolGetMessageStatus(req.Context, mc)
+			if err != nil {
+				return err
+			}
+
+			buf := &bytes.Buffer{}
+			switch {
+			case status.Pending:
+				fmt.Fprintf(buf, "%s: pending\n", mc)
+			case status.Reason == "":
+				fmt.Fprintf(buf, "%s: unknown, the message is neither pending nor removed recently\n", mc)
+			default:
+				fmt.Fprintf(buf, "%s: %s at %s", mc, status.Reason, status.Timestamp.Format(time.RFC3339))
+				if status.Epoch > 0 {
+					fmt.Fprintf(buf, " (epoch %d)", status.Epoch)
+				}
+				if status.Detail != "" {
+					fmt.Fprintf(buf, ", %s", status.Detail)
+				}
+				fmt.Fprintln(buf)
+			}
+
+			return re.Emit(buf)
+		}
+
 		from, _ := req.Options["from"].(string)
 		to, _ := req.Options["to"].(string)
 		method, _ := req.Options["method"].(int64)
@@ -208,6 +265,109 @@ var mpoolFindCmd = &cmds.Command{
 	},
 }
 
+var mpoolHistoryCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Print the lifetime of the messages of an address in the mpool",
+		ShortDescription: `
+Print the journaled add, replace, remove, prune and repub events of the messages
+sent by an address, grouped by nonce. It requires the sqlite mpool journal.
+`,
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("address", true, false, "address the messages were sent from"),
+	},
+	Options: []cmds.Option{
+		cmds.Uint64Option("nonce", "only print the events of the message with this nonce"),
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		addr, err := address.NewFromString(req.Arguments[0])
+		if err != nil {
+			return err
+		}
+
+		var nonce *uint64
+		if n, ok := req.Options["nonce"].(uint64); ok {
+			nonce = &n
+		}
+
+		entries, err := env.(*node.Env).MessagePoolAPI.MpoolHistory(req.Context, addr, nonce)
+		if err != nil {
+			return err
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Message.Nonce < entries[j].Message.Nonce
+		})
+
+		buf := &bytes.Buffer{}
+		tw := tabwriter.NewWriter(buf, 4, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "Nonce\tTime\tEvent\tCid\tGasFeeCap\tGasPremium\tReason\n")
+		for _, e := range entries {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", e.Message.Nonce, e.Timestamp.Format(time.RFC3339),
+				e.Event, e.Cid, e.Message.GasFeeCap, e.Message.GasPremium, e.Reason)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		return re.Emit(buf)
+	},
+}
+
+var mpoolFeeHistoryCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Print the fees paid by the messages of the last tipsets",
+		ShortDescription: `
+Print the base fee, the gas used and the effective gas premiums paid at the
+percentiles of the gas used by the messages of the last tipsets executed.
+`,
+	},
+	Options: []cmds.Option{
+		cmds.Uint64Option("blocks", "number of tipsets to print").WithDefault(uint64(20)),
+		cmds.StringOption("percentiles", "comma separated, ascending percentiles of the gas used").WithDefault("25,50,75"),
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		blocks, _ := req.Options["blocks"].(uint64)
+		percentilesStr, _ := req.Options["percentiles"].(string)
+
+		var percentiles []float64
+		for _, s := range strings.Split(percentilesStr, ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			p, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return fmt.Errorf("parsing percentile %q: %w", s, err)
+			}
+			percentiles = append(percentiles, p)
+		}
+
+		history, err := env.(*node.Env).MessagePoolAPI.GasEstimateFeeHistory(req.Context, blocks, percentiles, types.EmptyTSK)
+		if err != nil {
+			return err
+		}
+
+		buf := &bytes.Buffer{}
+		tw := tabwriter.NewWriter(buf, 4, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "Epoch\tBaseFee\tMessages\tGasUsed\tRatio")
+		for _, p := range history.Percentiles {
+			fmt.Fprintf(tw, "\tP%s", strconv.FormatFloat(p, 'f', -1, 64))
+		}
+		fmt.Fprintln(tw)
+		for _, e := range history.Epochs {
+			fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%.2f", e.Epoch, e.BaseFee, e.Messages, e.GasUsed, e.GasUsedRatio)
+			for _, premium := range e.Premiums {
+				fmt.Fprintf(tw, "\t%s", premium)
+			}
+			fmt.Fprintln(tw)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		return re.Emit(buf)
+	},
+}
+
 var mpoolReplaceCmd = &cmds.Command{
 	Helptext: cmds.HelpText{
 		Tagline:          "replace",
@@ -0,0 +1,875 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	init2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/init"
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	cbor "github.com/ipfs/go-ipld-cbor"
+
+	"github.com/filecoin-project/venus/app/node"
+	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/venus-shared/actors/adt"
+	"github.com/filecoin-project/venus/venus-shared/actors/builtin"
+	"github.com/filecoin-project/venus/venus-shared/actors/builtin/multisig"
+	"github.com/filecoin-project/venus/venus-shared/actors/policy"
+	"github.com/filecoin-project/venus/venus-shared/blockstore"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+var msigCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Interact with a multisig wallet",
+	},
+	Subcommands: map[string]*cmds.Command{
+		"create":         msigCreateCmd,
+		"inspect":        msigInspectCmd,
+		"propose":        msigProposeCmd,
+		"approve":        msigApproveCmd,
+		"cancel":         msigCancelCmd,
+		"propose-remove": msigRemoveProposeCmd,
+		"add-propose":    msigAddProposeCmd,
+		"add-approve":    msigAddApproveCmd,
+		"add-cancel":     msigAddCancelCmd,
+		"swap-propose":   msigSwapProposeCmd,
+		"swap-approve":   msigSwapApproveCmd,
+		"swap-cancel":    msigSwapCancelCmd,
+		"vested":         msigVestedCmd,
+	},
+}
+
+var msigFromOption = cmds.StringOption("from", "account to send the message from, defaults to the wallet default address")
+
+var msigConfidenceOption = cmds.Uint64Option("confidence", "number of block confirmations to wait for").WithDefault(constants.MessageConfidence)
+
+var msigCreateCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Create a new multisig wallet",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("addresses", true, true, "signers of the multisig wallet"),
+	},
+	Options: []cmds.Option{
+		cmds.Uint64Option("required", "number of required approvals (uses number of signers provided if omitted)"),
+		cmds.StringOption("value", "initial funds to give to multisig").WithDefault("0"),
+		cmds.Int64Option("duration", "length of the period over which funds unlock").WithDefault(int64(0)),
+		msigFromOption,
+		msigConfidenceOption,
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		ctx := req.Context
+		var addrs []address.Address
+		for _, a := range req.Arguments {
+			addr, err := address.NewFromString(a)
+			if err != nil {
+				return err
+			}
+			addrs = append(addrs, addr)
+		}
+
+		from, err := msigFromAddress(req, env)
+		if err != nil {
+			return err
+		}
+
+		val, _ := req.Options["value"].(string)
+		filval, err := types.ParseFIL(val)
+		if err != nil {
+			return err
+		}
+
+		required, _ := req.Options["required"].(uint64)
+		if required == 0 {
+			required = uint64(len(addrs))
+		}
+		duration, _ := req.Options["duration"].(int64)
+
+		proto, err := env.(*node.Env).MultiSigAPI.MsigCreate(ctx, required, addrs, abi.ChainEpoch(duration), types.BigInt(filval), from, types.NewInt(1))
+		if err != nil {
+			return err
+		}
+
+		wait, err := msigPushAndWait(req, re, env, proto, "create")
+		if err != nil {
+			return err
+		}
+
+		var execreturn init2.ExecReturn
+		if err := execreturn.UnmarshalCBOR(bytes.NewReader(wait.Receipt.Return)); err != nil {
+			return err
+		}
+
+		return re.Emit(fmt.Sprintf("Created new multisig: %s %s", execreturn.IDAddress, execreturn.RobustAddress))
+	},
+}
+
+var msigInspectCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Inspect a multisig wallet",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("address", true, false, "address of the multisig wallet"),
+	},
+	Options: []cmds.Option{
+		cmds.BoolOption("vesting", "include vesting details"),
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		ctx := req.Context
+		api := env.(*node.Env)
+		maddr, err := address.NewFromString(req.Arguments[0])
+		if err != nil {
+			return err
+		}
+
+		head, err := api.ChainAPI.ChainHead(ctx)
+		if err != nil {
+			return err
+		}
+
+		act, err := api.ChainAPI.StateGetActor(ctx, maddr, head.Key())
+		if err != nil {
+			return err
+		}
+		if !builtin.IsMultisigActor(act.Code) {
+			return fmt.Errorf("actor %s is not a multisig actor", maddr)
+		}
+
+		ownID, err := api.ChainAPI.StateLookupID(ctx, maddr, head.Key())
+		if err != nil {
+			return err
+		}
+
+		store := adt.WrapStore(ctx, cbor.NewCborStore(blockstore.NewAPIBlockstore(api.BlockStoreAPI)))
+		mstate, err := multisig.Load(store, act)
+		if err != nil {
+			return err
+		}
+
+		spendable, err := api.MultiSigAPI.MsigGetAvailableBalance(ctx, maddr, head.Key())
+		if err != nil {
+			return err
+		}
+
+		buf := new(bytes.Buffer)
+		writer := NewSilentWriter(buf)
+		writer.Printf("Balance: %s\n", types.FIL(act.Balance))
+		writer.Printf("Spendable: %s\n", types.FIL(spendable))
+
+		if vesting, _ := req.Options["vesting"].(bool); vesting {
+			vs, err := api.MultiSigAPI.MsigGetVestingSchedule(ctx, maddr, head.Key())
+			if err != nil {
+				return err
+			}
+			writer.Printf("InitialBalance: %s\n", types.FIL(vs.InitialBalance))
+			writer.Printf("StartEpoch: %d\n", vs.StartEpoch)
+			writer.Printf("UnlockDuration: %d\n", vs.UnlockDuration)
+		}
+
+		signers, err := mstate.Signers()
+		if err != nil {
+			return err
+		}
+		threshold, err := mstate.Threshold()
+		if err != nil {
+			return err
+		}
+		writer.Printf("Threshold: %d / %d\n", threshold, len(signers))
+		writer.Println("Signers:")
+
+		tw := tabwriter.NewWriter(buf, 8, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(tw, "ID\tAddress\n")
+		for _, s := range signers {
+			signerKey, err := api.ChainAPI.StateAccountKey(ctx, s, head.Key())
+			if err != nil {
+				_, _ = fmt.Fprintf(tw, "%s\t%s\n", s, "N/A")
+			} else {
+				_, _ = fmt.Fprintf(tw, "%s\t%s\n", s, signerKey)
+			}
+		}
+		if err := tw.Flush(); err != nil {
+			return fmt.Errorf("flushing output: %v", err)
+		}
+
+		pending, err := api.MultiSigAPI.MsigGetPending(ctx, maddr, head.Key())
+		if err != nil {
+			return fmt.Errorf("reading pending transactions: %w", err)
+		}
+		sort.Slice(pending, func(i, j int) bool {
+			return pending[i].ID < pending[j].ID
+		})
+
+		writer.Println("Transactions: ", len(pending))
+		if len(pending) > 0 {
+			tw := tabwriter.NewWriter(buf, 8, 4, 2, ' ', 0)
+			_, _ = fmt.Fprintf(tw, "ID\tState\tApprovals\tTo\tValue\tMethod\tParams\n")
+			for _, tx := range pending {
+				target := tx.To.String()
+				if tx.To == ownID {
+					target += " (self)"
+				}
+				_, _ = fmt.Fprintf(tw, "%d\t%s\t%d\t%s\t%s\t%d\t%x\n", tx.ID, "pending", len(tx.Approved), target, types.FIL(tx.Value), tx.Method, tx.Params)
+			}
+			if err := tw.Flush(); err != nil {
+				return fmt.Errorf("flushing output: %v", err)
+			}
+		}
+
+		return re.Emit(buf)
+	},
+}
+
+var msigProposeCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Propose a multisig transaction",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("multisig", true, false, "address of the multisig wallet"),
+		cmds.StringArg("destination", true, false, "recipient of the proposed transaction"),
+		cmds.StringArg("value", true, false, "value to transfer (FIL)"),
+		cmds.StringArg("method", false, false, "method to call in the proposed transaction"),
+		cmds.StringArg("params", false, false, "hex encoded params of the proposed transaction"),
+	},
+	Options: []cmds.Option{
+		msigFromOption,
+		msigConfidenceOption,
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		ctx := req.Context
+		if len(req.Arguments) != 3 && len(req.Arguments) != 5 {
+			return fmt.Errorf("must either pass three or five arguments")
+		}
+
+		msig, err := msigAddress(req, env, 0)
+		if err != nil {
+			return err
+		}
+		dest, err := address.NewFromString(req.Arguments[1])
+		if err != nil {
+			return err
+		}
+		value, err := types.ParseFIL(req.Arguments[2])
+		if err != nil {
+			return err
+		}
+		method, params, err := msigMethodParams(req.Arguments, 3)
+		if err != nil {
+			return err
+		}
+
+		from, err := msigFromAddress(req, env)
+		if err != nil {
+			return err
+		}
+
+		proto, err := env.(*node.Env).MultiSigAPI.MsigPropose(ctx, msig, dest, types.BigInt(value), from, method, params)
+		if err != nil {
+			return err
+		}
+
+		return msigPushProposal(req, re, env, proto)
+	},
+}
+
+var msigApproveCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Approve a multisig message",
+		ShortDescription: `Approve a pending transaction by ID. When the proposer, destination and
+value (and optionally method and params) are given, the approval only succeeds
+if they match the proposed transaction.`,
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("multisig", true, false, "address of the multisig wallet"),
+		cmds.StringArg("txID", true, false, "ID of the proposed transaction"),
+		cmds.StringArg("proposer", false, false, "address of the proposer"),
+		cmds.StringArg("destination", false, false, "recipient of the proposed transaction"),
+		cmds.StringArg("value", false, false, "value of the proposed transaction (FIL)"),
+		cmds.StringArg("method", false, false, "method of the proposed transaction"),
+		cmds.StringArg("params", false, false, "hex encoded params of the proposed transaction"),
+	},
+	Options: []cmds.Option{
+		msigFromOption,
+		msigConfidenceOption,
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		ctx := req.Context
+		nArgs := len(req.Arguments)
+		if nArgs != 2 && nArgs != 5 && nArgs != 7 {
+			return fmt.Errorf("usage: msig approve <msig addr> <txID> [<proposer address> <destination> <value> [<method> <params>]]")
+		}
+
+		msig, err := msigAddress(req, env, 0)
+		if err != nil {
+			return err
+		}
+		txID, err := strconv.ParseUint(req.Arguments[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		from, err := msigFromAddress(req, env)
+		if err != nil {
+			return err
+		}
+
+		var proto *types.MessagePrototype
+		if nArgs == 2 {
+			proto, err = env.(*node.Env).MultiSigAPI.MsigApprove(ctx, msig, txID, from)
+			if err != nil {
+				return err
+			}
+		} else {
+			proposer, err := address.NewFromString(req.Arguments[2])
+			if err != nil {
+				return err
+			}
+			dest, err := address.NewFromString(req.Arguments[3])
+			if err != nil {
+				return err
+			}
+			value, err := types.ParseFIL(req.Arguments[4])
+			if err != nil {
+				return err
+			}
+			method, params, err := msigMethodParams(req.Arguments, 5)
+			if err != nil {
+				return err
+			}
+
+			proto, err = env.(*node.Env).MultiSigAPI.MsigApproveTxnHash(ctx, msig, txID, proposer, dest, types.BigInt(value), from, method, params)
+			if err != nil {
+				return err
+			}
+		}
+
+		if _, err := msigPushAndWait(req, re, env, proto, "approve"); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+var msigCancelCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Cancel a multisig message",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("multisig", true, false, "address of the multisig wallet"),
+		cmds.StringArg("txID", true, false, "ID of the proposed transaction"),
+		cmds.StringArg("destination", false, false, "recipient of the proposed transaction"),
+		cmds.StringArg("value", false, false, "value of the proposed transaction (FIL)"),
+		cmds.StringArg("method", false, false, "method of the proposed transaction"),
+		cmds.StringArg("params", false, false, "hex encoded params of the proposed transaction"),
+	},
+	Options: []cmds.Option{
+		msigFromOption,
+		msigConfidenceOption,
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		ctx := req.Context
+		nArgs := len(req.Arguments)
+		if nArgs != 2 && nArgs != 4 && nArgs != 6 {
+			return fmt.Errorf("usage: msig cancel <msig addr> <txID> [<destination> <value> [<method> <params>]]")
+		}
+
+		msig, err := msigAddress(req, env, 0)
+		if err != nil {
+			return err
+		}
+		txID, err := strconv.ParseUint(req.Arguments[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		from, err := msigFromAddress(req, env)
+		if err != nil {
+			return err
+		}
+
+		var proto *types.MessagePrototype
+		if nArgs == 2 {
+			proto, err = env.(*node.Env).MultiSigAPI.MsigCancel(ctx, msig, txID, from)
+			if err != nil {
+				return err
+			}
+		} else {
+			dest, err := address.NewFromString(req.Arguments[2])
+			if err != nil {
+				return err
+			}
+			value, err := types.ParseFIL(req.Arguments[3])
+			if err != nil {
+				return err
+			}
+			method, params, err := msigMethodParams(req.Arguments, 4)
+			if err != nil {
+				return err
+			}
+
+			proto, err = env.(*node.Env).MultiSigAPI.MsigCancelTxnHash(ctx, msig, txID, dest, types.BigInt(value), from, method, params)
+			if err != nil {
+				return err
+			}
+		}
+
+		if _, err := msigPushAndWait(req, re, env, proto, "cancel"); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+var msigRemoveProposeCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Propose to remove a signer",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("multisig", true, false, "address of the multisig wallet"),
+		cmds.StringArg("signer", true, false, "signer to remove"),
+	},
+	Options: []cmds.Option{
+		cmds.BoolOption("decrease-threshold", "whether the number of required signers should be decreased"),
+		msigFromOption,
+		msigConfidenceOption,
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		msig, err := msigAddress(req, env, 0)
+		if err != nil {
+			return err
+		}
+		signer, err := address.NewFromString(req.Arguments[1])
+		if err != nil {
+			return err
+		}
+		from, err := msigFromAddress(req, env)
+		if err != nil {
+			return err
+		}
+		decrease, _ := req.Options["decrease-threshold"].(bool)
+
+		proto, err := env.(*node.Env).MultiSigAPI.MsigRemoveSigner(req.Context, msig, from, signer, decrease)
+		if err != nil {
+			return err
+		}
+
+		return msigPushProposal(req, re, env, proto)
+	},
+}
+
+var msigAddProposeCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Propose to add a signer",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("multisig", true, false, "address of the multisig wallet"),
+		cmds.StringArg("signer", true, false, "signer to add"),
+	},
+	Options: []cmds.Option{
+		cmds.BoolOption("increase-threshold", "whether the number of required signers should be increased"),
+		msigFromOption,
+		msigConfidenceOption,
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		msig, err := msigAddress(req, env, 0)
+		if err != nil {
+			return err
+		}
+		signer, err := address.NewFromString(req.Arguments[1])
+		if err != nil {
+			return err
+		}
+		from, err := msigFromAddress(req, env)
+		if err != nil {
+			return err
+		}
+		increase, _ := req.Options["increase-threshold"].(bool)
+
+		proto, err := env.(*node.Env).MultiSigAPI.MsigAddPropose(req.Context, msig, from, signer, increase)
+		if err != nil {
+			return err
+		}
+
+		return msigPushProposal(req, re, env, proto)
+	},
+}
+
+var msigAddApproveCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Approve a message to add a signer",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("multisig", true, false, "address of the multisig wallet"),
+		cmds.StringArg("proposer", true, false, "address of the proposer"),
+		cmds.StringArg("txID", true, false, "ID of the proposed transaction"),
+		cmds.StringArg("signer", true, false, "signer to add"),
+		cmds.StringArg("increase-threshold", true, false, "whether the number of required signers should be increased"),
+	},
+	Options: []cmds.Option{
+		msigFromOption,
+		msigConfidenceOption,
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		msig, err := msigAddress(req, env, 0)
+		if err != nil {
+			return err
+		}
+		proposer, err := address.NewFromString(req.Arguments[1])
+		if err != nil {
+			return err
+		}
+		txID, err := strconv.ParseUint(req.Arguments[2], 10, 64)
+		if err != nil {
+			return err
+		}
+		signer, err := address.NewFromString(req.Arguments[3])
+		if err != nil {
+			return err
+		}
+		increase, err := strconv.ParseBool(req.Arguments[4])
+		if err != nil {
+			return err
+		}
+		from, err := msigFromAddress(req, env)
+		if err != nil {
+			return err
+		}
+
+		proto, err := env.(*node.Env).MultiSigAPI.MsigAddApprove(req.Context, msig, from, txID, proposer, signer, increase)
+		if err != nil {
+			return err
+		}
+
+		_, err = msigPushAndWait(req, re, env, proto, "add approval")
+		return err
+	},
+}
+
+var msigAddCancelCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Cancel a message to add a signer",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("multisig", true, false, "address of the multisig wallet"),
+		cmds.StringArg("txID", true, false, "ID of the proposed transaction"),
+		cmds.StringArg("signer", true, false, "signer to add"),
+		cmds.StringArg("increase-threshold", true, false, "whether the number of required signers should be increased"),
+	},
+	Options: []cmds.Option{
+		msigFromOption,
+		msigConfidenceOption,
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		msig, err := msigAddress(req, env, 0)
+		if err != nil {
+			return err
+		}
+		txID, err := strconv.ParseUint(req.Arguments[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		signer, err := address.NewFromString(req.Arguments[2])
+		if err != nil {
+			return err
+		}
+		increase, err := strconv.ParseBool(req.Arguments[3])
+		if err != nil {
+			return err
+		}
+		from, err := msigFromAddress(req, env)
+		if err != nil {
+			return err
+		}
+
+		proto, err := env.(*node.Env).MultiSigAPI.MsigAddCancel(req.Context, msig, from, txID, signer, increase)
+		if err != nil {
+			return err
+		}
+
+		_, err = msigPushAndWait(req, re, env, proto, "add cancellation")
+		return err
+	},
+}
+
+var msigSwapProposeCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Propose to swap signers",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("multisig", true, false, "address of the multisig wallet"),
+		cmds.StringArg("old", true, false, "signer to remove"),
+		cmds.StringArg("new", true, false, "signer to add"),
+	},
+	Options: []cmds.Option{
+		msigFromOption,
+		msigConfidenceOption,
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		msig, err := msigAddress(req, env, 0)
+		if err != nil {
+			return err
+		}
+		oldAddr, err := address.NewFromString(req.Arguments[1])
+		if err != nil {
+			return err
+		}
+		newAddr, err := address.NewFromString(req.Arguments[2])
+		if err != nil {
+			return err
+		}
+		from, err := msigFromAddress(req, env)
+		if err != nil {
+			return err
+		}
+
+		proto, err := env.(*node.Env).MultiSigAPI.MsigSwapPropose(req.Context, msig, from, oldAddr, newAddr)
+		if err != nil {
+			return err
+		}
+
+		return msigPushProposal(req, re, env, proto)
+	},
+}
+
+var msigSwapApproveCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Approve a message to swap signers",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("multisig", true, false, "address of the multisig wallet"),
+		cmds.StringArg("proposer", true, false, "address of the proposer"),
+		cmds.StringArg("txID", true, false, "ID of the proposed transaction"),
+		cmds.StringArg("old", true, false, "signer to remove"),
+		cmds.StringArg("new", true, false, "signer to add"),
+	},
+	Options: []cmds.Option{
+		msigFromOption,
+		msigConfidenceOption,
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		msig, err := msigAddress(req, env, 0)
+		if err != nil {
+			return err
+		}
+		proposer, err := address.NewFromString(req.Arguments[1])
+		if err != nil {
+			return err
+		}
+		txID, err := strconv.ParseUint(req.Arguments[2], 10, 64)
+		if err != nil {
+			return err
+		}
+		oldAddr, err := address.NewFromString(req.Arguments[3])
+		if err != nil {
+			return err
+		}
+		newAddr, err := address.NewFromString(req.Arguments[4])
+		if err != nil {
+			return err
+		}
+		from, err := msigFromAddress(req, env)
+		if err != nil {
+			return err
+		}
+
+		proto, err := env.(*node.Env).MultiSigAPI.MsigSwapApprove(req.Context, msig, from, txID, proposer, oldAddr, newAddr)
+		if err != nil {
+			return err
+		}
+
+		_, err = msigPushAndWait(req, re, env, proto, "swap approval")
+		return err
+	},
+}
+
+var msigSwapCancelCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Cancel a message to swap signers",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("multisig", true, false, "address of the multisig wallet"),
+		cmds.StringArg("txID", true, false, "ID of the proposed transaction"),
+		cmds.StringArg("old", true, false, "signer to remove"),
+		cmds.StringArg("new", true, false, "signer to add"),
+	},
+	Options: []cmds.Option{
+		msigFromOption,
+		msigConfidenceOption,
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		msig, err := msigAddress(req, env, 0)
+		if err != nil {
+			return err
+		}
+		txID, err := strconv.ParseUint(req.Arguments[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		oldAddr, err := address.NewFromString(req.Arguments[2])
+		if err != nil {
+			return err
+		}
+		newAddr, err := address.NewFromString(req.Arguments[3])
+		if err != nil {
+			return err
+		}
+		from, err := msigFromAddress(req, env)
+		if err != nil {
+			return err
+		}
+
+		proto, err := env.(*node.Env).MultiSigAPI.MsigSwapCancel(req.Context, msig, from, txID, oldAddr, newAddr)
+		if err != nil {
+			return err
+		}
+
+		_, err = msigPushAndWait(req, re, env, proto, "swap cancellation")
+		return err
+	},
+}
+
+var msigVestedCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Gets the amount vested in an msig between two epochs",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("multisig", true, false, "address of the multisig wallet"),
+	},
+	Options: []cmds.Option{
+		cmds.Int64Option("start-epoch", "start epoch to measure vesting from").WithDefault(int64(0)),
+		cmds.Int64Option("end-epoch", "end epoch to stop measure vesting at, defaults to the chain head"),
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		ctx := req.Context
+		api := env.(*node.Env)
+		msig, err := address.NewFromString(req.Arguments[0])
+		if err != nil {
+			return err
+		}
+
+		head, err := api.ChainAPI.ChainHead(ctx)
+		if err != nil {
+			return err
+		}
+
+		startEpoch, _ := req.Options["start-epoch"].(int64)
+		start, err := api.ChainAPI.ChainGetTipSetByHeight(ctx, abi.ChainEpoch(startEpoch), head.Key())
+		if err != nil {
+			return err
+		}
+
+		end := head
+		if endEpoch, ok := req.Options["end-epoch"].(int64); ok {
+			end, err = api.ChainAPI.ChainGetTipSetByHeight(ctx, abi.ChainEpoch(endEpoch), head.Key())
+			if err != nil {
+				return err
+			}
+		}
+
+		vested, err := api.MultiSigAPI.MsigGetVested(ctx, msig, start.Key(), end.Key())
+		if err != nil {
+			return err
+		}
+
+		return printOneString(re, fmt.Sprintf("Vested: %s between %d and %d", types.FIL(vested), start.Height(), end.Height()))
+	},
+}
+
+// msigAddress parses the multisig address at argument idx and makes sure it is a multisig actor.
+func msigAddress(req *cmds.Request, env cmds.Environment, idx int) (address.Address, error) {
+	msig, err := address.NewFromString(req.Arguments[idx])
+	if err != nil {
+		return address.Undef, err
+	}
+
+	act, err := env.(*node.Env).ChainAPI.StateGetActor(req.Context, msig, types.EmptyTSK)
+	if err != nil {
+		return address.Undef, fmt.Errorf("failed to look up multisig %s: %w", msig, err)
+	}
+	if !builtin.IsMultisigActor(act.Code) {
+		return address.Undef, fmt.Errorf("actor %s is not a multisig actor", msig)
+	}
+
+	return msig, nil
+}
+
+// msigFromAddress returns the address of the `from` option or the default wallet address.
+func msigFromAddress(req *cmds.Request, env cmds.Environment) (address.Address, error) {
+	if from, _ := req.Options["from"].(string); from != "" {
+		return address.NewFromString(from)
+	}
+	return env.(*node.Env).WalletAPI.WalletDefaultAddress(req.Context)
+}
+
+// msigMethodParams parses the optional method number and hex encoded params starting at argument idx.
+func msigMethodParams(args []string, idx int) (uint64, []byte, error) {
+	if len(args) <= idx {
+		return 0, nil, nil
+	}
+	if len(args) != idx+2 {
+		return 0, nil, fmt.Errorf("method and params must be passed together")
+	}
+
+	method, err := strconv.ParseUint(args[idx], 10, 64)
+	if err != nil {
+		return 0, nil, err
+	}
+	params, err := hex.DecodeString(args[idx+1])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return method, params, nil
+}
+
+// msigPushAndWait pushes the message of proto to the mpool and waits for it to be executed successfully.
+func msigPushAndWait(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment, proto *types.MessagePrototype, action string) (*types.MsgLookup, error) {
+	ctx := req.Context
+	if proto.Message.Value.Nil() {
+		proto.Message.Value = big.Zero()
+	}
+	smsg, err := env.(*node.Env).MessagePoolAPI.MpoolPushMessage(ctx, &proto.Message, nil)
+	if err != nil {
+		return nil, err
+	}
+	_ = re.Emit(fmt.Sprintf("sent %s in message: %s", action, smsg.Cid()))
+
+	confidence, _ := req.Options["confidence"].(uint64)
+	wait, err := env.(*node.Env).ChainAPI.StateWaitMsg(ctx, smsg.Cid(), confidence, policy.ChainFinality, true)
+	if err != nil {
+		return nil, err
+	}
+	if wait.Receipt.ExitCode.IsError() {
+		return nil, fmt.Errorf("%s returned exit %d", action, wait.Receipt.ExitCode)
+	}
+
+	return wait, nil
+}
+
+// msigPushProposal pushes a proposal and prints the ID of the proposed transaction.
+func msigPushProposal(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment, proto *types.MessagePrototype) error {
+	wait, err := msigPushAndWait(req, re, env, proto, "proposal")
+	if err != nil {
+		return err
+	}
+
+	var retval types.ProposeReturn
+	if err := retval.UnmarshalCBOR(bytes.NewReader(wait.Receipt.Return)); err != nil {
+		return fmt.Errorf("failed to unmarshal propose return value: %w", err)
+	}
+
+	buf := new(bytes.Buffer)
+	writer := NewSilentWriter(buf)
+	writer.Printf("Transaction ID: %d\n", retval.TxnID)
+	if retval.Applied {
+		writer.Println("Transaction was executed during propose")
+		writer.Printf("Exit Code: %d\n", retval.Code)
+		writer.Printf("Return Value: %x\n", retval.Ret)
+	}
+
+	return re.Emit(buf)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+
+	"github.com/filecoin-project/venus/app/node"
+)
+
+var chainSplitStoreCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Manage the hot/cold splitstore",
+	},
+	Subcommands: map[string]*cmds.Command{
+		"info":    splitStoreInfoCmd,
+		"compact": splitStoreCompactCmd,
+	},
+}
+
+var splitStoreInfoCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Show the splitstore state and the progress of the compaction",
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		info, err := env.(*node.Env).BlockStoreAPI.ChainSplitStoreInfo(req.Context)
+		if err != nil {
+			return err
+		}
+
+		buf := new(bytes.Buffer)
+		writer := NewSilentWriter(buf)
+		writer.Printf("Cold store type:       %s\n", info.ColdStoreType)
+		writer.Printf("Hot store retention:   %d epochs\n", info.HotStoreRetention)
+		writer.Printf("Last compaction epoch: %d\n", info.BaseEpoch)
+		writer.Printf("Compacting:            %t\n", info.Compacting)
+		writer.Printf("Phase:                 %s\n", info.Phase)
+		writer.Printf("Marked:                %d\n", info.Marked)
+		writer.Printf("Moved:                 %d\n", info.Moved)
+		writer.Printf("Purged:                %d\n", info.Purged)
+		if !info.LastCompactionStart.IsZero() {
+			writer.Printf("Last compaction start: %s\n", info.LastCompactionStart.Format("2006-01-02 15:04:05"))
+			writer.Printf("Last compaction took:  %s\n", info.LastCompactionDuration)
+		}
+		if info.LastError != "" {
+			writer.Printf("Last error:            %s\n", info.LastError)
+		}
+
+		return re.Emit(buf)
+	},
+}
+
+var splitStoreCompactCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline:          "Start a splitstore compaction at the current head",
+		ShortDescription: "The compaction runs in the background, use `venus chain splitstore info` to watch its progress.",
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		if err := env.(*node.Env).BlockStoreAPI.ChainSplitStoreCompact(req.Context); err != nil {
+			return err
+		}
+
+		return printOneString(re, "compaction started")
+	},
+}
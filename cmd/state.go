@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"sort"
 	"strconv"
+	"text/tabwriter"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
@@ -56,6 +57,7 @@ var stateCmd = &cmds.Command{
 		"actor-cids":     stateSysActorCIDsCmd,
 		"replay":         stateReplayCmd,
 		"compute-state":  StateComputeStateCmd,
+		"diff":           stateDiffCmd,
 	},
 }
 
@@ -864,3 +866,99 @@ var StateComputeStateCmd = &cmds.Command{
 		return re.Emit(buf)
 	},
 }
+
+var stateDiffCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Print the actors that differ between the states of two tipsets",
+		ShortDescription: `
+Print the actors added, removed and modified between the parent states of two
+tipsets, with their balance and nonce deltas. The tipsets are given as comma
+separated block cids, or as @<height> or @head.
+`,
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("from", true, false, "the first tipset"),
+		cmds.StringArg("to", true, false, "the second tipset"),
+	},
+	Options: []cmds.Option{
+		cmds.BoolOption("decode", "also diff the states of the miner, market and power actors"),
+		cmds.BoolOption("json", "generate json output"),
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		ctx := req.Context
+		chainAPI := getEnv(env).ChainAPI
+
+		from, err := ParseTipSetRef(ctx, chainAPI, req.Arguments[0])
+		if err != nil {
+			return err
+		}
+		to, err := ParseTipSetRef(ctx, chainAPI, req.Arguments[1])
+		if err != nil {
+			return err
+		}
+
+		decode, _ := req.Options["decode"].(bool)
+		diff, err := chainAPI.StateDiff(ctx, from.Key(), to.Key(), types.StateDiffOpts{DecodeStates: decode})
+		if err != nil {
+			return err
+		}
+
+		buf := &bytes.Buffer{}
+		writer := NewSilentWriter(buf)
+
+		if ok, _ := req.Options["json"].(bool); ok {
+			out, err := json.MarshalIndent(diff, "", "  ")
+			if err != nil {
+				return err
+			}
+			writer.Println(string(out))
+			return re.Emit(buf)
+		}
+
+		writer.Printf("from %s (%d) to %s (%d)\n", diff.From, from.Height(), diff.To, to.Height())
+		writer.Printf("added: %d, removed: %d, modified: %d\n\n", len(diff.Added), len(diff.Removed), len(diff.Modified))
+
+		tw := tabwriter.NewWriter(buf, 4, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "Change\tAddress\tActor\tBalance\tNonce\tState\n")
+		print := func(change string, diffs []*types.ActorDiff) {
+			for _, d := range diffs {
+				act := d.To
+				if act == nil {
+					act = d.From
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%+d\t%s\n", change, d.Address, builtin.ActorNameByCode(act.Code),
+					types.FIL(d.BalanceDelta).Short(), d.NonceDelta, stateDiffSummary(d))
+			}
+		}
+		print("+", diff.Added)
+		print("-", diff.Removed)
+		print("~", diff.Modified)
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		return re.Emit(buf)
+	},
+}
+
+// stateDiffSummary summarizes the decoded changes of the state of an actor.
+func stateDiffSummary(d *types.ActorDiff) string {
+	switch {
+	case d.Miner != nil:
+		var partitions int
+		for _, dl := range d.Miner.Deadlines {
+			partitions += len(dl)
+		}
+		return fmt.Sprintf("sectors +%d ~%d -%d, precommits +%d -%d, partitions ~%d",
+			len(d.Miner.Sectors.Added), len(d.Miner.Sectors.Extended), len(d.Miner.Sectors.Removed),
+			len(d.Miner.PreCommits.Added), len(d.Miner.PreCommits.Removed), partitions)
+	case d.Market != nil:
+		return fmt.Sprintf("proposals +%d -%d, deal states +%d ~%d -%d",
+			len(d.Market.Proposals.Added), len(d.Market.Proposals.Removed),
+			len(d.Market.States.Added), len(d.Market.States.Modified), len(d.Market.States.Removed))
+	case d.Power != nil:
+		return fmt.Sprintf("claims +%d ~%d -%d", len(d.Power.Claims.Added), len(d.Power.Claims.Modified), len(d.Power.Claims.Removed))
+	default:
+		return ""
+	}
+}
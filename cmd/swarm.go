@@ -15,6 +15,7 @@ import (
 	"github.com/ipfs/go-cid"
 	cmds "github.com/ipfs/go-ipfs-cmds"
 	logging "github.com/ipfs/go-log/v2"
+	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/protocol"
 	"github.com/libp2p/go-libp2p/core/routing"
@@ -22,6 +23,7 @@ import (
 
 	"github.com/filecoin-project/venus/app/node"
 	"github.com/filecoin-project/venus/pkg/net"
+	"github.com/filecoin-project/venus/venus-shared/types"
 )
 
 const (
@@ -56,6 +58,9 @@ libp2p peers on the internet.
 		"unprotect":      protectRemoveCmd,
 		"list-protected": protectListCmd,
 		"scores":         swarmScoresCmd,
+		"block":          swarmBlockCmd,
+		"stat":           swarmStatCmd,
+		"limit":          swarmLimitCmd,
 	},
 }
 
@@ -766,3 +771,253 @@ func decode(idd map[string]*json.RawMessage, key string, dest interface{}) error
 	}
 	return nil
 }
+
+var swarmStatCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Print the resources used by the scopes of the resource manager",
+		ShortDescription: `
+The scope is one of:
+  - all            every scope in use (default)
+  - system         the whole node
+  - transient      the streams and connections not yet attached to a protocol or a peer
+  - svc:<service>  a libp2p service, e.g. svc:libp2p.identify
+  - proto:<id>     a protocol, e.g. proto:/fil/hello/1.0.0
+  - peer:<id>      a peer
+`,
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("scope", false, false, "resource scope"),
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		scope := net.ScopeAll
+		if len(req.Arguments) > 0 {
+			scope = req.Arguments[0]
+		}
+
+		stat, err := env.(*node.Env).NetworkAPI.NetStat(req.Context, scope)
+		if err != nil {
+			return err
+		}
+
+		buf := &bytes.Buffer{}
+		tw := tabwriter.NewWriter(buf, 4, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "Scope\tMemory\tStreamsIn\tStreamsOut\tConnsIn\tConnsOut\tFD\n")
+		printStat := func(name string, s network.ScopeStat) {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%d\n", name, humanize.IBytes(uint64(s.Memory)),
+				s.NumStreamsInbound, s.NumStreamsOutbound, s.NumConnsInbound, s.NumConnsOutbound, s.NumFD)
+		}
+		printStats := func(prefix string, stats map[string]network.ScopeStat) {
+			names := make([]string, 0, len(stats))
+			for name := range stats {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				printStat(prefix+name, stats[name])
+			}
+		}
+
+		if stat.System != nil {
+			printStat(net.ScopeSystem, *stat.System)
+		}
+		if stat.Transient != nil {
+			printStat(net.ScopeTransient, *stat.Transient)
+		}
+		printStats(net.ScopeServicePrefix, stat.Services)
+		printStats(net.ScopeProtocolPrefix, stat.Protocols)
+		printStats(net.ScopePeerPrefix, stat.Peers)
+
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		return re.Emit(buf)
+	},
+}
+
+var swarmLimitCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Print or change the limit of a scope of the resource manager",
+		ShortDescription: `
+The scope is one of system, transient, svc:<service>, proto:<id> or peer:<id>, see 'venus swarm stat'.
+The limit is changed when any of the options is set, the other values are kept. A value of -1
+removes the limit. The limits set here are lost when the node restarts, use the
+swarm.resourceManager section of the config to keep them.
+`,
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("scope", true, false, "resource scope"),
+	},
+	Options: []cmds.Option{
+		cmds.Int64Option("memory", "memory limit, in bytes"),
+		cmds.IntOption("streams", "total stream limit"),
+		cmds.IntOption("streams-inbound", "inbound stream limit"),
+		cmds.IntOption("streams-outbound", "outbound stream limit"),
+		cmds.IntOption("conns", "total connection limit"),
+		cmds.IntOption("conns-inbound", "inbound connection limit"),
+		cmds.IntOption("conns-outbound", "outbound connection limit"),
+		cmds.IntOption("fd", "file descriptor limit"),
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		ctx := req.Context
+		netAPI := env.(*node.Env).NetworkAPI
+		scope := req.Arguments[0]
+
+		var limit types.NetLimit
+		set := false
+		for name, field := range map[string]*int{
+			"streams":          &limit.Streams,
+			"streams-inbound":  &limit.StreamsInbound,
+			"streams-outbound": &limit.StreamsOutbound,
+			"conns":            &limit.Conns,
+			"conns-inbound":    &limit.ConnsInbound,
+			"conns-outbound":   &limit.ConnsOutbound,
+			"fd":               &limit.FD,
+		} {
+			if v, ok := req.Options[name].(int); ok {
+				*field = v
+				set = true
+			}
+		}
+		if v, ok := req.Options["memory"].(int64); ok {
+			limit.Memory = v
+			set = true
+		}
+
+		if set {
+			if err := netAPI.NetSetLimit(ctx, scope, limit); err != nil {
+				return err
+			}
+		}
+
+		limit, err := netAPI.NetLimit(ctx, scope)
+		if err != nil {
+			return err
+		}
+
+		buf := new(bytes.Buffer)
+		writer := NewSilentWriter(buf)
+		writer.Printf("Memory: %s\n", humanize.IBytes(uint64(limit.Memory)))
+		writer.Printf("Streams: %d (inbound %d, outbound %d)\n", limit.Streams, limit.StreamsInbound, limit.StreamsOutbound)
+		writer.Printf("Conns: %d (inbound %d, outbound %d)\n", limit.Conns, limit.ConnsInbound, limit.ConnsOutbound)
+		writer.Printf("FD: %d\n", limit.FD)
+
+		return re.Emit(buf)
+	},
+}
+
+var swarmBlockCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Manage the blocked peers, ip addresses and subnets",
+		ShortDescription: `
+The connections of the blocked peers, ip addresses and subnets are rejected, the blocklist is
+kept across restarts. The peers with a gossipsub score below the graylist threshold are
+rejected too.
+`,
+	},
+	Subcommands: map[string]*cmds.Command{
+		"add": {
+			Helptext: cmds.HelpText{
+				Tagline: "Block peers, ip addresses or subnets and close their connections",
+			},
+			Subcommands: map[string]*cmds.Command{
+				"peer":   swarmBlockEditCmd("peer", false),
+				"ip":     swarmBlockEditCmd("ip", false),
+				"subnet": swarmBlockEditCmd("subnet", false),
+			},
+		},
+		"remove": {
+			Helptext: cmds.HelpText{
+				Tagline: "Unblock peers, ip addresses or subnets",
+			},
+			Subcommands: map[string]*cmds.Command{
+				"peer":   swarmBlockEditCmd("peer", true),
+				"ip":     swarmBlockEditCmd("ip", true),
+				"subnet": swarmBlockEditCmd("subnet", true),
+			},
+		},
+		"list": swarmBlockListCmd,
+	},
+}
+
+// swarmBlockEditCmd returns the command blocking, or unblocking when remove is set, the peers,
+// ip addresses or subnets given as arguments.
+func swarmBlockEditCmd(kind string, remove bool) *cmds.Command {
+	tagline := fmt.Sprintf("Block one or more %ss", kind)
+	if remove {
+		tagline = fmt.Sprintf("Unblock one or more %ss", kind)
+	}
+	argDesc := map[string]string{
+		"peer":   "peer ids",
+		"ip":     "ip addresses",
+		"subnet": "subnets in CIDR notation, e.g. 10.0.0.0/8",
+	}[kind]
+
+	return &cmds.Command{
+		Helptext: cmds.HelpText{
+			Tagline: tagline,
+		},
+		Arguments: []cmds.Argument{
+			cmds.StringArg(kind+"s", true, true, argDesc),
+		},
+		Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+			ctx := req.Context
+			netAPI := env.(*node.Env).NetworkAPI
+
+			var acl types.NetBlockList
+			switch kind {
+			case "peer":
+				pids, err := decodePeerIDsFromArgs(req)
+				if err != nil {
+					return err
+				}
+				acl.Peers = pids
+			case "ip":
+				acl.IPAddrs = req.Arguments
+			case "subnet":
+				acl.IPSubnets = req.Arguments
+			}
+
+			if remove {
+				if err := netAPI.NetBlockRemove(ctx, acl); err != nil {
+					return err
+				}
+				return printOneString(re, fmt.Sprintf("unblocked %d %ss", len(req.Arguments), kind))
+			}
+
+			if err := netAPI.NetBlockAdd(ctx, acl); err != nil {
+				return err
+			}
+			return printOneString(re, fmt.Sprintf("blocked %d %ss", len(req.Arguments), kind))
+		},
+	}
+}
+
+var swarmBlockListCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "List the blocked peers, ip addresses and subnets",
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		acl, err := env.(*node.Env).NetworkAPI.NetBlockList(req.Context)
+		if err != nil {
+			return err
+		}
+
+		buf := new(bytes.Buffer)
+		writer := NewSilentWriter(buf)
+		writer.Println("Peers:")
+		for _, pid := range acl.Peers {
+			writer.Printf("\t%s\n", pid)
+		}
+		writer.Println("IP addresses:")
+		for _, ip := range acl.IPAddrs {
+			writer.Printf("\t%s\n", ip)
+		}
+		writer.Println("IP subnets:")
+		for _, subnet := range acl.IPSubnets {
+			writer.Printf("\t%s\n", subnet)
+		}
+
+		return re.Emit(buf)
+	},
+}
@@ -3,8 +3,12 @@ package cmd
 
 import (
 	"bytes"
+	"fmt"
 	"strconv"
 
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+
 	"github.com/filecoin-project/venus/venus-shared/types"
 
 	cmds "github.com/ipfs/go-ipfs-cmds"
@@ -21,6 +25,10 @@ var syncCmd = &cmds.Command{
 		"history":        historyCmd,
 		"concurrent":     getConcurrent,
 		"set-concurrent": setConcurrent,
+		"mark-bad":       syncMarkBadCmd,
+		"unmark-bad":     syncUnmarkBadCmd,
+		"check-bad":      syncCheckBadCmd,
+		"checkpoint":     syncCheckpointCmd,
 	},
 }
 
@@ -159,3 +167,114 @@ var historyCmd = &cmds.Command{
 		return re.Emit(w)
 	},
 }
+
+var syncCheckpointCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Mark a certain tipset as checkpointed, the node will never fork away from it",
+		ShortDescription: `The tipset is fetched from the network if it is missing and the chain is
+switched to it unless the current head already descends from it. The checkpoint
+is kept when the node restarts.`,
+	},
+	Options: []cmds.Option{
+		cmds.Int64Option("epoch", "checkpoint the tipset at the given epoch of the current chain"),
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("tipset", false, false, "comma separated block cids of the tipset"),
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		var tsk types.TipSetKey
+		if epoch, ok := req.Options["epoch"].(int64); ok {
+			ts, err := env.(*node.Env).ChainAPI.ChainGetTipSetByHeight(req.Context, abi.ChainEpoch(epoch), types.EmptyTSK)
+			if err != nil {
+				return err
+			}
+			tsk = ts.Key()
+		} else {
+			if len(req.Arguments) != 1 {
+				return cmds.ClientError("must specify tipset to checkpoint or --epoch")
+			}
+			cids, err := ParseTipSetString(req.Arguments[0])
+			if err != nil {
+				return err
+			}
+			tsk = types.NewTipSetKey(cids...)
+		}
+
+		if err := env.(*node.Env).SyncerAPI.SyncCheckpoint(req.Context, tsk); err != nil {
+			return err
+		}
+
+		return printOneString(re, fmt.Sprintf("Checkpoint set to %s", tsk))
+	},
+}
+
+var syncMarkBadCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline:          "Mark the given block as bad, will prevent syncing to a chain that contains it",
+		ShortDescription: "Use with extreme caution, the descendants of the block are rejected as well.",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("cid", true, false, "CID of the block to mark"),
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		bcid, err := cid.Decode(req.Arguments[0])
+		if err != nil {
+			return fmt.Errorf("failed to decode input as a cid: %w", err)
+		}
+
+		return env.(*node.Env).SyncerAPI.SyncMarkBad(req.Context, bcid)
+	},
+}
+
+var syncUnmarkBadCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Unmark the given block as bad, makes it possible to sync to a chain containing it",
+	},
+	Options: []cmds.Option{
+		cmds.BoolOption("all", "drop the entire bad block cache"),
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("cid", false, false, "CID of the block to unmark"),
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		api := env.(*node.Env).SyncerAPI
+		if all, _ := req.Options["all"].(bool); all {
+			return api.SyncUnmarkAllBad(req.Context)
+		}
+
+		if len(req.Arguments) != 1 {
+			return cmds.ClientError("must specify block cid to unmark")
+		}
+		bcid, err := cid.Decode(req.Arguments[0])
+		if err != nil {
+			return fmt.Errorf("failed to decode input as a cid: %w", err)
+		}
+
+		return api.SyncUnmarkBad(req.Context, bcid)
+	},
+}
+
+var syncCheckBadCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline: "Check if the given block was marked bad, and for what reason",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("cid", true, false, "CID of the block to check"),
+	},
+	Run: func(req *cmds.Request, re cmds.ResponseEmitter, env cmds.Environment) error {
+		bcid, err := cid.Decode(req.Arguments[0])
+		if err != nil {
+			return fmt.Errorf("failed to decode input as a cid: %w", err)
+		}
+
+		reason, err := env.(*node.Env).SyncerAPI.SyncCheckBad(req.Context, bcid)
+		if err != nil {
+			return err
+		}
+		if reason == "" {
+			return printOneString(re, "block was not marked as bad")
+		}
+
+		return printOneString(re, reason)
+	},
+}
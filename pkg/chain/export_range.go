@@ -0,0 +1,321 @@
+package chain
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"github.com/ipld/go-car"
+	carutil "github.com/ipld/go-car/util"
+	"github.com/multiformats/go-multicodec"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// cidVisitor records the visited cids, Visit returns false for the ones already visited.
+type cidVisitor interface {
+	Visit(cid.Cid) bool
+}
+
+// syncCidSet is a cid set shared by the walkers of a range export.
+type syncCidSet struct {
+	lk  sync.Mutex
+	set *cid.Set
+}
+
+func newSyncCidSet() *syncCidSet {
+	return &syncCidSet{set: cid.NewSet()}
+}
+
+func (s *syncCidSet) Visit(c cid.Cid) bool {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	return s.set.Visit(c)
+}
+
+// exportSection is the objects of a tipset of a range export, its block headers last.
+type exportSection struct {
+	cids []cid.Cid
+	err  error
+}
+
+// ExportRange writes a car of the tipsets from head down to the height tail to w. The tipsets are
+// walked by opts.Workers in parallel but written in order, each one as its messages, receipts and
+// state not written yet followed by its block headers, so that an interrupted export can be resumed
+// from the parents of the last tipset it completed, see ScanExportRange.
+func (store *Store) ExportRange(ctx context.Context, w io.Writer, head *types.TipSet, tail abi.ChainEpoch, opts types.ChainExportRangeOpts) error {
+	if tail < 0 {
+		tail = 0
+	}
+	if tail > head.Height() {
+		return fmt.Errorf("tail %d is above head %d", tail, head.Height())
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	if !opts.SkipHeader {
+		h := &car.CarHeader{
+			Roots:   head.Cids(),
+			Version: 1,
+		}
+		if err := car.WriteHeader(h, w); err != nil {
+			return fmt.Errorf("failed to write car header: %s", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	type job struct {
+		ts  *types.TipSet
+		out chan exportSection
+	}
+	jobs := make(chan job)
+	// the sections to write in order, at most workers of them are walked ahead of the writer
+	pending := make(chan chan exportSection, workers)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		defer close(pending)
+
+		ts := head
+		for {
+			out := make(chan exportSection, 1)
+			select {
+			case pending <- out:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- job{ts: ts, out: out}:
+			case <-ctx.Done():
+				return
+			}
+			if ts.Height() <= tail {
+				return
+			}
+
+			next, err := store.GetTipSet(ctx, ts.Parents())
+			if err != nil {
+				out := make(chan exportSection, 1)
+				out <- exportSection{err: fmt.Errorf("loading tipset %s: %w", ts.Parents(), err)}
+				select {
+				case pending <- out:
+				case <-ctx.Done():
+				}
+				return
+			}
+			ts = next
+		}
+	}()
+
+	walked := newSyncCidSet()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				cids, err := store.exportTipSet(ctx, j.ts, walked, opts)
+				j.out <- exportSection{cids: cids, err: err}
+			}
+		}()
+	}
+
+	log.Infow("range export started", "head", head.Height(), "tail", tail, "workers", workers)
+	exportStart := constants.Clock.Now()
+
+	for out := range pending {
+		var section exportSection
+		select {
+		case section = <-out:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if section.err != nil {
+			return section.err
+		}
+
+		for _, c := range section.cids {
+			blk, err := store.bsstore.Get(ctx, c)
+			if err != nil {
+				return fmt.Errorf("writing object to car, bs.Get: %w", err)
+			}
+			if err := carutil.LdWrite(w, c.Bytes(), blk.RawData()); err != nil {
+				return fmt.Errorf("failed to write block to car output: %w", err)
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	log.Infow("range export finished", "duration", constants.Clock.Now().Sub(exportStart).Seconds())
+
+	return nil
+}
+
+// exportTipSet returns the objects of ts not walked yet and its block headers.
+func (store *Store) exportTipSet(ctx context.Context, ts *types.TipSet, walked cidVisitor, opts types.ChainExportRangeOpts) ([]cid.Cid, error) {
+	var cids []cid.Cid
+	walk := func(root cid.Cid) error {
+		if !walked.Visit(root) {
+			return nil
+		}
+		var err error
+		cids, err = recurseLinks(ctx, store.bsstore, walked, root, append(cids, root))
+		if err != nil {
+			return err
+		}
+		return ctx.Err()
+	}
+
+	for _, b := range ts.Blocks() {
+		if opts.Messages {
+			if err := walk(b.Messages); err != nil {
+				return nil, fmt.Errorf("recursing messages failed: %w", err)
+			}
+		}
+		if opts.Receipts {
+			if err := walk(b.ParentMessageReceipts); err != nil {
+				return nil, fmt.Errorf("recursing receipts failed: %w", err)
+			}
+		}
+		// the genesis state is always included
+		if opts.StateRoots || b.Height == 0 {
+			if err := walk(b.ParentStateRoot); err != nil {
+				return nil, fmt.Errorf("recursing state failed: %w", err)
+			}
+		}
+		if b.Height == 0 {
+			for _, p := range b.Parents {
+				if walked.Visit(p) {
+					cids = append(cids, p)
+				}
+			}
+		}
+	}
+
+	out := make([]cid.Cid, 0, len(cids)+len(ts.Blocks()))
+	for _, c := range cids {
+		prefix := c.Prefix()
+
+		// Don't include identity CIDs.
+		if multicodec.Code(prefix.MhType) == multicodec.Identity {
+			continue
+		}
+
+		// We only include raw, cbor, and dagcbor, for now.
+		switch multicodec.Code(prefix.Codec) {
+		case multicodec.Cbor, multicodec.DagCbor, multicodec.Raw:
+		default:
+			continue
+		}
+
+		out = append(out, c)
+	}
+
+	return append(out, ts.Cids()...), nil
+}
+
+// ExportRangeProgress is how far a range export written to a car went.
+type ExportRangeProgress struct {
+	// Offset is the end of the last complete tipset in the car
+	Offset int64
+	// Height is the height of the last complete tipset
+	Height abi.ChainEpoch
+	// Next is the key of the parent of the last complete tipset, to resume the export from
+	Next types.TipSetKey
+}
+
+// ScanExportRange reads a range export, which may have been interrupted, and returns the last
+// tipset it completed, or nil when none. A tipset is complete once an object following its block
+// headers is read, so the last tipset of a car is never reported.
+func ScanExportRange(r io.Reader) (*ExportRangeProgress, error) {
+	br := bufio.NewReader(r)
+
+	hb, err := carutil.LdRead(br)
+	if err != nil {
+		return nil, fmt.Errorf("reading car header: %w", err)
+	}
+	var h car.CarHeader
+	if err := cbor.DecodeInto(hb, &h); err != nil {
+		return nil, fmt.Errorf("invalid car header: %w", err)
+	}
+	offset := ldSize(hb)
+
+	var progress, current *ExportRangeProgress
+	for {
+		data, err := carutil.LdRead(br)
+		if err != nil {
+			// the end of the car, or the section it was interrupted in
+			break
+		}
+		offset += ldSize(data)
+
+		bh, ok := decodeExportedHeader(data)
+		if !ok || (current != nil && current.Height != bh.Height) {
+			if current != nil {
+				progress = current
+				current = nil
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		if current == nil {
+			current = &ExportRangeProgress{
+				Height: bh.Height,
+				Next:   types.NewTipSetKey(bh.Parents...),
+			}
+		}
+		current.Offset = offset
+	}
+
+	return progress, nil
+}
+
+// ldSize returns the size of a length delimited section of a car.
+func ldSize(data []byte) int64 {
+	var buf [binary.MaxVarintLen64]byte
+	return int64(binary.PutUvarint(buf[:], uint64(len(data))) + len(data))
+}
+
+// decodeExportedHeader decodes a section of a car holding a block header.
+func decodeExportedHeader(data []byte) (*types.BlockHeader, bool) {
+	n, c, err := cid.CidFromBytes(data)
+	if err != nil || multicodec.Code(c.Prefix().Codec) != multicodec.DagCbor {
+		return nil, false
+	}
+	raw := data[n:]
+	// block headers are cbor arrays of 16 fields
+	if len(raw) == 0 || raw[0] != 0x90 {
+		return nil, false
+	}
+
+	var bh types.BlockHeader
+	if err := bh.UnmarshalCBOR(bytes.NewReader(raw)); err != nil {
+		return nil, false
+	}
+	if !bh.Cid().Equals(c) {
+		return nil, false
+	}
+	return &bh, true
+}
@@ -0,0 +1,97 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	carv2 "github.com/ipld/go-car/v2"
+	"github.com/stretchr/testify/require"
+
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// exportedHeights returns the heights of the block headers of a car, by block.
+func exportedHeights(t *testing.T, data []byte) (map[abi.ChainEpoch]int, int) {
+	br, err := carv2.NewBlockReader(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	heights := make(map[abi.ChainEpoch]int)
+	var objects int
+	for {
+		blk, err := br.Next()
+		if err != nil {
+			break
+		}
+		objects++
+		if bh, ok := decodeExportedHeader(append(blk.Cid().Bytes(), blk.RawData()...)); ok {
+			heights[bh.Height]++
+		}
+	}
+	return heights, objects
+}
+
+func TestExportRange(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	builder := NewBuilder(t, address.Undef)
+	head := builder.AppendManyOn(ctx, 4, builder.Genesis())
+	head = builder.AppendOn(ctx, head, 2)
+	head = builder.AppendManyOn(ctx, 4, head)
+	store := builder.Store()
+
+	opts := types.ChainExportRangeOpts{
+		Workers:    3,
+		Messages:   true,
+		Receipts:   true,
+		StateRoots: true,
+	}
+	tail := head.Height() - 6
+
+	var full bytes.Buffer
+	require.NoError(t, store.ExportRange(ctx, &full, head, tail, opts))
+
+	br, err := carv2.NewBlockReader(bytes.NewReader(full.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, head.Cids(), br.Roots)
+
+	heights, objects := exportedHeights(t, full.Bytes())
+	require.Len(t, heights, 7)
+	for ts := head; ts.Height() >= tail; {
+		require.Equal(t, ts.Len(), heights[ts.Height()])
+		if ts.Height() == tail {
+			break
+		}
+		ts, err = store.GetTipSet(ctx, ts.Parents())
+		require.NoError(t, err)
+	}
+
+	// the last tipset of the car is not known to be complete
+	progress, err := ScanExportRange(bytes.NewReader(full.Bytes()))
+	require.NoError(t, err)
+	require.NotNil(t, progress)
+	require.Equal(t, tail+1, progress.Height)
+
+	// resume an export interrupted in the middle of a tipset
+	progress, err = ScanExportRange(bytes.NewReader(full.Bytes()[:progress.Offset-1]))
+	require.NoError(t, err)
+	require.NotNil(t, progress)
+	require.Greater(t, progress.Height, tail+1)
+	require.Less(t, progress.Height, head.Height())
+
+	resumed := bytes.NewBuffer(append([]byte{}, full.Bytes()[:progress.Offset]...))
+	next, err := store.GetTipSet(ctx, progress.Next)
+	require.NoError(t, err)
+	opts.SkipHeader = true
+	require.NoError(t, store.ExportRange(ctx, resumed, next, tail, opts))
+
+	resumedHeights, resumedObjects := exportedHeights(t, resumed.Bytes())
+	require.Equal(t, heights, resumedHeights)
+	require.GreaterOrEqual(t, resumedObjects, objects)
+
+	require.Error(t, store.ExportRange(ctx, &full, next, head.Height(), opts))
+}
@@ -0,0 +1,218 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/venus-shared/actors/policy"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+const (
+	// DefaultByzantineFraction is the share of the power assumed to be adversarial.
+	DefaultByzantineFraction = 0.3
+	// DefaultFinalityThreshold is the reorg probability under which a tipset is considered safe.
+	DefaultFinalityThreshold = 1e-6
+
+	// finalityLookback is the number of epochs before the target height inspected
+	// to bound the lead an adversary may already have at the target height.
+	finalityLookback = 100
+	// negligibleProbability is the probability under which distribution tails are dropped.
+	negligibleProbability = 1e-25
+	// maxAdversarialLead bounds the adversarial lead distribution.
+	maxAdversarialLead = 400
+)
+
+type finalityChainReader interface {
+	GetTipSet(context.Context, types.TipSetKey) (*types.TipSet, error)
+}
+
+// FinalityCalculator computes the probability that a tipset is replaced by a
+// heavier fork, following the EC finality calculator of FRC-0089. Instead of
+// assuming a fixed number of blocks per epoch, it uses the number of blocks
+// actually observed at each epoch of the chain, so the depth needed for a
+// given safety level shrinks on a healthy chain and grows when blocks are
+// missing.
+type FinalityCalculator struct {
+	reader finalityChainReader
+
+	blocksPerEpoch    float64
+	byzantineFraction float64
+}
+
+// NewFinalityCalculator returns a calculator reading the chain from reader.
+func NewFinalityCalculator(reader finalityChainReader) *FinalityCalculator {
+	return &FinalityCalculator{
+		reader:            reader,
+		blocksPerEpoch:    float64(constants.ExpectedLeadersPerEpoch),
+		byzantineFraction: DefaultByzantineFraction,
+	}
+}
+
+// ReorgProbability returns an upper bound of the probability that the tipset
+// at height is reorged out of the chain ending at head.
+func (fc *FinalityCalculator) ReorgProbability(ctx context.Context, head *types.TipSet, height abi.ChainEpoch) (float64, error) {
+	if height > head.Height() {
+		return 0, fmt.Errorf("height %d is above the head %d", height, head.Height())
+	}
+	// tipsets past finality, and the genesis, are never replaced
+	if head.Height()-height >= policy.ChainFinality || height <= 0 {
+		return 0, nil
+	}
+
+	counts, start, err := fc.blockCounts(ctx, head, height-finalityLookback)
+	if err != nil {
+		return 0, err
+	}
+	return reorgProbability(counts, int(height-start), fc.blocksPerEpoch, fc.byzantineFraction), nil
+}
+
+// SafeHeight returns the highest epoch whose reorg probability relative to
+// head is at most threshold.
+func (fc *FinalityCalculator) SafeHeight(ctx context.Context, head *types.TipSet, threshold float64) (abi.ChainEpoch, error) {
+	low := head.Height() - policy.ChainFinality
+	if low < 0 {
+		low = 0
+	}
+	counts, start, err := fc.blockCounts(ctx, head, low-finalityLookback)
+	if err != nil {
+		return 0, err
+	}
+
+	// the reorg probability decreases with the depth, search for the first
+	// height above the safe one
+	high := head.Height() + 1
+	for low+1 < high {
+		mid := low + (high-low)/2
+		if reorgProbability(counts, int(mid-start), fc.blocksPerEpoch, fc.byzantineFraction) <= threshold {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return low, nil
+}
+
+// blockCounts returns the number of blocks at each epoch from `from` (or
+// genesis) up to head, and the epoch of the first entry. Null rounds count
+// as zero blocks.
+func (fc *FinalityCalculator) blockCounts(ctx context.Context, head *types.TipSet, from abi.ChainEpoch) ([]int, abi.ChainEpoch, error) {
+	if from < 0 {
+		from = 0
+	}
+	counts := make([]int, head.Height()-from+1)
+
+	ts := head
+	for ts.Height() >= from {
+		counts[ts.Height()-from] = len(ts.Blocks())
+		if ts.Height() == 0 {
+			break
+		}
+		parent, err := fc.reader.GetTipSet(ctx, ts.Parents())
+		if err != nil {
+			return nil, 0, fmt.Errorf("load parent of %s: %w", ts.Key(), err)
+		}
+		ts = parent
+	}
+	return counts, from, nil
+}
+
+// reorgProbability bounds the probability that an adversary holding
+// byzantineFraction of the power replaces the blocks at index target of
+// chain, where chain holds the number of blocks observed at each epoch and
+// its last entry is the current head. The adversary succeeds if its lead at
+// the target epoch (L), plus the blocks it mined since (A), plus the largest
+// lead it gains in the future (M) reaches the blocks of the public chain
+// after the target epoch.
+func reorgProbability(chain []int, target int, blocksPerEpoch, byzantineFraction float64) float64 {
+	if target < 0 || target >= len(chain) {
+		return 1
+	}
+	rateAdversarial := blocksPerEpoch * byzantineFraction
+	rateHonest := blocksPerEpoch - rateAdversarial
+	if rateAdversarial <= 0 {
+		return 0
+	}
+	ratio := rateAdversarial / rateHonest
+	if ratio >= 1 {
+		return 1
+	}
+
+	// L: the lead of a fork started at any of the previous epochs, maximized
+	// over the fork start
+	prL := make([]float64, 0, maxAdversarialLead+1)
+	total := 0.0
+	for k := 0; k <= maxAdversarialLead; k++ {
+		var p, expected float64
+		var observed int
+		for i := target; i >= 0 && i > target-finalityLookback; i-- {
+			expected += rateAdversarial
+			observed += chain[i]
+			p = math.Max(p, poissonPMF(k+observed, expected))
+		}
+		prL = append(prL, p)
+		total += p
+		if k > 1 && p < negligibleProbability && p < prL[k-1] {
+			break
+		}
+	}
+	// the lead is never negative, the missing mass belongs to k=0
+	if total < 1 {
+		prL[0] += 1 - total
+	}
+
+	// A: blocks mined by the adversary after the target epoch, it has to
+	// catch up with the blocks of the public chain
+	behind := 0
+	for _, c := range chain[target+1:] {
+		behind += c
+	}
+	expectedA := rateAdversarial * float64(len(chain)-target-1)
+
+	// pa[a] = Pr(A=a), computed past behind until the tail is negligible
+	var pa []float64
+	for a := 0; a < behind || float64(a) <= expectedA; a++ {
+		p := poissonPMF(a, expectedA)
+		if a >= behind && p < negligibleProbability {
+			break
+		}
+		pa = append(pa, p)
+	}
+	// tail[n] = sum over a < n of Pr(A=a) * Pr(M >= n-a), using Pr(M >= j) = ratio^j
+	tail := make([]float64, behind+1)
+	for n := 0; n < behind; n++ {
+		tail[n+1] = ratio * (tail[n] + pa[n])
+	}
+	// atLeast[n] = Pr(A >= n)
+	atLeast := make([]float64, len(pa)+1)
+	for n := len(pa) - 1; n >= 0; n-- {
+		atLeast[n] = atLeast[n+1] + pa[n]
+	}
+
+	var prError float64
+	for lead, pl := range prL {
+		need := behind - lead
+		if need <= 0 {
+			prError += pl
+			continue
+		}
+		prError += pl * (tail[need] + atLeast[need])
+	}
+
+	return math.Min(prError, 1)
+}
+
+func poissonPMF(k int, lambda float64) float64 {
+	if lambda <= 0 {
+		if k == 0 {
+			return 1
+		}
+		return 0
+	}
+	lg, _ := math.Lgamma(float64(k + 1))
+	return math.Exp(float64(k)*math.Log(lambda) - lambda - lg)
+}
@@ -0,0 +1,76 @@
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+)
+
+func healthyChain(epochs, blocks int) []int {
+	chain := make([]int, epochs)
+	for i := range chain {
+		chain[i] = blocks
+	}
+	return chain
+}
+
+func TestReorgProbability(t *testing.T) {
+	tf.UnitTest(t)
+
+	chain := healthyChain(200, 5)
+	head := len(chain) - 1
+
+	// the head itself can always be replaced
+	assert.InDelta(t, 1, reorgProbability(chain, head, 5, DefaultByzantineFraction), 1e-9)
+
+	prev := 1.0
+	for depth := 1; depth <= 100; depth++ {
+		p := reorgProbability(chain, head-depth, 5, DefaultByzantineFraction)
+		assert.LessOrEqual(t, p, prev, "depth %d", depth)
+		prev = p
+	}
+	assert.Less(t, prev, 1e-10)
+
+	// missing blocks after the target make it less final
+	sparse := healthyChain(200, 5)
+	for i := head - 20; i <= head; i++ {
+		sparse[i] = 1
+	}
+	assert.Greater(t,
+		reorgProbability(sparse, head-20, 5, DefaultByzantineFraction),
+		reorgProbability(chain, head-20, 5, DefaultByzantineFraction))
+
+	// an honest network never reorgs, a majority attacker always does
+	assert.Zero(t, reorgProbability(chain, head-1, 5, 0))
+	assert.Equal(t, 1.0, reorgProbability(chain, head-50, 5, 0.5))
+}
+
+func TestFinalityCalculator(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	builder := NewBuilder(t, address.Undef)
+	head := builder.AppendManyOn(ctx, 40, builder.Genesis())
+
+	fc := NewFinalityCalculator(builder)
+	p, err := fc.ReorgProbability(ctx, head, head.Height())
+	require.NoError(t, err)
+	assert.InDelta(t, 1, p, 1e-9)
+
+	_, err = fc.ReorgProbability(ctx, head, head.Height()+1)
+	require.Error(t, err)
+
+	// the builder mines a single block per epoch, far from the expected 5
+	safe, err := fc.SafeHeight(ctx, head, DefaultFinalityThreshold)
+	require.NoError(t, err)
+	assert.Less(t, safe, head.Height())
+
+	p, err = fc.ReorgProbability(ctx, head, safe)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, p, DefaultFinalityThreshold)
+}
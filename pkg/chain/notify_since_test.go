@@ -0,0 +1,192 @@
+// stm: #unit
+package chain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/stretchr/testify/require"
+
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+func nextHeadChanges(t *testing.T, ch <-chan []*types.HeadChange) []*types.HeadChange {
+	select {
+	case changes, ok := <-ch:
+		require.True(t, ok, "head change subscription closed")
+		return changes
+	case <-time.After(10 * time.Second):
+		t.Helper()
+		t.Fatal("no head changes")
+		return nil
+	}
+}
+
+// followHeadChanges reads the changes from the tipset from until the tipset to, it checks that
+// each change follows the previous ones, and returns how many messages it read.
+func followHeadChanges(ctx context.Context, t *testing.T, store *Store, ch <-chan []*types.HeadChange, from, to *types.TipSet) int {
+	cur, msgs := from, 0
+	for !cur.Equals(to) {
+		msgs++
+		for _, change := range nextHeadChanges(t, ch) {
+			switch change.Type {
+			case types.HCApply:
+				require.Equal(t, cur.Key(), change.Val.Parents())
+				cur = change.Val
+			case types.HCRevert:
+				require.True(t, cur.Equals(change.Val))
+				parent, err := store.GetTipSet(ctx, cur.Parents())
+				require.NoError(t, err)
+				cur = parent
+			default:
+				t.Fatalf("unexpected %s", change.Type)
+			}
+		}
+	}
+	return msgs
+}
+
+func requireChange(t *testing.T, change *types.HeadChange, typ types.HeadChangeType, ts *types.TipSet) {
+	require.Equal(t, typ, change.Type)
+	require.True(t, ts.Equals(change.Val), "change is %d, expect %d", change.Val.Height(), ts.Height())
+}
+
+func TestSubHeadChangesSince(t *testing.T) {
+	tf.UnitTest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewBuilder(t, address.Undef)
+	store := builder.store
+	genesis := builder.Genesis()
+	link1 := builder.AppendOn(ctx, genesis, 1)
+	link2 := builder.AppendOn(ctx, link1, 1)
+	link3 := builder.AppendOn(ctx, link2, 1)
+	fork1 := builder.AppendOn(ctx, genesis, 2)
+	fork2 := builder.AppendOn(ctx, fork1, 2)
+	require.NoError(t, store.SetHead(ctx, link3))
+
+	// a reader at the head gets the current head
+	ch, err := store.SubHeadChangesSince(ctx, link3)
+	require.NoError(t, err)
+	changes := nextHeadChanges(t, ch)
+	require.Len(t, changes, 1)
+	requireChange(t, changes[0], types.HCCurrent, link3)
+
+	// a reader behind the head gets what it missed
+	ch, err = store.SubHeadChangesSince(ctx, link1)
+	require.NoError(t, err)
+	changes = nextHeadChanges(t, ch)
+	require.Len(t, changes, 2)
+	requireChange(t, changes[0], types.HCApply, link2)
+	requireChange(t, changes[1], types.HCApply, link3)
+
+	// a reader on a fork reverts it first, then gets the live changes
+	ch, err = store.SubHeadChangesSince(ctx, fork2)
+	require.NoError(t, err)
+	changes = nextHeadChanges(t, ch)
+	require.Len(t, changes, 5)
+	requireChange(t, changes[0], types.HCRevert, fork2)
+	requireChange(t, changes[1], types.HCRevert, fork1)
+	requireChange(t, changes[2], types.HCApply, link1)
+	requireChange(t, changes[3], types.HCApply, link2)
+	requireChange(t, changes[4], types.HCApply, link3)
+
+	link4 := builder.AppendOn(ctx, link3, 1)
+	require.NoError(t, store.SetHead(ctx, link4))
+	followHeadChanges(ctx, t, store, ch, link3, link4)
+
+	fork3 := builder.AppendOn(ctx, fork2, 2)
+	fork4 := builder.AppendOn(ctx, fork3, 2)
+	fork5 := builder.AppendOn(ctx, fork4, 2)
+	require.NoError(t, store.SetHead(ctx, fork5))
+	followHeadChanges(ctx, t, store, ch, link4, fork5)
+}
+
+func TestSubHeadChangesSinceSlowReader(t *testing.T) {
+	tf.UnitTest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defer func(maxPending, batchSize int) {
+		sinceMaxPending, sinceBatchSize = maxPending, batchSize
+	}(sinceMaxPending, sinceBatchSize)
+	sinceMaxPending, sinceBatchSize = 3, 5
+
+	builder := NewBuilder(t, address.Undef)
+	store := builder.store
+	genesis := builder.Genesis()
+
+	ch, err := store.SubHeadChangesSince(ctx, genesis)
+	require.NoError(t, err)
+	changes := nextHeadChanges(t, ch)
+	require.Len(t, changes, 1)
+	requireChange(t, changes[0], types.HCCurrent, genesis)
+
+	// the reader does not read while the head moves, it is not dropped but gets fewer messages
+	head := genesis
+	for i := 0; i < 40; i++ {
+		head = builder.AppendOn(ctx, head, 1)
+		require.NoError(t, store.SetHead(ctx, head))
+	}
+	// let the notifications reach the subscription before reading
+	time.Sleep(100 * time.Millisecond)
+	require.Less(t, followHeadChanges(ctx, t, store, ch, genesis, head), 40)
+
+	next := builder.AppendOn(ctx, head, 1)
+	require.NoError(t, store.SetHead(ctx, next))
+	followHeadChanges(ctx, t, store, ch, head, next)
+}
+
+func TestSubHeadChangesSinceReplay(t *testing.T) {
+	tf.UnitTest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defer func(maxDepth abi.ChainEpoch, batchSize int) {
+		sinceMaxDepth, sinceBatchSize = maxDepth, batchSize
+	}(sinceMaxDepth, sinceBatchSize)
+	sinceMaxDepth, sinceBatchSize = 5, 2
+
+	builder := NewBuilder(t, address.Undef)
+	store := builder.store
+	genesis := builder.Genesis()
+	link1 := builder.AppendOn(ctx, genesis, 1)
+	fork := builder.AppendManyOn(ctx, 2, link1)
+	head := builder.AppendManyOn(ctx, 5, link1)
+	require.NoError(t, store.SetHead(ctx, head))
+
+	// the path is replayed in messages of at most two changes, the reverts then the applies
+	ch, err := store.SubHeadChangesSince(ctx, fork)
+	require.NoError(t, err)
+	var changes []*types.HeadChange
+	for cur := fork; !cur.Equals(head); {
+		msg := nextHeadChanges(t, ch)
+		require.LessOrEqual(t, len(msg), 2)
+		changes = append(changes, msg...)
+		cur = msg[len(msg)-1].Val
+		if msg[len(msg)-1].Type == types.HCRevert {
+			cur, err = store.GetTipSet(ctx, cur.Parents())
+			require.NoError(t, err)
+		}
+	}
+	require.Len(t, changes, 7)
+	requireChange(t, changes[0], types.HCRevert, fork)
+	requireChange(t, changes[6], types.HCApply, head)
+	for _, change := range changes[2:] {
+		require.Equal(t, types.HCApply, change.Type)
+	}
+
+	// a tipset which forks off the chain more than the max depth behind the head is refused
+	_, err = store.SubHeadChangesSince(ctx, genesis)
+	require.ErrorContains(t, err, "forks off the chain")
+	_, err = store.SubHeadChangesSince(ctx, link1)
+	require.NoError(t, err)
+	old := builder.AppendManyOn(ctx, 3, genesis)
+	_, err = store.SubHeadChangesSince(ctx, old)
+	require.ErrorContains(t, err, "forks off the chain")
+}
@@ -0,0 +1,341 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	actorstypes "github.com/filecoin-project/go-state-types/actors"
+	"github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/multiformats/go-multicodec"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/venus-shared/actors"
+	"github.com/filecoin-project/venus/venus-shared/actors/policy"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// ErrPruneNotSupported is returned when the blockstore cannot enumerate its keys.
+var ErrPruneNotSupported = errors.New("blockstore does not support pruning")
+
+// pruneBatchSize is the number of objects deleted at once.
+var pruneBatchSize = 16 << 10
+
+// keyIterator is implemented by blockstores that can be pruned, e.g. BadgerBlockstore.
+type keyIterator interface {
+	ForEachKey(f func(cid.Cid) error) error
+}
+
+// writeObserver is implemented by blockstores that report the objects written to them, so that
+// the objects written while pruning are kept, e.g. BadgerBlockstore.
+type writeObserver interface {
+	ObserveWrites(f func(cid.Cid)) (stop func())
+}
+
+// PruneRoots returns objects to keep while pruning which are not reachable from the chain, e.g.
+// the messages pending in the message pool.
+type PruneRoots func(ctx context.Context) ([]cid.Cid, error)
+
+// AddPruneRoots makes Prune keep the objects returned by roots, and the objects they link to.
+func (store *Store) AddPruneRoots(roots PruneRoots) {
+	store.pruneRootsLk.Lock()
+	defer store.pruneRootsLk.Unlock()
+	store.pruneRoots = append(store.pruneRoots, roots)
+}
+
+// markSet holds the multihashes of the reachable objects, blockstores are keyed
+// by multihash so the codec of a cid does not matter.
+type markSet map[string]struct{}
+
+// protectSet holds the multihashes of the objects written while pruning.
+type protectSet struct {
+	lk      sync.Mutex
+	written markSet
+}
+
+func (p *protectSet) add(c cid.Cid) {
+	p.lk.Lock()
+	defer p.lk.Unlock()
+	p.written.visit(c)
+}
+
+func (p *protectSet) has(c cid.Cid) bool {
+	p.lk.Lock()
+	defer p.lk.Unlock()
+	return p.written.has(c)
+}
+
+func (m markSet) visit(c cid.Cid) bool {
+	k := string(c.Hash())
+	if _, ok := m[k]; ok {
+		return false
+	}
+	m[k] = struct{}{}
+	return true
+}
+
+func (m markSet) has(c cid.Cid) bool {
+	_, ok := m[string(c.Hash())]
+	return ok
+}
+
+// Prune deletes every object that is not reachable from the current head.
+// The chain headers are kept down to genesis, the state, messages and receipts
+// only for the last RetainState epochs. The objects of the PruneRoots, and the
+// objects written to the blockstore while pruning, are kept as well, so the node
+// keeps syncing and accepting messages meanwhile.
+func (store *Store) Prune(ctx context.Context, opts types.ChainPruneOpts) (*types.ChainPruneResult, error) {
+	keys, ok := store.bsstore.(keyIterator)
+	if !ok {
+		return nil, ErrPruneNotSupported
+	}
+	observer, ok := store.bsstore.(writeObserver)
+	if !ok {
+		return nil, ErrPruneNotSupported
+	}
+	if opts.RetainState < policy.ChainFinality {
+		return nil, fmt.Errorf("must retain at least %d epochs of state, got %d", policy.ChainFinality, opts.RetainState)
+	}
+
+	start := constants.Clock.Now()
+	protect := &protectSet{written: make(markSet)}
+	stop := observer.ObserveWrites(protect.add)
+	defer stop()
+
+	head := store.GetHead()
+	marked := make(markSet)
+
+	log.Infow("prune: marking reachable objects", "head", head.Height(), "retainState", opts.RetainState)
+	if err := store.markChain(ctx, head, head.Height()-opts.RetainState, false, marked); err != nil {
+		return nil, fmt.Errorf("marking chain: %w", err)
+	}
+	if err := store.markManifests(ctx, marked); err != nil {
+		return nil, fmt.Errorf("marking actor bundles: %w", err)
+	}
+	if err := store.markPruneRoots(ctx, marked); err != nil {
+		return nil, err
+	}
+
+	// the node keeps syncing while marking, mark everything the new tipsets brought in
+	if newHead := store.GetHead(); !newHead.Equals(head) {
+		if err := store.markChain(ctx, newHead, head.Height()-opts.RetainState, true, marked); err != nil {
+			return nil, fmt.Errorf("marking new tipsets: %w", err)
+		}
+	}
+
+	res := &types.ChainPruneResult{
+		Head:   head.Height(),
+		DryRun: opts.DryRun,
+		Marked: uint64(len(marked)),
+	}
+
+	log.Infow("prune: sweeping unreachable objects", "marked", res.Marked, "dryRun", opts.DryRun)
+	batch := make([]cid.Cid, 0, pruneBatchSize)
+	// deleteBatch deletes the objects of the batch which were not written since they were read,
+	// the writes wait for it, and then write the objects again
+	deleteBatch := func() error {
+		protect.lk.Lock()
+		defer protect.lk.Unlock()
+
+		toDelete := batch[:0]
+		for _, c := range batch {
+			if !protect.written.has(c) {
+				toDelete = append(toDelete, c)
+			}
+		}
+		batch = batch[:0]
+		if len(toDelete) == 0 {
+			return nil
+		}
+		return store.bsstore.DeleteMany(ctx, toDelete)
+	}
+	err := keys.ForEachKey(func(c cid.Cid) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if marked.has(c) || protect.has(c) {
+			return nil
+		}
+
+		size, err := store.bsstore.GetSize(ctx, c)
+		if ipld.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("getting size of %s: %w", c, err)
+		}
+		res.Pruned++
+		res.PrunedBytes += uint64(size)
+
+		if opts.DryRun {
+			return nil
+		}
+		batch = append(batch, c)
+		if len(batch) < pruneBatchSize {
+			return nil
+		}
+		return deleteBatch()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sweeping blockstore: %w", err)
+	}
+	if len(batch) > 0 {
+		if err := deleteBatch(); err != nil {
+			return nil, fmt.Errorf("sweeping blockstore: %w", err)
+		}
+	}
+
+	res.Duration = constants.Clock.Since(start)
+	log.Infow("prune finished", "pruned", res.Pruned, "bytes", res.PrunedBytes, "dryRun", opts.DryRun, "duration", res.Duration)
+
+	return res, nil
+}
+
+// markChain walks the ancestors of head and marks the headers of every tipset,
+// and the state, messages and receipts of the tipsets above boundary. The genesis
+// state is always marked. With stopAtMarked the walk ends at the first tipset
+// that was already marked.
+func (store *Store) markChain(ctx context.Context, head *types.TipSet, boundary abi.ChainEpoch, stopAtMarked bool, marked markSet) error {
+	var err error
+	for it := IterAncestors(ctx, store, head); !it.Complete(); err = it.Next(ctx) {
+		if err != nil {
+			return err
+		}
+
+		ts := it.Value()
+		tskBlk, err := ts.Key().ToStorageBlock()
+		if err != nil {
+			return err
+		}
+		if !marked.visit(tskBlk.Cid()) && stopAtMarked {
+			return nil
+		}
+
+		for _, blk := range ts.Blocks() {
+			if !marked.visit(blk.Cid()) {
+				continue
+			}
+
+			if ts.Height() == 0 {
+				// the genesis block links to the cbor genesis block as its parent
+				for _, p := range blk.Parents {
+					marked.visit(p)
+				}
+			}
+
+			if ts.Height() > boundary || ts.Height() == 0 {
+				for _, root := range []cid.Cid{blk.ParentStateRoot, blk.ParentMessageReceipts, blk.Messages} {
+					if err := store.markDAG(ctx, root, marked); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		// the state computed from this tipset is only referenced by the chain datastore
+		if ts.Height() > boundary {
+			if meta, err := store.GetTipsetMetadata(ctx, ts); err == nil {
+				if err := store.markDAG(ctx, meta.TipSetStateRoot, marked); err != nil {
+					return err
+				}
+				if err := store.markDAG(ctx, meta.TipSetReceipts, marked); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return err
+}
+
+// LoadPruneRoots returns the objects of the PruneRoots.
+func (store *Store) LoadPruneRoots(ctx context.Context) ([]cid.Cid, error) {
+	store.pruneRootsLk.Lock()
+	pruneRoots := append([]PruneRoots{}, store.pruneRoots...)
+	store.pruneRootsLk.Unlock()
+
+	var cids []cid.Cid
+	for _, roots := range pruneRoots {
+		rs, err := roots(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("loading prune roots: %w", err)
+		}
+		cids = append(cids, rs...)
+	}
+	return cids, nil
+}
+
+// markPruneRoots marks the objects of the PruneRoots.
+func (store *Store) markPruneRoots(ctx context.Context, marked markSet) error {
+	cids, err := store.LoadPruneRoots(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range cids {
+		if err := store.markDAG(ctx, c, marked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markManifests marks the builtin actor bundles, bundles of upcoming upgrades
+// are not referenced by any state yet.
+func (store *Store) markManifests(ctx context.Context, marked markSet) error {
+	for _, v := range actors.Versions {
+		manifest, ok := actors.GetManifest(actorstypes.Version(v))
+		if !ok {
+			continue
+		}
+		has, err := store.bsstore.Has(ctx, manifest)
+		if err != nil {
+			return err
+		}
+		if !has {
+			continue
+		}
+		if err := store.markDAG(ctx, manifest, marked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markDAG marks root and every object reachable from it.
+func (store *Store) markDAG(ctx context.Context, root cid.Cid, marked markSet) error {
+	stack := []cid.Cid{root}
+	for len(stack) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		prefix := c.Prefix()
+		if multicodec.Code(prefix.MhType) == multicodec.Identity {
+			continue
+		}
+		if !marked.visit(c) || multicodec.Code(prefix.Codec) != multicodec.DagCbor {
+			continue
+		}
+
+		err := store.bsstore.View(ctx, c, func(data []byte) error {
+			return cbg.ScanForLinks(bytes.NewReader(data), func(link cid.Cid) {
+				stack = append(stack, link)
+			})
+		})
+		if ipld.IsNotFound(err) {
+			// nothing to keep, e.g. state that was never synced
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("scanning links of %s: %w", c, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package chain
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	"github.com/filecoin-project/venus/venus-shared/actors/policy"
+	blockstoreutil "github.com/filecoin-project/venus/venus-shared/blockstore"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// iterableBlockstore adds ForEachKey and ObserveWrites to a blockstore for the prune tests.
+type iterableBlockstore struct {
+	blockstoreutil.Blockstore
+
+	lk       sync.Mutex
+	observer func(cid.Cid)
+	// onSweep is called when the sweep starts
+	onSweep func()
+}
+
+func newIterableBlockstore(bs blockstoreutil.Blockstore) *iterableBlockstore {
+	return &iterableBlockstore{Blockstore: bs}
+}
+
+func (bs *iterableBlockstore) ForEachKey(f func(cid.Cid) error) error {
+	if bs.onSweep != nil {
+		bs.onSweep()
+	}
+	ch, err := bs.AllKeysChan(context.Background())
+	if err != nil {
+		return err
+	}
+	var keys []cid.Cid
+	for c := range ch {
+		keys = append(keys, c)
+	}
+	for _, c := range keys {
+		if err := f(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bs *iterableBlockstore) ObserveWrites(f func(cid.Cid)) func() {
+	bs.lk.Lock()
+	defer bs.lk.Unlock()
+	bs.observer = f
+	return func() {
+		bs.lk.Lock()
+		defer bs.lk.Unlock()
+		bs.observer = nil
+	}
+}
+
+func (bs *iterableBlockstore) observe(c cid.Cid) {
+	bs.lk.Lock()
+	observer := bs.observer
+	bs.lk.Unlock()
+	if observer != nil {
+		observer(c)
+	}
+}
+
+func (bs *iterableBlockstore) Has(ctx context.Context, c cid.Cid) (bool, error) {
+	bs.observe(c)
+	return bs.Blockstore.Has(ctx, c)
+}
+
+func (bs *iterableBlockstore) Put(ctx context.Context, blk blocks.Block) error {
+	bs.observe(blk.Cid())
+	return bs.Blockstore.Put(ctx, blk)
+}
+
+func TestPruneGuards(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	builder := NewBuilder(t, address.Undef)
+	_, err := builder.Store().Prune(ctx, types.ChainPruneOpts{RetainState: policy.ChainFinality})
+	require.ErrorIs(t, err, ErrPruneNotSupported)
+
+	store := builder.Store()
+	store.bsstore = newIterableBlockstore(store.bsstore)
+	_, err = store.Prune(ctx, types.ChainPruneOpts{RetainState: policy.ChainFinality - 1})
+	require.Error(t, err)
+}
+
+func TestPruneDeletesUnreachableObjects(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	builder := NewBuilder(t, address.Undef)
+	head := builder.AppendManyOn(ctx, 5, builder.Genesis())
+	store := builder.Store()
+	require.NoError(t, store.SetHead(ctx, head))
+	store.bsstore = newIterableBlockstore(store.bsstore)
+
+	orphan := blocks.NewBlock([]byte("orphan"))
+	require.NoError(t, store.bsstore.Put(ctx, orphan))
+
+	opts := types.ChainPruneOpts{RetainState: policy.ChainFinality, DryRun: true}
+	res, err := store.Prune(ctx, opts)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, res.Pruned)
+	has, err := store.bsstore.Has(ctx, orphan.Cid())
+	require.NoError(t, err)
+	require.True(t, has)
+
+	opts.DryRun = false
+	res, err = store.Prune(ctx, opts)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, res.Pruned)
+	has, err = store.bsstore.Has(ctx, orphan.Cid())
+	require.NoError(t, err)
+	require.False(t, has)
+
+	for _, blk := range head.Blocks() {
+		has, err := store.bsstore.Has(ctx, blk.Cid())
+		require.NoError(t, err)
+		require.True(t, has)
+	}
+}
+
+func TestPruneKeepsWrittenObjectsAndRoots(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	builder := NewBuilder(t, address.Undef)
+	head := builder.AppendManyOn(ctx, 5, builder.Genesis())
+	store := builder.Store()
+	require.NoError(t, store.SetHead(ctx, head))
+	bs := newIterableBlockstore(store.bsstore)
+	store.bsstore = bs
+
+	orphan := blocks.NewBlock([]byte("orphan"))
+	pending := blocks.NewBlock([]byte("pending message"))
+	existing := blocks.NewBlock([]byte("checked while sweeping"))
+	require.NoError(t, bs.Put(ctx, orphan))
+	require.NoError(t, bs.Put(ctx, pending))
+	require.NoError(t, bs.Put(ctx, existing))
+	store.AddPruneRoots(func(context.Context) ([]cid.Cid, error) {
+		return []cid.Cid{pending.Cid()}, nil
+	})
+
+	// the node writes and uses objects while the blockstore is swept
+	written := blocks.NewBlock([]byte("written while sweeping"))
+	bs.onSweep = func() {
+		require.NoError(t, bs.Put(ctx, written))
+		has, err := bs.Has(ctx, existing.Cid())
+		require.NoError(t, err)
+		require.True(t, has)
+	}
+
+	res, err := store.Prune(ctx, types.ChainPruneOpts{RetainState: policy.ChainFinality})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, res.Pruned)
+
+	for blk, kept := range map[blocks.Block]bool{orphan: false, pending: true, existing: true, written: true} {
+		has, err := store.bsstore.Has(ctx, blk.Cid())
+		require.NoError(t, err)
+		require.Equal(t, kept, has, string(blk.RawData()))
+	}
+}
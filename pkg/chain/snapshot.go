@@ -0,0 +1,158 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ipfs/go-datastore"
+
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// SnapshotImportKey is the key at which the status of the last snapshot import is written in the datastore.
+var SnapshotImportKey = datastore.NewKey("/chain/snapshotImport")
+
+// PutSnapshotImportStatus persists the status of the snapshot import.
+func (store *Store) PutSnapshotImportStatus(ctx context.Context, status *types.SnapshotImportStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot import status: %w", err)
+	}
+	return store.ds.Put(ctx, SnapshotImportKey, data)
+}
+
+// GetSnapshotImportStatus returns the status of the last snapshot import, or nil when no snapshot
+// was imported.
+func (store *Store) GetSnapshotImportStatus(ctx context.Context) (*types.SnapshotImportStatus, error) {
+	data, err := store.ds.Get(ctx, SnapshotImportKey)
+	if err == datastore.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var status types.SnapshotImportStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot import status: %w", err)
+	}
+	return &status, nil
+}
+
+// ImportProgress tracks the bytes read and the blocks written by a snapshot import.
+type ImportProgress struct {
+	bytes  uint64
+	blocks uint64
+
+	lk     sync.Mutex
+	status types.SnapshotImportStatus
+}
+
+// NewImportProgress tracks the import of the snapshot at source, of total bytes when known.
+func NewImportProgress(source string, total uint64) *ImportProgress {
+	now := constants.Clock.Now()
+	return &ImportProgress{
+		status: types.SnapshotImportStatus{
+			Source:     source,
+			State:      types.SnapshotImporting,
+			StartedAt:  now,
+			UpdatedAt:  now,
+			TotalBytes: total,
+		},
+	}
+}
+
+// Reader counts the bytes read from r, which should be the snapshot before it is decompressed.
+func (p *ImportProgress) Reader(r io.Reader) io.Reader {
+	return &progressReader{r: r, p: p}
+}
+
+type progressReader struct {
+	r io.Reader
+	p *ImportProgress
+}
+
+func (pr *progressReader) Read(b []byte) (int, error) {
+	n, err := pr.r.Read(b)
+	atomic.AddUint64(&pr.p.bytes, uint64(n))
+	return n, err
+}
+
+func (p *ImportProgress) addBlocks(n int) {
+	if p != nil {
+		atomic.AddUint64(&p.blocks, uint64(n))
+	}
+}
+
+// Update applies f to the status, e.g. to change its state.
+func (p *ImportProgress) Update(f func(status *types.SnapshotImportStatus)) {
+	p.lk.Lock()
+	defer p.lk.Unlock()
+	f(&p.status)
+}
+
+// Status returns the status of the import, with its rates and the time left to read the snapshot.
+func (p *ImportProgress) Status() *types.SnapshotImportStatus {
+	p.lk.Lock()
+	status := p.status
+	p.lk.Unlock()
+
+	status.UpdatedAt = constants.Clock.Now()
+	status.Bytes = atomic.LoadUint64(&p.bytes)
+	status.Blocks = atomic.LoadUint64(&p.blocks)
+	if elapsed := status.UpdatedAt.Sub(status.StartedAt).Seconds(); elapsed > 0 {
+		status.BytesPerSecond = float64(status.Bytes) / elapsed
+		status.BlocksPerSecond = float64(status.Blocks) / elapsed
+	}
+	if status.TotalBytes > status.Bytes && status.BytesPerSecond > 0 {
+		status.ETA = time.Duration(float64(status.TotalBytes-status.Bytes) / status.BytesPerSecond * float64(time.Second))
+	}
+	return &status
+}
+
+// VerifyChainLinks checks the links of the tipsets from head down to genesis: the parents of every
+// tipset are stored, lower and not heavier than it, and its blocks share their parent state.
+// progress is called with the number of tipsets checked so far and the last one.
+func (store *Store) VerifyChainLinks(ctx context.Context, head *types.TipSet, genesis *types.BlockHeader, progress func(checked uint64, ts *types.TipSet)) error {
+	var checked uint64
+	for ts := head; ts.Height() > 0; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		parent, err := store.GetTipSet(ctx, ts.Parents())
+		if err != nil {
+			return fmt.Errorf("loading the parents %s of %d: %w", ts.Parents(), ts.Height(), err)
+		}
+		if parent.Height() >= ts.Height() {
+			return fmt.Errorf("parent of %d has height %d", ts.Height(), parent.Height())
+		}
+		if big.Cmp(ts.ParentWeight(), parent.ParentWeight()) < 0 {
+			return fmt.Errorf("parent weight %s of %d is lower than the one of its parent %s", ts.ParentWeight(), ts.Height(), parent.ParentWeight())
+		}
+		for _, b := range ts.Blocks() {
+			if b.ParentStateRoot != ts.ParentState() {
+				return fmt.Errorf("block %s of %d has parent state %s instead of %s", b.Cid(), ts.Height(), b.ParentStateRoot, ts.ParentState())
+			}
+		}
+
+		checked++
+		if progress != nil {
+			progress(checked, ts)
+		}
+		ts = parent
+		if ts.Height() == 0 && genesis != nil && !ts.Key().Equals(types.NewTipSetKey(genesis.Cid())) {
+			return fmt.Errorf("chain ends at genesis %s instead of %s", ts.Key(), genesis.Cid())
+		}
+	}
+
+	return nil
+}
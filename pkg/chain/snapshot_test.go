@@ -0,0 +1,92 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus/pkg/consensus/chainselector"
+	"github.com/filecoin-project/venus/pkg/repo"
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	blockstoreutil "github.com/filecoin-project/venus/venus-shared/blockstore"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+func TestImportVerifiedSnapshot(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	builder := NewBuilder(t, address.Undef)
+	head := builder.AppendManyOn(ctx, 3, builder.Genesis())
+	head = builder.AppendOn(ctx, head, 2)
+	head = builder.AppendManyOn(ctx, 3, head)
+
+	var snapshot bytes.Buffer
+	require.NoError(t, builder.Store().ExportRange(ctx, &snapshot, head, 0, types.ChainExportRangeOpts{
+		Messages:   true,
+		Receipts:   true,
+		StateRoots: true,
+	}))
+
+	ds := repo.NewInMemoryRepo().ChainDatastore()
+	store := NewStore(ds, blockstoreutil.NewBlockstore(datastore.NewMapDatastore()), cid.Undef, NewMockCirculatingSupplyCalculator(), chainselector.Weight)
+
+	progress := NewImportProgress("snapshot.car", uint64(snapshot.Len()))
+	root, genesis, err := store.Import(ctx, progress.Reader(&snapshot), progress)
+	require.NoError(t, err)
+	require.Equal(t, head.Key(), root.Key())
+	require.Equal(t, builder.Genesis().At(0).Cid(), genesis.Cid())
+
+	status := progress.Status()
+	require.Equal(t, types.SnapshotImporting, status.State)
+	require.NotZero(t, status.Blocks)
+	require.Greater(t, status.Bytes, uint64(0))
+	require.LessOrEqual(t, status.Bytes, status.TotalBytes)
+
+	var checked uint64
+	require.NoError(t, store.VerifyChainLinks(ctx, root, genesis, func(n uint64, _ *types.TipSet) {
+		checked = n
+	}))
+	require.EqualValues(t, 7, checked)
+
+	other := *genesis
+	other.Timestamp++
+	require.Error(t, store.VerifyChainLinks(ctx, root, &other, nil))
+
+	// the status of the import survives restarts
+	none, err := store.GetSnapshotImportStatus(ctx)
+	require.NoError(t, err)
+	require.Nil(t, none)
+
+	progress.Update(func(status *types.SnapshotImportStatus) {
+		status.State = types.SnapshotImported
+		status.Head = root.Key()
+		status.LinksVerified = checked
+	})
+	require.NoError(t, store.PutSnapshotImportStatus(ctx, progress.Status()))
+	saved, err := store.GetSnapshotImportStatus(ctx)
+	require.NoError(t, err)
+	require.Equal(t, types.SnapshotImported, saved.State)
+	require.Equal(t, root.Key(), saved.Head)
+	require.Equal(t, checked, saved.LinksVerified)
+}
+
+func TestImportProgressETA(t *testing.T) {
+	tf.UnitTest(t)
+
+	progress := NewImportProgress("snapshot.car", 1<<20)
+	_, err := io.CopyN(io.Discard, progress.Reader(bytes.NewReader(make([]byte, 1<<20))), 1<<19)
+	require.NoError(t, err)
+
+	status := progress.Status()
+	require.EqualValues(t, 1<<19, status.Bytes)
+	require.Greater(t, status.BytesPerSecond, float64(0))
+	require.Greater(t, status.ETA, time.Duration(0))
+}
@@ -137,8 +137,18 @@ type Store struct {
 	tipsets map[abi.ChainEpoch][]cid.Cid
 
 	weight WeightFunc
+
+	// metadataLoader loads the metadata the store does not have, when set.
+	metadataLoader TipSetMetadataLoader
+
+	pruneRootsLk sync.Mutex
+	pruneRoots   []PruneRoots
 }
 
+// TipSetMetadataLoader loads the metadata of a tipset from elsewhere than the chain datastore, e.g.
+// from the node a follower reads the chain of.
+type TipSetMetadataLoader func(ctx context.Context, ts *types.TipSet) (*TipSetMetadata, error)
+
 // NewStore constructs a new default store.
 func NewStore(chainDs repo.Datastore,
 	bsstore blockstoreutil.Blockstore,
@@ -270,6 +280,9 @@ func (store *Store) LoadTipsetMetadata(ctx context.Context, ts *types.TipSet) (*
 	key := datastore.NewKey(makeKey(ts.String(), h))
 
 	tsStateBytes, err := store.ds.Get(ctx, key)
+	if errors.Is(err, datastore.ErrNotFound) && store.metadataLoader != nil {
+		return store.loadRemoteTipsetMetadata(ctx, ts)
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to read tipset key %s", ts.String())
 	}
@@ -286,6 +299,24 @@ func (store *Store) LoadTipsetMetadata(ctx context.Context, ts *types.TipSet) (*
 	}, nil
 }
 
+// SetTipSetMetadataLoader sets the loader of the metadata missing from the chain datastore, it must
+// be set before the store is loaded.
+func (store *Store) SetTipSetMetadataLoader(loader TipSetMetadataLoader) {
+	store.metadataLoader = loader
+}
+
+// loadRemoteTipsetMetadata loads the metadata of a tipset with the metadata loader and persists it.
+func (store *Store) loadRemoteTipsetMetadata(ctx context.Context, ts *types.TipSet) (*TipSetMetadata, error) {
+	tsm, err := store.metadataLoader(ctx, ts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load tip set metadata %s", ts.String())
+	}
+	if err := store.writeTipSetMetadata(ctx, tsm); err != nil {
+		return nil, errors.Wrapf(err, "failed to persist tip set metadata %s", ts.String())
+	}
+	return tsm, nil
+}
+
 // PutTipSetMetadata persists the blocks of a tipset and the tipset index.
 func (store *Store) PutTipSetMetadata(ctx context.Context, tsm *TipSetMetadata) error {
 	// Update tipindex.
@@ -757,6 +788,228 @@ func (store *Store) SubHeadChanges(ctx context.Context) chan []*types.HeadChange
 	return out
 }
 
+var (
+	// sinceBatchSize is the maximum number of changes in a message of the path SubHeadChangesSince
+	// replays.
+	sinceBatchSize = 100
+	// sinceMaxPending is the number of changes a SubHeadChangesSince reader may lag behind, the
+	// reader then replays the path from what it received to the head instead.
+	sinceMaxPending = 1000
+	// sinceMaxDepth is how far behind the head the tipset a SubHeadChangesSince reader starts from
+	// may fork off the chain.
+	sinceMaxDepth = policy.ChainFinality
+)
+
+// headChanges is a message of head changes along with the head the reader is at after it.
+type headChanges struct {
+	changes []*types.HeadChange
+	head    *types.TipSet
+}
+
+// SubHeadChangesSince returns channel with chain head updates since the tipset from.
+// The first messages are the path from `from` to the head, made of reverts and then applies,
+// or a single 'current' event when `from` is the head. Then events may be HCApply and HCRevert.
+// `from` must not fork off the chain more than finality behind the head. A reader that lags
+// behind is not dropped, its pending changes are replaced by the shorter path from the last
+// tipset it received to the head. The path is loaded one message at a time, as the reader reads.
+func (store *Store) SubHeadChangesSince(ctx context.Context, from *types.TipSet) (chan []*types.HeadChange, error) {
+	batchSize, maxPending := sinceBatchSize, sinceMaxPending
+
+	store.mu.RLock()
+	subCh := store.headEvents.Sub(types.HeadChangeTopic)
+	head := store.head
+	store.mu.RUnlock()
+
+	unsub := func() {
+		store.headEvents.Unsub(subCh)
+		for range subCh {
+		}
+	}
+
+	// pending are the live changes the reader gets next, when there are none and the reader is
+	// behind the head, it replays the path from what it received to the head
+	var pending []headChanges
+	if from.Equals(head) {
+		pending = []headChanges{{changes: []*types.HeadChange{{Type: types.HCCurrent, Val: head}}, head: head}}
+	} else if err := store.checkForkDepth(ctx, from, head, sinceMaxDepth); err != nil {
+		unsub()
+		return nil, err
+	}
+
+	out := make(chan []*types.HeadChange, 16)
+	go func() {
+		defer func() {
+			// Tell the caller we're done first, the following may block for a bit.
+			close(out)
+			unsub()
+		}()
+
+		// delivered is the head the reader is at with what it received so far
+		delivered, pendingLen := from, 0
+		// replay is the next message of the path from delivered to head
+		var replay *headChanges
+		for {
+			var sendCh chan []*types.HeadChange
+			var next []*types.HeadChange
+			if len(pending) > 0 {
+				sendCh, next = out, pending[0].changes
+			} else if !delivered.Equals(head) {
+				if replay == nil {
+					hc, err := store.nextHeadChanges(ctx, delivered, head, batchSize)
+					if err != nil {
+						log.Errorf("closing head change subscription, failed to compute the path: %v", err)
+						return
+					}
+					replay = &hc
+				}
+				sendCh, next = out, replay.changes
+			}
+
+			select {
+			case val, ok := <-subCh:
+				if !ok {
+					log.Warn("chain head sub exit loop")
+					return
+				}
+				changes := val.([]*types.HeadChange)
+				if len(changes) == 0 {
+					continue
+				}
+				last := changes[len(changes)-1]
+				newHead := last.Val
+				if last.Type == types.HCRevert {
+					parent, err := store.GetTipSet(ctx, last.Val.Parents())
+					if err != nil {
+						log.Errorf("closing head change subscription, failed to load tipset: %v", err)
+						return
+					}
+					newHead = parent
+				}
+
+				first := changes[0]
+				follows := (first.Type == types.HCApply && first.Val.Parents() == head.Key()) ||
+					(first.Type == types.HCRevert && first.Val.Equals(head))
+				if !follows {
+					// the changes were published before the subscription, or the head moved past
+					// them, the reader replays the path to the current head so that it stays
+					// consistent
+					newHead = store.GetHead()
+				}
+				if newHead.Equals(head) {
+					continue
+				}
+
+				// the changes are queued when they follow what the reader gets
+				if follows && (len(pending) > 0 || delivered.Equals(head)) {
+					if pendingLen+len(changes) <= maxPending {
+						pending = append(pending, headChanges{changes: changes, head: newHead})
+						pendingLen += len(changes)
+					} else {
+						log.Warnf("head change sub is slow, dropping %d pending changes", pendingLen)
+						pending, pendingLen = nil, 0
+					}
+				} else {
+					pending, pendingLen = nil, 0
+				}
+				head, replay = newHead, nil
+			case sendCh <- next:
+				if len(pending) > 0 {
+					delivered = pending[0].head
+					pendingLen -= len(next)
+					pending = pending[1:]
+				} else {
+					delivered, replay = replay.head, nil
+				}
+			case <-ctx.Done():
+				log.Infof("exit sub head change: %v", ctx.Err())
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// checkForkDepth returns an error when the chain of ts forks off the one of head more than
+// maxDepth epochs behind head.
+func (store *Store) checkForkDepth(ctx context.Context, ts, head *types.TipSet, maxDepth abi.ChainEpoch) error {
+	for cur := ts; ; {
+		if head.Height()-cur.Height() > maxDepth {
+			return fmt.Errorf("tipset %s forks off the chain more than %d epochs behind the head", ts.Key(), maxDepth)
+		}
+		onChain, err := store.isAncestor(ctx, cur, head)
+		if err != nil {
+			return err
+		}
+		if onChain {
+			return nil
+		}
+		if cur, err = store.GetTipSet(ctx, cur.Parents()); err != nil {
+			return err
+		}
+	}
+}
+
+// nextHeadChanges returns the first changes of the path from a to b, at most batchSize of them:
+// the reverts down to the chain of b, then the applies up to b.
+func (store *Store) nextHeadChanges(ctx context.Context, a, b *types.TipSet, batchSize int) (headChanges, error) {
+	var changes []*types.HeadChange
+	cur := a
+	for len(changes) < batchSize {
+		onChain, err := store.isAncestor(ctx, cur, b)
+		if err != nil {
+			return headChanges{}, err
+		}
+		if onChain {
+			break
+		}
+		changes = append(changes, &types.HeadChange{Type: types.HCRevert, Val: cur})
+		if cur, err = store.GetTipSet(ctx, cur.Parents()); err != nil {
+			return headChanges{}, err
+		}
+	}
+
+	if remaining := batchSize - len(changes); remaining > 0 && !cur.Equals(b) {
+		height := cur.Height() + abi.ChainEpoch(remaining)
+		if height > b.Height() {
+			height = b.Height()
+		}
+		top, err := store.GetTipSetByHeight(ctx, b, height, true)
+		if err == nil && top.Equals(cur) {
+			// only null rounds up to height, take the first tipset after them
+			top, err = store.GetTipSetByHeight(ctx, b, height, false)
+		}
+		if err != nil {
+			return headChanges{}, err
+		}
+
+		var applies []*types.TipSet
+		for ts := top; !ts.Equals(cur); {
+			applies = append(applies, ts)
+			if ts, err = store.GetTipSet(ctx, ts.Parents()); err != nil {
+				return headChanges{}, err
+			}
+		}
+		for i := len(applies) - 1; i >= 0; i-- {
+			changes = append(changes, &types.HeadChange{Type: types.HCApply, Val: applies[i]})
+		}
+		cur = top
+	}
+
+	return headChanges{changes: changes, head: cur}, nil
+}
+
+// isAncestor returns whether ts is on the chain of head.
+func (store *Store) isAncestor(ctx context.Context, ts, head *types.TipSet) (bool, error) {
+	if ts.Height() > head.Height() {
+		return false, nil
+	}
+	at, err := store.GetTipSetByHeight(ctx, head, ts.Height(), true)
+	if err != nil {
+		return false, err
+	}
+	return at.Equals(ts), nil
+}
+
 // SubscribeHeadChanges subscribe head change event
 func (store *Store) SubscribeHeadChanges(f ReorgNotifee) {
 	store.reorgNotifeeCh <- f
@@ -836,7 +1089,7 @@ func (store *Store) GenesisRootCid() cid.Cid {
 	return genesis.ParentStateRoot
 }
 
-func recurseLinks(ctx context.Context, bs blockstore.Blockstore, walked *cid.Set, root cid.Cid, in []cid.Cid) ([]cid.Cid, error) {
+func recurseLinks(ctx context.Context, bs blockstore.Blockstore, walked cidVisitor, root cid.Cid, in []cid.Cid) ([]cid.Cid, error) {
 	if multicodec.Code(root.Prefix().Codec) != multicodec.DagCbor {
 		return in, nil
 	}
@@ -1010,8 +1263,8 @@ func (store *Store) WalkSnapshot(ctx context.Context, ts *types.TipSet, inclRece
 	return nil
 }
 
-// Import import a car file into local db
-func (store *Store) Import(ctx context.Context, r io.Reader) (*types.TipSet, *types.BlockHeader, error) {
+// Import import a car file into local db, counting the blocks written in progress when it is not nil
+func (store *Store) Import(ctx context.Context, r io.Reader, progress *ImportProgress) (*types.TipSet, *types.BlockHeader, error) {
 	br, err := carv2.NewBlockReader(r)
 	if err != nil {
 		return nil, nil, fmt.Errorf("loadcar failed: %w", err)
@@ -1061,6 +1314,7 @@ func (store *Store) Import(ctx context.Context, r io.Reader) (*types.TipSet, *ty
 		}
 
 		buf = append(buf, blk)
+		progress.addBlocks(1)
 
 		if len(buf) > 1000 {
 			if lastErr := <-putThrottle; lastErr != nil { // consume one error to have the right to add one
@@ -549,3 +549,36 @@ func requireGetTipSetStateRoot(ctx context.Context, t *testing.T, chainStore tip
 	require.NoError(t, err)
 	return stateCid
 }
+
+func TestTipSetMetadataLoader(t *testing.T) {
+	tf.UnitTest(t)
+
+	ctx := context.Background()
+	builder := chain.NewBuilder(t, address.Undef)
+	genTS := builder.Genesis()
+	r := repo.NewInMemoryRepo()
+	cs := newChainStore(r, genTS)
+
+	ts := builder.AppendOn(ctx, genTS, 1)
+	requirePutBlocksToCborStore(t, cs.cborStore, ts.Blocks()...)
+	_, err := cs.LoadTipsetMetadata(ctx, ts)
+	require.Error(t, err)
+
+	// the metadata the store misses are loaded and persisted
+	loads := 0
+	cs.SetTipSetMetadataLoader(func(ctx context.Context, ts *types.TipSet) (*chain.TipSetMetadata, error) {
+		loads++
+		return &chain.TipSetMetadata{
+			TipSet:          ts,
+			TipSetStateRoot: ts.At(0).ParentStateRoot,
+			TipSetReceipts:  testhelpers.EmptyReceiptsCID,
+		}, nil
+	})
+	for i := 0; i < 2; i++ {
+		meta, err := cs.LoadTipsetMetadata(ctx, ts)
+		require.NoError(t, err)
+		require.Equal(t, ts.At(0).ParentStateRoot, meta.TipSetStateRoot)
+		require.Equal(t, testhelpers.EmptyReceiptsCID, meta.TipSetReceipts)
+	}
+	require.Equal(t, 1, loads)
+}
@@ -44,6 +44,7 @@ type Waiter struct {
 	messageProvider MessageProvider
 	cst             cbor.IpldStore
 	bs              bstore.Blockstore
+	finality        *FinalityCalculator
 	Stmgr           IStmgr
 }
 
@@ -56,6 +57,7 @@ func NewWaiter(chainStore waiterChainReader, messages MessageProvider, bs bstore
 		chainReader:     chainStore,
 		cst:             cst,
 		bs:              bs,
+		finality:        NewFinalityCalculator(chainStore),
 		messageProvider: messages,
 	}
 }
@@ -69,35 +71,67 @@ func (w *Waiter) Find(ctx context.Context, msg types.ChainMsg, lookback abi.Chai
 	return w.findMessage(ctx, ts, msg, lookback, allowReplaced)
 }
 
-// WaitPredicate invokes the callback when the passed predicate succeeds.
-// See api description.
-//
-// Note: this method does too much -- the callback should just receive the tipset
-// containing the message and the caller should pull the receipt out of the block
-// if in fact that's what it wants to do, using something like receiptFromTipset.
-// Something like receiptFromTipset is necessary because not every message in
-// a block will have a receipt in the tipset: it might be a duplicate message.
-// This method will always check for the message in the current head tipset.
-// A lookback parameter > 1 will cause this method to check for the message in
-// up to that many previous tipsets on the chain of the current head.
-func (w *Waiter) WaitPredicate(ctx context.Context, msg types.ChainMsg, confidence uint64, lookback abi.ChainEpoch, allowReplaced bool) (*types.ChainMessage, error) {
-	ch := w.chainReader.SubHeadChanges(ctx)
-	chainMsg, found, err := w.waitForMessage(ctx, ch, msg, confidence, lookback, allowReplaced)
+// FindFinal is Find, but only returns the message once the probability that the
+// tipset including it is reorged out of the chain of ts is at most reorgProbability.
+func (w *Waiter) FindFinal(ctx context.Context, msg types.ChainMsg, reorgProbability float64, lookback abi.ChainEpoch, ts *types.TipSet, allowReplaced bool) (*types.ChainMessage, bool, error) {
+	if ts == nil {
+		ts = w.chainReader.GetHead()
+	}
+
+	chainMsg, found, err := w.findMessage(ctx, ts, msg, lookback, allowReplaced)
+	if err != nil || !found {
+		return chainMsg, found, err
+	}
+	p, err := w.finality.ReorgProbability(ctx, ts, chainMsg.TS.Height())
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	if found {
-		return chainMsg, nil
+	if p > reorgProbability {
+		return nil, false, nil
 	}
-	return nil, nil
+	return chainMsg, true, nil
+}
+
+// WaitPredicate looks for a message with the given cid in the chain of the
+// current head, up to lookback tipsets back, and waits for it to appear on chain
+// when it is not found. The message is returned once the probability that the
+// tipset including it is reorged out, as given by the FinalityCalculator, is at
+// most reorgProbability.
+func (w *Waiter) WaitPredicate(ctx context.Context, msg types.ChainMsg, reorgProbability float64, lookback abi.ChainEpoch, allowReplaced bool) (*types.ChainMessage, error) {
+	return w.wait(ctx, msg, lookback, allowReplaced, func(ctx context.Context, ts, head *types.TipSet) (bool, error) {
+		p, err := w.finality.ReorgProbability(ctx, head, ts.Height())
+		if err != nil {
+			return false, err
+		}
+		return p <= reorgProbability, nil
+	})
 }
 
-// Wait uses WaitPredicate to invoke the callback when a message with the given cid appears on chain.
+// Wait invokes the callback when a message with the given cid appears on chain
+// and confidence epochs have been mined on top of it.
 func (w *Waiter) Wait(ctx context.Context, msg types.ChainMsg, confidence uint64, lookbackLimit abi.ChainEpoch, allowReplaced bool) (*types.ChainMessage, error) {
 	mid := msg.VMMessage().Cid()
 	log.Infof("Calling Waiter.Wait CID: %s", mid.String())
 
-	return w.WaitPredicate(ctx, msg, confidence, lookbackLimit, allowReplaced)
+	return w.wait(ctx, msg, lookbackLimit, allowReplaced, func(_ context.Context, ts, head *types.TipSet) (bool, error) {
+		return head.Height() >= ts.Height()+abi.ChainEpoch(confidence), nil
+	})
+}
+
+// confirmedFunc reports whether the tipset ts including a message is final enough
+// relative to head.
+type confirmedFunc func(ctx context.Context, ts, head *types.TipSet) (bool, error)
+
+func (w *Waiter) wait(ctx context.Context, msg types.ChainMsg, lookback abi.ChainEpoch, allowReplaced bool, confirmed confirmedFunc) (*types.ChainMessage, error) {
+	ch := w.chainReader.SubHeadChanges(ctx)
+	chainMsg, found, err := w.waitForMessage(ctx, ch, msg, confirmed, lookback, allowReplaced)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return chainMsg, nil
+	}
+	return nil, nil
 }
 
 // findMessage looks for a matching in the chain and returns the message,
@@ -170,7 +204,7 @@ func (w *Waiter) findMessage(ctx context.Context, from *types.TipSet, m types.Ch
 // channel closed without finding it), whether it was found, or an error.
 // notice matching mesage by message from and nonce. the return message may not be
 // expected, because there maybe another message have the same from and nonce value
-func (w *Waiter) waitForMessage(ctx context.Context, ch <-chan []*types.HeadChange, msg types.ChainMsg, confidence uint64, lookbackLimit abi.ChainEpoch, allowReplaced bool) (*types.ChainMessage, bool, error) {
+func (w *Waiter) waitForMessage(ctx context.Context, ch <-chan []*types.HeadChange, msg types.ChainMsg, confirmed confirmedFunc, lookbackLimit abi.ChainEpoch, allowReplaced bool) (*types.ChainMessage, bool, error) {
 	current, ok := <-ch
 	if !ok {
 		return nil, false, fmt.Errorf("SubHeadChanges stream was invalid")
@@ -209,7 +243,7 @@ func (w *Waiter) waitForMessage(ctx context.Context, ch <-chan []*types.HeadChan
 
 	var candidateTS *types.TipSet
 	var candidateRcp *types.ChainMessage
-	heightOfHead := currentHead.Height()
+	head := currentHead
 	reverts := map[string]bool{}
 
 	for {
@@ -229,8 +263,14 @@ func (w *Waiter) waitForMessage(ctx context.Context, ch <-chan []*types.HeadChan
 						reverts[val.Val.Key().String()] = true
 					}
 				case types.HCApply:
-					if candidateTS != nil && val.Val.Height() >= candidateTS.Height()+abi.ChainEpoch(confidence) {
-						return candidateRcp, true, nil
+					if candidateTS != nil {
+						ok, err := confirmed(ctx, candidateTS, val.Val)
+						if err != nil {
+							return nil, false, err
+						}
+						if ok {
+							return candidateRcp, true, nil
+						}
 					}
 
 					r, foundMsg, err := w.receiptForTipset(ctx, val.Val, msg, allowReplaced)
@@ -238,20 +278,28 @@ func (w *Waiter) waitForMessage(ctx context.Context, ch <-chan []*types.HeadChan
 						return nil, false, err
 					}
 					if r != nil {
-						if confidence == 0 {
-							return r, foundMsg, err
+						ok, err := confirmed(ctx, val.Val, val.Val)
+						if err != nil {
+							return nil, false, err
+						}
+						if ok {
+							return r, foundMsg, nil
 						}
 						candidateTS = val.Val
 						candidateRcp = r
 					}
-					heightOfHead = val.Val.Height()
+					head = val.Val
 				}
 			}
 		case <-backSearchWait:
 			// check if we found the message in the chain and that is hasn't been reverted since we started searching
 			if backRcp != nil && !reverts[backRcp.TS.Key().String()] {
 				// if head is at or past confidence interval, return immediately
-				if heightOfHead >= backRcp.TS.Height()+abi.ChainEpoch(confidence) {
+				ok, err := confirmed(ctx, backRcp.TS, head)
+				if err != nil {
+					return nil, false, err
+				}
+				if ok {
 					return backRcp, true, nil
 				}
 
@@ -51,6 +51,9 @@ func TestWaitRespectsContextCancel(t *testing.T) {
 		// stm: @CHAIN_WAITER_WAIT_001
 		chainMessage, err = waiter.Wait(ctx, newSignedMessage(0), constants.DefaultConfidence, constants.DefaultMessageWaitLookback, true)
 		doneCh <- err
+
+		chainMessage, err = waiter.WaitPredicate(ctx, newSignedMessage(0), DefaultFinalityThreshold, constants.DefaultMessageWaitLookback, true)
+		doneCh <- err
 	}()
 
 	cancel()
@@ -3,6 +3,8 @@ package chainsync
 import (
 	"context"
 
+	"github.com/ipfs/go-datastore"
+
 	chain2 "github.com/filecoin-project/venus/app/submodule/chain"
 	"github.com/filecoin-project/venus/pkg/chainsync/types"
 	"github.com/filecoin-project/venus/pkg/consensus"
@@ -33,6 +35,8 @@ var _ = (BlockProposer)((*dispatcher.Dispatcher)(nil))
 // Manager sync the chain.
 type Manager struct {
 	dispatcher *dispatcher.Dispatcher
+	syncer     *syncer.Syncer
+	badTipSets *types.BadTipSetCache
 }
 
 // NewManager creates a new chain sync manager.
@@ -41,18 +45,26 @@ func NewManager(
 	hv *consensus.BlockValidator,
 	submodule *chain2.ChainSubmodule,
 	bsstore blockstoreutil.Blockstore,
+	chainDs datastore.Batching,
 	exchangeClient exchange.Client,
 	c clock.Clock,
 	fork fork.IFork,
 ) (Manager, error) {
+	badTipSets, err := types.NewBadTipSetCache(chainDs, types.BadTipSetCacheSize)
+	if err != nil {
+		return Manager{}, err
+	}
+
 	chainSyncer, err := syncer.NewSyncer(stmgr, hv, submodule.ChainReader,
 		submodule.MessageStore, bsstore,
-		exchangeClient, c, fork)
+		exchangeClient, c, fork, badTipSets)
 	if err != nil {
 		return Manager{}, err
 	}
 
 	return Manager{
+		syncer:     chainSyncer,
+		badTipSets: badTipSets,
 		dispatcher: dispatcher.NewDispatcher(struct {
 			*syncer.Syncer
 			*consensus.BlockValidator
@@ -70,3 +82,13 @@ func (m *Manager) Start(ctx context.Context) error {
 func (m *Manager) BlockProposer() BlockProposer {
 	return m.dispatcher
 }
+
+// SyncCheckpoint switches the chain to the given tipset and keeps it as checkpoint.
+func (m *Manager) SyncCheckpoint(ctx context.Context, tsk types2.TipSetKey) error {
+	return m.syncer.SyncCheckpoint(ctx, tsk)
+}
+
+// BadTipSets returns the cache of the blocks the syncer rejected.
+func (m *Manager) BadTipSets() *types.BadTipSetCache {
+	return m.badTipSets
+}
@@ -3,126 +3,214 @@ package slashfilter
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/filecoin-project/go-address"
 	cborutil "github.com/filecoin-project/go-cbor-util"
+	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/builtin"
 	"github.com/filecoin-project/go-state-types/builtin/v8/miner"
+	"github.com/ipfs/go-cid"
+	ds "github.com/ipfs/go-datastore"
+	levelds "github.com/ipfs/go-ds-leveldb"
+	ldbopts "github.com/syndtr/goleveldb/leveldb/opt"
+
 	"github.com/filecoin-project/venus/pkg/config"
 	"github.com/filecoin-project/venus/venus-shared/actors"
-	v1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
 	"github.com/filecoin-project/venus/venus-shared/types"
-	levelds "github.com/ipfs/go-ds-leveldb"
-	ldbopts "github.com/syndtr/goleveldb/leveldb/opt"
 )
 
-func SlashConsensus(ctx context.Context,
-	cfg *config.FaultReporterConfig,
-	walletAPI v1.IWallet,
-	chainAPI v1.IChain,
-	mpoolAPI v1.IMessagePool,
-	syncAPI v1.ISyncer,
-) error {
-	ds, err := levelds.NewDatastore(cfg.ConsensusFaultReporterDataDir, &levelds.Options{
+// reportHeadPollInterval is the interval the head is polled at while a report waits for the
+// epoch of the fault to pass.
+var reportHeadPollInterval = 10 * time.Second
+
+// FaultReporterAPI is the part of the node API the consensus fault reporter uses.
+type FaultReporterAPI interface {
+	WalletDefaultAddress(ctx context.Context) (address.Address, error)
+	ChainHead(ctx context.Context) (*types.TipSet, error)
+	ChainGetBlock(ctx context.Context, id cid.Cid) (*types.BlockHeader, error)
+	MpoolPushMessage(ctx context.Context, msg *types.Message, spec *types.MessageSendSpec) (*types.SignedMessage, error)
+	SyncIncomingBlocks(ctx context.Context) (<-chan *types.BlockHeader, error)
+}
+
+// ConsensusFaultReporter watches the incoming blocks for the double-fork, time-offset and
+// parent-grinding faults of any miner, and reports them with ReportConsensusFault messages.
+type ConsensusFaultReporter struct {
+	cfg  *config.FaultReporterConfig
+	api  FaultReporterAPI
+	ds   ds.Batching
+	sf   ISlashFilter
+	from address.Address
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewConsensusFaultReporter creates a reporter keeping the blocks it saw in a leveldb datastore in
+// dataDir.
+func NewConsensusFaultReporter(cfg *config.FaultReporterConfig, dataDir string, api FaultReporterAPI) (*ConsensusFaultReporter, error) {
+	dstore, err := levelds.NewDatastore(dataDir, &levelds.Options{
 		Compression: ldbopts.NoCompression,
 		NoSync:      false,
 		Strict:      ldbopts.StrictAll,
 		ReadOnly:    false,
 	})
 	if err != nil {
-		return fmt.Errorf("open leveldb: %w", err)
+		return nil, fmt.Errorf("open leveldb: %w", err)
+	}
+
+	return newConsensusFaultReporter(cfg, dstore, api), nil
+}
+
+func newConsensusFaultReporter(cfg *config.FaultReporterConfig, dstore ds.Batching, api FaultReporterAPI) *ConsensusFaultReporter {
+	return &ConsensusFaultReporter{
+		cfg: cfg,
+		api: api,
+		ds:  dstore,
+		sf:  NewLocalSlashFilter(dstore),
 	}
-	sf := NewLocalSlashFilter(ds)
+}
 
-	var fromAddr address.Address
-	if cfg.ConsensusFaultReporterAddress == "" {
-		defaddr, err := walletAPI.WalletDefaultAddress(ctx)
+// Start resolves the reporting wallet and starts watching the incoming blocks.
+func (r *ConsensusFaultReporter) Start(ctx context.Context) error {
+	if r.cfg.ConsensusFaultReporterAddress == "" {
+		defaddr, err := r.api.WalletDefaultAddress(ctx)
 		if err != nil {
 			return err
 		}
-		fromAddr = defaddr
+		if defaddr.Empty() {
+			return fmt.Errorf("no consensus fault reporter address configured and no default wallet address")
+		}
+		r.from = defaddr
 	} else {
-		addr, err := address.NewFromString(cfg.ConsensusFaultReporterAddress)
+		addr, err := address.NewFromString(r.cfg.ConsensusFaultReporterAddress)
 		if err != nil {
 			return err
 		}
-
-		fromAddr = addr
+		r.from = addr
 	}
 
-	blocks, err := syncAPI.SyncIncomingBlocks(ctx)
+	ctx, r.cancel = context.WithCancel(ctx)
+	blocks, err := r.api.SyncIncomingBlocks(ctx)
 	if err != nil {
+		r.cancel()
 		return fmt.Errorf("sync incoming blocks failed: %w", err)
 	}
 
-	log.Infow("consensus fault reporter", "from", fromAddr)
+	log.Infow("consensus fault reporter", "from", r.from, "maxFee", r.cfg.ConsensusFaultReporterMaxFee)
+	r.wg.Add(1)
 	go func() {
-		for block := range blocks {
-			otherBlock, extraBlock, fault, err := slashFilterMinedBlock(ctx, sf, chainAPI, block)
+		defer r.wg.Done()
+		for {
+			var block *types.BlockHeader
+			select {
+			case block = <-blocks:
+				if block == nil {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+
+			otherBlock, extraBlock, fault, err := slashFilterMinedBlock(ctx, r.sf, r.api, block)
 			if err != nil {
 				log.Errorf("slash detector errored: %s", err)
 				continue
 			}
-			if fault {
-				log.Errorf("<!!> SLASH FILTER DETECTED FAULT DUE TO BLOCKS %s and %s", otherBlock.Cid(), block.Cid())
-				bh1, err := cborutil.Dump(otherBlock)
-				if err != nil {
-					log.Errorf("could not dump otherblock:%s, err:%s", otherBlock.Cid(), err)
-					continue
-				}
+			if !fault {
+				continue
+			}
 
-				bh2, err := cborutil.Dump(block)
-				if err != nil {
-					log.Errorf("could not dump block:%s, err:%s", block.Cid(), err)
-					continue
+			log.Errorf("<!!> SLASH FILTER DETECTED FAULT DUE TO BLOCKS %s and %s", otherBlock.Cid(), block.Cid())
+			r.wg.Add(1)
+			go func(block *types.BlockHeader) {
+				defer r.wg.Done()
+				if err := r.report(ctx, block, otherBlock, extraBlock); err != nil {
+					log.Errorf("failed to report the consensus fault of %s at %d: %s", block.Miner, block.Height, err)
 				}
+			}(block)
+		}
+	}()
 
-				params := miner.ReportConsensusFaultParams{
-					BlockHeader1: bh1,
-					BlockHeader2: bh2,
-				}
-				if extraBlock != nil {
-					be, err := cborutil.Dump(extraBlock)
-					if err != nil {
-						log.Errorf("could not dump block:%s, err:%s", block.Cid(), err)
-						continue
-					}
-					params.BlockHeaderExtra = be
-				}
+	return nil
+}
 
-				enc, err := actors.SerializeParams(&params)
-				if err != nil {
-					log.Errorf("could not serialize declare faults parameters: %s", err)
-					continue
-				}
-				for {
-					head, err := chainAPI.ChainHead(ctx)
-					if err != nil || head.Height() > block.Height {
-						break
-					}
-					time.Sleep(time.Second * 10)
-				}
-				message, err := mpoolAPI.MpoolPushMessage(ctx, &types.Message{
-					To:     block.Miner,
-					From:   fromAddr,
-					Value:  types.NewInt(0),
-					Method: builtin.MethodsMiner.ReportConsensusFault,
-					Params: enc,
-				}, nil)
-				if err != nil {
-					log.Errorf("ReportConsensusFault to messagepool error:%s", err)
-					continue
-				}
-				log.Infof("ReportConsensusFault message CID:%s", message.Cid())
-			}
+// report sends a ReportConsensusFault message for block and otherBlock, once the epoch of the fault passed.
+func (r *ConsensusFaultReporter) report(ctx context.Context, block, otherBlock, extraBlock *types.BlockHeader) error {
+	bh1, err := cborutil.Dump(otherBlock)
+	if err != nil {
+		return fmt.Errorf("could not dump otherblock:%s, err:%s", otherBlock.Cid(), err)
+	}
+
+	bh2, err := cborutil.Dump(block)
+	if err != nil {
+		return fmt.Errorf("could not dump block:%s, err:%s", block.Cid(), err)
+	}
+
+	params := miner.ReportConsensusFaultParams{
+		BlockHeader1: bh1,
+		BlockHeader2: bh2,
+	}
+	if extraBlock != nil {
+		be, err := cborutil.Dump(extraBlock)
+		if err != nil {
+			return fmt.Errorf("could not dump block:%s, err:%s", extraBlock.Cid(), err)
 		}
-	}()
+		params.BlockHeaderExtra = be
+	}
+
+	enc, err := actors.SerializeParams(&params)
+	if err != nil {
+		return fmt.Errorf("could not serialize declare faults parameters: %s", err)
+	}
+
+	if err := r.waitPast(ctx, block.Height); err != nil {
+		return err
+	}
+
+	message, err := r.api.MpoolPushMessage(ctx, &types.Message{
+		To:     block.Miner,
+		From:   r.from,
+		Value:  types.NewInt(0),
+		Method: builtin.MethodsMiner.ReportConsensusFault,
+		Params: enc,
+	}, &types.MessageSendSpec{MaxFee: abi.TokenAmount(r.cfg.ConsensusFaultReporterMaxFee)})
+	if err != nil {
+		return fmt.Errorf("ReportConsensusFault to messagepool error:%s", err)
+	}
+	log.Infof("ReportConsensusFault message CID:%s", message.Cid())
+
+	return nil
+}
 
-	return err
+// waitPast waits for the head to be above height, as faults are reported after their epoch.
+func (r *ConsensusFaultReporter) waitPast(ctx context.Context, height abi.ChainEpoch) error {
+	for {
+		head, err := r.api.ChainHead(ctx)
+		if err != nil || head.Height() > height {
+			return nil
+		}
+		select {
+		case <-time.After(reportHeadPollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Stop stops watching the blocks, waits for the pending reports and closes the datastore.
+func (r *ConsensusFaultReporter) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+	if err := r.ds.Close(); err != nil {
+		log.Warnf("failed to close the fault reporter datastore: %s", err)
+	}
 }
 
-func slashFilterMinedBlock(ctx context.Context, sf ISlashFilter, chainAPI v1.IChain, blockB *types.BlockHeader) (*types.BlockHeader, *types.BlockHeader, bool, error) {
+func slashFilterMinedBlock(ctx context.Context, sf ISlashFilter, chainAPI FaultReporterAPI, blockB *types.BlockHeader) (*types.BlockHeader, *types.BlockHeader, bool, error) {
 	blockC, err := chainAPI.ChainGetBlock(ctx, blockB.Parents[0])
 	if err != nil {
 		return nil, nil, false, fmt.Errorf("chain get block error:%s", err)
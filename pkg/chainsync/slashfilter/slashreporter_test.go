@@ -0,0 +1,115 @@
+package slashfilter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/builtin"
+	"github.com/ipfs/go-cid"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus/pkg/config"
+	"github.com/filecoin-project/venus/pkg/testhelpers"
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+type fakeFaultReporterAPI struct {
+	from   address.Address
+	blocks map[cid.Cid]*types.BlockHeader
+	head   *types.TipSet
+
+	incoming chan *types.BlockHeader
+	pushed   chan *types.Message
+	specs    chan *types.MessageSendSpec
+}
+
+func (f *fakeFaultReporterAPI) WalletDefaultAddress(context.Context) (address.Address, error) {
+	return f.from, nil
+}
+
+func (f *fakeFaultReporterAPI) ChainHead(context.Context) (*types.TipSet, error) {
+	return f.head, nil
+}
+
+func (f *fakeFaultReporterAPI) ChainGetBlock(_ context.Context, id cid.Cid) (*types.BlockHeader, error) {
+	return f.blocks[id], nil
+}
+
+func (f *fakeFaultReporterAPI) MpoolPushMessage(_ context.Context, msg *types.Message, spec *types.MessageSendSpec) (*types.SignedMessage, error) {
+	f.pushed <- msg
+	f.specs <- spec
+	return &types.SignedMessage{Message: *msg}, nil
+}
+
+func (f *fakeFaultReporterAPI) SyncIncomingBlocks(context.Context) (<-chan *types.BlockHeader, error) {
+	return f.incoming, nil
+}
+
+func newTestHeader(miner address.Address, height abi.ChainEpoch, timestamp uint64, parents ...cid.Cid) *types.BlockHeader {
+	return &types.BlockHeader{
+		Miner:                 miner,
+		Height:                height,
+		Parents:               parents,
+		Timestamp:             timestamp,
+		ParentWeight:          types.NewInt(0),
+		ParentBaseFee:         types.NewInt(0),
+		ParentStateRoot:       testhelpers.EmptyMessagesCID,
+		ParentMessageReceipts: testhelpers.EmptyMessagesCID,
+		Messages:              testhelpers.EmptyMessagesCID,
+	}
+}
+
+func TestConsensusFaultReporterDoubleFork(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	miner, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+	from, err := address.NewIDAddress(100)
+	require.NoError(t, err)
+
+	parent := newTestHeader(miner, 9, 0)
+	blockA := newTestHeader(miner, 10, 1, parent.Cid())
+	blockB := newTestHeader(miner, 10, 2, parent.Cid())
+	head, err := types.NewTipSet([]*types.BlockHeader{newTestHeader(miner, 11, 0, blockA.Cid())})
+	require.NoError(t, err)
+
+	api := &fakeFaultReporterAPI{
+		from: from,
+		blocks: map[cid.Cid]*types.BlockHeader{
+			parent.Cid(): parent,
+			blockA.Cid(): blockA,
+			blockB.Cid(): blockB,
+		},
+		head:     head,
+		incoming: make(chan *types.BlockHeader, 2),
+		pushed:   make(chan *types.Message, 1),
+		specs:    make(chan *types.MessageSendSpec, 1),
+	}
+
+	cfg := &config.FaultReporterConfig{
+		EnableConsensusFaultReporter: true,
+		ConsensusFaultReporterMaxFee: types.MustParseFIL("0.1"),
+	}
+	reporter := newConsensusFaultReporter(cfg, ds.NewMapDatastore(), api)
+	require.NoError(t, reporter.Start(ctx))
+	defer reporter.Stop()
+
+	api.incoming <- blockA
+	api.incoming <- blockB
+
+	select {
+	case msg := <-api.pushed:
+		require.Equal(t, miner, msg.To)
+		require.Equal(t, from, msg.From)
+		require.Equal(t, builtin.MethodsMiner.ReportConsensusFault, msg.Method)
+		require.Equal(t, abi.TokenAmount(cfg.ConsensusFaultReporterMaxFee), (<-api.specs).MaxFee)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the consensus fault was not reported")
+	}
+}
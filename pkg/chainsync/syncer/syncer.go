@@ -54,6 +54,8 @@ import (
 var (
 	// ErrForkTooLong is return when the syncing chain has fork with local
 	ErrForkTooLong = fmt.Errorf("fork longer than threshold")
+	// ErrForkCheckpoint is returned when the syncing chain forked from the local chain below the checkpoint.
+	ErrForkCheckpoint = errors.New("fork would require us to diverge from checkpointed chain")
 	// ErrChainHasBadTipSet is returned when the syncer traverses a chain with a cached bad tipset.
 	ErrChainHasBadTipSet = errors.New("input chain contains a cached bad tipset")
 	// ErrNewChainTooLong is returned when processing a fork that split off from the main chain too many blocks ago.
@@ -64,6 +66,28 @@ var (
 	logSyncer = logging.Logger("chainsync.syncer")
 )
 
+// invalidTipSetError is returned when a block of a tipset fails consensus
+// validation. Only such tipsets are added to the bad tipset cache: other
+// failures may be caused by the local node and say nothing of the chain.
+type invalidTipSetError struct {
+	err error
+}
+
+func (e *invalidTipSetError) Error() string {
+	return e.err.Error()
+}
+
+func (e *invalidTipSetError) Unwrap() error {
+	return e.err
+}
+
+// isInvalidTipSet reports whether the tipset failing with err is bad. A root
+// mismatch is not enough, the local state may be the wrong one.
+func isInvalidTipSet(err error) bool {
+	var invalid *invalidTipSetError
+	return errors.As(err, &invalid) && !isRootNotMatch(err)
+}
+
 // metrics handlers
 var (
 	// epoch should not use as a label, because it has a lot of values
@@ -155,6 +179,7 @@ func NewSyncer(stmgr *statemanger.Stmgr,
 	exchangeClient exchange.Client,
 	c clock.Clock,
 	fork fork.IFork,
+	badTipSets *syncTypes.BadTipSetCache,
 ) (*Syncer, error) {
 	if constants.InsecurePoStValidation {
 		logSyncer.Warn("*********************************************************************************************")
@@ -164,7 +189,7 @@ func NewSyncer(stmgr *statemanger.Stmgr,
 
 	syncer := &Syncer{
 		exchangeClient:  exchangeClient,
-		badTipSets:      syncTypes.NewBadTipSetCache(),
+		badTipSets:      badTipSets,
 		blockValidator:  hv,
 		bsstore:         bsstore,
 		chainStore:      s,
@@ -207,13 +232,17 @@ func (syncer *Syncer) syncOne(ctx context.Context, parent, next *types.TipSet) e
 			blk := next.At(i)
 			wg.Go(func() error {
 				// Fetch the URL.
-				err := syncer.blockValidator.ValidateFullBlock(ctx, blk)
-				if err == nil {
-					if err := syncer.chainStore.AddToTipSetTracker(ctx, blk); err != nil {
-						return fmt.Errorf("failed to add validated header to tipset tracker: %w", err)
+				if err := syncer.blockValidator.ValidateFullBlock(ctx, blk); err != nil {
+					// a block from the future or a cancelled sync may be valid later
+					if ctx.Err() != nil || errors.Is(err, consensus.ErrTemporal) {
+						return err
 					}
+					return &invalidTipSetError{err: err}
+				}
+				if err := syncer.chainStore.AddToTipSetTracker(ctx, blk); err != nil {
+					return fmt.Errorf("failed to add validated header to tipset tracker: %w", err)
 				}
-				return err
+				return nil
 			})
 		}
 		err = wg.Wait()
@@ -297,11 +326,18 @@ func (syncer *Syncer) HandleNewTipSet(ctx context.Context, target *syncTypes.Tar
 		return errors.New("do not sync to a target has synced before")
 	}
 
+	if reason, bad := syncer.badTipSets.HasTipSet(target.Head); bad {
+		return errors.Wrapf(ErrChainHasBadTipSet, "target %s: %s", target.Head.Key(), reason)
+	}
+
 	syncer.exchangeClient.AddPeer(target.Sender)
 	tipsets, err := syncer.fetchChainBlocks(ctx, head, target.Head)
 	if err != nil {
 		return errors.Wrapf(err, "failure fetching or validating headers")
 	}
+	if err := syncer.checkCheckpoint(ctx, tipsets[0]); err != nil {
+		return err
+	}
 	logSyncer.Debugf("fetch header success at %v %s ...", tipsets[0].Height(), tipsets[0].Key())
 
 	if err = syncer.syncSegement(ctx, target, tipsets); err == nil {
@@ -311,6 +347,84 @@ func (syncer *Syncer) HandleNewTipSet(ctx context.Context, target *syncTypes.Tar
 	return err
 }
 
+// checkCheckpoint rejects a chain whose first tipset forks from the local chain
+// below the checkpoint, such a chain can not contain the checkpoint.
+func (syncer *Syncer) checkCheckpoint(ctx context.Context, first *types.TipSet) error {
+	checkPoint, err := syncer.chainStore.GetTipSet(ctx, syncer.chainStore.GetCheckPoint())
+	if err != nil {
+		return fmt.Errorf("load checkpoint: %w", err)
+	}
+	parent, err := syncer.chainStore.GetTipSet(ctx, first.Parents())
+	if err != nil {
+		return fmt.Errorf("load parent of %s: %w", first.Key(), err)
+	}
+	if parent.Height() < checkPoint.Height() {
+		return errors.Wrapf(ErrForkCheckpoint, "chain forks at %d below checkpoint %s at %d", parent.Height(), checkPoint.Key(), checkPoint.Height())
+	}
+	return nil
+}
+
+// SyncCheckpoint fetches the tipset tsk if it is missing and syncs the chain up
+// to it. Unless the current head already descends from it, the tipset becomes
+// the new head. It is then written as the checkpoint of the chain store, which
+// survives restarts: forks that do not contain it are rejected from now on.
+func (syncer *Syncer) SyncCheckpoint(ctx context.Context, tsk types.TipSetKey) error {
+	if tsk.IsEmpty() {
+		return errors.New("called with empty tsk")
+	}
+
+	ts, err := syncer.chainStore.GetTipSet(ctx, tsk)
+	if err != nil {
+		tipsets, err := syncer.exchangeClient.GetBlocks(ctx, tsk, 1)
+		if err != nil {
+			return fmt.Errorf("fetch tipset %s: %w", tsk, err)
+		}
+		if len(tipsets) != 1 {
+			return fmt.Errorf("expected 1 tipset, got %d", len(tipsets))
+		}
+		ts = tipsets[0]
+		// the headers must be stored before the tipset can become the head,
+		// else the head can not be loaded after a restart
+		cborStore := cbor.NewCborStore(syncer.bsstore)
+		for _, blk := range ts.Blocks() {
+			if _, err := cborStore.Put(ctx, blk); err != nil {
+				return fmt.Errorf("store header of checkpoint %s: %w", tsk, err)
+			}
+		}
+	}
+
+	head := syncer.chainStore.GetHead()
+	onChain := false
+	if ts.Height() <= head.Height() {
+		ancestor, err := syncer.chainStore.GetTipSetByHeight(ctx, head, ts.Height(), false)
+		if err != nil {
+			return fmt.Errorf("load ancestor of head at %d: %w", ts.Height(), err)
+		}
+		onChain = ancestor.Equals(ts)
+	}
+
+	if !onChain {
+		logSyncer.Warnf("switching chain to checkpoint %s at %d", ts.Key(), ts.Height())
+		if !syncer.chainStore.HasTipSetAndState(ctx, ts) {
+			tipsets, err := syncer.fetchChainBlocks(ctx, head, ts)
+			if err != nil {
+				return fmt.Errorf("fetch chain of checkpoint: %w", err)
+			}
+			target := &syncTypes.Target{Head: ts, Base: head, Start: time.Now()}
+			if err := syncer.syncSegement(ctx, target, tipsets); err != nil {
+				return fmt.Errorf("sync chain of checkpoint: %w", err)
+			}
+			syncer.delayRunTx.update(ts)
+		}
+		if err := syncer.chainStore.SetHead(ctx, ts); err != nil {
+			return fmt.Errorf("set head to checkpoint: %w", err)
+		}
+	}
+
+	syncer.chainStore.SetCheckPoint(ts.Key())
+	return syncer.chainStore.WriteCheckPoint(ctx, ts.Key())
+}
+
 func (syncer *Syncer) syncSegement(ctx context.Context, target *syncTypes.Target, tipsets []*types.TipSet) error {
 	parent, err := syncer.chainStore.GetTipSet(ctx, tipsets[0].Parents())
 	if err != nil {
@@ -396,6 +510,9 @@ loop:
 	for chainTipsets[len(chainTipsets)-1].Height() > untilHeight {
 		tipSet, err := syncer.chainStore.GetTipSet(ctx, targetTip.Parents())
 		if err == nil {
+			if err := syncer.checkBadTipSet(tipSet, chainTipsets); err != nil {
+				return nil, err
+			}
 			chainTipsets = append(chainTipsets, tipSet)
 			targetTip = tipSet
 			count++
@@ -427,6 +544,9 @@ loop:
 			if b.Height() < untilHeight {
 				break loop
 			}
+			if err := syncer.checkBadTipSet(b, chainTipsets); err != nil {
+				return nil, err
+			}
 			chainTipsets = append(chainTipsets, b)
 			targetTip = b
 		}
@@ -458,23 +578,39 @@ loop:
 	fork, err := syncer.syncFork(ctx, base, knownTip)
 	if err != nil {
 		if errors.Is(err, ErrForkTooLong) {
-			// TODO: we're marking this block bad in the same way that we mark invalid blocks bad. Maybe distinguish?
-			logSyncer.Warn("adding forked chain to our bad tipset cache")
-			/*		for _, b := range incoming.Blocks() {
-					syncer.bad.Add(b.Cid(), NewBadBlockReason(incoming.Cids(), "fork past finality"))
-				}*/
+			// the bad tipset cache is persisted, a fork past our finality is not a
+			// consensus failure, so the forked chain is not marked bad
+			logSyncer.Warnf("forked chain %s goes past finality", base.Key())
 		}
 		return nil, fmt.Errorf("failed to sync fork: %w", err)
 	}
+	for _, ts := range fork {
+		if err := syncer.checkBadTipSet(ts, chainTipsets); err != nil {
+			return nil, err
+		}
+		chainTipsets = append(chainTipsets, ts)
+	}
 	err = flushDB(fork)
 	if err != nil {
 		return nil, err
 	}
-	chainTipsets = append(chainTipsets, fork...)
 	chain.Reverse(chainTipsets)
 	return chainTipsets, nil
 }
 
+// checkBadTipSet returns ErrChainHasBadTipSet when ts is in the bad tipset cache.
+// The descendants of ts are remembered too, so their headers are not fetched again.
+func (syncer *Syncer) checkBadTipSet(ts *types.TipSet, descendants []*types.TipSet) error {
+	reason, bad := syncer.badTipSets.HasTipSet(ts)
+	if !bad {
+		return nil
+	}
+	for _, child := range descendants {
+		syncer.badTipSets.AddTipSet(child, fmt.Sprintf("linked to bad tipset %s", ts.Key()))
+	}
+	return errors.Wrapf(ErrChainHasBadTipSet, "tipset %s: %s", ts.Key(), reason)
+}
+
 // syncFork tries to obtain the chain fragment that links a fork into a common
 // ancestor in our view of the chain.
 //
@@ -648,12 +784,11 @@ func (syncer *Syncer) processTipSetSegment(ctx context.Context, target *syncType
 	for i, ts := range segTipset {
 		err := syncer.syncOne(ctx, parent, ts)
 		if err != nil {
-			// While `syncOne` can indeed fail for reasons other than consensus,
-			// adding to the badTipSets at this point is the simplest, since we
-			// have access to the chain. If syncOne fails for non-consensus reasons,
-			// there is no assumption that the running node's data is valid at all,
-			// so we don't really lose anything with this simplification.
-			syncer.badTipSets.AddChain(segTipset[i:])
+			// only a tipset failing consensus validation is bad, the chain
+			// may be valid when syncOne fails for a local reason
+			if isInvalidTipSet(err) {
+				syncer.badTipSets.AddChain(segTipset[i:], err.Error())
+			}
 			return nil, errors.Wrapf(err, "failed to sync tipset %s, number %d of %d in chain", ts.Key().String(), i, len(segTipset))
 		}
 		parent = ts
@@ -43,7 +43,7 @@ func TestLoadFork(t *testing.T) {
 	require.NoError(t, err)
 
 	s, err := syncer.NewSyncer(stmgr, blockValidator, builder.Store(),
-		builder.Mstore(), builder.BlockStore(), builder, clock.NewFake(time.Unix(1234567890, 0)), fork.NewMockFork())
+		builder.Mstore(), builder.BlockStore(), builder, clock.NewFake(time.Unix(1234567890, 0)), fork.NewMockFork(), newBadTipSetCache(t, builder))
 
 	require.NoError(t, err)
 
@@ -100,7 +100,7 @@ func TestLoadFork(t *testing.T) {
 		builder.BlockStore(),
 		builder,
 		clock.NewFake(time.Unix(1234567890, 0)),
-		fork.NewMockFork())
+		fork.NewMockFork(), newBadTipSetCache(t, builder))
 	require.NoError(t, err)
 
 	assert.True(t, newStore.HasTipSetAndState(ctx, left))
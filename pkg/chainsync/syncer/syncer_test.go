@@ -2,6 +2,7 @@ package syncer_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -15,6 +16,8 @@ import (
 	"github.com/filecoin-project/venus/pkg/chainsync/syncer"
 	syncTypes "github.com/filecoin-project/venus/pkg/chainsync/types"
 	"github.com/filecoin-project/venus/pkg/clock"
+	"github.com/filecoin-project/venus/pkg/consensus"
+	"github.com/filecoin-project/venus/pkg/consensus/chainselector"
 	_ "github.com/filecoin-project/venus/pkg/crypto/bls"
 	_ "github.com/filecoin-project/venus/pkg/crypto/secp"
 	"github.com/filecoin-project/venus/pkg/fork"
@@ -213,6 +216,91 @@ func TestIgnoreLightFork(t *testing.T) {
 	verifyHead(t, builder.Store(), t4)
 }
 
+func TestSyncCheckpoint(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+	builder, s := setup(ctx, t)
+	genesis := builder.Store().GetHead()
+
+	forkbase := builder.AppendOn(ctx, genesis, 1)
+	forkHead := builder.AppendOn(ctx, forkbase, 1)
+
+	t1 := builder.AppendOn(ctx, forkbase, 1)
+	t2 := builder.AppendOn(ctx, t1, 1)
+	t3 := builder.AppendOn(ctx, t2, 1)
+
+	require.NoError(t, s.HandleNewTipSet(ctx, &syncTypes.Target{Head: t3}))
+	verifyHead(t, builder.Store(), t3)
+
+	// checkpointing the lighter fork switches the chain to it
+	require.NoError(t, s.SyncCheckpoint(ctx, forkHead.Key()))
+	require.NoError(t, builder.FlushHead(ctx))
+	verifyTip(t, builder.Store(), forkHead, builder.StateForKey(ctx, forkHead.Key()))
+	verifyHead(t, builder.Store(), forkHead)
+	assert.Equal(t, forkHead.Key(), builder.Store().GetCheckPoint())
+
+	// the checkpoint is kept on restart
+	restarted := chain.NewStore(builder.Repo().ChainDatastore(), builder.BlockStore(), genesis.At(0).Cid(),
+		chain.NewMockCirculatingSupplyCalculator(), chainselector.Weight)
+	assert.Equal(t, forkHead.Key(), restarted.GetCheckPoint())
+
+	// a heavier chain that does not contain the checkpoint is rejected
+	t4 := builder.AppendOn(ctx, t3, 1)
+	err := s.HandleNewTipSet(ctx, &syncTypes.Target{Head: t4})
+	assert.ErrorIs(t, err, syncer.ErrForkCheckpoint)
+	verifyHead(t, builder.Store(), forkHead)
+}
+
+// remoteBlocks serves the tipsets that are missing from the local blockstore.
+type remoteBlocks struct {
+	*chain.Builder
+	tipsets map[types.TipSetKey]*types.TipSet
+}
+
+func (r *remoteBlocks) GetBlocks(ctx context.Context, tsk types.TipSetKey, count int) ([]*types.TipSet, error) {
+	if ts, ok := r.tipsets[tsk]; ok {
+		return []*types.TipSet{ts}, nil
+	}
+	return r.Builder.GetBlocks(ctx, tsk, count)
+}
+
+func TestSyncCheckpointFetchesTipSet(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+	builder := chain.NewBuilder(t, address.Undef)
+	eval := builder.FakeStateEvaluator()
+	stmgr, err := statemanger.NewStateManager(builder.Store(), builder.MessageStore(), eval, nil, nil, nil, nil, false)
+	require.NoError(t, err)
+	genesis := builder.Store().GetHead()
+
+	forkbase := builder.AppendOn(ctx, genesis, 1)
+	forkHead := builder.AppendOn(ctx, forkbase, 1)
+	t1 := builder.AppendOn(ctx, forkbase, 1)
+	t2 := builder.AppendOn(ctx, t1, 1)
+
+	// the headers of the checkpoint are only known to the network
+	for _, blk := range forkHead.Blocks() {
+		require.NoError(t, builder.BlockStore().DeleteBlock(ctx, blk.Cid()))
+	}
+	remote := &remoteBlocks{Builder: builder, tipsets: map[types.TipSetKey]*types.TipSet{forkHead.Key(): forkHead}}
+	s, err := syncer.NewSyncer(stmgr, eval, builder.Store(), builder.Mstore(), builder.BlockStore(), remote,
+		clock.NewFake(time.Unix(1234567890, 0)), fork.NewMockFork(), newBadTipSetCache(t, builder))
+	require.NoError(t, err)
+
+	require.NoError(t, s.HandleNewTipSet(ctx, &syncTypes.Target{Head: t2}))
+	verifyHead(t, builder.Store(), t2)
+
+	require.NoError(t, s.SyncCheckpoint(ctx, forkHead.Key()))
+	verifyHead(t, builder.Store(), forkHead)
+
+	// the head and the checkpoint can be loaded after a restart
+	restarted := chain.NewStore(builder.Repo().ChainDatastore(), builder.BlockStore(), genesis.At(0).Cid(),
+		chain.NewMockCirculatingSupplyCalculator(), chainselector.Weight)
+	require.NoError(t, restarted.Load(ctx))
+	verifyHead(t, restarted, forkHead)
+	assert.Equal(t, forkHead.Key(), restarted.GetCheckPoint())
+}
+
 func TestAcceptHeavierFork(t *testing.T) {
 	tf.UnitTest(t)
 	ctx := context.Background()
@@ -329,7 +417,7 @@ func TestNoUncessesaryFetch(t *testing.T) {
 		builder.BlockStore(),
 		builder,
 		clock.NewFake(time.Unix(1234567890, 0)),
-		fork.NewMockFork())
+		fork.NewMockFork(), newBadTipSetCache(t, builder))
 	require.NoError(t, err)
 
 	target2 := &syncTypes.Target{
@@ -460,8 +548,9 @@ func TestBlockNotLinkedRejected(t *testing.T) {
 }
 
 type poisonValidator struct {
-	headerFailureTS uint64
-	fullFailureTS   uint64
+	headerFailureTS   uint64
+	fullFailureTS     uint64
+	temporalFailureTS uint64
 }
 
 func newPoisonValidator(t *testing.T, headerFailure, fullFailure uint64) *poisonValidator {
@@ -477,6 +566,9 @@ func (pv *poisonValidator) RunStateTransition(ctx context.Context, ts *types.Tip
 }
 
 func (pv *poisonValidator) ValidateFullBlock(ctx context.Context, blk *types.BlockHeader) error {
+	if pv.temporalFailureTS == blk.Timestamp {
+		return fmt.Errorf("block from the future: %w", consensus.ErrTemporal)
+	}
 	if pv.headerFailureTS == blk.Timestamp {
 		return errors.New("val semantic fails on poison timestamp")
 	}
@@ -536,6 +628,71 @@ func TestSemanticallyBadTipSetFails(t *testing.T) {
 	err = syncer.HandleNewTipSet(ctx, target1)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "val semantic fails")
+
+	// the bad tipset is remembered and rejected without being validated again
+	err = syncer.HandleNewTipSet(ctx, &syncTypes.Target{Head: link1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cached bad tipset")
+	assert.Contains(t, err.Error(), "val semantic fails")
+}
+
+func TestBadAncestorRejectsHeaders(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+	eval := newPoisonValidator(t, 98, 99)
+	builder := chain.NewBuilder(t, address.Undef)
+
+	stmgr, err := statemanger.NewStateManager(builder.Store(), builder.MessageStore(), eval, nil, nil, nil, nil, false)
+	require.NoError(t, err)
+
+	builder, syncer := setupWithValidator(ctx, t, builder, stmgr, eval)
+	genesis := builder.Store().GetHead()
+
+	link1 := builder.BuildOneOn(ctx, genesis, func(bb *chain.BlockBuilder) {
+		bb.SetTimestamp(98) // poison header val
+	})
+	link2 := builder.AppendOn(ctx, link1, 1)
+
+	err = syncer.HandleNewTipSet(ctx, &syncTypes.Target{Head: link1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "val semantic fails")
+
+	// the headers of a child are rejected before its messages are fetched
+	err = syncer.HandleNewTipSet(ctx, &syncTypes.Target{Head: link2})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cached bad tipset")
+	err = syncer.HandleNewTipSet(ctx, &syncTypes.Target{Head: link2})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "linked to bad tipset")
+}
+
+func TestTemporalFailureNotBad(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+	eval := newPoisonValidator(t, 98, 99)
+	eval.temporalFailureTS = 97
+	builder := chain.NewBuilder(t, address.Undef)
+
+	stmgr, err := statemanger.NewStateManager(builder.Store(), builder.MessageStore(), eval, nil, nil, nil, nil, false)
+	require.NoError(t, err)
+
+	badTipSets := newBadTipSetCache(t, builder)
+	s, err := syncer.NewSyncer(stmgr, eval, builder.Store(), builder.Mstore(), builder.BlockStore(), builder,
+		clock.NewFake(time.Unix(1234567890, 0)), fork.NewMockFork(), badTipSets)
+	require.NoError(t, err)
+	genesis := builder.Store().GetHead()
+
+	link1 := builder.BuildOneOn(ctx, genesis, func(bb *chain.BlockBuilder) {
+		bb.SetTimestamp(97)
+	})
+
+	// a block from the future may become valid, it is validated again
+	for i := 0; i < 2; i++ {
+		err = s.HandleNewTipSet(ctx, &syncTypes.Target{Head: link1})
+		require.ErrorIs(t, err, consensus.ErrTemporal)
+	}
+	_, bad := badTipSets.HasTipSet(link1)
+	assert.False(t, bad)
 }
 
 // TODO: fix test
@@ -600,12 +757,18 @@ func setupWithValidator(ctx context.Context, t *testing.T, builder *chain.Builde
 		builder.BlockStore(),
 		builder,
 		clock.NewFake(time.Unix(1234567890, 0)),
-		fork.NewMockFork())
+		fork.NewMockFork(), newBadTipSetCache(t, builder))
 	require.NoError(t, err)
 
 	return builder, syncer
 }
 
+func newBadTipSetCache(t *testing.T, builder *chain.Builder) *syncTypes.BadTipSetCache {
+	cache, err := syncTypes.NewBadTipSetCache(builder.Repo().ChainDatastore(), syncTypes.BadTipSetCacheSize)
+	require.NoError(t, err)
+	return cache
+}
+
 // /// Verification helpers /////
 
 // Sub-interface of the bsstore used for verification.
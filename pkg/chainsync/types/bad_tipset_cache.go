@@ -1,48 +1,129 @@
 package types
 
 import (
-	"sync"
+	"context"
+	"fmt"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/ipfs/go-cid"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/ipfs/go-datastore/query"
 
 	"github.com/filecoin-project/venus/venus-shared/types"
 )
 
+// BadTipSetCacheSize is the number of bad blocks remembered by default.
+const BadTipSetCacheSize = 1 << 15
+
+var badBlocksPrefix = ds.NewKey("/chainsync/badblocks")
+
 // BadTipSetCache keeps track of bad tipsets that the syncer should not try to
-// download. Readers and writers grab a lock. The purpose of this cache is to
-// prevent a node from having to repeatedly invalidate a block (and its children)
-// in the event that the tipset does not conform to the rules of consensus. Note
-// that the cache is only in-memory, so it is reset whenever the node is restarted.
-// TODO: this needs to be limited.
+// download. The purpose of this cache is to prevent a node from having to
+// repeatedly invalidate a block (and its children) in the event that the tipset
+// does not conform to the rules of consensus. The blocks of a bad tipset are
+// recorded together with the reason they were rejected, and persisted in the
+// chain datastore so the cache survives a restart. Only the most recently
+// marked blocks are kept once the cache is full.
 type BadTipSetCache struct {
-	mu  sync.Mutex
-	bad map[string]struct{}
+	ds  ds.Datastore
+	bad *lru.Cache[cid.Cid, string]
 }
 
-func NewBadTipSetCache() *BadTipSetCache {
-	return &BadTipSetCache{
-		bad: make(map[string]struct{}),
+// NewBadTipSetCache loads the bad blocks persisted in dstore and returns a
+// cache remembering at most size blocks.
+func NewBadTipSetCache(dstore ds.Batching, size int) (*BadTipSetCache, error) {
+	cache := &BadTipSetCache{
+		ds: namespace.Wrap(dstore, badBlocksPrefix),
+	}
+
+	bad, err := lru.NewWithEvict(size, cache.onEvict)
+	if err != nil {
+		return nil, err
+	}
+	cache.bad = bad
+
+	ctx := context.TODO()
+	res, err := cache.ds.Query(ctx, query.Query{})
+	if err != nil {
+		return nil, fmt.Errorf("query bad blocks: %w", err)
 	}
+	entries, err := res.Rest()
+	if err != nil {
+		return nil, fmt.Errorf("load bad blocks: %w", err)
+	}
+
+	for _, e := range entries {
+		c, err := cid.Decode(ds.RawKey(e.Key).BaseNamespace())
+		if err != nil {
+			log.Warnf("drop invalid bad block key %s: %v", e.Key, err)
+			_ = cache.ds.Delete(ctx, ds.RawKey(e.Key))
+			continue
+		}
+		cache.bad.Add(c, string(e.Value))
+	}
+
+	return cache, nil
 }
 
-// AddChain adds the chain of tipsets to the BadTipSetCache.  For now it just
-// does the simplest thing and adds all blocks of the chain to the cache.
-// TODO: might want to cache a random subset once cache size is limited.
-func (cache *BadTipSetCache) AddChain(chain []*types.TipSet) {
-	for _, ts := range chain {
-		cache.Add(ts.String())
+func (cache *BadTipSetCache) onEvict(c cid.Cid, _ string) {
+	if err := cache.ds.Delete(context.TODO(), ds.NewKey(c.String())); err != nil {
+		log.Warnf("remove bad block %s from datastore: %v", c, err)
 	}
 }
 
-// Add adds a single tipset key to the BadTipSetCache.
-func (cache *BadTipSetCache) Add(tsKey string) {
-	cache.mu.Lock()
-	defer cache.mu.Unlock()
-	cache.bad[tsKey] = struct{}{}
+// AddChain adds the chain of tipsets to the BadTipSetCache. The first tipset
+// is recorded with reason, the following ones as descendants of it.
+func (cache *BadTipSetCache) AddChain(chain []*types.TipSet, reason string) {
+	if len(chain) == 0 {
+		return
+	}
+	cache.AddTipSet(chain[0], reason)
+
+	linked := fmt.Sprintf("linked to bad tipset %s", chain[0].Key())
+	for _, ts := range chain[1:] {
+		cache.AddTipSet(ts, linked)
+	}
 }
 
-// Has checks for membership in the BadTipSetCache.
-func (cache *BadTipSetCache) Has(tsKey string) bool {
-	cache.mu.Lock()
-	defer cache.mu.Unlock()
-	_, ok := cache.bad[tsKey]
-	return ok
+// AddTipSet adds all blocks of a tipset to the BadTipSetCache.
+func (cache *BadTipSetCache) AddTipSet(ts *types.TipSet, reason string) {
+	for _, c := range ts.Cids() {
+		cache.Add(c, reason)
+	}
+}
+
+// Add adds a single block to the BadTipSetCache.
+func (cache *BadTipSetCache) Add(c cid.Cid, reason string) {
+	cache.bad.Add(c, reason)
+	if err := cache.ds.Put(context.TODO(), ds.NewKey(c.String()), []byte(reason)); err != nil {
+		log.Warnf("persist bad block %s: %v", c, err)
+	}
+}
+
+// Remove removes a single block from the BadTipSetCache.
+func (cache *BadTipSetCache) Remove(c cid.Cid) {
+	// the eviction callback removes the block from the datastore
+	cache.bad.Remove(c)
+}
+
+// Purge removes all blocks from the BadTipSetCache.
+func (cache *BadTipSetCache) Purge() {
+	cache.bad.Purge()
+}
+
+// Has checks for membership in the BadTipSetCache and returns the reason the
+// block was marked bad.
+func (cache *BadTipSetCache) Has(c cid.Cid) (string, bool) {
+	return cache.bad.Get(c)
+}
+
+// HasTipSet checks whether any block of the tipset is in the BadTipSetCache.
+func (cache *BadTipSetCache) HasTipSet(ts *types.TipSet) (string, bool) {
+	for _, c := range ts.Cids() {
+		if reason, ok := cache.Has(c); ok {
+			return fmt.Sprintf("block %s: %s", c, reason), true
+		}
+	}
+	return "", false
 }
@@ -4,29 +4,92 @@ package types
 import (
 	"testing"
 
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
 	"github.com/filecoin-project/venus/venus-shared/testutil"
 	"github.com/filecoin-project/venus/venus-shared/types"
-	"github.com/stretchr/testify/assert"
 )
 
 func TestBadTipsetCache(t *testing.T) {
 	tf.UnitTest(t)
-	badTSCache := NewBadTipSetCache()
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	badTSCache, err := NewBadTipSetCache(ds, BadTipSetCacheSize)
+	require.NoError(t, err)
 
-	var ts types.TipSet
-	testutil.Provide(t, &ts)
+	parent, child := newTipSet(t), newTipSet(t)
 
 	// stm: @CHAINSYNC_TYPES_ADD_CHAIN_001
-	badTSCache.AddChain([]*types.TipSet{&ts})
+	badTSCache.AddChain([]*types.TipSet{parent, child}, "invalid state root")
 
-	var tsKey types.TipSetKey
-	testutil.Provide(t, &tsKey, testutil.WithSliceLen(3))
+	var c cid.Cid
+	testutil.Provide(t, &c)
 
 	// stm: @CHAINSYNC_TYPES_ADD_001
-	badTSCache.Add(tsKey.String())
+	badTSCache.Add(c, "marked by user")
 
 	// stm: @CHAINSYNC_TYPES_HAS_001
-	assert.True(t, badTSCache.Has(ts.Key().String()))
-	assert.True(t, badTSCache.Has(tsKey.String()))
+	reason, ok := badTSCache.Has(parent.Cids()[0])
+	assert.True(t, ok)
+	assert.Equal(t, "invalid state root", reason)
+
+	reason, ok = badTSCache.HasTipSet(child)
+	assert.True(t, ok)
+	assert.Contains(t, reason, parent.Key().String())
+
+	// the cache is restored from the datastore
+	reloaded, err := NewBadTipSetCache(ds, BadTipSetCacheSize)
+	require.NoError(t, err)
+	reason, ok = reloaded.Has(c)
+	assert.True(t, ok)
+	assert.Equal(t, "marked by user", reason)
+
+	reloaded.Remove(c)
+	_, ok = reloaded.Has(c)
+	assert.False(t, ok)
+
+	reloaded.Purge()
+	_, ok = reloaded.HasTipSet(parent)
+	assert.False(t, ok)
+
+	reloaded, err = NewBadTipSetCache(ds, BadTipSetCacheSize)
+	require.NoError(t, err)
+	_, ok = reloaded.HasTipSet(child)
+	assert.False(t, ok)
+}
+
+func newTipSet(t *testing.T) *types.TipSet {
+	var blk types.BlockHeader
+	testutil.Provide(t, &blk)
+	ts, err := types.NewTipSet([]*types.BlockHeader{&blk})
+	require.NoError(t, err)
+	return ts
+}
+
+func TestBadTipsetCacheBounded(t *testing.T) {
+	tf.UnitTest(t)
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	badTSCache, err := NewBadTipSetCache(ds, 2)
+	require.NoError(t, err)
+
+	var cids []cid.Cid
+	testutil.Provide(t, &cids, testutil.WithSliceLen(3))
+	for _, c := range cids {
+		badTSCache.Add(c, "bad")
+	}
+
+	_, ok := badTSCache.Has(cids[0])
+	assert.False(t, ok)
+
+	// evicted blocks are removed from the datastore as well
+	reloaded, err := NewBadTipSetCache(ds, 3)
+	require.NoError(t, err)
+	_, ok = reloaded.Has(cids[0])
+	assert.False(t, ok)
+	_, ok = reloaded.Has(cids[2])
+	assert.True(t, ok)
 }
@@ -43,6 +43,9 @@ type Config struct {
 	EventsConfig  *EventsConfig        `json:"events"`
 	PubsubConfig  *PubsubConfig        `json:"pubsub"`
 	FaultReporter *FaultReporterConfig `json:"faultReporter"`
+	Disputer      *DisputerConfig      `json:"disputer"`
+	EventSinks    *EventSinksConfig    `json:"eventSinks"`
+	Follower      *FollowerConfig      `json:"follower"`
 }
 
 // APIConfig holds all configuration options related to the api.
@@ -81,6 +84,32 @@ func newDefaultAPIConfig() *APIConfig {
 type DatastoreConfig struct {
 	Type string `json:"type"`
 	Path string `json:"path"`
+
+	// SplitStore only takes effect when Type is "splitstore".
+	SplitStore *SplitStoreConfig `json:"splitstore,omitempty"`
+}
+
+// SplitStoreConfig holds the configuration of the hot/cold split blockstore.
+// The hot store keeps the chain headers and the most recent state and messages,
+// everything older is moved to (or discarded from) the cold store during compaction.
+type SplitStoreConfig struct {
+	// ColdStoreType is one of "universal" and "discard".
+	// "universal" moves unreachable objects from the hot store into the cold store,
+	// "discard" deletes them, the cold store is still used for reading old objects.
+	ColdStoreType string `json:"coldStoreType"`
+
+	// HotStorePath is the directory of the hot store, relative to the repo root.
+	// The cold store lives in Datastore.Path, so an existing badger store becomes
+	// the cold store after switching to the splitstore.
+	HotStorePath string `json:"hotStorePath"`
+
+	// HotStoreFinalityRetention is how many finalities of state and messages are
+	// kept in the hot store after a compaction.
+	HotStoreFinalityRetention uint64 `json:"hotStoreFinalityRetention"`
+
+	// CompactionFinalityInterval is how many finalities the head has to advance
+	// since the last compaction before an automatic compaction is started.
+	CompactionFinalityInterval uint64 `json:"compactionFinalityInterval"`
 }
 
 // Validators hold the list of validation functions for each configuration
@@ -94,8 +123,18 @@ var Validators = map[string]func(string, string) error{
 
 func newDefaultDatastoreConfig() *DatastoreConfig {
 	return &DatastoreConfig{
-		Type: "badgerds",
-		Path: "badger",
+		Type:       "badgerds",
+		Path:       "badger",
+		SplitStore: newDefaultSplitStoreConfig(),
+	}
+}
+
+func newDefaultSplitStoreConfig() *SplitStoreConfig {
+	return &SplitStoreConfig{
+		ColdStoreType:              "universal",
+		HotStorePath:               "hotstore",
+		HotStoreFinalityRetention:  4,
+		CompactionFinalityInterval: 1,
 	}
 }
 
@@ -117,6 +156,32 @@ type SwarmConfig struct {
 	// ConnMgrGrace is a time duration that new connections are immune from being
 	// closed by the connection manager.
 	ConnMgrGrace Duration `json:"connMgrGrace"`
+
+	// ResourceManager bounds the streams, connections, file descriptors and memory used by the peers.
+	ResourceManager *ResourceManagerConfig `json:"resourceManager"`
+}
+
+// ResourceManagerConfig holds the limits of the libp2p resource manager. The default limits
+// scale with the memory and the file descriptors of the host, the zero fields of the limits
+// below keep the default value and -1 removes the limit.
+type ResourceManagerConfig struct {
+	// Enable turns the resource manager on, when off the resources used by the peers are not limited.
+	Enable bool `json:"enable"`
+
+	// System bounds the resources used by the whole node.
+	System types.NetLimit `json:"system"`
+	// Transient bounds the resources used by the streams and connections not yet attached to a protocol or a peer.
+	Transient types.NetLimit `json:"transient"`
+
+	// ProtocolDefault bounds the resources used by each protocol.
+	ProtocolDefault types.NetLimit `json:"protocolDefault"`
+	// Protocols overrides ProtocolDefault for the given protocol ids.
+	Protocols map[string]types.NetLimit `json:"protocols"`
+
+	// PeerDefault bounds the resources used by each peer.
+	PeerDefault types.NetLimit `json:"peerDefault"`
+	// Peers overrides PeerDefault for the given peer ids.
+	Peers map[string]types.NetLimit `json:"peers"`
 }
 
 func newDefaultSwarmConfig() *SwarmConfig {
@@ -125,6 +190,9 @@ func newDefaultSwarmConfig() *SwarmConfig {
 		ConnMgrLow:   150,
 		ConnMgrHigh:  180,
 		ConnMgrGrace: Duration(20 * time.Second),
+		ResourceManager: &ResourceManagerConfig{
+			Enable: true,
+		},
 	}
 }
 
@@ -272,17 +340,39 @@ type MessagePoolConfig struct {
 	MaxNonceGap uint64 `json:"maxNonceGap"`
 	// MaxFee
 	MaxFee types.FIL `json:"maxFee"`
-}
+	// JournalType selects where the events of the message pool are journaled: "fs", the default,
+	// writes them to rolling ndjson files, without the add and remove events, "sqlite" records
+	// every event in a database queried by `venus mpool history`, and "none" disables the journal
+	JournalType string `json:"journalType"`
+	// JournalRetention is how long the sqlite journal keeps the events, zero keeps them forever
+	JournalRetention Duration `json:"journalRetention"`
+	// FeeHistoryWindow is the number of epochs whose fees are kept in the metadata datastore for
+	// the fee history, zero only keeps them in memory
+	FeeHistoryWindow int `json:"feeHistoryWindow"`
+}
+
+// The backends of the message pool journal.
+const (
+	MpoolJournalSqlite = "sqlite"
+	MpoolJournalFS     = "fs"
+	MpoolJournalNone   = "none"
+)
 
 var DefaultMessagePoolParam = &MessagePoolConfig{
-	MaxNonceGap: 100,
-	MaxFee:      DefaultDefaultMaxFee,
+	MaxNonceGap:      100,
+	MaxFee:           DefaultDefaultMaxFee,
+	JournalType:      MpoolJournalFS,
+	JournalRetention: Duration(7 * 24 * time.Hour),
+	FeeHistoryWindow: 1024,
 }
 
 func newDefaultMessagePoolConfig() *MessagePoolConfig {
 	return &MessagePoolConfig{
-		MaxNonceGap: 100,
-		MaxFee:      DefaultDefaultMaxFee,
+		MaxNonceGap:      100,
+		MaxFee:           DefaultDefaultMaxFee,
+		JournalType:      MpoolJournalFS,
+		JournalRetention: Duration(7 * 24 * time.Hour),
+		FeeHistoryWindow: 1024,
 	}
 }
 
@@ -455,6 +545,15 @@ type EventConfig struct {
 	// relative to the CWD (current working directory).
 	DatabasePath string `json:"databasePath"`
 
+	// DatabaseType selects where actor events are indexed: "sqlite" (the default) uses the database at
+	// DatabasePath, "postgres" and "mysql" use the database of Database, which can be shared by several nodes.
+	// A shared database is written by a single node, the first to connect, the others only read it and
+	// take over when the writer disconnects.
+	DatabaseType string `json:"databaseType"`
+
+	// Database is the connection to the postgres or mysql database used when DatabaseType is not sqlite.
+	Database MySQLConfig `json:"database"`
+
 	// Others, not implemented yet:
 	// Set a limit on the number of active websocket subscriptions (may be zero)
 	// Set a timeout for subscription clients
@@ -467,6 +566,8 @@ type FevmConfig struct {
 	// EthTxHashMappingLifetimeDays the transaction hash lookup database will delete mappings that have been stored for more than x days
 	// Set to 0 to keep all mappings
 	EthTxHashMappingLifetimeDays int `json:"ethTxHashMappingLifetimeDays"`
+	// EthTraceFilterMaxResults sets the maximum results returned per request by trace_filter
+	EthTraceFilterMaxResults uint64 `json:"ethTraceFilterMaxResults"`
 
 	Event EventConfig `json:"event"`
 }
@@ -483,6 +584,7 @@ func newFevmConfig() *FevmConfig {
 	return &FevmConfig{
 		EnableEthRPC:                 false,
 		EthTxHashMappingLifetimeDays: 0,
+		EthTraceFilterMaxResults:     500,
 		Event: EventConfig{
 			DisableRealTimeFilterAPI: false,
 			DisableHistoricFilterAPI: false,
@@ -490,6 +592,7 @@ func newFevmConfig() *FevmConfig {
 			MaxFilters:               100,
 			MaxFilterResults:         10000,
 			MaxFilterHeightRange:     2880, // conservative limit of one day
+			DatabaseType:             "sqlite",
 		},
 	}
 }
@@ -527,10 +630,120 @@ type FaultReporterConfig struct {
 	// ReportConsensusFault messages. It will pay for gas fees, and receive any
 	// rewards. This address should have adequate funds to cover gas fees.
 	ConsensusFaultReporterAddress string `json:"consensusFaultReporterAddress"`
+
+	// ConsensusFaultReporterMaxFee caps the fee of each ReportConsensusFault message.
+	ConsensusFaultReporterMaxFee types.FIL `json:"consensusFaultReporterMaxFee"`
 }
 
 func newFaultReporterConfig() *FaultReporterConfig {
-	return &FaultReporterConfig{}
+	return &FaultReporterConfig{
+		ConsensusFaultReporterMaxFee: types.MustParseFIL("0.5"),
+	}
+}
+
+type DisputerConfig struct {
+	// Enable starts the window post disputer with the daemon. It can also be started and
+	// stopped through the API, and then keeps running across restarts until it is stopped.
+	Enable bool `json:"enable"`
+
+	// From is the wallet address sending DisputeWindowedPoSt messages and receiving the
+	// rewards, the default wallet address when empty.
+	From string `json:"from"`
+
+	// MaxFee caps the fee of each DisputeWindowedPoSt message, the message pool default
+	// applies when zero.
+	MaxFee types.FIL `json:"maxFee"`
+
+	// StartEpoch is the epoch after which the disputer starts sending disputes.
+	StartEpoch abi.ChainEpoch `json:"startEpoch"`
+
+	// AllowMiners restricts the disputer to these miners when not empty.
+	AllowMiners []string `json:"allowMiners"`
+
+	// DenyMiners are the miners the disputer never disputes.
+	DenyMiners []string `json:"denyMiners"`
+}
+
+func newDisputerConfig() *DisputerConfig {
+	return &DisputerConfig{
+		MaxFee:      types.FIL(types.NewInt(0)),
+		AllowMiners: []string{},
+		DenyMiners:  []string{},
+	}
+}
+
+// EventSinksConfig configures the sinks the node pushes chain events to.
+type EventSinksConfig struct {
+	Sinks []*EventSinkConfig `json:"sinks"`
+}
+
+// EventSinkConfig configures a sink receiving a batch of the head change, actor events and
+// receipts of each tipset, at least once.
+type EventSinkConfig struct {
+	// Name identifies the sink and the cursor of what it received, it must be unique.
+	Name string `json:"name"`
+
+	// Type is the kind of sink, webhook or nats.
+	Type string `json:"type"`
+
+	// URL is the http url batches are posted to for a webhook, or the nats://[user:pass@]host:port
+	// of a NATS server.
+	URL string `json:"url"`
+
+	// Subject is the NATS subject batches are published on, venus.chain when empty. A JetStream
+	// stream must capture it, the stream acknowledges each batch it stores.
+	Subject string `json:"subject"`
+
+	// Headers are added to the webhook requests, e.g. for authentication.
+	Headers map[string]string `json:"headers"`
+
+	// Timeout bounds each delivery of a batch.
+	Timeout Duration `json:"timeout"`
+
+	// Confidence is the number of epochs a tipset waits before it is pushed, to avoid most reorgs.
+	Confidence abi.ChainEpoch `json:"confidence"`
+
+	// HeadChanges pushes the tipsets the chain applies and reverts.
+	HeadChanges bool `json:"headChanges"`
+
+	// ActorEvents pushes the actor events emitted by one of EventAddresses with EventFields, any
+	// event matches when they are empty.
+	ActorEvents    bool                               `json:"actorEvents"`
+	EventAddresses []string                           `json:"eventAddresses"`
+	EventFields    map[string][]types.ActorEventBlock `json:"eventFields"`
+
+	// Receipts pushes the receipts of the messages sent from or to one of ReceiptAddresses with one
+	// of ReceiptMethods, any message matches when they are empty.
+	Receipts         bool            `json:"receipts"`
+	ReceiptAddresses []string        `json:"receiptAddresses"`
+	ReceiptMethods   []abi.MethodNum `json:"receiptMethods"`
+}
+
+func newEventSinksConfig() *EventSinksConfig {
+	return &EventSinksConfig{Sinks: []*EventSinkConfig{}}
+}
+
+// FollowerConfig configures a read-only follower node, which does not sync the chain but reads it
+// from the blockstore of a leader node and follows the head of the leader.
+type FollowerConfig struct {
+	// Enable runs the node as a follower of LeaderAPI.
+	Enable bool `json:"enable"`
+
+	// LeaderAPI is the api address of the leader, e.g. /ip4/127.0.0.1/tcp/3453.
+	LeaderAPI string `json:"leaderAPI"`
+
+	// LeaderToken is a token of the leader with the read permission, and the sign permission to
+	// forward messages to the leader, which signs them with its wallet.
+	LeaderToken string `json:"leaderToken"`
+
+	// BlockCacheBytes bounds the size of the blocks read from the leader the node keeps in memory.
+	BlockCacheBytes int64 `json:"blockCacheBytes"`
+}
+
+func newFollowerConfig() *FollowerConfig {
+	return &FollowerConfig{
+		BlockCacheBytes: 1 << 30,
+	}
 }
 
 // NewDefaultConfig returns a config object with all the fields filled out to
@@ -551,6 +764,9 @@ func NewDefaultConfig() *Config {
 		EventsConfig:  newEventsConfig(),
 		PubsubConfig:  newPubsubConfig(),
 		FaultReporter: newFaultReporterConfig(),
+		Disputer:      newDisputerConfig(),
+		EventSinks:    newEventSinksConfig(),
+		Follower:      newFollowerConfig(),
 	}
 }
 
@@ -165,7 +165,7 @@ func (bv *BlockValidator) validateBlock(ctx context.Context, blk *types.BlockHea
 
 	now := uint64(time.Now().Unix())
 	if blk.Timestamp > now+bv.config.AllowableClockDriftSecs {
-		return fmt.Errorf("block was from the future (now=%d, blk=%d): %v", now, blk.Timestamp, ErrTemporal)
+		return fmt.Errorf("block was from the future (now=%d, blk=%d): %w", now, blk.Timestamp, ErrTemporal)
 	}
 	if blk.Timestamp > now {
 		logExpect.Warn("Got block from the future, but within threshold ", blk.Timestamp, time.Now().Unix())
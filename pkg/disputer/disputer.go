@@ -0,0 +1,729 @@
+package disputer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/dline"
+	builtin3 "github.com/filecoin-project/specs-actors/v3/actors/builtin"
+	miner3 "github.com/filecoin-project/specs-actors/v3/actors/builtin/miner"
+	"github.com/ipfs-force-community/metrics"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	logging "github.com/ipfs/go-log/v2"
+
+	"github.com/filecoin-project/venus/pkg/config"
+	"github.com/filecoin-project/venus/venus-shared/actors"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+var log = logging.Logger("disputer")
+
+// Confidence is the number of epochs waited after a deadline closed before its proofs are checked.
+const Confidence = 10
+
+var (
+	progressKey = datastore.NewKey("/disputer/progress")
+	historyKey  = datastore.NewKey("/disputer/history")
+)
+
+var (
+	proofsCheckedCt     = metrics.NewInt64WithCounter("disputer/proofs_checked", "Number of window post proofs checked by the disputer", "")
+	disputesSentCt      = metrics.NewCounter("disputer/disputes_sent", "Number of DisputeWindowedPoSt messages sent")
+	disputesSucceededCt = metrics.NewCounter("disputer/disputes_succeeded", "Number of DisputeWindowedPoSt messages that succeeded on chain")
+	disputesFailedCt    = metrics.NewCounter("disputer/disputes_failed", "Number of DisputeWindowedPoSt messages that could not be sent or failed on chain")
+)
+
+// statusCheckInterval is the interval at which new miners are looked for.
+var statusCheckInterval = time.Hour
+
+// API is the part of the node API the disputer uses.
+type API interface {
+	ChainNotify(ctx context.Context) (<-chan []*types.HeadChange, error)
+	StateListMiners(ctx context.Context, tsk types.TipSetKey) ([]address.Address, error)
+	StateMinerProvingDeadline(ctx context.Context, maddr address.Address, tsk types.TipSetKey) (*dline.Info, error)
+	StateMinerDeadlines(ctx context.Context, maddr address.Address, tsk types.TipSetKey) ([]types.Deadline, error)
+	StateCall(ctx context.Context, msg *types.Message, tsk types.TipSetKey) (*types.InvocResult, error)
+	StateLookupID(ctx context.Context, addr address.Address, tsk types.TipSetKey) (address.Address, error)
+	StateSearchMsg(ctx context.Context, from types.TipSetKey, msg cid.Cid, limit abi.ChainEpoch, allowReplaced bool) (*types.MsgLookup, error)
+	StateReplay(ctx context.Context, tsk types.TipSetKey, msg cid.Cid) (*types.InvocResult, error)
+	WalletDefaultAddress(ctx context.Context) (address.Address, error)
+	WalletHas(ctx context.Context, addr address.Address) (bool, error)
+	MpoolPushMessage(ctx context.Context, msg *types.Message, spec *types.MessageSendSpec) (*types.SignedMessage, error)
+}
+
+type minerDeadline struct {
+	Miner address.Address
+	Index uint64
+}
+
+// progress is what the disputer persists to resume where it stopped.
+type progress struct {
+	// Running is set while the disputer was started and not stopped
+	Running   bool
+	NextEpoch abi.ChainEpoch
+	// Deadlines are the deadlines of the miners, by the epoch their proofs are checked at
+	Deadlines map[abi.ChainEpoch][]minerDeadline
+
+	ProofsChecked     uint64
+	DisputesSent      uint64
+	DisputesSucceeded uint64
+	DisputesFailed    uint64
+	RewardsEarned     types.BigInt
+}
+
+// options are the disputer config resolved when it starts.
+type options struct {
+	from, fromID address.Address
+	spec         *types.MessageSendSpec
+	startEpoch   abi.ChainEpoch
+	allow, deny  map[address.Address]struct{}
+}
+
+func (o *options) allowed(miner address.Address) bool {
+	if _, ok := o.deny[miner]; ok {
+		return false
+	}
+	if len(o.allow) == 0 {
+		return true
+	}
+	_, ok := o.allow[miner]
+	return ok
+}
+
+// Disputer watches the window post deadlines of the miners once they closed, and disputes the
+// invalid proofs. Its progress and the disputes it sent are persisted, so it resumes after a restart.
+type Disputer struct {
+	api API
+	ds  datastore.Batching
+	cfg func() *config.DisputerConfig
+
+	lk      sync.Mutex
+	prog    progress
+	opts    *options
+	miners  int
+	pending map[cid.Cid]*types.DisputeRecord
+	err     string
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewDisputer loads the progress of the disputer from ds, cfg returns its current config.
+func NewDisputer(ctx context.Context, api API, ds datastore.Batching, cfg func() *config.DisputerConfig) (*Disputer, error) {
+	d := &Disputer{
+		api:     api,
+		ds:      ds,
+		cfg:     cfg,
+		pending: make(map[cid.Cid]*types.DisputeRecord),
+	}
+
+	data, err := ds.Get(ctx, progressKey)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &d.prog); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal disputer progress: %w", err)
+		}
+	case errors.Is(err, datastore.ErrNotFound):
+	default:
+		return nil, err
+	}
+	if d.prog.Deadlines == nil {
+		d.prog.Deadlines = make(map[abi.ChainEpoch][]minerDeadline)
+	}
+	if d.prog.RewardsEarned.Int == nil {
+		d.prog.RewardsEarned = big.Zero()
+	}
+	d.miners = countMiners(d.prog.Deadlines)
+
+	history, err := d.History(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range history {
+		if rec.State == types.DisputePending {
+			d.pending[rec.Message] = rec
+		}
+	}
+
+	return d, nil
+}
+
+// Resume starts the disputer when it is enabled in the config or was left running.
+func (d *Disputer) Resume(ctx context.Context) error {
+	d.lk.Lock()
+	running := d.prog.Running
+	d.lk.Unlock()
+
+	if !running && !d.cfg().Enable {
+		return nil
+	}
+	return d.Start(ctx)
+}
+
+// Start starts the disputer with its current config.
+func (d *Disputer) Start(ctx context.Context) error {
+	d.lk.Lock()
+	defer d.lk.Unlock()
+
+	if d.cancel != nil {
+		return fmt.Errorf("disputer is already running")
+	}
+
+	opts, err := d.resolveOptions(ctx, d.cfg())
+	if err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	headChanges, err := d.api.ChainNotify(runCtx)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	d.opts = opts
+	d.err = ""
+	d.prog.Running = true
+	if err := d.saveLocked(ctx); err != nil {
+		cancel()
+		return err
+	}
+
+	d.cancel = cancel
+	d.done = make(chan struct{})
+	go d.run(runCtx, headChanges, d.done)
+
+	log.Infow("starting window post disputer", "from", opts.from, "startEpoch", opts.startEpoch)
+	return nil
+}
+
+// Stop stops the disputer, it does not resume when the node restarts.
+func (d *Disputer) Stop(ctx context.Context) error {
+	halted := d.halt()
+
+	d.lk.Lock()
+	defer d.lk.Unlock()
+	// a disputer that stopped on an error still resumes with the node until it is stopped
+	if !halted && !d.prog.Running {
+		return fmt.Errorf("disputer is not running")
+	}
+	d.prog.Running = false
+	return d.saveLocked(ctx)
+}
+
+// Close stops the disputer when the node shuts down, it resumes when the node restarts.
+func (d *Disputer) Close() {
+	d.halt()
+}
+
+func (d *Disputer) halt() bool {
+	d.lk.Lock()
+	cancel, done := d.cancel, d.done
+	d.cancel, d.done = nil, nil
+	d.lk.Unlock()
+
+	if cancel == nil {
+		return false
+	}
+	cancel()
+	<-done
+	return true
+}
+
+// Status reports the progress of the disputer and the disputes it sent.
+func (d *Disputer) Status(ctx context.Context) *types.DisputerStatus {
+	d.lk.Lock()
+	defer d.lk.Unlock()
+
+	status := &types.DisputerStatus{
+		Running:           d.cancel != nil,
+		NextEpoch:         d.prog.NextEpoch,
+		Miners:            d.miners,
+		ProofsChecked:     d.prog.ProofsChecked,
+		DisputesSent:      d.prog.DisputesSent,
+		DisputesSucceeded: d.prog.DisputesSucceeded,
+		DisputesFailed:    d.prog.DisputesFailed,
+		RewardsEarned:     d.prog.RewardsEarned,
+		Error:             d.err,
+	}
+	if d.opts != nil {
+		status.From = d.opts.from
+	}
+	return status
+}
+
+// History returns the disputes sent, oldest first.
+func (d *Disputer) History(ctx context.Context) ([]*types.DisputeRecord, error) {
+	res, err := d.ds.Query(ctx, query.Query{Prefix: historyKey.String()})
+	if err != nil {
+		return nil, err
+	}
+	entries, err := res.Rest()
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*types.DisputeRecord, 0, len(entries))
+	for _, entry := range entries {
+		var rec types.DisputeRecord
+		if err := json.Unmarshal(entry.Value, &rec); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal dispute %s: %w", entry.Key, err)
+		}
+		history = append(history, &rec)
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].SentAt < history[j].SentAt
+	})
+	return history, nil
+}
+
+func (d *Disputer) resolveOptions(ctx context.Context, cfg *config.DisputerConfig) (*options, error) {
+	opts := &options{startEpoch: cfg.StartEpoch}
+
+	var err error
+	if cfg.From == "" {
+		if opts.from, err = d.api.WalletDefaultAddress(ctx); err != nil {
+			return nil, err
+		}
+		if opts.from.Empty() {
+			return nil, fmt.Errorf("no disputer address configured and no default wallet address")
+		}
+	} else {
+		if opts.from, err = address.NewFromString(cfg.From); err != nil {
+			return nil, fmt.Errorf("invalid disputer address %q: %w", cfg.From, err)
+		}
+		has, err := d.api.WalletHas(ctx, opts.from)
+		if err != nil {
+			return nil, err
+		}
+		if !has {
+			return nil, fmt.Errorf("wallet doesn't contain: %s", opts.from)
+		}
+	}
+	if opts.fromID, err = d.api.StateLookupID(ctx, opts.from, types.EmptyTSK); err != nil {
+		return nil, fmt.Errorf("looking up the id of %s: %w", opts.from, err)
+	}
+
+	if maxFee := abi.TokenAmount(cfg.MaxFee); maxFee.Int != nil && maxFee.GreaterThan(big.Zero()) {
+		opts.spec = &types.MessageSendSpec{MaxFee: maxFee}
+	}
+
+	if opts.allow, err = d.lookupMiners(ctx, cfg.AllowMiners); err != nil {
+		return nil, err
+	}
+	if opts.deny, err = d.lookupMiners(ctx, cfg.DenyMiners); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
+
+// lookupMiners returns the id addresses of the miners, as the ones StateListMiners returns.
+func (d *Disputer) lookupMiners(ctx context.Context, miners []string) (map[address.Address]struct{}, error) {
+	ids := make(map[address.Address]struct{}, len(miners))
+	for _, m := range miners {
+		addr, err := address.NewFromString(m)
+		if err != nil {
+			return nil, fmt.Errorf("invalid miner address %q: %w", m, err)
+		}
+		if addr, err = d.api.StateLookupID(ctx, addr, types.EmptyTSK); err != nil {
+			return nil, fmt.Errorf("looking up the id of %s: %w", m, err)
+		}
+		ids[addr] = struct{}{}
+	}
+	return ids, nil
+}
+
+func (d *Disputer) run(ctx context.Context, headChanges <-chan []*types.HeadChange, done chan struct{}) {
+	defer close(done)
+
+	if err := d.loop(ctx, headChanges); err != nil && !errors.Is(err, context.Canceled) {
+		log.Errorw("disputer shutting down", "err", err)
+		d.lk.Lock()
+		d.err = err.Error()
+		if d.cancel != nil {
+			d.cancel()
+			d.cancel, d.done = nil, nil
+		}
+		d.lk.Unlock()
+		return
+	}
+	log.Info("disputer shutting down")
+}
+
+func (d *Disputer) loop(ctx context.Context, headChanges <-chan []*types.HeadChange) error {
+	var head *types.TipSet
+	select {
+	case notif, ok := <-headChanges:
+		if !ok {
+			return fmt.Errorf("notify stream was invalid")
+		}
+		if len(notif) != 1 || notif[0].Type != types.HCCurrent {
+			return fmt.Errorf("expected current head on Notify stream")
+		}
+		head = notif[0].Val
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// a new disputer starts at the head, one that ran before checks the epochs it missed
+	d.lk.Lock()
+	if d.prog.NextEpoch == 0 {
+		d.prog.NextEpoch = head.Height()
+	}
+	d.lk.Unlock()
+	if err := d.checkMiners(ctx); err != nil {
+		return err
+	}
+	if err := d.applyTo(ctx, head); err != nil {
+		return err
+	}
+
+	// when this fires, check for newly created miners, and purge any "missed" epochs from the deadlines
+	statusCheckTicker := time.NewTicker(statusCheckInterval)
+	defer statusCheckTicker.Stop()
+
+	for {
+		select {
+		case notif, ok := <-headChanges:
+			if !ok {
+				return fmt.Errorf("head change channel errored")
+			}
+
+			for _, val := range notif {
+				switch val.Type {
+				case types.HCApply:
+					if err := d.applyTo(ctx, val.Val); err != nil {
+						return err
+					}
+				case types.HCRevert:
+					// do nothing
+				default:
+					return fmt.Errorf("unexpected head change type %s", val.Type)
+				}
+			}
+		case <-statusCheckTicker.C:
+			log.Infof("running status check")
+			if err := d.checkMiners(ctx); err != nil {
+				return err
+			}
+			log.Infof("status check complete")
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// applyTo checks the deadlines of the epochs up to ts, and the disputes that are pending.
+func (d *Disputer) applyTo(ctx context.Context, ts *types.TipSet) error {
+	for {
+		d.lk.Lock()
+		epoch := d.prog.NextEpoch
+		d.lk.Unlock()
+		if epoch > ts.Height() {
+			break
+		}
+
+		if err := d.applyEpoch(ctx, epoch, ts.Key()); err != nil {
+			return err
+		}
+
+		d.lk.Lock()
+		d.prog.NextEpoch++
+		d.miners = countMiners(d.prog.Deadlines)
+		err := d.saveLocked(ctx)
+		d.lk.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.checkPending(ctx, ts)
+}
+
+func (d *Disputer) applyEpoch(ctx context.Context, epoch abi.ChainEpoch, tsk types.TipSetKey) error {
+	log.Debugw("checking epoch", "epoch", epoch)
+	d.lk.Lock()
+	dls, ok := d.prog.Deadlines[epoch]
+	delete(d.prog.Deadlines, epoch)
+	d.lk.Unlock()
+	if !ok {
+		// no deadlines closed at this epoch - Confidence
+		return nil
+	}
+
+	var disputes []*types.DisputeRecord
+	startTime := time.Now()
+	proofsChecked := uint64(0)
+
+	for _, dl := range dls {
+		if !d.opts.allowed(dl.Miner) {
+			continue
+		}
+
+		if epoch > d.opts.startEpoch {
+			ds, n, err := d.checkDeadline(ctx, dl, tsk)
+			if err != nil {
+				log.Errorw("failed to check for disputes", "miner", dl.Miner, "deadline", dl.Index, "err", err)
+			}
+			disputes = append(disputes, ds...)
+			proofsChecked += n
+		}
+
+		if err := d.schedule(ctx, dl.Miner); err != nil {
+			log.Errorw("failed to schedule the next deadline", "miner", dl.Miner, "err", err)
+		}
+	}
+
+	if proofsChecked > 0 {
+		log.Infow("checked proofs", "count", proofsChecked, "duration", time.Since(startTime))
+		proofsCheckedCt.Inc(ctx, int64(proofsChecked))
+	}
+
+	for _, rec := range disputes {
+		rec.SentAt = epoch
+		d.send(ctx, rec)
+	}
+
+	d.lk.Lock()
+	d.prog.ProofsChecked += proofsChecked
+	d.lk.Unlock()
+
+	return nil
+}
+
+// checkDeadline tries to dispute the proofs of a deadline, and returns the disputes that are
+// expected to succeed with the number of proofs checked.
+func (d *Disputer) checkDeadline(ctx context.Context, dl minerDeadline, tsk types.TipSetKey) ([]*types.DisputeRecord, uint64, error) {
+	fullDeadlines, err := d.api.StateMinerDeadlines(ctx, dl.Miner, tsk)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load deadlines: %w", err)
+	}
+	if int(dl.Index) >= len(fullDeadlines) {
+		return nil, 0, fmt.Errorf("deadline index %d not found in deadlines", dl.Index)
+	}
+
+	disputableProofs := fullDeadlines[dl.Index].DisputableProofCount
+	var disputes []*types.DisputeRecord
+	for i := uint64(0); i < disputableProofs; i++ {
+		msg, err := disputeMessage(dl.Miner, d.opts.from, dl.Index, i)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		rslt, err := d.api.StateCall(ctx, msg, types.EmptyTSK)
+		if err == nil && rslt.MsgRct.ExitCode == 0 {
+			disputes = append(disputes, &types.DisputeRecord{
+				Miner:     dl.Miner,
+				Deadline:  dl.Index,
+				PoStIndex: i,
+			})
+		}
+	}
+
+	return disputes, disputableProofs, nil
+}
+
+func (d *Disputer) send(ctx context.Context, rec *types.DisputeRecord) {
+	log.Infow("disputing a PoSt", "miner", rec.Miner, "deadline", rec.Deadline, "postIndex", rec.PoStIndex)
+	rec.State = types.DisputePending
+	rec.Reward = big.Zero()
+
+	msg, err := disputeMessage(rec.Miner, d.opts.from, rec.Deadline, rec.PoStIndex)
+	if err == nil {
+		var sm *types.SignedMessage
+		if sm, err = d.api.MpoolPushMessage(ctx, msg, d.opts.spec); err == nil {
+			rec.Message = sm.Cid()
+		}
+	}
+
+	d.lk.Lock()
+	defer d.lk.Unlock()
+	if err != nil {
+		log.Errorw("failed to dispute post message", "err", err.Error(), "miner", rec.Miner)
+		disputesFailedCt.Tick(ctx)
+		d.prog.DisputesFailed++
+		return
+	}
+
+	log.Infow("submited dispute", "mcid", rec.Message, "miner", rec.Miner)
+	disputesSentCt.Tick(ctx)
+	d.prog.DisputesSent++
+	d.pending[rec.Message] = rec
+	if err := d.putRecord(ctx, rec); err != nil {
+		log.Errorw("failed to record dispute", "err", err, "miner", rec.Miner)
+	}
+}
+
+// checkPending looks for the pending disputes on chain, and records their outcome and reward.
+func (d *Disputer) checkPending(ctx context.Context, ts *types.TipSet) error {
+	d.lk.Lock()
+	pending := make([]*types.DisputeRecord, 0, len(d.pending))
+	for _, rec := range d.pending {
+		pending = append(pending, rec)
+	}
+	d.lk.Unlock()
+
+	for _, rec := range pending {
+		lookup, err := d.api.StateSearchMsg(ctx, ts.Key(), rec.Message, ts.Height()-rec.SentAt+1, true)
+		if err != nil {
+			log.Warnw("failed to look up dispute", "mcid", rec.Message, "err", err)
+			continue
+		}
+		if lookup == nil {
+			continue
+		}
+
+		updated := *rec
+		updated.ExitCode = lookup.Receipt.ExitCode
+		if lookup.Receipt.ExitCode.IsSuccess() {
+			updated.State = types.DisputeSucceeded
+			res, err := d.api.StateReplay(ctx, lookup.TipSet, lookup.Message)
+			if err != nil {
+				log.Warnw("failed to replay dispute", "mcid", lookup.Message, "err", err)
+			} else {
+				updated.Reward = received(res.ExecutionTrace.Subcalls, d.opts.fromID)
+			}
+		} else {
+			updated.State = types.DisputeFailed
+		}
+
+		d.lk.Lock()
+		delete(d.pending, rec.Message)
+		if updated.State == types.DisputeSucceeded {
+			disputesSucceededCt.Tick(ctx)
+			d.prog.DisputesSucceeded++
+			d.prog.RewardsEarned = big.Add(d.prog.RewardsEarned, updated.Reward)
+		} else {
+			disputesFailedCt.Tick(ctx)
+			d.prog.DisputesFailed++
+		}
+		err = d.putRecord(ctx, &updated)
+		if err == nil {
+			err = d.saveLocked(ctx)
+		}
+		d.lk.Unlock()
+		if err != nil {
+			return err
+		}
+		log.Infow("dispute landed", "mcid", lookup.Message, "miner", rec.Miner, "state", updated.State, "reward", types.FIL(updated.Reward))
+	}
+
+	return nil
+}
+
+// received sums the value sent to addr by the calls of a trace.
+func received(calls []types.ExecutionTrace, addr address.Address) abi.TokenAmount {
+	sum := big.Zero()
+	for _, call := range calls {
+		if call.Msg.To == addr && call.Msg.Value.Int != nil {
+			sum = big.Add(sum, call.Msg.Value)
+		}
+		sum = big.Add(sum, received(call.Subcalls, addr))
+	}
+	return sum
+}
+
+// checkMiners schedules the deadlines of the miners that are not watched yet, and purges the
+// epochs that were skipped.
+func (d *Disputer) checkMiners(ctx context.Context) error {
+	minerList, err := d.api.StateListMiners(ctx, types.EmptyTSK)
+	if err != nil {
+		return fmt.Errorf("getting miner list: %w", err)
+	}
+
+	d.lk.Lock()
+	known := make(map[address.Address]struct{})
+	for epoch, dls := range d.prog.Deadlines {
+		if epoch < d.prog.NextEpoch {
+			// if an epoch got "skipped" somehow, just fry it now instead of letting it sit around forever
+			log.Infow("epoch skipped during execution, deleting it from deadlines", "epoch", epoch)
+			delete(d.prog.Deadlines, epoch)
+			continue
+		}
+		for _, dl := range dls {
+			known[dl.Miner] = struct{}{}
+		}
+	}
+	d.lk.Unlock()
+
+	for _, m := range minerList {
+		if _, ok := known[m]; ok || !d.opts.allowed(m) {
+			continue
+		}
+		if err := d.schedule(ctx, m); err != nil {
+			return fmt.Errorf("making deadline: %w", err)
+		}
+	}
+
+	d.lk.Lock()
+	defer d.lk.Unlock()
+	d.miners = countMiners(d.prog.Deadlines)
+	return d.saveLocked(ctx)
+}
+
+// schedule adds the current proving deadline of the miner, to be checked Confidence epochs after it closes.
+func (d *Disputer) schedule(ctx context.Context, miner address.Address) error {
+	dl, err := d.api.StateMinerProvingDeadline(ctx, miner, types.EmptyTSK)
+	if err != nil {
+		return fmt.Errorf("getting proving index list: %w", err)
+	}
+
+	d.lk.Lock()
+	defer d.lk.Unlock()
+	epoch := dl.Close + Confidence
+	d.prog.Deadlines[epoch] = append(d.prog.Deadlines[epoch], minerDeadline{Miner: miner, Index: dl.Index})
+	return nil
+}
+
+func (d *Disputer) saveLocked(ctx context.Context) error {
+	data, err := json.Marshal(&d.prog)
+	if err != nil {
+		return fmt.Errorf("failed to marshal disputer progress: %w", err)
+	}
+	return d.ds.Put(ctx, progressKey, data)
+}
+
+func (d *Disputer) putRecord(ctx context.Context, rec *types.DisputeRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return d.ds.Put(ctx, historyKey.ChildString(rec.Message.String()), data)
+}
+
+func countMiners(deadlines map[abi.ChainEpoch][]minerDeadline) int {
+	n := 0
+	for _, dls := range deadlines {
+		n += len(dls)
+	}
+	return n
+}
+
+func disputeMessage(miner, from address.Address, deadline, postIndex uint64) (*types.Message, error) {
+	dpp, aerr := actors.SerializeParams(&miner3.DisputeWindowedPoStParams{
+		Deadline:  deadline,
+		PoStIndex: postIndex,
+	})
+	if aerr != nil {
+		return nil, fmt.Errorf("failed to serailize params: %w", aerr)
+	}
+
+	return &types.Message{
+		To:     miner,
+		From:   from,
+		Value:  big.Zero(),
+		Method: builtin3.MethodsMiner.DisputeWindowedPoSt,
+		Params: dpp,
+	}, nil
+}
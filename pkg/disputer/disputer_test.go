@@ -0,0 +1,194 @@
+package disputer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/dline"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus/pkg/config"
+	"github.com/filecoin-project/venus/pkg/testhelpers"
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+type fakeAPI struct {
+	from   address.Address
+	miners []address.Address
+	reward abi.TokenAmount
+
+	lk       sync.Mutex
+	head     *types.TipSet
+	notify   chan []*types.HeadChange
+	pushed   []*types.Message
+	deadline map[address.Address]int
+}
+
+func (f *fakeAPI) ChainNotify(ctx context.Context) (<-chan []*types.HeadChange, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	f.notify = make(chan []*types.HeadChange, 16)
+	f.notify <- []*types.HeadChange{{Type: types.HCCurrent, Val: f.head}}
+	return f.notify, nil
+}
+
+func (f *fakeAPI) apply(ts *types.TipSet) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	f.head = ts
+	f.notify <- []*types.HeadChange{{Type: types.HCApply, Val: ts}}
+}
+
+func (f *fakeAPI) StateListMiners(context.Context, types.TipSetKey) ([]address.Address, error) {
+	return f.miners, nil
+}
+
+func (f *fakeAPI) StateMinerProvingDeadline(_ context.Context, maddr address.Address, _ types.TipSetKey) (*dline.Info, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	// every miner has one deadline closing each 100 epochs
+	n := f.deadline[maddr]
+	f.deadline[maddr]++
+	return &dline.Info{Index: 3, Close: abi.ChainEpoch(100 * (n + 1))}, nil
+}
+
+func (f *fakeAPI) StateMinerDeadlines(context.Context, address.Address, types.TipSetKey) ([]types.Deadline, error) {
+	deadlines := make([]types.Deadline, 4)
+	deadlines[3].DisputableProofCount = 2
+	return deadlines, nil
+}
+
+func (f *fakeAPI) StateCall(context.Context, *types.Message, types.TipSetKey) (*types.InvocResult, error) {
+	return &types.InvocResult{MsgRct: &types.MessageReceipt{}}, nil
+}
+
+func (f *fakeAPI) StateLookupID(_ context.Context, addr address.Address, _ types.TipSetKey) (address.Address, error) {
+	return addr, nil
+}
+
+func (f *fakeAPI) StateSearchMsg(_ context.Context, _ types.TipSetKey, msg cid.Cid, _ abi.ChainEpoch, _ bool) (*types.MsgLookup, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	return &types.MsgLookup{Message: msg, TipSet: f.head.Key()}, nil
+}
+
+func (f *fakeAPI) StateReplay(context.Context, types.TipSetKey, cid.Cid) (*types.InvocResult, error) {
+	return &types.InvocResult{ExecutionTrace: types.ExecutionTrace{
+		Subcalls: []types.ExecutionTrace{{Msg: types.MessageTrace{To: f.from, Value: f.reward}}},
+	}}, nil
+}
+
+func (f *fakeAPI) WalletDefaultAddress(context.Context) (address.Address, error) {
+	return f.from, nil
+}
+
+func (f *fakeAPI) WalletHas(context.Context, address.Address) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeAPI) MpoolPushMessage(_ context.Context, msg *types.Message, _ *types.MessageSendSpec) (*types.SignedMessage, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	f.pushed = append(f.pushed, msg)
+	msg.Nonce = uint64(len(f.pushed))
+	return &types.SignedMessage{Message: *msg}, nil
+}
+
+func idAddr(t *testing.T, id uint64) address.Address {
+	addr, err := address.NewIDAddress(id)
+	require.NoError(t, err)
+	return addr
+}
+
+func newTipSet(t *testing.T, height abi.ChainEpoch) *types.TipSet {
+	ts, err := types.NewTipSet([]*types.BlockHeader{{
+		Miner:                 idAddr(t, 1),
+		Height:                height,
+		ParentWeight:          types.NewInt(0),
+		ParentBaseFee:         types.NewInt(0),
+		ParentStateRoot:       testhelpers.EmptyMessagesCID,
+		ParentMessageReceipts: testhelpers.EmptyMessagesCID,
+		Messages:              testhelpers.EmptyMessagesCID,
+	}})
+	require.NoError(t, err)
+	return ts
+}
+
+func TestDisputerResumes(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	allowed, denied := idAddr(t, 1000), idAddr(t, 1001)
+	api := &fakeAPI{
+		from:     idAddr(t, 100),
+		miners:   []address.Address{allowed, denied},
+		reward:   types.NewInt(5),
+		head:     newTipSet(t, 50),
+		deadline: make(map[address.Address]int),
+	}
+	cfg := &config.DisputerConfig{DenyMiners: []string{denied.String()}}
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+
+	d, err := NewDisputer(ctx, api, ds, func() *config.DisputerConfig { return cfg })
+	require.NoError(t, err)
+	require.NoError(t, d.Resume(ctx))
+	require.False(t, d.Status(ctx).Running)
+
+	require.NoError(t, d.Start(ctx))
+	require.Error(t, d.Start(ctx))
+	require.Eventually(t, func() bool { return d.Status(ctx).Miners == 1 }, 10*time.Second, 10*time.Millisecond)
+
+	// the deadline closing at 100 is checked at 100+Confidence, the denied miner is never checked
+	api.apply(newTipSet(t, 100+Confidence))
+	require.Eventually(t, func() bool { return d.Status(ctx).DisputesSucceeded == 2 }, 10*time.Second, 10*time.Millisecond)
+
+	status := d.Status(ctx)
+	require.Equal(t, api.from, status.From)
+	require.EqualValues(t, 101+Confidence, status.NextEpoch)
+	require.EqualValues(t, 2, status.DisputesSent)
+	require.EqualValues(t, 2, status.DisputesSucceeded)
+	require.Equal(t, big.NewInt(10), status.RewardsEarned)
+	api.lk.Lock()
+	pushed := api.pushed
+	api.lk.Unlock()
+	require.Len(t, pushed, 2)
+	for _, msg := range pushed {
+		require.Equal(t, allowed, msg.To)
+	}
+
+	history, err := d.History(ctx)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	for _, rec := range history {
+		require.Equal(t, types.DisputeSucceeded, rec.State)
+		require.Equal(t, big.NewInt(5), rec.Reward)
+		require.EqualValues(t, 100+Confidence, rec.SentAt)
+	}
+
+	// closing the node keeps the disputer running when it restarts, at the epoch it stopped
+	d.Close()
+	d, err = NewDisputer(ctx, api, ds, func() *config.DisputerConfig { return cfg })
+	require.NoError(t, err)
+	require.NoError(t, d.Resume(ctx))
+	status = d.Status(ctx)
+	require.True(t, status.Running)
+	require.EqualValues(t, 101+Confidence, status.NextEpoch)
+	require.EqualValues(t, 2, status.DisputesSucceeded)
+
+	// a stopped disputer does not resume
+	require.NoError(t, d.Stop(ctx))
+	require.Error(t, d.Stop(ctx))
+	d, err = NewDisputer(ctx, api, ds, func() *config.DisputerConfig { return cfg })
+	require.NoError(t, err)
+	require.NoError(t, d.Resume(ctx))
+	require.False(t, d.Status(ctx).Running)
+}
@@ -0,0 +1,200 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+type tipSetLoader interface {
+	GetTipSet(context.Context, types.TipSetKey) (*types.TipSet, error)
+}
+
+// Backfill indexes the events of the tipsets of the chain ending at head whose height is
+// within [from, to]. The events are read from the receipts, and their events AMTs, found in
+// the child of each tipset, so the messages of head itself are never indexed. Events already
+// in the index are kept, so it is safe to backfill a range more than once. visit, when not nil,
+// is called with each tipset of the range, so that other indexes are fed by the same walk. It
+// returns the number of tipsets and events visited.
+func (m *EventFilterManager) Backfill(ctx context.Context, cs tipSetLoader, head *types.TipSet, from, to abi.ChainEpoch, visit func(*types.TipSet) error) (int, int, error) {
+	if m.EventIndex == nil {
+		return 0, 0, fmt.Errorf("historic event index disabled")
+	}
+	if from > to {
+		return 0, 0, fmt.Errorf("from %d is after to %d", from, to)
+	}
+	if ok, err := m.EventIndex.writable(ctx); err != nil {
+		return 0, 0, err
+	} else if !ok {
+		return 0, 0, ErrNotIndexWriter
+	}
+
+	var tipsets, events int
+	child := head
+	for child.Height() > from {
+		if err := ctx.Err(); err != nil {
+			return tipsets, events, err
+		}
+
+		ts, err := cs.GetTipSet(ctx, child.Parents())
+		if err != nil {
+			return tipsets, events, fmt.Errorf("load parent of %s: %w", child.Key(), err)
+		}
+		if ts.Height() < from {
+			break
+		}
+
+		if ts.Height() <= to {
+			tse := &TipSetEvents{
+				msgTS: ts,
+				rctTS: child,
+				load:  m.loadExecutedMessages,
+			}
+
+			m.mu.Lock()
+			err = m.EventIndex.CollectEvents(ctx, tse, false, m.AddressResolver)
+			m.mu.Unlock()
+			if err != nil {
+				return tipsets, events, fmt.Errorf("index events of %s: %w", ts.Key(), err)
+			}
+
+			// the executed messages were loaded, and cached, by CollectEvents
+			ems, err := tse.messages(ctx)
+			if err != nil {
+				return tipsets, events, err
+			}
+			for _, em := range ems {
+				events += len(em.Events())
+			}
+			if visit != nil {
+				if err := visit(ts); err != nil {
+					return tipsets, events, err
+				}
+			}
+			tipsets++
+			if tipsets%1000 == 0 {
+				log.Infof("backfilled events down to height %d (remaining %d)", ts.Height(), ts.Height()-from)
+			}
+		}
+
+		child = ts
+	}
+
+	return tipsets, events, nil
+}
+
+// IndexValidation lists the inconsistencies found between the event index and the chain.
+type IndexValidation struct {
+	// TipSets is the number of tipsets of the chain that were checked
+	TipSets int
+	// Missing holds the heights of the tipsets whose events are not all indexed
+	Missing []abi.ChainEpoch
+	// Unreverted holds the heights of the tipsets no longer on the chain whose events are
+	// not marked as reverted
+	Unreverted []abi.ChainEpoch
+}
+
+// Validate compares the events indexed between from and to with the events of the chain ending
+// at head. When fix is set, the missing events are indexed and the events of the tipsets no longer
+// on the chain are marked as reverted. visit, when not nil, is called with each tipset of the
+// range, like for Backfill.
+func (m *EventFilterManager) Validate(ctx context.Context, cs tipSetLoader, head *types.TipSet, from, to abi.ChainEpoch, fix bool, visit func(*types.TipSet) error) (*IndexValidation, error) {
+	if m.EventIndex == nil {
+		return nil, fmt.Errorf("historic event index disabled")
+	}
+	if from > to {
+		return nil, fmt.Errorf("from %d is after to %d", from, to)
+	}
+	if fix {
+		if ok, err := m.EventIndex.writable(ctx); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, ErrNotIndexWriter
+		}
+	}
+
+	indexed, err := m.EventIndex.indexedTipSets(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &IndexValidation{}
+	canonical := make(map[abi.ChainEpoch]types.TipSetKey)
+	child := head
+	for child.Height() > from {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		ts, err := cs.GetTipSet(ctx, child.Parents())
+		if err != nil {
+			return nil, fmt.Errorf("load parent of %s: %w", child.Key(), err)
+		}
+		if ts.Height() < from {
+			break
+		}
+
+		if ts.Height() <= to {
+			canonical[ts.Height()] = ts.Key()
+
+			tse := &TipSetEvents{
+				msgTS: ts,
+				rctTS: child,
+				load:  m.loadExecutedMessages,
+			}
+			ems, err := tse.messages(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("load executed messages of %s: %w", ts.Key(), err)
+			}
+			var events int
+			for _, em := range ems {
+				events += len(em.Events())
+			}
+
+			if indexed[ts.Height()][ts.Key()] < events {
+				res.Missing = append(res.Missing, ts.Height())
+				if fix {
+					m.mu.Lock()
+					err = m.EventIndex.CollectEvents(ctx, tse, false, m.AddressResolver)
+					m.mu.Unlock()
+					if err != nil {
+						return nil, fmt.Errorf("index events of %s: %w", ts.Key(), err)
+					}
+				}
+			}
+			if visit != nil {
+				if err := visit(ts); err != nil {
+					return nil, err
+				}
+			}
+			res.TipSets++
+		}
+
+		child = ts
+	}
+
+	for height, keys := range indexed {
+		for key := range keys {
+			if canonical[height] == key {
+				continue
+			}
+			res.Unreverted = append(res.Unreverted, height)
+			if fix {
+				m.mu.Lock()
+				err = m.EventIndex.revertTipSet(ctx, height, key)
+				m.mu.Unlock()
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	sort.Slice(res.Missing, func(i, j int) bool { return res.Missing[i] < res.Missing[j] })
+	sort.Slice(res.Unreverted, func(i, j int) bool { return res.Unreverted[i] < res.Unreverted[j] })
+
+	return res, nil
+}
@@ -250,6 +250,28 @@ func (f *eventFilter) matchKeys(ees []types.EventEntry) bool {
 	return false
 }
 
+// EventMatcher matches events against the addresses and fields of an actor event filter, as an
+// installed filter does, for the consumers that collect the events themselves.
+type EventMatcher struct {
+	f *eventFilter
+}
+
+// NewEventMatcher returns a matcher of the events emitted by one of addresses with the fields,
+// empty addresses or fields match any event.
+func NewEventMatcher(addresses []address.Address, fields map[string][]types.ActorEventBlock) *EventMatcher {
+	return &EventMatcher{f: &eventFilter{
+		minHeight:     -1,
+		maxHeight:     -1,
+		addresses:     addresses,
+		keysWithCodec: fields,
+	}}
+}
+
+// Match reports whether the event emitted by emitter with the entries matches.
+func (m *EventMatcher) Match(emitter address.Address, entries []types.EventEntry) bool {
+	return m.f.matchAddress(emitter) && m.f.matchKeys(entries)
+}
+
 type TipSetEvents struct {
 	rctTS *types.TipSet // rctTs is the tipset containing the receipts of executed messages
 	msgTS *types.TipSet // msgTs is the tipset containing the messages that have been executed
@@ -306,7 +328,7 @@ type EventFilterManager struct {
 	ChainStore       blockstore.Blockstore
 	AddressResolver  func(ctx context.Context, emitter abi.ActorID, ts *types.TipSet) (address.Address, bool)
 	MaxFilterResults int
-	EventIndex       *EventIndex
+	EventIndex       EventIndex
 
 	mu            sync.Mutex // guards mutations to filters
 	filters       map[types.FilterID]EventFilter
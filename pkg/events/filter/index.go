@@ -7,80 +7,124 @@ import (
 	"fmt"
 	"sort"
 	"strings"
-	"time"
+	"sync"
 
-	"github.com/filecoin-project/venus/pkg/chain"
 	"github.com/ipfs/go-cid"
 	logging "github.com/ipfs/go-log/v2"
-	_ "github.com/mattn/go-sqlite3"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/venus/venus-shared/types"
 )
 
-var pragmas = []string{
-	"PRAGMA synchronous = normal",
-	"PRAGMA temp_store = memory",
-	"PRAGMA mmap_size = 30000000000",
-	"PRAGMA page_size = 32768",
-	"PRAGMA auto_vacuum = NONE",
-	"PRAGMA automatic_index = OFF",
-	"PRAGMA journal_mode = WAL",
-	"PRAGMA read_uncommitted = ON",
+var (
+	log = logging.Logger("filter")
+)
+
+const schemaVersion = 2
+
+// ErrNotIndexWriter is returned when writing a shared event index written by another node.
+var ErrNotIndexWriter = errors.New("the event index is written by another node")
+
+// EventIndex stores the actor events of the chain and serves the historic event filters,
+// it is backed by a local sqlite database (see NewEventIndex) or by a postgres or mysql
+// database shared by several nodes (see NewSQLEventIndex).
+type EventIndex interface {
+	// CollectEvents indexes the events of te, or marks them as reverted when revert is set.
+	// It does nothing when another node writes the index.
+	CollectEvents(ctx context.Context, te *TipSetEvents, revert bool, resolver func(ctx context.Context, emitter abi.ActorID, ts *types.TipSet) (address.Address, bool)) error
+	Close() error
+
+	// prefillFilter fills a filter's collection of events from the historic index
+	prefillFilter(ctx context.Context, f *eventFilter, excludeReverted bool) error
+	// indexedTipSets counts the events, not marked as reverted, of each tipset indexed between from and to
+	indexedTipSets(ctx context.Context, from, to abi.ChainEpoch) (map[abi.ChainEpoch]map[types.TipSetKey]int, error)
+	// revertTipSet marks the events of a tipset as reverted
+	revertTipSet(ctx context.Context, height abi.ChainEpoch, key types.TipSetKey) error
+	// writable reports whether this node writes the index
+	writable(ctx context.Context) (bool, error)
 }
 
-var ddls = []string{
-	`CREATE TABLE IF NOT EXISTS event (
-		id INTEGER PRIMARY KEY,
-		height INTEGER NOT NULL,
-		tipset_key BLOB NOT NULL,
-		tipset_key_cid BLOB NOT NULL,
-		emitter_addr BLOB NOT NULL,
-		event_index INTEGER NOT NULL,
-		message_cid BLOB NOT NULL,
-		message_index INTEGER NOT NULL,
-		reverted INTEGER NOT NULL
-	)`,
-
-	`CREATE INDEX IF NOT EXISTS height_tipset_key ON event (height,tipset_key)`,
-
-	`CREATE TABLE IF NOT EXISTS event_entry (
-		event_id INTEGER,
-		indexed INTEGER NOT NULL,
-		flags BLOB NOT NULL,
-		key TEXT NOT NULL,
-		codec INTEGER,
-		value BLOB NOT NULL
-	)`,
-
-	// metadata containing version of schema
-	`CREATE TABLE IF NOT EXISTS _meta (
-    	version UINT64 NOT NULL UNIQUE
-	)`,
-
-	// version 1.
-	`INSERT OR IGNORE INTO _meta (version) VALUES (1)`,
-	`INSERT OR IGNORE INTO _meta (version) VALUES (2)`,
+// sqlDialect holds what differs between the databases backing the index.
+type sqlDialect struct {
+	eventExists          string
+	insertEvent          string
+	insertEntry          string
+	revertEventsInTipset string
+	restoreEvent         string
+
+	// insertReturnsID is set when insertEvent returns the id of the new event, instead of
+	// relying on LastInsertId. No id is returned when the event was already inserted.
+	insertReturnsID bool
+	// prefillOrder sorts the rows of the prefill query, the rows of an event must be contiguous
+	prefillOrder string
+	// rebind converts the `?` placeholders of a query to the ones of the database
+	rebind func(query string) string
+	// writerLock tries to take the session lock of the node writing a shared index, it
+	// returns whether the lock was taken. It is empty when the index is not shared.
+	writerLock string
 }
 
-// const schemaVersion = 1
-var (
-	log = logging.Logger("filter")
-)
+// indexWriter elects the single node writing a shared index. The events of a tipset are marked
+// reverted or restored following the chain of the writer, several writers would overwrite each
+// other's view of the chain. The writer holds a lock of the database session of conn, which is
+// released by the database when the session ends, letting another node take over.
+type indexWriter struct {
+	lk   sync.Mutex
+	db   *sql.DB
+	lock string
+	conn *sql.Conn
+}
 
-const (
-	schemaVersion = 2
+// writable reports whether this node writes the index, taking the lock when it is free.
+func (w *indexWriter) writable(ctx context.Context) (bool, error) {
+	w.lk.Lock()
+	defer w.lk.Unlock()
 
-	eventExists          = `SELECT MAX(id) FROM event WHERE height=? AND tipset_key=? AND tipset_key_cid=? AND emitter_addr=? AND event_index=? AND message_cid=? AND message_index=?`
-	insertEvent          = `INSERT OR IGNORE INTO event(height, tipset_key, tipset_key_cid, emitter_addr, event_index, message_cid, message_index, reverted) VALUES(?, ?, ?, ?, ?, ?, ?, ?)`
-	insertEntry          = `INSERT OR IGNORE INTO event_entry(event_id, indexed, flags, key, codec, value) VALUES(?, ?, ?, ?, ?, ?)`
-	revertEventsInTipset = `UPDATE event SET reverted=true WHERE height=? AND tipset_key=?`
-	restoreEvent         = `UPDATE event SET reverted=false WHERE height=? AND tipset_key=? AND tipset_key_cid=? AND emitter_addr=? AND event_index=? AND message_cid=? AND message_index=?`
-)
+	if w.conn != nil {
+		err := w.conn.PingContext(ctx)
+		if err == nil {
+			return true, nil
+		}
+		log.Warnf("lost the session holding the event index writer lock: %v", err)
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+
+	conn, err := w.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("get a connection: %w", err)
+	}
+	var locked sql.NullBool
+	if err := conn.QueryRowContext(ctx, w.lock).Scan(&locked); err != nil {
+		_ = conn.Close()
+		return false, fmt.Errorf("take the event index writer lock: %w", err)
+	}
+	if !locked.Valid || !locked.Bool {
+		_ = conn.Close()
+		return false, nil
+	}
+	w.conn = conn
+	log.Info("this node now writes the event index")
+	return true, nil
+}
+
+func (w *indexWriter) close() error {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
 
-type EventIndex struct {
-	db *sql.DB
+type sqlEventIndex struct {
+	db      *sql.DB
+	dialect *sqlDialect
+	// writer is nil when the index is not shared
+	writer *indexWriter
 
 	stmtEventExists          *sql.Stmt
 	stmtInsertEvent          *sql.Stmt
@@ -89,28 +133,30 @@ type EventIndex struct {
 	stmtRestoreEvent         *sql.Stmt
 }
 
-func (ei *EventIndex) initStatements() (err error) {
-	ei.stmtEventExists, err = ei.db.Prepare(eventExists)
+var _ EventIndex = (*sqlEventIndex)(nil)
+
+func (ei *sqlEventIndex) initStatements() (err error) {
+	ei.stmtEventExists, err = ei.db.Prepare(ei.dialect.rebind(ei.dialect.eventExists))
 	if err != nil {
 		return fmt.Errorf("prepare stmtEventExists: %w", err)
 	}
 
-	ei.stmtInsertEvent, err = ei.db.Prepare(insertEvent)
+	ei.stmtInsertEvent, err = ei.db.Prepare(ei.dialect.rebind(ei.dialect.insertEvent))
 	if err != nil {
 		return fmt.Errorf("prepare stmtInsertEvent: %w", err)
 	}
 
-	ei.stmtInsertEntry, err = ei.db.Prepare(insertEntry)
+	ei.stmtInsertEntry, err = ei.db.Prepare(ei.dialect.rebind(ei.dialect.insertEntry))
 	if err != nil {
 		return fmt.Errorf("prepare stmtInsertEntry: %w", err)
 	}
 
-	ei.stmtRevertEventsInTipset, err = ei.db.Prepare(revertEventsInTipset)
+	ei.stmtRevertEventsInTipset, err = ei.db.Prepare(ei.dialect.rebind(ei.dialect.revertEventsInTipset))
 	if err != nil {
 		return fmt.Errorf("prepare stmtRevertEventsInTipset: %w", err)
 	}
 
-	ei.stmtRestoreEvent, err = ei.db.Prepare(restoreEvent)
+	ei.stmtRestoreEvent, err = ei.db.Prepare(ei.dialect.rebind(ei.dialect.restoreEvent))
 	if err != nil {
 		return fmt.Errorf("prepare stmtRestoreEvent: %w", err)
 	}
@@ -118,235 +164,55 @@ func (ei *EventIndex) initStatements() (err error) {
 	return nil
 }
 
-func (ei *EventIndex) migrateToVersion2(ctx context.Context, chainStore *chain.Store) error {
-	now := time.Now()
-
-	tx, err := ei.db.Begin()
-	if err != nil {
-		return fmt.Errorf("begin transaction: %w", err)
-	}
-	// rollback the transaction (a no-op if the transaction was already committed)
-	defer tx.Rollback() //nolint:errcheck
-
-	// create some temporary indices to help speed up the migration
-	_, err = tx.Exec("CREATE INDEX IF NOT EXISTS tmp_height_tipset_key_cid ON event (height,tipset_key_cid)")
-	if err != nil {
-		return fmt.Errorf("create index tmp_height_tipset_key_cid: %w", err)
-	}
-	_, err = tx.Exec("CREATE INDEX IF NOT EXISTS tmp_tipset_key_cid ON event (tipset_key_cid)")
-	if err != nil {
-		return fmt.Errorf("create index tmp_tipset_key_cid: %w", err)
-	}
-
-	stmtDeleteOffChainEvent, err := tx.Prepare("DELETE FROM event WHERE tipset_key_cid!=? and height=?")
-	if err != nil {
-		return fmt.Errorf("prepare stmtDeleteOffChainEvent: %w", err)
-	}
-
-	stmtSelectEvent, err := tx.Prepare("SELECT id FROM event WHERE tipset_key_cid=? ORDER BY message_index ASC, event_index ASC, id DESC LIMIT 1")
-	if err != nil {
-		return fmt.Errorf("prepare stmtSelectEvent: %w", err)
-	}
-
-	stmtDeleteEvent, err := tx.Prepare("DELETE FROM event WHERE tipset_key_cid=? AND id<?")
-	if err != nil {
-		return fmt.Errorf("prepare stmtDeleteEvent: %w", err)
-	}
-
-	// get the lowest height tipset
-	var minHeight sql.NullInt64
-	err = ei.db.QueryRow("SELECT MIN(height) FROM event").Scan(&minHeight)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil
-		}
-
-		return fmt.Errorf("query min height: %w", err)
+func (ei *sqlEventIndex) Close() error {
+	if ei.db == nil {
+		return nil
 	}
-	log.Infof("Migrating events from head to %d", minHeight.Int64)
-
-	currTS := chainStore.GetHead()
-
-	// minHeight > 0 说明 event 表里有数据，需要迁移数据
-	if minHeight.Int64 > 0 {
-		for int64(currTS.Height()) >= minHeight.Int64 {
-			if currTS.Height()%1000 == 0 {
-				log.Infof("Migrating height %d (remaining %d)", currTS.Height(), int64(currTS.Height())-minHeight.Int64)
-			}
-
-			tsKey := currTS.Parents()
-			currTS, err = chainStore.GetTipSet(ctx, tsKey)
-			if err != nil {
-				return fmt.Errorf("get tipset from key: %w", err)
-			}
-			log.Debugf("Migrating height %d", currTS.Height())
-
-			tsKeyCid, err := currTS.Key().Cid()
-			if err != nil {
-				return fmt.Errorf("tipset key cid: %w", err)
-			}
-
-			// delete all events that are not in the canonical chain
-			_, err = stmtDeleteOffChainEvent.Exec(tsKeyCid.Bytes(), currTS.Height())
-			if err != nil {
-				return fmt.Errorf("delete off chain event: %w", err)
-			}
-
-			// find the first eventID from the last time the tipset was applied
-			var eventID sql.NullInt64
-			err = stmtSelectEvent.QueryRow(tsKeyCid.Bytes()).Scan(&eventID)
-			if err != nil {
-				if err == sql.ErrNoRows {
-					continue
-				}
-				return fmt.Errorf("select event: %w", err)
-			}
-
-			// this tipset might not have any events which is ok
-			if !eventID.Valid {
-				continue
-			}
-			log.Debugf("Deleting all events with id < %d at height %d", eventID.Int64, currTS.Height())
-
-			res, err := stmtDeleteEvent.Exec(tsKeyCid.Bytes(), eventID.Int64)
-			if err != nil {
-				return fmt.Errorf("delete event: %w", err)
-			}
-
-			nrRowsAffected, err := res.RowsAffected()
-			if err != nil {
-				return fmt.Errorf("rows affected: %w", err)
-			}
-			log.Debugf("deleted %d events from tipset %s", nrRowsAffected, tsKeyCid.String())
+	if ei.writer != nil {
+		if err := ei.writer.close(); err != nil {
+			log.Warnf("release the event index writer lock: %v", err)
 		}
 	}
-
-	// delete all entries that have an event_id that doesn't exist (since we don't have a foreign
-	// key constraint that gives us cascading deletes)
-	res, err := tx.Exec("DELETE FROM event_entry WHERE event_id NOT IN (SELECT id FROM event)")
-	if err != nil {
-		return fmt.Errorf("delete event_entry: %w", err)
-	}
-
-	nrRowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("rows affected: %w", err)
-	}
-	log.Infof("cleaned up %d entries that had deleted events", nrRowsAffected)
-
-	// drop the temporary indices after the migration
-	_, err = tx.Exec("DROP INDEX IF EXISTS tmp_tipset_key_cid")
-	if err != nil {
-		return fmt.Errorf("create index tmp_tipset_key_cid: %w", err)
-	}
-	_, err = tx.Exec("DROP INDEX IF EXISTS tmp_height_tipset_key_cid")
-	if err != nil {
-		return fmt.Errorf("drop index tmp_height_tipset_key_cid: %w", err)
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
-	}
-
-	// during the migration, we have likely increased the WAL size a lot, so lets do some
-	// simple DB administration to free up space (VACUUM followed by truncating the WAL file)
-	// as this would be a good time to do it when no other writes are happening
-	log.Infof("Performing DB vacuum and wal checkpointing to free up space after the migration")
-	_, err = ei.db.Exec("VACUUM")
-	if err != nil {
-		log.Warnf("error vacuuming database: %s", err)
-	}
-	_, err = ei.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
-	if err != nil {
-		log.Warnf("error checkpointing wal: %s", err)
-	}
-
-	log.Infof("Successfully migrated events to version 2 in %s", time.Since(now))
-
-	return nil
+	return ei.db.Close()
 }
 
-func NewEventIndex(ctx context.Context, path string, chainStore *chain.Store) (*EventIndex, error) {
-	db, err := sql.Open("sqlite3", path+"?mode=rwc")
-	if err != nil {
-		return nil, fmt.Errorf("open sqlite3 database: %w", err)
+func (ei *sqlEventIndex) writable(ctx context.Context) (bool, error) {
+	if ei.writer == nil {
+		return true, nil
 	}
+	return ei.writer.writable(ctx)
+}
 
-	for _, pragma := range pragmas {
-		if _, err := db.Exec(pragma); err != nil {
-			_ = db.Close()
-			return nil, fmt.Errorf("exec pragma %q: %w", pragma, err)
+// insertEvent inserts an event and returns its id, or false if another node inserted it first.
+func (ei *sqlEventIndex) insertEvent(tx *sql.Tx, args ...any) (int64, bool, error) {
+	if ei.dialect.insertReturnsID {
+		var id int64
+		err := tx.Stmt(ei.stmtInsertEvent).QueryRow(args...).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
 		}
+		return id, err == nil, err
 	}
 
-	eventIndex := EventIndex{db: db}
-
-	q, err := db.Query("SELECT name FROM sqlite_master WHERE type='table' AND name='_meta';")
-	if err == sql.ErrNoRows || !q.Next() {
-		// empty database, create the schema
-		for _, ddl := range ddls {
-			if _, err := db.Exec(ddl); err != nil {
-				_ = db.Close()
-				return nil, fmt.Errorf("exec ddl %q: %w", ddl, err)
-			}
-		}
-	} else if err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("looking for _meta table: %w", err)
-	} else {
-		// check the schema version to see if we need to upgrade the database schema
-		var version int
-		err := db.QueryRow("SELECT max(version) FROM _meta").Scan(&version)
-		if err != nil {
-			_ = db.Close()
-			return nil, fmt.Errorf("invalid database version: no version found")
-		}
-
-		if version == 1 {
-			log.Infof("upgrading event index from version 1 to version 2")
-
-			err = eventIndex.migrateToVersion2(ctx, chainStore)
-			if err != nil {
-				_ = db.Close()
-				return nil, fmt.Errorf("could not migrate sql data to version 2: %w", err)
-			}
-
-			// to upgrade to version version 2 we only need to create an index on the event table
-			// which means we can just recreate the schema (it will not have any effect on existing data)
-			for _, ddl := range ddls {
-				if _, err := db.Exec(ddl); err != nil {
-					_ = db.Close()
-					return nil, fmt.Errorf("could not upgrade index to version 2, exec ddl %q: %w", ddl, err)
-				}
-			}
-
-			version = 2
-		}
-
-		if version != schemaVersion {
-			_ = db.Close()
-			return nil, fmt.Errorf("invalid database version: got %d, expected %d", version, schemaVersion)
-		}
+	res, err := tx.Stmt(ei.stmtInsertEvent).Exec(args...)
+	if err != nil {
+		return 0, false, err
 	}
-
-	err = eventIndex.initStatements()
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return 0, false, nil
+	}
+	id, err := res.LastInsertId()
 	if err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("error preparing eventIndex database statements: %w", err)
+		return 0, false, fmt.Errorf("get last row id: %w", err)
 	}
-
-	return &eventIndex, nil
+	return id, true, nil
 }
 
-func (ei *EventIndex) Close() error {
-	if ei.db == nil {
-		return nil
+func (ei *sqlEventIndex) CollectEvents(ctx context.Context, te *TipSetEvents, revert bool, resolver func(ctx context.Context, emitter abi.ActorID, ts *types.TipSet) (address.Address, bool)) error {
+	if ok, err := ei.writable(ctx); err != nil || !ok {
+		return err
 	}
-	return ei.db.Close()
-}
 
-func (ei *EventIndex) CollectEvents(ctx context.Context, te *TipSetEvents, revert bool, resolver func(ctx context.Context, emitter abi.ActorID, ts *types.TipSet) (address.Address, bool)) error {
 	tx, err := ei.db.Begin()
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
@@ -412,9 +278,10 @@ func (ei *EventIndex) CollectEvents(ctx context.Context, te *TipSetEvents, rever
 				return fmt.Errorf("error checking if event exists: %w", err)
 			}
 
+			inserted := false
 			if !entryID.Valid {
 				// event does not exist, lets insert it
-				res, err := tx.Stmt(ei.stmtInsertEvent).Exec(
+				entryID.Int64, inserted, err = ei.insertEvent(tx,
 					te.msgTS.Height(),          // height
 					te.msgTS.Key().Bytes(),     // tipset_key
 					tsKeyCid.Bytes(),           // tipset_key_cid
@@ -427,12 +294,9 @@ func (ei *EventIndex) CollectEvents(ctx context.Context, te *TipSetEvents, rever
 				if err != nil {
 					return fmt.Errorf("exec insert event: %w", err)
 				}
+			}
 
-				entryID.Int64, err = res.LastInsertId()
-				if err != nil {
-					return fmt.Errorf("get last row id: %w", err)
-				}
-
+			if inserted {
 				// insert all the entries for this event
 				for _, entry := range ev.Entries {
 					_, err = tx.Stmt(ei.stmtInsertEntry).Exec(
@@ -483,8 +347,49 @@ func (ei *EventIndex) CollectEvents(ctx context.Context, te *TipSetEvents, rever
 	return nil
 }
 
+func (ei *sqlEventIndex) indexedTipSets(ctx context.Context, from, to abi.ChainEpoch) (map[abi.ChainEpoch]map[types.TipSetKey]int, error) {
+	q, err := ei.db.QueryContext(ctx, ei.dialect.rebind(countEventsInTipSets), from, to, false)
+	if err != nil {
+		return nil, fmt.Errorf("count indexed events: %w", err)
+	}
+	defer q.Close() //nolint:errcheck
+
+	indexed := make(map[abi.ChainEpoch]map[types.TipSetKey]int)
+	for q.Next() {
+		var height int64
+		var keyBytes []byte
+		var count int
+		if err := q.Scan(&height, &keyBytes, &count); err != nil {
+			return nil, fmt.Errorf("read indexed tipset: %w", err)
+		}
+
+		key, err := types.TipSetKeyFromBytes(keyBytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse tipsetkey: %w", err)
+		}
+		if indexed[abi.ChainEpoch(height)] == nil {
+			indexed[abi.ChainEpoch(height)] = make(map[types.TipSetKey]int)
+		}
+		indexed[abi.ChainEpoch(height)][key] = count
+	}
+
+	return indexed, q.Err()
+}
+
+func (ei *sqlEventIndex) revertTipSet(ctx context.Context, height abi.ChainEpoch, key types.TipSetKey) error {
+	if ok, err := ei.writable(ctx); err != nil {
+		return err
+	} else if !ok {
+		return ErrNotIndexWriter
+	}
+	if _, err := ei.stmtRevertEventsInTipset.ExecContext(ctx, height, key.Bytes()); err != nil {
+		return fmt.Errorf("revert events of %s: %w", key, err)
+	}
+	return nil
+}
+
 // prefillFilter fills a filter's collection of events from the historic index
-func (ei *EventIndex) prefillFilter(ctx context.Context, f *eventFilter, excludeReverted bool) error {
+func (ei *sqlEventIndex) prefillFilter(ctx context.Context, f *eventFilter, excludeReverted bool) error {
 	clauses := []string{}
 	values := []any{}
 	joins := []string{}
@@ -519,8 +424,8 @@ func (ei *EventIndex) prefillFilter(ctx context.Context, f *eventFilter, exclude
 				join++
 				joinAlias := fmt.Sprintf("ee%d", join)
 				joins = append(joins, fmt.Sprintf("event_entry %s on event.id=%[1]s.event_id", joinAlias))
-				clauses = append(clauses, fmt.Sprintf("%s.indexed=1 AND %[1]s.key=?", joinAlias))
-				values = append(values, key)
+				clauses = append(clauses, fmt.Sprintf("%s.indexed=? AND %[1]s.key=?", joinAlias))
+				values = append(values, true, key)
 				subclauses := []string{}
 				for _, val := range vals {
 					subclauses = append(subclauses, fmt.Sprintf("(%s.value=? AND %[1]s.codec=?)", joinAlias))
@@ -548,19 +453,20 @@ func (ei *EventIndex) prefillFilter(ctx context.Context, f *eventFilter, exclude
 		FROM event JOIN event_entry ON event.id=event_entry.event_id`
 
 	if len(joins) > 0 {
-		s = s + ", " + strings.Join(joins, ", ")
+		s = s + " JOIN " + strings.Join(joins, " JOIN ")
 	}
 
 	if len(clauses) > 0 {
 		s = s + " WHERE " + strings.Join(clauses, " AND ")
 	}
 
-	s += " ORDER BY event.height DESC"
+	s += " ORDER BY " + ei.dialect.prefillOrder
 
-	stmt, err := ei.db.Prepare(s)
+	stmt, err := ei.db.Prepare(ei.dialect.rebind(s))
 	if err != nil {
 		return fmt.Errorf("prepare prefill query: %w", err)
 	}
+	defer stmt.Close() //nolint:errcheck
 
 	q, err := stmt.QueryContext(ctx, values...)
 	if err != nil {
@@ -569,6 +475,7 @@ func (ei *EventIndex) prefillFilter(ctx context.Context, f *eventFilter, exclude
 		}
 		return fmt.Errorf("exec prefill query: %w", err)
 	}
+	defer q.Close() //nolint:errcheck
 
 	var ces []*CollectedEvent
 	var currentID int64 = -1
@@ -0,0 +1,197 @@
+package filter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+
+	"github.com/filecoin-project/venus/pkg/config"
+)
+
+// The databases able to store the event index.
+const (
+	EventIndexSqlite   = "sqlite"
+	EventIndexPostgres = "postgres"
+	EventIndexMySQL    = "mysql"
+)
+
+// The rows of an event are unique by their position in the tipset, so that nodes sharing the
+// database don't insert the same event twice.
+var postgresDDLs = []string{
+	`CREATE TABLE IF NOT EXISTS event (
+		id BIGSERIAL PRIMARY KEY,
+		height BIGINT NOT NULL,
+		tipset_key BYTEA NOT NULL,
+		tipset_key_cid BYTEA NOT NULL,
+		emitter_addr BYTEA NOT NULL,
+		event_index INTEGER NOT NULL,
+		message_cid BYTEA NOT NULL,
+		message_index INTEGER NOT NULL,
+		reverted BOOLEAN NOT NULL,
+		UNIQUE (tipset_key_cid, message_index, event_index)
+	)`,
+
+	`CREATE INDEX IF NOT EXISTS height_tipset_key ON event (height,tipset_key)`,
+
+	`CREATE TABLE IF NOT EXISTS event_entry (
+		id BIGSERIAL PRIMARY KEY,
+		event_id BIGINT NOT NULL REFERENCES event(id) ON DELETE CASCADE,
+		indexed BOOLEAN NOT NULL,
+		flags BYTEA NOT NULL,
+		key TEXT NOT NULL,
+		codec BIGINT,
+		value BYTEA NOT NULL
+	)`,
+
+	`CREATE INDEX IF NOT EXISTS event_entry_event_id ON event_entry (event_id)`,
+
+	// metadata containing version of schema
+	`CREATE TABLE IF NOT EXISTS _meta (
+		version BIGINT NOT NULL UNIQUE
+	)`,
+
+	`INSERT INTO _meta (version) VALUES (2) ON CONFLICT DO NOTHING`,
+}
+
+var postgresDialect = &sqlDialect{
+	eventExists:          eventExists,
+	insertEvent:          `INSERT INTO event(height, tipset_key, tipset_key_cid, emitter_addr, event_index, message_cid, message_index, reverted) VALUES(?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT DO NOTHING RETURNING id`,
+	insertEntry:          `INSERT INTO event_entry(event_id, indexed, flags, key, codec, value) VALUES(?, ?, ?, ?, ?, ?)`,
+	revertEventsInTipset: revertEventsInTipset,
+	restoreEvent:         restoreEvent,
+
+	insertReturnsID: true,
+	prefillOrder:    "event.height DESC, event.id DESC, event_entry.id ASC",
+	rebind:          numberedPlaceholders,
+	writerLock:      `SELECT pg_try_advisory_lock(hashtext('venus_event_index'))`,
+}
+
+// `key` is a reserved word of mysql, it only needs to be quoted when it isn't qualified by a table.
+var mysqlDDLs = []string{
+	`CREATE TABLE IF NOT EXISTS event (
+		id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		height BIGINT NOT NULL,
+		tipset_key VARBINARY(2048) NOT NULL,
+		tipset_key_cid VARBINARY(128) NOT NULL,
+		emitter_addr VARBINARY(128) NOT NULL,
+		event_index INT NOT NULL,
+		message_cid VARBINARY(128) NOT NULL,
+		message_index INT NOT NULL,
+		reverted BOOLEAN NOT NULL,
+		UNIQUE KEY event_position (tipset_key_cid, message_index, event_index),
+		KEY height_tipset_key (height, tipset_key(255))
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS event_entry (
+		id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		event_id BIGINT NOT NULL,
+		indexed BOOLEAN NOT NULL,
+		flags VARBINARY(1) NOT NULL,
+		` + "`key`" + ` VARCHAR(255) NOT NULL,
+		codec BIGINT UNSIGNED,
+		value MEDIUMBLOB NOT NULL,
+		KEY event_entry_event_id (event_id),
+		FOREIGN KEY (event_id) REFERENCES event(id) ON DELETE CASCADE
+	)`,
+
+	// metadata containing version of schema
+	`CREATE TABLE IF NOT EXISTS _meta (
+		version BIGINT NOT NULL,
+		UNIQUE KEY version (version)
+	)`,
+
+	`INSERT IGNORE INTO _meta (version) VALUES (2)`,
+}
+
+var mysqlDialect = &sqlDialect{
+	eventExists:          eventExists,
+	insertEvent:          `INSERT IGNORE INTO event(height, tipset_key, tipset_key_cid, emitter_addr, event_index, message_cid, message_index, reverted) VALUES(?, ?, ?, ?, ?, ?, ?, ?)`,
+	insertEntry:          "INSERT INTO event_entry(event_id, indexed, flags, `key`, codec, value) VALUES(?, ?, ?, ?, ?, ?)",
+	revertEventsInTipset: revertEventsInTipset,
+	restoreEvent:         restoreEvent,
+
+	prefillOrder: "event.height DESC, event.id DESC, event_entry.id ASC",
+	rebind:       func(query string) string { return query },
+	writerLock:   `SELECT GET_LOCK('venus_event_index', 0)`,
+}
+
+// numberedPlaceholders replaces the `?` placeholders of query with `$1`, `$2`...
+func numberedPlaceholders(query string) string {
+	var sb strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			sb.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// NewSQLEventIndex opens the event index stored in the postgres or mysql database of cfg,
+// creating its schema when missing. The database can be shared by several nodes: the first
+// one to connect writes the index, the others only read it until the writer disconnects.
+func NewSQLEventIndex(ctx context.Context, dbType string, cfg config.MySQLConfig) (EventIndex, error) {
+	var driver string
+	var dialect *sqlDialect
+	var ddls []string
+	switch dbType {
+	case EventIndexPostgres:
+		driver, dialect, ddls = "postgres", postgresDialect, postgresDDLs
+	case EventIndexMySQL:
+		driver, dialect, ddls = "mysql", mysqlDialect, mysqlDDLs
+	default:
+		return nil, fmt.Errorf("unsupported event index database %q", dbType)
+	}
+
+	db, err := sql.Open(driver, cfg.ConnectionString)
+	if err != nil {
+		return nil, fmt.Errorf("open %s database: %w", dbType, err)
+	}
+
+	// Set the maximum number of idle connections in the connection pool.
+	db.SetMaxIdleConns(cfg.MaxIdleConn)
+	// Set the maximum number of open database connections.
+	db.SetMaxOpenConns(cfg.MaxOpenConn)
+	// The maximum time that the connection can be reused is set.
+	db.SetConnMaxLifetime(time.Second * cfg.ConnMaxLifeTime)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("connect to %s database: %w", dbType, err)
+	}
+
+	for _, ddl := range ddls {
+		if _, err := db.ExecContext(ctx, ddl); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("exec ddl %q: %w", ddl, err)
+		}
+	}
+
+	var version int
+	if err := db.QueryRowContext(ctx, "SELECT MAX(version) FROM _meta").Scan(&version); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("invalid database version: %w", err)
+	}
+	if version != schemaVersion {
+		_ = db.Close()
+		return nil, fmt.Errorf("invalid database version: got %d, expected %d", version, schemaVersion)
+	}
+
+	eventIndex := &sqlEventIndex{db: db, dialect: dialect, writer: &indexWriter{db: db, lock: dialect.writerLock}}
+	if err := eventIndex.initStatements(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("error preparing eventIndex database statements: %w", err)
+	}
+
+	log.Infof("using the %s event index", dbType)
+	return eventIndex, nil
+}
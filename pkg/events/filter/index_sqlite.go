@@ -0,0 +1,298 @@
+package filter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/filecoin-project/venus/pkg/chain"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+var pragmas = []string{
+	"PRAGMA synchronous = normal",
+	"PRAGMA temp_store = memory",
+	"PRAGMA mmap_size = 30000000000",
+	"PRAGMA page_size = 32768",
+	"PRAGMA auto_vacuum = NONE",
+	"PRAGMA automatic_index = OFF",
+	"PRAGMA journal_mode = WAL",
+	"PRAGMA read_uncommitted = ON",
+}
+
+var ddls = []string{
+	`CREATE TABLE IF NOT EXISTS event (
+		id INTEGER PRIMARY KEY,
+		height INTEGER NOT NULL,
+		tipset_key BLOB NOT NULL,
+		tipset_key_cid BLOB NOT NULL,
+		emitter_addr BLOB NOT NULL,
+		event_index INTEGER NOT NULL,
+		message_cid BLOB NOT NULL,
+		message_index INTEGER NOT NULL,
+		reverted INTEGER NOT NULL
+	)`,
+
+	`CREATE INDEX IF NOT EXISTS height_tipset_key ON event (height,tipset_key)`,
+
+	`CREATE TABLE IF NOT EXISTS event_entry (
+		event_id INTEGER,
+		indexed INTEGER NOT NULL,
+		flags BLOB NOT NULL,
+		key TEXT NOT NULL,
+		codec INTEGER,
+		value BLOB NOT NULL
+	)`,
+
+	// metadata containing version of schema
+	`CREATE TABLE IF NOT EXISTS _meta (
+    	version UINT64 NOT NULL UNIQUE
+	)`,
+
+	// version 1.
+	`INSERT OR IGNORE INTO _meta (version) VALUES (1)`,
+	`INSERT OR IGNORE INTO _meta (version) VALUES (2)`,
+}
+
+const (
+	eventExists          = `SELECT MAX(id) FROM event WHERE height=? AND tipset_key=? AND tipset_key_cid=? AND emitter_addr=? AND event_index=? AND message_cid=? AND message_index=?`
+	insertEvent          = `INSERT OR IGNORE INTO event(height, tipset_key, tipset_key_cid, emitter_addr, event_index, message_cid, message_index, reverted) VALUES(?, ?, ?, ?, ?, ?, ?, ?)`
+	insertEntry          = `INSERT OR IGNORE INTO event_entry(event_id, indexed, flags, key, codec, value) VALUES(?, ?, ?, ?, ?, ?)`
+	revertEventsInTipset = `UPDATE event SET reverted=true WHERE height=? AND tipset_key=?`
+	restoreEvent         = `UPDATE event SET reverted=false WHERE height=? AND tipset_key=? AND tipset_key_cid=? AND emitter_addr=? AND event_index=? AND message_cid=? AND message_index=?`
+	countEventsInTipSets = `SELECT height, tipset_key, COUNT(*) FROM event WHERE height>=? AND height<=? AND reverted=? GROUP BY height, tipset_key`
+)
+
+var sqliteDialect = &sqlDialect{
+	eventExists:          eventExists,
+	insertEvent:          insertEvent,
+	insertEntry:          insertEntry,
+	revertEventsInTipset: revertEventsInTipset,
+	restoreEvent:         restoreEvent,
+
+	prefillOrder: "event.height DESC",
+	rebind:       func(query string) string { return query },
+}
+
+func (ei *sqlEventIndex) migrateToVersion2(ctx context.Context, chainStore *chain.Store) error {
+	now := time.Now()
+
+	tx, err := ei.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	// rollback the transaction (a no-op if the transaction was already committed)
+	defer tx.Rollback() //nolint:errcheck
+
+	// create some temporary indices to help speed up the migration
+	_, err = tx.Exec("CREATE INDEX IF NOT EXISTS tmp_height_tipset_key_cid ON event (height,tipset_key_cid)")
+	if err != nil {
+		return fmt.Errorf("create index tmp_height_tipset_key_cid: %w", err)
+	}
+	_, err = tx.Exec("CREATE INDEX IF NOT EXISTS tmp_tipset_key_cid ON event (tipset_key_cid)")
+	if err != nil {
+		return fmt.Errorf("create index tmp_tipset_key_cid: %w", err)
+	}
+
+	stmtDeleteOffChainEvent, err := tx.Prepare("DELETE FROM event WHERE tipset_key_cid!=? and height=?")
+	if err != nil {
+		return fmt.Errorf("prepare stmtDeleteOffChainEvent: %w", err)
+	}
+
+	stmtSelectEvent, err := tx.Prepare("SELECT id FROM event WHERE tipset_key_cid=? ORDER BY message_index ASC, event_index ASC, id DESC LIMIT 1")
+	if err != nil {
+		return fmt.Errorf("prepare stmtSelectEvent: %w", err)
+	}
+
+	stmtDeleteEvent, err := tx.Prepare("DELETE FROM event WHERE tipset_key_cid=? AND id<?")
+	if err != nil {
+		return fmt.Errorf("prepare stmtDeleteEvent: %w", err)
+	}
+
+	// get the lowest height tipset
+	var minHeight sql.NullInt64
+	err = ei.db.QueryRow("SELECT MIN(height) FROM event").Scan(&minHeight)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+
+		return fmt.Errorf("query min height: %w", err)
+	}
+	log.Infof("Migrating events from head to %d", minHeight.Int64)
+
+	currTS := chainStore.GetHead()
+
+	// minHeight > 0 说明 event 表里有数据，需要迁移数据
+	if minHeight.Int64 > 0 {
+		for int64(currTS.Height()) >= minHeight.Int64 {
+			if currTS.Height()%1000 == 0 {
+				log.Infof("Migrating height %d (remaining %d)", currTS.Height(), int64(currTS.Height())-minHeight.Int64)
+			}
+
+			tsKey := currTS.Parents()
+			currTS, err = chainStore.GetTipSet(ctx, tsKey)
+			if err != nil {
+				return fmt.Errorf("get tipset from key: %w", err)
+			}
+			log.Debugf("Migrating height %d", currTS.Height())
+
+			tsKeyCid, err := currTS.Key().Cid()
+			if err != nil {
+				return fmt.Errorf("tipset key cid: %w", err)
+			}
+
+			// delete all events that are not in the canonical chain
+			_, err = stmtDeleteOffChainEvent.Exec(tsKeyCid.Bytes(), currTS.Height())
+			if err != nil {
+				return fmt.Errorf("delete off chain event: %w", err)
+			}
+
+			// find the first eventID from the last time the tipset was applied
+			var eventID sql.NullInt64
+			err = stmtSelectEvent.QueryRow(tsKeyCid.Bytes()).Scan(&eventID)
+			if err != nil {
+				if err == sql.ErrNoRows {
+					continue
+				}
+				return fmt.Errorf("select event: %w", err)
+			}
+
+			// this tipset might not have any events which is ok
+			if !eventID.Valid {
+				continue
+			}
+			log.Debugf("Deleting all events with id < %d at height %d", eventID.Int64, currTS.Height())
+
+			res, err := stmtDeleteEvent.Exec(tsKeyCid.Bytes(), eventID.Int64)
+			if err != nil {
+				return fmt.Errorf("delete event: %w", err)
+			}
+
+			nrRowsAffected, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("rows affected: %w", err)
+			}
+			log.Debugf("deleted %d events from tipset %s", nrRowsAffected, tsKeyCid.String())
+		}
+	}
+
+	// delete all entries that have an event_id that doesn't exist (since we don't have a foreign
+	// key constraint that gives us cascading deletes)
+	res, err := tx.Exec("DELETE FROM event_entry WHERE event_id NOT IN (SELECT id FROM event)")
+	if err != nil {
+		return fmt.Errorf("delete event_entry: %w", err)
+	}
+
+	nrRowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	log.Infof("cleaned up %d entries that had deleted events", nrRowsAffected)
+
+	// drop the temporary indices after the migration
+	_, err = tx.Exec("DROP INDEX IF EXISTS tmp_tipset_key_cid")
+	if err != nil {
+		return fmt.Errorf("create index tmp_tipset_key_cid: %w", err)
+	}
+	_, err = tx.Exec("DROP INDEX IF EXISTS tmp_height_tipset_key_cid")
+	if err != nil {
+		return fmt.Errorf("drop index tmp_height_tipset_key_cid: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	// during the migration, we have likely increased the WAL size a lot, so lets do some
+	// simple DB administration to free up space (VACUUM followed by truncating the WAL file)
+	// as this would be a good time to do it when no other writes are happening
+	log.Infof("Performing DB vacuum and wal checkpointing to free up space after the migration")
+	_, err = ei.db.Exec("VACUUM")
+	if err != nil {
+		log.Warnf("error vacuuming database: %s", err)
+	}
+	_, err = ei.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
+	if err != nil {
+		log.Warnf("error checkpointing wal: %s", err)
+	}
+
+	log.Infof("Successfully migrated events to version 2 in %s", time.Since(now))
+
+	return nil
+}
+
+// NewEventIndex opens, or creates, the sqlite event index at path.
+func NewEventIndex(ctx context.Context, path string, chainStore *chain.Store) (EventIndex, error) {
+	db, err := sql.Open("sqlite3", path+"?mode=rwc")
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite3 database: %w", err)
+	}
+
+	for _, pragma := range pragmas {
+		if _, err := db.Exec(pragma); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("exec pragma %q: %w", pragma, err)
+		}
+	}
+
+	eventIndex := sqlEventIndex{db: db, dialect: sqliteDialect}
+
+	q, err := db.Query("SELECT name FROM sqlite_master WHERE type='table' AND name='_meta';")
+	if err == sql.ErrNoRows || !q.Next() {
+		// empty database, create the schema
+		for _, ddl := range ddls {
+			if _, err := db.Exec(ddl); err != nil {
+				_ = db.Close()
+				return nil, fmt.Errorf("exec ddl %q: %w", ddl, err)
+			}
+		}
+	} else if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("looking for _meta table: %w", err)
+	} else {
+		// check the schema version to see if we need to upgrade the database schema
+		var version int
+		err := db.QueryRow("SELECT max(version) FROM _meta").Scan(&version)
+		if err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("invalid database version: no version found")
+		}
+
+		if version == 1 {
+			log.Infof("upgrading event index from version 1 to version 2")
+
+			err = eventIndex.migrateToVersion2(ctx, chainStore)
+			if err != nil {
+				_ = db.Close()
+				return nil, fmt.Errorf("could not migrate sql data to version 2: %w", err)
+			}
+
+			// to upgrade to version version 2 we only need to create an index on the event table
+			// which means we can just recreate the schema (it will not have any effect on existing data)
+			for _, ddl := range ddls {
+				if _, err := db.Exec(ddl); err != nil {
+					_ = db.Close()
+					return nil, fmt.Errorf("could not upgrade index to version 2, exec ddl %q: %w", ddl, err)
+				}
+			}
+
+			version = 2
+		}
+
+		if version != schemaVersion {
+			_ = db.Close()
+			return nil, fmt.Errorf("invalid database version: got %d, expected %d", version, schemaVersion)
+		}
+	}
+
+	err = eventIndex.initStatements()
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("error preparing eventIndex database statements: %w", err)
+	}
+
+	return &eventIndex, nil
+}
@@ -897,3 +897,103 @@ func TestEventIndexPrefillFilterExcludeReverted(t *testing.T) {
 		})
 	}
 }
+
+func TestNumberedPlaceholders(t *testing.T) {
+	require.Equal(t, "SELECT id FROM event WHERE height=$1 AND (emitter_addr=$2 OR emitter_addr=$3)",
+		numberedPlaceholders("SELECT id FROM event WHERE height=? AND (emitter_addr=? OR emitter_addr=?)"))
+	// the sqlite and mysql queries keep their placeholders
+	require.Equal(t, restoreEvent, sqliteDialect.rebind(restoreEvent))
+	require.Equal(t, mysqlDialect.insertEntry, mysqlDialect.rebind(mysqlDialect.insertEntry))
+	require.Contains(t, postgresDialect.rebind(postgresDialect.insertEvent), "VALUES($1, $2, $3, $4, $5, $6, $7, $8)")
+}
+
+func TestEventIndexIndexedTipSets(t *testing.T) {
+	ctx := context.Background()
+	rng := pseudo.New(pseudo.NewSource(299792458))
+	a1 := randomF4Addr(t, rng)
+	a1ID := abi.ActorID(1)
+
+	addrMap := addressMap{}
+	addrMap.add(a1ID, a1)
+
+	events := []*types.Event{
+		fakeEvent(a1ID, []kv{{k: "type", v: []byte("approval")}}, nil),
+		fakeEvent(a1ID, []kv{{k: "type", v: []byte("transfer")}}, nil),
+	}
+	em := executedMessage{
+		msg: fakeMessage(randomF4Addr(t, rng), randomF4Addr(t, rng)),
+		rct: fakeReceipt(t, rng, newStore(), events),
+		evs: events,
+	}
+
+	ei, err := NewEventIndex(ctx, filepath.Join(t.TempDir(), "actorevents.db"), nil)
+	require.NoError(t, err, "create event index")
+	defer ei.Close() //nolint:errcheck
+
+	events14000 := buildTipSetEvents(t, rng, 14000, em)
+	events14001 := buildTipSetEvents(t, rng, 14001, em)
+	require.NoError(t, ei.CollectEvents(ctx, events14000, false, addrMap.ResolveAddress))
+	require.NoError(t, ei.CollectEvents(ctx, events14001, false, addrMap.ResolveAddress))
+
+	indexed, err := ei.indexedTipSets(ctx, 14000, 14000)
+	require.NoError(t, err)
+	require.Equal(t, map[abi.ChainEpoch]map[types.TipSetKey]int{
+		14000: {events14000.msgTS.Key(): 2},
+	}, indexed)
+
+	// the events of reverted tipsets are not counted
+	require.NoError(t, ei.revertTipSet(ctx, 14001, events14001.msgTS.Key()))
+	indexed, err = ei.indexedTipSets(ctx, 14000, 14001)
+	require.NoError(t, err)
+	require.Equal(t, map[abi.ChainEpoch]map[types.TipSetKey]int{
+		14000: {events14000.msgTS.Key(): 2},
+	}, indexed)
+}
+
+func TestEventIndexSingleWriter(t *testing.T) {
+	ctx := context.Background()
+	rng := pseudo.New(pseudo.NewSource(299792458))
+	a1 := randomF4Addr(t, rng)
+	a1ID := abi.ActorID(1)
+
+	addrMap := addressMap{}
+	addrMap.add(a1ID, a1)
+
+	events := []*types.Event{
+		fakeEvent(a1ID, []kv{{k: "type", v: []byte("approval")}}, nil),
+	}
+	em := executedMessage{
+		msg: fakeMessage(randomF4Addr(t, rng), randomF4Addr(t, rng)),
+		rct: fakeReceipt(t, rng, newStore(), events),
+		evs: events,
+	}
+
+	index, err := NewEventIndex(ctx, filepath.Join(t.TempDir(), "actorevents.db"), nil)
+	require.NoError(t, err, "create event index")
+	defer index.Close() //nolint:errcheck
+	ei := index.(*sqlEventIndex)
+
+	// another node holds the writer lock, the events are left to it
+	ei.writer = &indexWriter{db: ei.db, lock: "SELECT false"}
+	events14000 := buildTipSetEvents(t, rng, 14000, em)
+	require.NoError(t, ei.CollectEvents(ctx, events14000, false, addrMap.ResolveAddress))
+	indexed, err := ei.indexedTipSets(ctx, 14000, 14000)
+	require.NoError(t, err)
+	require.Empty(t, indexed)
+	require.ErrorIs(t, ei.revertTipSet(ctx, 14000, events14000.msgTS.Key()), ErrNotIndexWriter)
+
+	// the lock is free, this node writes the index
+	ei.writer.lock = "SELECT true"
+	require.NoError(t, ei.CollectEvents(ctx, events14000, false, addrMap.ResolveAddress))
+	indexed, err = ei.indexedTipSets(ctx, 14000, 14000)
+	require.NoError(t, err)
+	require.Equal(t, map[abi.ChainEpoch]map[types.TipSetKey]int{
+		14000: {events14000.msgTS.Key(): 1},
+	}, indexed)
+
+	// the lock is kept by the session of the writer
+	ei.writer.lock = "SELECT false"
+	ok, err := ei.writable(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
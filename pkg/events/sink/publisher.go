@@ -0,0 +1,142 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/filecoin-project/venus/pkg/config"
+)
+
+const (
+	webhookSink = "webhook"
+	natsSink    = "nats"
+
+	defaultNATSSubject = "venus.chain"
+)
+
+// publisher delivers the batches of a sink, it returns once the receiver acknowledged a batch.
+type publisher interface {
+	Publish(ctx context.Context, payload []byte) error
+	Close() error
+}
+
+func newPublisher(cfg *config.EventSinkConfig) (publisher, error) {
+	switch cfg.Type {
+	case webhookSink:
+		if _, err := url.ParseRequestURI(cfg.URL); err != nil {
+			return nil, fmt.Errorf("invalid webhook url %q: %w", cfg.URL, err)
+		}
+		return &webhook{url: cfg.URL, headers: cfg.Headers, client: &http.Client{}}, nil
+	case natsSink:
+		u, err := url.Parse(cfg.URL)
+		if err != nil || u.Scheme != "nats" || u.Host == "" {
+			return nil, fmt.Errorf("invalid nats url %q, expect nats://[user:pass@]host:port", cfg.URL)
+		}
+		subject := cfg.Subject
+		if subject == "" {
+			subject = defaultNATSSubject
+		}
+		if strings.ContainsAny(subject, " \t\r\n") {
+			return nil, fmt.Errorf("invalid nats subject %q", subject)
+		}
+		return &natsPublisher{url: cfg.URL, subject: subject}, nil
+	default:
+		return nil, fmt.Errorf("unknown sink type %q, expect %s or %s", cfg.Type, webhookSink, natsSink)
+	}
+}
+
+// webhook posts the batches to an http endpoint, any 2xx response acknowledges a batch.
+type webhook struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (w *webhook) Publish(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
+
+func (w *webhook) Close() error {
+	w.client.CloseIdleConnections()
+	return nil
+}
+
+// natsPublisher publishes the batches on a subject of a NATS JetStream stream, a batch is
+// acknowledged once the stream stored it. The stream capturing the subject is created by the
+// operator of the server.
+type natsPublisher struct {
+	url     string
+	subject string
+
+	lk sync.Mutex
+	nc *nats.Conn
+	js nats.JetStreamContext
+}
+
+func (n *natsPublisher) Publish(ctx context.Context, payload []byte) error {
+	js, err := n.jetStream()
+	if err != nil {
+		return err
+	}
+	_, err = js.Publish(n.subject, payload, nats.Context(ctx))
+	return err
+}
+
+// jetStream connects to the server on the first publish, the client reconnects on its own after.
+func (n *natsPublisher) jetStream() (nats.JetStreamContext, error) {
+	n.lk.Lock()
+	defer n.lk.Unlock()
+
+	if n.js != nil {
+		return n.js, nil
+	}
+	nc, err := nats.Connect(n.url, nats.Name("venus"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+	js, err := nc.JetStream()
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+	n.nc, n.js = nc, js
+	return js, nil
+}
+
+func (n *natsPublisher) Close() error {
+	n.lk.Lock()
+	defer n.lk.Unlock()
+	if n.nc == nil {
+		return nil
+	}
+	err := n.nc.Drain()
+	n.nc, n.js = nil, nil
+	return err
+}
@@ -0,0 +1,68 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus/pkg/config"
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+)
+
+func TestNATSPublisher(t *testing.T) {
+	tf.UnitTest(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoSigs:    true,
+		Users:     []*server.User{{Username: "venus", Password: "secret"}},
+	})
+	require.NoError(t, err)
+	srv.Start()
+	defer srv.Shutdown()
+	require.True(t, srv.ReadyForConnections(10*time.Second))
+	url := fmt.Sprintf("nats://venus:secret@%s", srv.Addr())
+
+	pub, err := newPublisher(&config.EventSinkConfig{Type: natsSink, URL: url, Subject: "venus.test"})
+	require.NoError(t, err)
+	defer pub.Close() // nolint: errcheck
+
+	// no stream captures the subject, the batch is not acknowledged
+	require.Error(t, pub.Publish(ctx, []byte(`{"height":1}`)))
+
+	nc, err := nats.Connect(url)
+	require.NoError(t, err)
+	defer nc.Close()
+	js, err := nc.JetStream()
+	require.NoError(t, err)
+	_, err = js.AddStream(&nats.StreamConfig{Name: "VENUS", Subjects: []string{"venus.>"}})
+	require.NoError(t, err)
+
+	require.NoError(t, pub.Publish(ctx, []byte(`{"height":1}`)))
+	require.NoError(t, pub.Publish(ctx, []byte(`{"height":2}`)))
+
+	sub, err := js.SubscribeSync("venus.test", nats.DeliverAll())
+	require.NoError(t, err)
+	for _, expected := range []string{`{"height":1}`, `{"height":2}`} {
+		msg, err := sub.NextMsg(10 * time.Second)
+		require.NoError(t, err)
+		require.Equal(t, expected, string(msg.Data))
+	}
+
+	// the publisher connects again after it was closed
+	require.NoError(t, pub.Close())
+	require.NoError(t, pub.Publish(ctx, []byte(`{"height":3}`)))
+	msg, err := sub.NextMsg(10 * time.Second)
+	require.NoError(t, err)
+	require.Equal(t, `{"height":3}`, string(msg.Data))
+}
@@ -0,0 +1,435 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	logging "github.com/ipfs/go-log/v2"
+
+	"github.com/filecoin-project/venus/pkg/config"
+	"github.com/filecoin-project/venus/pkg/events/filter"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+var log = logging.Logger("eventsink")
+
+var cursorKey = datastore.NewKey("/eventsinks")
+
+var (
+	// pollInterval is the interval at which the sinks look for new tipsets.
+	pollInterval = 5 * time.Second
+	// maxRetryInterval caps the interval between the deliveries of a batch that failed.
+	maxRetryInterval = time.Minute
+)
+
+const defaultTimeout = 30 * time.Second
+
+// API is the part of the node API the sinks read the chain from.
+type API interface {
+	ChainHead(ctx context.Context) (*types.TipSet, error)
+	ChainGetTipSet(ctx context.Context, key types.TipSetKey) (*types.TipSet, error)
+	ChainGetTipSetByHeight(ctx context.Context, height abi.ChainEpoch, tsk types.TipSetKey) (*types.TipSet, error)
+	ChainGetPath(ctx context.Context, from types.TipSetKey, to types.TipSetKey) ([]*types.HeadChange, error)
+	ChainGetParentMessages(ctx context.Context, bcid cid.Cid) ([]types.MessageCID, error)
+	ChainGetParentReceipts(ctx context.Context, bcid cid.Cid) ([]*types.MessageReceipt, error)
+	ChainGetEvents(ctx context.Context, root cid.Cid) ([]types.Event, error)
+	StateGetActor(ctx context.Context, actor address.Address, tsk types.TipSetKey) (*types.Actor, error)
+	StateLookupID(ctx context.Context, addr address.Address, tsk types.TipSetKey) (address.Address, error)
+	StateLookupRobustAddress(ctx context.Context, addr address.Address, tsk types.TipSetKey) (address.Address, error)
+}
+
+// Batch is what a sink receives for each tipset the chain applied or reverted.
+type Batch struct {
+	Sink string `json:"sink"`
+	// Type is apply when the chain moved to the tipset, or revert when a reorg removed it
+	Type      types.HeadChangeType `json:"type"`
+	Height    abi.ChainEpoch       `json:"height"`
+	TipSetKey types.TipSetKey      `json:"tipsetKey"`
+	// TipSet is set when the sink receives the head changes
+	TipSet *types.TipSet `json:"tipset,omitempty"`
+	// Events are the actor events of the messages executed by the tipset, which belong to its parent
+	Events []*types.ActorEvent `json:"events,omitempty"`
+	// Receipts are the receipts of the messages executed by the tipset
+	Receipts []*Receipt `json:"receipts,omitempty"`
+}
+
+// Receipt is the receipt of a message executed by a tipset.
+type Receipt struct {
+	MsgCid cid.Cid         `json:"msgCid"`
+	From   address.Address `json:"from"`
+	To     address.Address `json:"to"`
+	Method abi.MethodNum   `json:"method"`
+	// Height and TipSetKey are the ones of the tipset that contained the message
+	Height    abi.ChainEpoch       `json:"height"`
+	TipSetKey types.TipSetKey      `json:"tipsetKey"`
+	Reverted  bool                 `json:"reverted"`
+	Receipt   types.MessageReceipt `json:"receipt"`
+}
+
+// Service pushes the head changes, actor events and message receipts of the chain to the sinks
+// of the config. Each sink has a cursor in the datastore, which moves once a batch was
+// acknowledged, so that batches are delivered at least once across failures and restarts.
+type Service struct {
+	api   API
+	ds    datastore.Batching
+	sinks []*sink
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewService checks the config of the sinks and creates their publishers.
+func NewService(api API, ds datastore.Batching, cfg *config.EventSinksConfig) (*Service, error) {
+	s := &Service{api: api, ds: ds}
+	names := make(map[string]struct{})
+	for _, sc := range cfg.Sinks {
+		if sc.Name == "" {
+			return nil, fmt.Errorf("event sink without name")
+		}
+		if _, ok := names[sc.Name]; ok {
+			return nil, fmt.Errorf("duplicate event sink %s", sc.Name)
+		}
+		names[sc.Name] = struct{}{}
+		if sc.Confidence < 0 {
+			return nil, fmt.Errorf("event sink %s: negative confidence", sc.Name)
+		}
+
+		pub, err := newPublisher(sc)
+		if err != nil {
+			return nil, fmt.Errorf("event sink %s: %w", sc.Name, err)
+		}
+		s.sinks = append(s.sinks, &sink{
+			cfg: sc,
+			api: api,
+			ds:  ds,
+			pub: pub,
+			key: cursorKey.ChildString(sc.Name),
+		})
+	}
+
+	return s, nil
+}
+
+// Start resolves the filters of the sinks and starts pushing to them.
+func (s *Service) Start(ctx context.Context) error {
+	for _, sk := range s.sinks {
+		if err := sk.resolveFilters(ctx); err != nil {
+			return fmt.Errorf("event sink %s: %w", sk.cfg.Name, err)
+		}
+	}
+
+	ctx, s.cancel = context.WithCancel(context.Background())
+	for _, sk := range s.sinks {
+		s.wg.Add(1)
+		go func(sk *sink) {
+			defer s.wg.Done()
+			sk.run(ctx)
+		}(sk)
+	}
+	return nil
+}
+
+// Stop stops pushing to the sinks, the batches being delivered are sent again after a restart.
+func (s *Service) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	for _, sk := range s.sinks {
+		if err := sk.pub.Close(); err != nil {
+			log.Warnf("failed to close event sink %s: %s", sk.cfg.Name, err)
+		}
+	}
+}
+
+type sink struct {
+	cfg *config.EventSinkConfig
+	api API
+	ds  datastore.Batching
+	pub publisher
+	key datastore.Key
+
+	events           *filter.EventMatcher
+	receiptAddresses map[address.Address]struct{}
+	receiptMethods   map[abi.MethodNum]struct{}
+}
+
+func (sk *sink) resolveFilters(ctx context.Context) error {
+	var eventAddresses []address.Address
+	for _, a := range sk.cfg.EventAddresses {
+		addr, err := address.NewFromString(a)
+		if err != nil {
+			return fmt.Errorf("invalid event address %q: %w", a, err)
+		}
+		eventAddresses = append(eventAddresses, addr)
+	}
+	sk.events = filter.NewEventMatcher(eventAddresses, sk.cfg.EventFields)
+
+	// messages are matched on the address they carry, which may be the id or the robust one
+	sk.receiptAddresses = make(map[address.Address]struct{})
+	for _, a := range sk.cfg.ReceiptAddresses {
+		addr, err := address.NewFromString(a)
+		if err != nil {
+			return fmt.Errorf("invalid receipt address %q: %w", a, err)
+		}
+		sk.receiptAddresses[addr] = struct{}{}
+		if addr.Protocol() == address.ID {
+			if robust, err := sk.api.StateLookupRobustAddress(ctx, addr, types.EmptyTSK); err == nil {
+				sk.receiptAddresses[robust] = struct{}{}
+			}
+		} else if id, err := sk.api.StateLookupID(ctx, addr, types.EmptyTSK); err == nil {
+			sk.receiptAddresses[id] = struct{}{}
+		}
+	}
+	sk.receiptMethods = make(map[abi.MethodNum]struct{})
+	for _, m := range sk.cfg.ReceiptMethods {
+		sk.receiptMethods[m] = struct{}{}
+	}
+
+	return nil
+}
+
+func (sk *sink) run(ctx context.Context) {
+	log.Infow("starting event sink", "name", sk.cfg.Name, "type", sk.cfg.Type)
+
+	wait := pollInterval
+	for {
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+
+		if err := sk.catchUp(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warnw("event sink failed, retrying", "name", sk.cfg.Name, "retryIn", wait, "err", err)
+			if wait *= 2; wait > maxRetryInterval {
+				wait = maxRetryInterval
+			}
+			continue
+		}
+		wait = pollInterval
+	}
+}
+
+// catchUp pushes the tipsets from the cursor up to the head less the confidence.
+func (sk *sink) catchUp(ctx context.Context) error {
+	head, err := sk.api.ChainHead(ctx)
+	if err != nil {
+		return err
+	}
+	height := head.Height() - sk.cfg.Confidence
+	if height < 0 {
+		return nil
+	}
+	target, err := sk.api.ChainGetTipSetByHeight(ctx, height, head.Key())
+	if err != nil {
+		return err
+	}
+
+	cursor, err := sk.cursor(ctx)
+	if err != nil {
+		return err
+	}
+	if cursor == nil {
+		// a new sink starts at the chain as it is now
+		return sk.setCursor(ctx, target.Key())
+	}
+	if cursor.Equals(target.Key()) {
+		return nil
+	}
+
+	changes, err := sk.api.ChainGetPath(ctx, *cursor, target.Key())
+	if err != nil {
+		return fmt.Errorf("getting the path from %s to %s: %w", cursor, target.Key(), err)
+	}
+	for _, change := range changes {
+		if err := sk.push(ctx, change); err != nil {
+			return err
+		}
+
+		next := change.Val.Key()
+		if change.Type == types.HCRevert {
+			next = change.Val.Parents()
+		}
+		if err := sk.setCursor(ctx, next); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// push delivers the batch of a head change, it skips the batches with nothing to deliver.
+func (sk *sink) push(ctx context.Context, change *types.HeadChange) error {
+	batch, err := sk.batch(ctx, change)
+	if err != nil {
+		return fmt.Errorf("making the batch of %d: %w", change.Val.Height(), err)
+	}
+	if batch.TipSet == nil && len(batch.Events) == 0 && len(batch.Receipts) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	timeout := time.Duration(sk.cfg.Timeout)
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	pctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := sk.pub.Publish(pctx, payload); err != nil {
+		return fmt.Errorf("delivering the batch of %d: %w", change.Val.Height(), err)
+	}
+	log.Debugw("delivered batch", "name", sk.cfg.Name, "type", batch.Type, "height", batch.Height)
+	return nil
+}
+
+func (sk *sink) batch(ctx context.Context, change *types.HeadChange) (*Batch, error) {
+	ts := change.Val
+	batch := &Batch{
+		Sink:      sk.cfg.Name,
+		Type:      change.Type,
+		Height:    ts.Height(),
+		TipSetKey: ts.Key(),
+	}
+	if sk.cfg.HeadChanges {
+		batch.TipSet = ts
+	}
+	if (!sk.cfg.ActorEvents && !sk.cfg.Receipts) || ts.Height() == 0 {
+		return batch, nil
+	}
+
+	parent, err := sk.api.ChainGetTipSet(ctx, ts.Parents())
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := sk.api.ChainGetParentMessages(ctx, ts.At(0).Cid())
+	if err != nil {
+		return nil, err
+	}
+	rcpts, err := sk.api.ChainGetParentReceipts(ctx, ts.At(0).Cid())
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) != len(rcpts) {
+		return nil, fmt.Errorf("%d messages but %d receipts", len(msgs), len(rcpts))
+	}
+
+	reverted := change.Type == types.HCRevert
+	// cache of the addresses the emitters are matched with
+	emitters := make(map[abi.ActorID]address.Address)
+	for i, msg := range msgs {
+		rcpt := rcpts[i]
+		if sk.cfg.Receipts && sk.matchReceipt(msg.Message) {
+			batch.Receipts = append(batch.Receipts, &Receipt{
+				MsgCid:    msg.Cid,
+				From:      msg.Message.From,
+				To:        msg.Message.To,
+				Method:    msg.Message.Method,
+				Height:    parent.Height(),
+				TipSetKey: parent.Key(),
+				Reverted:  reverted,
+				Receipt:   *rcpt,
+			})
+		}
+
+		if !sk.cfg.ActorEvents || rcpt.EventsRoot == nil {
+			continue
+		}
+		events, err := sk.api.ChainGetEvents(ctx, *rcpt.EventsRoot)
+		if err != nil {
+			return nil, fmt.Errorf("loading the events of %s: %w", msg.Cid, err)
+		}
+		for _, ev := range events {
+			emitter, ok := emitters[ev.Emitter]
+			if !ok {
+				if emitter, err = sk.resolveEmitter(ctx, ev.Emitter, ts.Key()); err != nil {
+					return nil, err
+				}
+				emitters[ev.Emitter] = emitter
+			}
+			if !sk.events.Match(emitter, ev.Entries) {
+				if idAddr, _ := address.NewIDAddress(uint64(ev.Emitter)); emitter == idAddr || !sk.events.Match(idAddr, ev.Entries) {
+					continue
+				}
+			}
+
+			batch.Events = append(batch.Events, &types.ActorEvent{
+				Entries:   ev.Entries,
+				Emitter:   emitter,
+				Reverted:  reverted,
+				Height:    parent.Height(),
+				TipSetKey: parent.Key(),
+				MsgCid:    msg.Cid,
+			})
+		}
+	}
+
+	return batch, nil
+}
+
+// resolveEmitter returns the f4 address of the emitter when it has one, its id address otherwise,
+// as the actor event filters do.
+func (sk *sink) resolveEmitter(ctx context.Context, emitter abi.ActorID, tsk types.TipSetKey) (address.Address, error) {
+	idAddr, err := address.NewIDAddress(uint64(emitter))
+	if err != nil {
+		return address.Undef, err
+	}
+	actor, err := sk.api.StateGetActor(ctx, idAddr, tsk)
+	if err != nil || actor.Address == nil {
+		return idAddr, nil
+	}
+	return *actor.Address, nil
+}
+
+func (sk *sink) matchReceipt(msg *types.Message) bool {
+	if len(sk.receiptMethods) > 0 {
+		if _, ok := sk.receiptMethods[msg.Method]; !ok {
+			return false
+		}
+	}
+	if len(sk.receiptAddresses) == 0 {
+		return true
+	}
+	_, from := sk.receiptAddresses[msg.From]
+	_, to := sk.receiptAddresses[msg.To]
+	return from || to
+}
+
+func (sk *sink) cursor(ctx context.Context) (*types.TipSetKey, error) {
+	data, err := sk.ds.Get(ctx, sk.key)
+	if errors.Is(err, datastore.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var tsk types.TipSetKey
+	if err := json.Unmarshal(data, &tsk); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the cursor of %s: %w", sk.cfg.Name, err)
+	}
+	return &tsk, nil
+}
+
+func (sk *sink) setCursor(ctx context.Context, tsk types.TipSetKey) error {
+	data, err := json.Marshal(tsk)
+	if err != nil {
+		return err
+	}
+	return sk.ds.Put(ctx, sk.key, data)
+}
@@ -0,0 +1,440 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus/pkg/config"
+	"github.com/filecoin-project/venus/pkg/testhelpers"
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+type fakeAPI struct {
+	lk       sync.Mutex
+	head     *types.TipSet
+	tipsets  map[types.TipSetKey]*types.TipSet
+	messages map[cid.Cid]*types.Message
+	// events are keyed by the cid of the block executing the message which emitted them
+	events map[cid.Cid][]types.Event
+	actors map[address.Address]*types.Actor
+}
+
+func newFakeAPI(genesis *types.TipSet) *fakeAPI {
+	api := &fakeAPI{
+		tipsets:  make(map[types.TipSetKey]*types.TipSet),
+		messages: make(map[cid.Cid]*types.Message),
+		events:   make(map[cid.Cid][]types.Event),
+		actors:   make(map[address.Address]*types.Actor),
+	}
+	api.add(genesis)
+	return api
+}
+
+func (f *fakeAPI) add(ts *types.TipSet) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	f.tipsets[ts.Key()] = ts
+	f.head = ts
+}
+
+func (f *fakeAPI) ChainHead(context.Context) (*types.TipSet, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	return f.head, nil
+}
+
+func (f *fakeAPI) ChainGetTipSet(_ context.Context, key types.TipSetKey) (*types.TipSet, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	return f.tipsets[key], nil
+}
+
+func (f *fakeAPI) ChainGetTipSetByHeight(_ context.Context, height abi.ChainEpoch, tsk types.TipSetKey) (*types.TipSet, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	ts := f.tipsets[tsk]
+	for ts.Height() > height {
+		ts = f.tipsets[ts.Parents()]
+	}
+	return ts, nil
+}
+
+func (f *fakeAPI) ChainGetPath(_ context.Context, from types.TipSetKey, to types.TipSetKey) ([]*types.HeadChange, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	left, right := f.tipsets[from], f.tipsets[to]
+	if left == nil || right == nil {
+		return nil, fmt.Errorf("tipset not found")
+	}
+	var reverts, applies []*types.HeadChange
+	for !left.Equals(right) {
+		if left.Height() >= right.Height() {
+			reverts = append(reverts, &types.HeadChange{Type: types.HCRevert, Val: left})
+			left = f.tipsets[left.Parents()]
+		} else {
+			applies = append(applies, &types.HeadChange{Type: types.HCApply, Val: right})
+			right = f.tipsets[right.Parents()]
+		}
+	}
+	for i := len(applies) - 1; i >= 0; i-- {
+		reverts = append(reverts, applies[i])
+	}
+	return reverts, nil
+}
+
+func (f *fakeAPI) ChainGetParentMessages(_ context.Context, bcid cid.Cid) ([]types.MessageCID, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	msg := f.messages[bcid]
+	return []types.MessageCID{{Cid: msg.Cid(), Message: msg}}, nil
+}
+
+func (f *fakeAPI) ChainGetParentReceipts(_ context.Context, bcid cid.Cid) ([]*types.MessageReceipt, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	rcpt := &types.MessageReceipt{GasUsed: 10}
+	if _, ok := f.events[bcid]; ok {
+		rcpt.EventsRoot = &bcid
+	}
+	return []*types.MessageReceipt{rcpt}, nil
+}
+
+func (f *fakeAPI) ChainGetEvents(_ context.Context, root cid.Cid) ([]types.Event, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	return f.events[root], nil
+}
+
+func (f *fakeAPI) StateGetActor(_ context.Context, addr address.Address, _ types.TipSetKey) (*types.Actor, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	if actor, ok := f.actors[addr]; ok {
+		return actor, nil
+	}
+	return &types.Actor{}, nil
+}
+
+func (f *fakeAPI) StateLookupID(_ context.Context, addr address.Address, _ types.TipSetKey) (address.Address, error) {
+	return addr, nil
+}
+
+func (f *fakeAPI) StateLookupRobustAddress(_ context.Context, addr address.Address, _ types.TipSetKey) (address.Address, error) {
+	return addr, nil
+}
+
+// extend adds a tipset on top of parent, which executes a message of parent sent to `to`.
+func (f *fakeAPI) extend(t *testing.T, parent *types.TipSet, miner uint64, to address.Address) *types.TipSet {
+	blk := &types.BlockHeader{
+		Miner:                 idAddr(t, miner),
+		Height:                parent.Height() + 1,
+		Parents:               parent.Cids(),
+		ParentWeight:          types.NewInt(0),
+		ParentBaseFee:         types.NewInt(0),
+		ParentStateRoot:       testhelpers.EmptyMessagesCID,
+		ParentMessageReceipts: testhelpers.EmptyMessagesCID,
+		Messages:              testhelpers.EmptyMessagesCID,
+	}
+	ts, err := types.NewTipSet([]*types.BlockHeader{blk})
+	require.NoError(t, err)
+
+	f.lk.Lock()
+	f.messages[blk.Cid()] = &types.Message{From: idAddr(t, 100), To: to, Nonce: uint64(blk.Height), Value: types.NewInt(0), GasFeeCap: types.NewInt(0), GasPremium: types.NewInt(0)}
+	f.lk.Unlock()
+	f.add(ts)
+	return ts
+}
+
+func newGenesis(t *testing.T) *types.TipSet {
+	genesis, err := types.NewTipSet([]*types.BlockHeader{{
+		Miner:                 idAddr(t, 1),
+		ParentWeight:          types.NewInt(0),
+		ParentBaseFee:         types.NewInt(0),
+		ParentStateRoot:       testhelpers.EmptyMessagesCID,
+		ParentMessageReceipts: testhelpers.EmptyMessagesCID,
+		Messages:              testhelpers.EmptyMessagesCID,
+	}})
+	require.NoError(t, err)
+	return genesis
+}
+
+func idAddr(t *testing.T, id uint64) address.Address {
+	addr, err := address.NewIDAddress(id)
+	require.NoError(t, err)
+	return addr
+}
+
+// recorder records the batches it is given, it fails once it received failAfter batches.
+type recorder struct {
+	failAfter int
+	batches   []*Batch
+}
+
+func (r *recorder) Publish(_ context.Context, payload []byte) error {
+	if r.failAfter >= 0 && len(r.batches) >= r.failAfter {
+		return fmt.Errorf("unavailable")
+	}
+	var batch Batch
+	if err := json.Unmarshal(payload, &batch); err != nil {
+		return err
+	}
+	r.batches = append(r.batches, &batch)
+	return nil
+}
+
+func (r *recorder) Close() error {
+	return nil
+}
+
+func newTestSink(t *testing.T, api *fakeAPI, cfg *config.EventSinkConfig, pub publisher) *sink {
+	sk := &sink{
+		cfg: cfg,
+		api: api,
+		ds:  datastore.NewMapDatastore(),
+		pub: pub,
+		key: cursorKey.ChildString(cfg.Name),
+	}
+	require.NoError(t, sk.resolveFilters(context.Background()))
+	return sk
+}
+
+type stub struct {
+	lk      sync.Mutex
+	fail    int
+	batches []*Batch
+}
+
+func (s *stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	if s.fail > 0 {
+		s.fail--
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	data, _ := io.ReadAll(r.Body)
+	var batch Batch
+	if err := json.Unmarshal(data, &batch); err != nil || r.Header.Get("X-Token") != "secret" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.batches = append(s.batches, &batch)
+}
+
+func (s *stub) received() []*Batch {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	return append([]*Batch{}, s.batches...)
+}
+
+func TestWebhookSink(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	interval := pollInterval
+	t.Cleanup(func() { pollInterval = interval })
+	pollInterval = 10 * time.Millisecond
+	watched, other := idAddr(t, 1000), idAddr(t, 1001)
+
+	genesis := newGenesis(t)
+	api := newFakeAPI(genesis)
+
+	srv := &stub{fail: 2}
+	httpSrv := httptest.NewServer(srv)
+	defer httpSrv.Close()
+
+	cfg := &config.EventSinksConfig{Sinks: []*config.EventSinkConfig{{
+		Name:             "test",
+		Type:             webhookSink,
+		URL:              httpSrv.URL,
+		Headers:          map[string]string{"X-Token": "secret"},
+		Confidence:       1,
+		HeadChanges:      true,
+		Receipts:         true,
+		ReceiptAddresses: []string{watched.String()},
+	}}}
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+
+	start := func() *Service {
+		s, err := NewService(api, ds, cfg)
+		require.NoError(t, err)
+		require.NoError(t, s.Start(ctx))
+		return s
+	}
+
+	// a new sink starts at the chain as it is now, less the confidence
+	ts1 := api.extend(t, genesis, 1, watched)
+	s := start()
+	require.Eventually(t, func() bool {
+		cursor, err := s.sinks[0].cursor(ctx)
+		return err == nil && cursor != nil && cursor.Equals(genesis.Key())
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// ts3 is not confident yet
+	ts2 := api.extend(t, ts1, 1, watched)
+	api.extend(t, ts2, 1, other)
+
+	// the first deliveries fail, the batches are sent again
+	require.Eventually(t, func() bool { return len(srv.received()) == 2 }, 10*time.Second, 10*time.Millisecond)
+	batches := srv.received()
+	require.Equal(t, ts1.Key(), batches[0].TipSetKey)
+	require.Len(t, batches[0].Receipts, 1)
+	require.Equal(t, genesis.Key(), batches[0].Receipts[0].TipSetKey)
+	require.Equal(t, ts2.Key(), batches[1].TipSetKey)
+	require.Len(t, batches[1].Receipts, 1)
+	require.Equal(t, watched, batches[1].Receipts[0].To)
+	require.Equal(t, ts1.Key(), batches[1].Receipts[0].TipSetKey)
+	require.EqualValues(t, 10, batches[1].Receipts[0].Receipt.GasUsed)
+	require.Eventually(t, func() bool {
+		cursor, err := s.sinks[0].cursor(ctx)
+		return err == nil && cursor.Equals(ts2.Key())
+	}, 10*time.Second, 10*time.Millisecond)
+	s.Stop()
+
+	// the chain reorgs while the sink is stopped, it resumes from its cursor
+	fork := api.extend(t, ts1, 2, watched)
+	api.extend(t, fork, 2, other)
+	s = start()
+	defer s.Stop()
+
+	require.Eventually(t, func() bool { return len(srv.received()) == 4 }, 10*time.Second, 10*time.Millisecond)
+	batches = srv.received()
+	require.Equal(t, types.HCRevert, batches[2].Type)
+	require.Equal(t, ts2.Key(), batches[2].TipSetKey)
+	require.True(t, batches[2].Receipts[0].Reverted)
+	require.Equal(t, types.HCApply, batches[3].Type)
+	require.Equal(t, fork.Key(), batches[3].TipSetKey)
+	require.Len(t, batches[3].Receipts, 1)
+	require.False(t, batches[3].Receipts[0].Reverted)
+}
+
+func TestNewServiceRejectsInvalidSinks(t *testing.T) {
+	tf.UnitTest(t)
+
+	ds := datastore.NewMapDatastore()
+	for _, sinks := range [][]*config.EventSinkConfig{
+		{{Type: webhookSink, URL: "http://127.0.0.1"}},
+		{{Name: "a", Type: webhookSink, URL: "http://127.0.0.1"}, {Name: "a", Type: webhookSink, URL: "http://127.0.0.1"}},
+		{{Name: "a", Type: "kafka", URL: "kafka://127.0.0.1"}},
+		{{Name: "a", Type: natsSink, URL: "http://127.0.0.1"}},
+	} {
+		_, err := NewService(&fakeAPI{}, ds, &config.EventSinksConfig{Sinks: sinks})
+		require.Error(t, err)
+	}
+}
+
+func TestSinkActorEvents(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	genesis := newGenesis(t)
+	api := newFakeAPI(genesis)
+	ts1 := api.extend(t, genesis, 1, idAddr(t, 1000))
+
+	delegated := func(id uint64) address.Address {
+		addr, err := address.NewDelegatedAddress(10, []byte(fmt.Sprintf("%020d", id)))
+		require.NoError(t, err)
+		api.actors[idAddr(t, id)] = &types.Actor{Address: &addr}
+		return addr
+	}
+	// 2000 and 2001 have f4 addresses, 2002 only an id one
+	f4a, f4b := delegated(2000), delegated(2001)
+	transfer := []types.EventEntry{{Flags: types.EventFlagIndexedValue, Key: "type", Codec: 0x55, Value: []byte("transfer")}}
+	approval := []types.EventEntry{{Flags: types.EventFlagIndexedValue, Key: "type", Codec: 0x55, Value: []byte("approval")}}
+	api.events[ts1.At(0).Cid()] = []types.Event{
+		{Emitter: 2000, Entries: transfer},
+		{Emitter: 2000, Entries: approval},
+		{Emitter: 2001, Entries: transfer},
+		{Emitter: 2002, Entries: transfer},
+		{Emitter: 2003, Entries: transfer},
+	}
+
+	rec := &recorder{failAfter: -1}
+	sk := newTestSink(t, api, &config.EventSinkConfig{
+		Name:        "events",
+		ActorEvents: true,
+		// 2001 is given by its id address, it still matches the events reported with its f4 one
+		EventAddresses: []string{f4a.String(), idAddr(t, 2001).String(), idAddr(t, 2002).String()},
+		EventFields:    map[string][]types.ActorEventBlock{"type": {{Codec: 0x55, Value: []byte("transfer")}}},
+	}, rec)
+
+	require.NoError(t, sk.push(ctx, &types.HeadChange{Type: types.HCApply, Val: ts1}))
+	require.NoError(t, sk.push(ctx, &types.HeadChange{Type: types.HCRevert, Val: ts1}))
+	require.Len(t, rec.batches, 2)
+
+	for i, batch := range rec.batches {
+		reverted := i == 1
+		require.Nil(t, batch.TipSet)
+		require.Len(t, batch.Events, 3)
+		for j, emitter := range []address.Address{f4a, f4b, idAddr(t, 2002)} {
+			ev := batch.Events[j]
+			require.Equal(t, emitter, ev.Emitter)
+			require.Equal(t, transfer, ev.Entries)
+			require.Equal(t, reverted, ev.Reverted)
+			require.Equal(t, genesis.Key(), ev.TipSetKey)
+			require.Equal(t, api.messages[ts1.At(0).Cid()].Cid(), ev.MsgCid)
+		}
+	}
+
+	// nothing is delivered for a tipset without matching events
+	ts2 := api.extend(t, ts1, 1, idAddr(t, 1000))
+	require.NoError(t, sk.push(ctx, &types.HeadChange{Type: types.HCApply, Val: ts2}))
+	require.Len(t, rec.batches, 2)
+}
+
+func TestSinkCursor(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	genesis := newGenesis(t)
+	api := newFakeAPI(genesis)
+	ts1 := api.extend(t, genesis, 1, idAddr(t, 1000))
+	ts2 := api.extend(t, ts1, 1, idAddr(t, 1000))
+	fork := api.extend(t, ts1, 2, idAddr(t, 1000))
+
+	// the delivery of the apply of the fork fails after the revert of ts2 was acknowledged
+	rec := &recorder{failAfter: 1}
+	sk := newTestSink(t, api, &config.EventSinkConfig{Name: "cursor", HeadChanges: true}, rec)
+	require.NoError(t, sk.setCursor(ctx, ts2.Key()))
+
+	require.Error(t, sk.catchUp(ctx))
+	require.Len(t, rec.batches, 1)
+	require.Equal(t, types.HCRevert, rec.batches[0].Type)
+	require.Equal(t, ts2.Key(), rec.batches[0].TipSetKey)
+	cursor, err := sk.cursor(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ts2.Parents(), *cursor)
+
+	// the next run resumes from the parent of the reverted tipset
+	rec.failAfter = -1
+	require.NoError(t, sk.catchUp(ctx))
+	require.Len(t, rec.batches, 2)
+	require.Equal(t, types.HCApply, rec.batches[1].Type)
+	require.Equal(t, fork.Key(), rec.batches[1].TipSetKey)
+	cursor, err = sk.cursor(ctx)
+	require.NoError(t, err)
+	require.Equal(t, fork.Key(), *cursor)
+
+	// a cursor on a tipset the node doesn't know fails without moving
+	unknown := types.NewTipSetKey(testhelpers.EmptyMessagesCID)
+	require.NoError(t, sk.setCursor(ctx, unknown))
+	require.ErrorContains(t, sk.catchUp(ctx), "getting the path from")
+	cursor, err = sk.cursor(ctx)
+	require.NoError(t, err)
+	require.Equal(t, unknown, *cursor)
+	require.Len(t, rec.batches, 2)
+}
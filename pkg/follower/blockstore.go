@@ -0,0 +1,239 @@
+package follower
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+
+	blockstoreutil "github.com/filecoin-project/venus/venus-shared/blockstore"
+)
+
+// Dialer opens a websocket connection to the blockstore of the leader.
+type Dialer func(ctx context.Context) (*websocket.Conn, error)
+
+// NewDialer returns a Dialer of the blockstore served at url, with the http header of the
+// authentication.
+func NewDialer(url string, header http.Header) Dialer {
+	return func(ctx context.Context) (*websocket.Conn, error) {
+		wc, resp, err := websocket.DefaultDialer.DialContext(ctx, url, header)
+		if err != nil {
+			if resp != nil {
+				return nil, fmt.Errorf("dialing %s: %w (%s)", url, err, resp.Status)
+			}
+			return nil, fmt.Errorf("dialing %s: %w", url, err)
+		}
+		return wc, nil
+	}
+}
+
+// Blockstore reads the blocks from the local blockstore, then from the blockstore of the leader,
+// it keeps the blocks read from the leader in memory. The writes only go to the local blockstore.
+type Blockstore struct {
+	local blockstoreutil.Blockstore
+	cache *blockCache
+	dial  Dialer
+
+	lk     sync.Mutex
+	remote *blockstoreutil.NetworkStore
+	conn   *websocket.Conn
+}
+
+var _ blockstoreutil.Blockstore = (*Blockstore)(nil)
+
+// NewBlockstore creates a Blockstore caching up to cacheBytes bytes of blocks of the leader, the
+// connection to the leader is opened at the first read the local blockstore misses, and opened
+// again after it closes.
+func NewBlockstore(local blockstoreutil.Blockstore, dial Dialer, cacheBytes int64) (*Blockstore, error) {
+	if cacheBytes <= 0 {
+		return nil, fmt.Errorf("invalid block cache size %d", cacheBytes)
+	}
+	return &Blockstore{
+		local: local,
+		cache: newBlockCache(cacheBytes),
+		dial:  dial,
+	}, nil
+}
+
+// blockCache keeps the blocks last read up to a total size in bytes.
+type blockCache struct {
+	lk       sync.Mutex
+	blocks   *simplelru.LRU[cid.Cid, blocks.Block]
+	size     int64
+	maxBytes int64
+}
+
+func newBlockCache(maxBytes int64) *blockCache {
+	c := &blockCache{maxBytes: maxBytes}
+	// the number of blocks is only bounded by their size
+	c.blocks, _ = simplelru.NewLRU[cid.Cid, blocks.Block](math.MaxInt, func(_ cid.Cid, blk blocks.Block) {
+		c.size -= int64(len(blk.RawData()))
+	})
+	return c
+}
+
+func (c *blockCache) Get(key cid.Cid) (blocks.Block, bool) {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.blocks.Get(key)
+}
+
+func (c *blockCache) Contains(key cid.Cid) bool {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.blocks.Contains(key)
+}
+
+// Add keeps blk, evicting the blocks least recently used until the cache fits in its size.
+func (c *blockCache) Add(blk blocks.Block) {
+	size := int64(len(blk.RawData()))
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	if size > c.maxBytes || c.blocks.Contains(blk.Cid()) {
+		return
+	}
+	c.blocks.Add(blk.Cid(), blk)
+	c.size += size
+	for c.size > c.maxBytes {
+		c.blocks.RemoveOldest()
+	}
+}
+
+// remoteStore returns the blockstore of the leader, connecting to it when needed.
+func (bs *Blockstore) remoteStore(ctx context.Context) (*blockstoreutil.NetworkStore, error) {
+	bs.lk.Lock()
+	if bs.remote != nil {
+		defer bs.lk.Unlock()
+		return bs.remote, nil
+	}
+	wc, err := bs.dial(ctx)
+	if err != nil {
+		bs.lk.Unlock()
+		return nil, err
+	}
+	remote := blockstoreutil.NewNetworkStoreWS(wc)
+	bs.remote, bs.conn = remote, wc
+	bs.lk.Unlock()
+
+	// the callback runs at once when the connection is already closed, so not under the lock
+	remote.OnClose(func() {
+		bs.lk.Lock()
+		defer bs.lk.Unlock()
+		if bs.remote == remote {
+			bs.remote, bs.conn = nil, nil
+		}
+	})
+	return remote, nil
+}
+
+// getRemote reads a block the local blockstore misses from the leader.
+func (bs *Blockstore) getRemote(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	if blk, ok := bs.cache.Get(c); ok {
+		return blk, nil
+	}
+	remote, err := bs.remoteStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := remote.Get(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	bs.cache.Add(blk)
+	return blk, nil
+}
+
+func (bs *Blockstore) Has(ctx context.Context, c cid.Cid) (bool, error) {
+	if has, err := bs.local.Has(ctx, c); err != nil || has {
+		return has, err
+	}
+	if bs.cache.Contains(c) {
+		return true, nil
+	}
+	remote, err := bs.remoteStore(ctx)
+	if err != nil {
+		return false, err
+	}
+	return remote.Has(ctx, c)
+}
+
+func (bs *Blockstore) Get(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	blk, err := bs.local.Get(ctx, c)
+	if ipld.IsNotFound(err) {
+		return bs.getRemote(ctx, c)
+	}
+	return blk, err
+}
+
+func (bs *Blockstore) View(ctx context.Context, c cid.Cid, callback func([]byte) error) error {
+	err := bs.local.View(ctx, c, callback)
+	if ipld.IsNotFound(err) {
+		blk, err := bs.getRemote(ctx, c)
+		if err != nil {
+			return err
+		}
+		return callback(blk.RawData())
+	}
+	return err
+}
+
+func (bs *Blockstore) GetSize(ctx context.Context, c cid.Cid) (int, error) {
+	size, err := bs.local.GetSize(ctx, c)
+	if ipld.IsNotFound(err) {
+		blk, err := bs.getRemote(ctx, c)
+		if err != nil {
+			return 0, err
+		}
+		return len(blk.RawData()), nil
+	}
+	return size, err
+}
+
+func (bs *Blockstore) Put(ctx context.Context, blk blocks.Block) error {
+	return bs.local.Put(ctx, blk)
+}
+
+func (bs *Blockstore) PutMany(ctx context.Context, blks []blocks.Block) error {
+	return bs.local.PutMany(ctx, blks)
+}
+
+func (bs *Blockstore) DeleteBlock(ctx context.Context, c cid.Cid) error {
+	return bs.local.DeleteBlock(ctx, c)
+}
+
+func (bs *Blockstore) DeleteMany(ctx context.Context, cids []cid.Cid) error {
+	return bs.local.DeleteMany(ctx, cids)
+}
+
+// AllKeysChan only lists the local blocks.
+func (bs *Blockstore) AllKeysChan(ctx context.Context) (<-chan cid.Cid, error) {
+	return bs.local.AllKeysChan(ctx)
+}
+
+func (bs *Blockstore) HashOnRead(enabled bool) {
+	bs.local.HashOnRead(enabled)
+}
+
+func (bs *Blockstore) Flush(ctx context.Context) error {
+	return bs.local.Flush(ctx)
+}
+
+// Close closes the connection to the leader.
+func (bs *Blockstore) Close() error {
+	bs.lk.Lock()
+	defer bs.lk.Unlock()
+
+	if bs.conn == nil {
+		return nil
+	}
+	conn := bs.conn
+	bs.remote, bs.conn = nil, nil
+	return conn.Close()
+}
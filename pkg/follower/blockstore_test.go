@@ -0,0 +1,143 @@
+package follower
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	blocks "github.com/ipfs/go-block-format"
+	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/stretchr/testify/require"
+
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	blockstoreutil "github.com/filecoin-project/venus/venus-shared/blockstore"
+)
+
+// leaderServer serves bs like a leader and keeps the connections of the followers.
+type leaderServer struct {
+	bs blockstoreutil.Blockstore
+
+	lk    sync.Mutex
+	conns []*websocket.Conn
+}
+
+func (s *leaderServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	wc, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	s.lk.Lock()
+	s.conns = append(s.conns, wc)
+	s.lk.Unlock()
+	blockstoreutil.HandleNetBstoreWS(context.Background(), s.bs, wc)
+}
+
+func (s *leaderServer) connections() []*websocket.Conn {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	return append([]*websocket.Conn{}, s.conns...)
+}
+
+func TestBlockstore(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	leaderBs := blockstoreutil.NewTemporarySync()
+	leaderBlk := blocks.NewBlock([]byte("leader"))
+	require.NoError(t, leaderBs.Put(ctx, leaderBlk))
+
+	leader := &leaderServer{bs: leaderBs}
+	srv := httptest.NewServer(leader)
+	defer srv.Close()
+
+	local := blockstoreutil.NewTemporarySync()
+	bs, err := NewBlockstore(local, NewDialer("ws"+strings.TrimPrefix(srv.URL, "http")+BlockstorePath, nil), 1<<20)
+	require.NoError(t, err)
+	defer bs.Close() // nolint
+
+	// the writes and the reads of the local blocks do not reach the leader
+	localBlk := blocks.NewBlock([]byte("local"))
+	require.NoError(t, bs.Put(ctx, localBlk))
+	blk, err := bs.Get(ctx, localBlk.Cid())
+	require.NoError(t, err)
+	require.Equal(t, localBlk.RawData(), blk.RawData())
+	has, err := leaderBs.Has(ctx, localBlk.Cid())
+	require.NoError(t, err)
+	require.False(t, has)
+	require.Empty(t, leader.connections())
+
+	// the blocks the node misses are read from the leader, and only kept in memory
+	blk, err = bs.Get(ctx, leaderBlk.Cid())
+	require.NoError(t, err)
+	require.Equal(t, leaderBlk.RawData(), blk.RawData())
+	require.NoError(t, bs.View(ctx, leaderBlk.Cid(), func(data []byte) error {
+		require.Equal(t, leaderBlk.RawData(), data)
+		return nil
+	}))
+	size, err := bs.GetSize(ctx, leaderBlk.Cid())
+	require.NoError(t, err)
+	require.Equal(t, len(leaderBlk.RawData()), size)
+	has, err = local.Has(ctx, leaderBlk.Cid())
+	require.NoError(t, err)
+	require.False(t, has)
+
+	missing := blocks.NewBlock([]byte("missing"))
+	_, err = bs.Get(ctx, missing.Cid())
+	require.True(t, ipld.IsNotFound(err), err)
+	has, err = bs.Has(ctx, missing.Cid())
+	require.NoError(t, err)
+	require.False(t, has)
+
+	// the blockstore connects again after the connection to the leader closes
+	require.Len(t, leader.connections(), 1)
+	require.NoError(t, leader.connections()[0].Close())
+	nextBlk := blocks.NewBlock([]byte("next"))
+	require.NoError(t, leaderBs.Put(ctx, nextBlk))
+	require.Eventually(t, func() bool {
+		_, err := bs.Get(ctx, nextBlk.Cid())
+		return err == nil
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Len(t, leader.connections(), 2)
+}
+
+func TestBlockstoreURL(t *testing.T) {
+	tf.UnitTest(t)
+
+	for addr, expect := range map[string]string{
+		"/ip4/127.0.0.1/tcp/3453":       "ws://127.0.0.1:3453/chain/blockstore",
+		"/ip4/127.0.0.1/tcp/3453/https": "wss://127.0.0.1:3453/chain/blockstore",
+		"http://leader:3453":            "ws://leader:3453/chain/blockstore",
+		"wss://leader:3453/rpc/v1":      "wss://leader:3453/chain/blockstore",
+	} {
+		url, err := blockstoreURL(addr)
+		require.NoError(t, err)
+		require.Equal(t, expect, url, addr)
+	}
+}
+
+func TestBlockCache(t *testing.T) {
+	tf.UnitTest(t)
+
+	cache := newBlockCache(10)
+	a, b, c := blocks.NewBlock([]byte("aaaa")), blocks.NewBlock([]byte("bbbb")), blocks.NewBlock([]byte("cccc"))
+	cache.Add(a)
+	cache.Add(b)
+	// a is used last, b is evicted to fit c
+	_, ok := cache.Get(a.Cid())
+	require.True(t, ok)
+	cache.Add(c)
+	require.True(t, cache.Contains(a.Cid()))
+	require.False(t, cache.Contains(b.Cid()))
+	require.True(t, cache.Contains(c.Cid()))
+	require.EqualValues(t, 8, cache.size)
+
+	// a block larger than the cache is not kept
+	cache.Add(blocks.NewBlock([]byte("larger than the cache")))
+	require.True(t, cache.Contains(a.Cid()))
+	require.EqualValues(t, 8, cache.size)
+}
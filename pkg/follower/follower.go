@@ -0,0 +1,277 @@
+// Package follower runs a read-only node which does not sync the chain, but reads it from the
+// blockstore of a leader node and follows the head of the leader.
+package follower
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/filecoin-project/go-jsonrpc"
+	logging "github.com/ipfs/go-log/v2"
+
+	"github.com/filecoin-project/venus/pkg/chain"
+	"github.com/filecoin-project/venus/pkg/config"
+	"github.com/filecoin-project/venus/pkg/repo"
+	"github.com/filecoin-project/venus/venus-shared/actors/policy"
+	"github.com/filecoin-project/venus/venus-shared/api"
+	v1api "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	blockstoreutil "github.com/filecoin-project/venus/venus-shared/blockstore"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+var log = logging.Logger("follower")
+
+// BlockstorePath is the http path a leader serves its blockstore at over websocket.
+const BlockstorePath = "/chain/blockstore"
+
+// retryInterval is the wait before following the leader again after the subscription to its head
+// changes failed or closed.
+var retryInterval = 5 * time.Second
+
+// Follower follows the head of the leader. The blocks and the states of the tipsets the node does
+// not have are read from the leader.
+type Follower struct {
+	leader v1api.FullNode
+	closer jsonrpc.ClientCloser
+	bs     *Blockstore
+	repo   *followerRepo
+
+	store  *chain.Store
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New connects to the leader configured in cfg. The blockstore of the returned Repo reads from
+// the leader what r misses. When the head of r is far behind the head of the leader, or on a chain
+// the leader does not know, New moves it to the head of the leader.
+func New(ctx context.Context, r repo.Repo, cfg *config.FollowerConfig) (*Follower, error) {
+	if cfg.LeaderAPI == "" {
+		return nil, errors.New("the api of the leader is not configured")
+	}
+	bsURL, err := blockstoreURL(cfg.LeaderAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	leader, closer, err := v1api.DialFullNodeRPC(ctx, cfg.LeaderAPI, cfg.LeaderToken, nil)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to the leader %s: %w", cfg.LeaderAPI, err)
+	}
+	dial := NewDialer(bsURL, api.NewAPIInfo(cfg.LeaderAPI, cfg.LeaderToken).AuthHeader())
+	bs, err := NewBlockstore(r.Datastore(), dial, cfg.BlockCacheBytes)
+	if err != nil {
+		closer()
+		return nil, err
+	}
+
+	f := &Follower{
+		leader: leader,
+		closer: closer,
+		bs:     bs,
+		repo:   &followerRepo{baseRepo: r, bs: bs},
+	}
+	if err := f.checkGenesis(ctx); err != nil {
+		f.close()
+		return nil, err
+	}
+	if err := f.bootstrap(ctx); err != nil {
+		f.close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// blockstoreURL returns the websocket url of the blockstore of the leader at addr.
+func blockstoreURL(addr string) (string, error) {
+	base, err := api.ParseAddr(addr)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	}
+	u.Path = BlockstorePath
+	return u.String(), nil
+}
+
+// checkGenesis refuses a leader on another network.
+func (f *Follower) checkGenesis(ctx context.Context) error {
+	local, err := chain.GenesisBlock(ctx, f.repo.ChainDatastore(), f.repo.Datastore())
+	if err != nil {
+		return err
+	}
+	genesis, err := f.leader.ChainGetGenesis(ctx)
+	if err != nil {
+		return fmt.Errorf("loading the genesis of the leader: %w", err)
+	}
+	if !genesis.Key().Equals(types.NewTipSetKey(local.Cid())) {
+		return fmt.Errorf("the genesis of the leader %s is not the genesis of the node %s", genesis.Key(), local.Cid())
+	}
+	return nil
+}
+
+// bootstrap moves the stored head to the head of the leader when following the leader from it
+// would apply more than a finality of tipsets, the chain store then loads the states of the recent
+// tipsets from the leader.
+func (f *Follower) bootstrap(ctx context.Context) error {
+	head, err := f.leader.ChainHead(ctx)
+	if err != nil {
+		return fmt.Errorf("loading the head of the leader: %w", err)
+	}
+
+	ds := f.repo.ChainDatastore()
+	if data, err := ds.Get(ctx, chain.HeadKey); err == nil {
+		var tsk types.TipSetKey
+		if err := tsk.UnmarshalCBOR(bytes.NewReader(data)); err == nil {
+			ts, err := f.leader.ChainGetTipSet(ctx, tsk)
+			if err == nil && head.Height()-ts.Height() <= policy.ChainFinality {
+				return nil
+			}
+		}
+	}
+
+	log.Infof("moving the head to the head of the leader %s at %d", head.Key(), head.Height())
+	buf := new(bytes.Buffer)
+	if err := head.Key().MarshalCBOR(buf); err != nil {
+		return err
+	}
+	return ds.Put(ctx, chain.HeadKey, buf.Bytes())
+}
+
+// Repo returns the repo of the node, which reads the blocks it misses from the leader.
+func (f *Follower) Repo() repo.Repo {
+	return f.repo
+}
+
+// Leader returns the api of the leader.
+func (f *Follower) Leader() v1api.FullNode {
+	return f.leader
+}
+
+// LoadTipSetMetadata loads the state root and the receipts root of ts from the leader, it is the
+// chain.TipSetMetadataLoader of a follower.
+func (f *Follower) LoadTipSetMetadata(ctx context.Context, ts *types.TipSet) (*chain.TipSetMetadata, error) {
+	state, err := f.leader.ChainGetTipSetState(ctx, ts.Key())
+	if err != nil {
+		return nil, err
+	}
+	return &chain.TipSetMetadata{
+		TipSet:          ts,
+		TipSetStateRoot: state.StateRoot,
+		TipSetReceipts:  state.Receipts,
+	}, nil
+}
+
+// Start follows the head of the leader, it sets the head of store to the head of the leader.
+func (f *Follower) Start(ctx context.Context, store *chain.Store) {
+	ctx, f.cancel = context.WithCancel(ctx)
+	f.store = store
+	f.done = make(chan struct{})
+
+	go func() {
+		defer close(f.done)
+		for {
+			err := f.follow(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warnf("following the leader: %s, retrying in %s", err, retryInterval)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+		}
+	}()
+}
+
+// follow subscribes to the head changes of the leader from the head of the node, and applies them
+// until the subscription closes.
+func (f *Follower) follow(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	head := f.store.GetHead()
+	ch, err := f.leader.ChainNotifySince(ctx, head.Key())
+	if err != nil {
+		// the leader does not know the head of the node, or it is too far behind, the node jumps
+		// to the head of the leader
+		log.Warnf("following the leader from %s: %s, moving to the head of the leader", head.Key(), err)
+		if head, err = f.leader.ChainHead(ctx); err != nil {
+			return fmt.Errorf("loading the head of the leader: %w", err)
+		}
+		if err := f.store.SetHead(ctx, head); err != nil {
+			return fmt.Errorf("setting the head to %s: %w", head.Key(), err)
+		}
+		if ch, err = f.leader.ChainNotifySince(ctx, head.Key()); err != nil {
+			return fmt.Errorf("subscribing to the head changes of the leader from %s: %w", head.Key(), err)
+		}
+	}
+	log.Infof("following the leader from %s at %d", head.Key(), head.Height())
+
+	for changes := range ch {
+		var next *types.TipSet
+		for _, change := range changes {
+			switch change.Type {
+			case types.HCCurrent, types.HCApply:
+				next = change.Val
+			case types.HCRevert:
+				if next, err = f.store.GetTipSet(ctx, change.Val.Parents()); err != nil {
+					return fmt.Errorf("loading the parent of reverted tipset %s: %w", change.Val.Key(), err)
+				}
+			}
+		}
+		if next == nil {
+			continue
+		}
+		if err := f.store.SetHead(ctx, next); err != nil {
+			return fmt.Errorf("setting the head to %s: %w", next.Key(), err)
+		}
+	}
+	return errors.New("the leader closed the subscription to its head changes")
+}
+
+// Stop stops following the leader and closes the connections to the leader.
+func (f *Follower) Stop() {
+	if f.cancel != nil {
+		f.cancel()
+		<-f.done
+	}
+	f.close()
+}
+
+func (f *Follower) close() {
+	if err := f.bs.Close(); err != nil {
+		log.Warnf("closing the blockstore of the leader: %s", err)
+	}
+	f.closer()
+}
+
+// baseRepo names the embedded repo of followerRepo, which overrides its Repo method.
+type baseRepo = repo.Repo
+
+// followerRepo is the repo of a follower, its blockstore reads the blocks it misses from the
+// leader.
+type followerRepo struct {
+	baseRepo
+	bs blockstoreutil.Blockstore
+}
+
+func (r *followerRepo) Datastore() blockstoreutil.Blockstore {
+	return r.bs
+}
+
+func (r *followerRepo) Repo() repo.Repo {
+	return r
+}
@@ -0,0 +1,157 @@
+package follower
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus/pkg/chain"
+	"github.com/filecoin-project/venus/pkg/consensus/chainselector"
+	"github.com/filecoin-project/venus/pkg/repo"
+	"github.com/filecoin-project/venus/pkg/testhelpers"
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	v1api "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// fakeLeader serves the chain of a builder like a leader.
+type fakeLeader struct {
+	v1api.FullNode
+	builder *chain.Builder
+
+	lk sync.Mutex
+	// forgotten are the tipsets the leader refuses to follow from, like the ones it pruned
+	forgotten map[types.TipSetKey]bool
+	// stateLoads counts the calls to ChainGetTipSetState
+	stateLoads int
+}
+
+func (l *fakeLeader) ChainHead(context.Context) (*types.TipSet, error) {
+	return l.builder.Store().GetHead(), nil
+}
+
+func (l *fakeLeader) ChainNotifySince(ctx context.Context, tsk types.TipSetKey) (<-chan []*types.HeadChange, error) {
+	l.lk.Lock()
+	forgotten := l.forgotten[tsk]
+	l.lk.Unlock()
+	if forgotten {
+		return nil, fmt.Errorf("loading tipset %s: not found", tsk)
+	}
+	from, err := l.builder.Store().GetTipSet(ctx, tsk)
+	if err != nil {
+		return nil, err
+	}
+	return l.builder.Store().SubHeadChangesSince(ctx, from)
+}
+
+func (l *fakeLeader) ChainGetTipSetState(ctx context.Context, tsk types.TipSetKey) (*types.TipSetState, error) {
+	l.lk.Lock()
+	l.stateLoads++
+	l.lk.Unlock()
+	return &types.TipSetState{StateRoot: l.builder.StateForKey(ctx, tsk), Receipts: testhelpers.EmptyReceiptsCID}, nil
+}
+
+func (l *fakeLeader) loads() int {
+	l.lk.Lock()
+	defer l.lk.Unlock()
+	return l.stateLoads
+}
+
+func (l *fakeLeader) forget(tsk types.TipSetKey) {
+	l.lk.Lock()
+	defer l.lk.Unlock()
+	l.forgotten[tsk] = true
+}
+
+// newTestFollower returns a follower of leader, with a chain store at the genesis sharing the
+// blocks of the leader.
+func newTestFollower(t *testing.T, leader *fakeLeader) (*Follower, *chain.Store) {
+	bs, err := NewBlockstore(leader.builder.BlockStore(), func(context.Context) (*websocket.Conn, error) {
+		return nil, fmt.Errorf("no blockstore")
+	}, 1<<20)
+	require.NoError(t, err)
+	f := &Follower{leader: leader, closer: func() {}, bs: bs}
+
+	genesis := leader.builder.Genesis()
+	store := chain.NewStore(repo.NewInMemoryRepo().ChainDatastore(), bs, genesis.At(0).Cid(), chain.NewMockCirculatingSupplyCalculator(), chainselector.Weight)
+	store.SetTipSetMetadataLoader(f.LoadTipSetMetadata)
+	require.NoError(t, store.SetHead(context.Background(), genesis))
+	return f, store
+}
+
+func requireHead(t *testing.T, store *chain.Store, ts *types.TipSet) {
+	require.Eventually(t, func() bool {
+		return store.GetHead().Equals(ts)
+	}, 10*time.Second, 10*time.Millisecond, "the follower does not reach %d", ts.Height())
+}
+
+func TestFollower(t *testing.T) {
+	tf.UnitTest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defer func(interval time.Duration) { retryInterval = interval }(retryInterval)
+	retryInterval = 10 * time.Millisecond
+
+	builder := chain.NewBuilder(t, address.Undef)
+	leader := &fakeLeader{builder: builder, forgotten: make(map[types.TipSetKey]bool)}
+	link1 := builder.AppendOn(ctx, builder.Genesis(), 1)
+	link3 := builder.AppendManyOn(ctx, 2, link1)
+	require.NoError(t, builder.Store().SetHead(ctx, link3))
+
+	// the follower catches up with the head of the leader
+	f, store := newTestFollower(t, leader)
+	changes := store.SubHeadChanges(ctx)
+	f.Start(ctx, store)
+	requireHead(t, store, link3)
+
+	// the follower reverts the tipsets the leader reverts
+	fork := builder.AppendManyOn(ctx, 3, link1)
+	require.NoError(t, builder.Store().SetHead(ctx, fork))
+	requireHead(t, store, fork)
+	var reverted []*types.TipSet
+	for len(reverted) < 2 {
+		select {
+		case hcs := <-changes:
+			for _, hc := range hcs {
+				if hc.Type == types.HCRevert {
+					reverted = append(reverted, hc.Val)
+				}
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("no revert")
+		}
+	}
+	require.True(t, reverted[0].Equals(link3))
+	require.Equal(t, link3.Parents(), reverted[1].Key())
+
+	// the states of the tipsets are loaded from the leader once, then read from the node
+	root, err := store.GetTipSetStateRoot(ctx, fork)
+	require.NoError(t, err)
+	require.Equal(t, builder.StateForKey(ctx, fork.Key()), root)
+	require.Equal(t, 1, leader.loads())
+	meta, err := store.LoadTipsetMetadata(ctx, fork)
+	require.NoError(t, err)
+	require.Equal(t, root, meta.TipSetStateRoot)
+	require.Equal(t, 1, leader.loads())
+	f.Stop()
+
+	// the leader does not know the head of the follower any more, the follower moves to the head
+	// of the leader
+	leader.forget(fork.Key())
+	next := builder.AppendManyOn(ctx, 2, link1)
+	require.NoError(t, builder.Store().SetHead(ctx, next))
+	f.Start(ctx, store)
+	defer f.Stop()
+	requireHead(t, store, next)
+
+	last := builder.AppendOn(ctx, next, 1)
+	require.NoError(t, builder.Store().SetHead(ctx, last))
+	requireHead(t, store, last)
+}
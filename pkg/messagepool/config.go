@@ -34,6 +34,11 @@ type MpoolConfig struct {
 	ReplaceByFeeRatio      types.Percent
 	PruneCooldown          time.Duration
 	GasLimitOverestimation float64
+	// SelectionStrategy is the name of the strategy selecting the messages of the blocks
+	SelectionStrategy string
+	// MaxSenderGasLimit is the total gas limit of the messages of a sender selected in a block
+	// by the sender-gas-cap strategy
+	MaxSenderGasLimit int64
 }
 
 func (mc *MpoolConfig) Clone() *MpoolConfig {
@@ -83,6 +88,12 @@ func validateConfg(cfg *MpoolConfig) error {
 	if cfg.GasLimitOverestimation < 1 {
 		return fmt.Errorf("'GasLimitOverestimation' cannot be less than 1")
 	}
+	if _, err := getSelectionStrategy(cfg.SelectionStrategy); err != nil {
+		return err
+	}
+	if cfg.MaxSenderGasLimit < 0 {
+		return fmt.Errorf("'MaxSenderGasLimit' cannot be negative")
+	}
 	return nil
 }
 
@@ -110,5 +121,6 @@ func DefaultConfig() *MpoolConfig {
 		ReplaceByFeeRatio:      ReplaceByFeePercentageDefault,
 		PruneCooldown:          PruneCooldownDefault,
 		GasLimitOverestimation: GasLimitOverestimation,
+		SelectionStrategy:      DefaultSelectionStrategy,
 	}
 }
@@ -0,0 +1,341 @@
+package messagepool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/ipfs/go-datastore/query"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/pkg/repo"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+const (
+	// MaxFeeHistoryBlocks is the maximum number of tipsets of a fee history.
+	MaxFeeHistoryBlocks = 1024
+	// MaxFeeHistoryPercentiles is the maximum number of percentiles of a fee history.
+	MaxFeeHistoryPercentiles = 100
+)
+
+var feeHistoryDsPrefix = datastore.NewKey("/mpool/fees")
+
+// feeOraclePruneInterval is the number of recorded tipsets between the prunes of the datastore.
+const feeOraclePruneInterval = 120
+
+// FeeReward is the effective premium paid by a message for the gas it used.
+type FeeReward struct {
+	Premium abi.TokenAmount
+	GasUsed int64
+}
+
+// FeeRecord holds the fees paid by the messages executed by a tipset.
+type FeeRecord struct {
+	TipSet types.TipSetKey
+	Epoch  abi.ChainEpoch
+	Blocks int
+	// BaseFee is the base fee burnt by the messages of the tipset
+	BaseFee abi.TokenAmount
+	// Rewards are the rewards of the messages, by ascending premium
+	Rewards []FeeReward
+}
+
+// GasUsed returns the gas used by the messages of the tipset.
+func (r *FeeRecord) GasUsed() int64 {
+	var gasUsed int64
+	for _, rw := range r.Rewards {
+		gasUsed += rw.GasUsed
+	}
+	return gasUsed
+}
+
+// GasUsedRatio returns the gas used over the gas limit of the blocks of the tipset.
+func (r *FeeRecord) GasUsedRatio() float64 {
+	return float64(r.GasUsed()) / float64(constants.BlockGasLimit*int64(r.Blocks))
+}
+
+// Premiums returns the premiums paid at the percentiles of the gas used, which are ascending
+// between 0 and 100. The premium is MinGasPremium when the tipset has no message.
+func (r *FeeRecord) Premiums(percentiles []float64) []abi.TokenAmount {
+	return FeePercentiles(percentiles, r.Rewards)
+}
+
+// FeePercentiles returns the premiums of rewards, sorted by ascending premium, paid at the
+// percentiles of their gas used.
+func FeePercentiles(percentiles []float64, rewards []FeeReward) []abi.TokenAmount {
+	premiums := make([]abi.TokenAmount, len(percentiles))
+	for i := range premiums {
+		premiums[i] = big.NewInt(MinGasPremium)
+	}
+
+	if len(rewards) == 0 {
+		return premiums
+	}
+
+	var gasUsedTotal int64
+	for _, rw := range rewards {
+		gasUsedTotal += rw.GasUsed
+	}
+
+	var idx int
+	var sum int64
+	for i, percentile := range percentiles {
+		threshold := int64(float64(gasUsedTotal) * percentile / 100)
+		for sum < threshold && idx < len(rewards)-1 {
+			sum += rewards[idx].GasUsed
+			idx++
+		}
+		premiums[i] = rewards[idx].Premium
+	}
+
+	return premiums
+}
+
+// CheckFeePercentiles checks that the percentiles are ascending between 0 and 100.
+func CheckFeePercentiles(percentiles []float64) error {
+	if len(percentiles) > MaxFeeHistoryPercentiles {
+		return fmt.Errorf("length of the percentile array cannot be greater than %d", MaxFeeHistoryPercentiles)
+	}
+	for i, p := range percentiles {
+		if p < 0 || p > 100 {
+			return fmt.Errorf("invalid percentile: %f should be between 0 and 100", p)
+		}
+		if i > 0 && p < percentiles[i-1] {
+			return fmt.Errorf("invalid percentile: %f should be larger than %f", p, percentiles[i-1])
+		}
+	}
+	return nil
+}
+
+// FeeOracle keeps the fees paid by the messages of the last tipsets of the chain, persisting
+// the ones of the rolling window in the datastore so that they survive restarts.
+type FeeOracle struct {
+	api    Provider
+	ds     datastore.Datastore
+	window abi.ChainEpoch
+
+	cache *lru.Cache[abi.ChainEpoch, *FeeRecord]
+
+	lk       sync.Mutex
+	recorded int
+}
+
+// NewFeeOracle creates a fee oracle keeping the fees of window epochs in ds, a window of zero only
+// caches the fees in memory.
+func NewFeeOracle(api Provider, ds repo.Datastore, window int) *FeeOracle {
+	cache, _ := lru.New[abi.ChainEpoch, *FeeRecord](MaxFeeHistoryBlocks)
+
+	return &FeeOracle{
+		api:    api,
+		ds:     namespace.Wrap(ds, feeHistoryDsPrefix),
+		window: abi.ChainEpoch(window),
+		cache:  cache,
+	}
+}
+
+func feeRecordKey(epoch abi.ChainEpoch) datastore.Key {
+	return datastore.NewKey(strconv.FormatInt(int64(epoch), 10))
+}
+
+// Record returns the fees paid by the messages executed by ts.
+func (o *FeeOracle) Record(ctx context.Context, ts *types.TipSet) (*FeeRecord, error) {
+	if r, ok := o.cache.Get(ts.Height()); ok && r.TipSet.Equals(ts.Key()) {
+		return r, nil
+	}
+
+	r, err := o.load(ctx, ts)
+	if err != nil {
+		return nil, err
+	}
+	if r != nil {
+		o.cache.Add(ts.Height(), r)
+		return r, nil
+	}
+
+	msgs, err := o.api.MessagesForTipset(ctx, ts)
+	if err != nil {
+		return nil, fmt.Errorf("loading messages of %d: %w", ts.Height(), err)
+	}
+	rcpts, err := o.api.MessageReceipts(ctx, ts)
+	if err != nil {
+		return nil, fmt.Errorf("loading receipts of %d: %w", ts.Height(), err)
+	}
+	if len(msgs) != len(rcpts) {
+		return nil, fmt.Errorf("%d messages but %d receipts at %d", len(msgs), len(rcpts), ts.Height())
+	}
+
+	r = &FeeRecord{
+		TipSet:  ts.Key(),
+		Epoch:   ts.Height(),
+		Blocks:  len(ts.Blocks()),
+		BaseFee: ts.Blocks()[0].ParentBaseFee,
+		Rewards: make([]FeeReward, 0, len(msgs)),
+	}
+	for i, m := range msgs {
+		r.Rewards = append(r.Rewards, FeeReward{
+			Premium: m.VMMessage().EffectiveGasPremium(r.BaseFee),
+			GasUsed: rcpts[i].GasUsed,
+		})
+	}
+	sort.SliceStable(r.Rewards, func(i, j int) bool {
+		return r.Rewards[i].Premium.LessThan(r.Rewards[j].Premium)
+	})
+
+	o.cache.Add(ts.Height(), r)
+	if err := o.store(ctx, r); err != nil {
+		log.Warnf("failed to store the fees of %d: %s", ts.Height(), err)
+	}
+
+	return r, nil
+}
+
+// load returns the record of ts in the datastore, or nil when there is none.
+func (o *FeeOracle) load(ctx context.Context, ts *types.TipSet) (*FeeRecord, error) {
+	if o.window <= 0 {
+		return nil, nil
+	}
+
+	b, err := o.ds.Get(ctx, feeRecordKey(ts.Height()))
+	if errors.Is(err, datastore.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading the fees of %d: %w", ts.Height(), err)
+	}
+
+	r := new(FeeRecord)
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("decoding the fees of %d: %w", ts.Height(), err)
+	}
+	// the tipset at this height changed since it was recorded
+	if !r.TipSet.Equals(ts.Key()) {
+		return nil, nil
+	}
+	return r, nil
+}
+
+func (o *FeeOracle) store(ctx context.Context, r *FeeRecord) error {
+	if o.window <= 0 {
+		return nil
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return o.ds.Put(ctx, feeRecordKey(r.Epoch), b)
+}
+
+// HeadChange records the fees of the parents of the applied tipsets, whose messages they executed.
+func (o *FeeOracle) HeadChange(ctx context.Context, _, apply []*types.TipSet) {
+	if o.api.IsLite() {
+		return
+	}
+
+	for _, ts := range apply {
+		if ts.Height() == 0 {
+			continue
+		}
+		pts, err := o.api.LoadTipSet(ctx, ts.Parents())
+		if err != nil {
+			log.Warnf("failed to load the parent of %d to record its fees: %s", ts.Height(), err)
+			continue
+		}
+		if _, err := o.Record(ctx, pts); err != nil {
+			log.Warnf("failed to record the fees of %d: %s", pts.Height(), err)
+			continue
+		}
+
+		o.lk.Lock()
+		o.recorded++
+		prune := o.recorded%feeOraclePruneInterval == 1
+		o.lk.Unlock()
+		if prune {
+			if err := o.prune(ctx, pts.Height()-o.window); err != nil {
+				log.Warnf("failed to prune the fee history: %s", err)
+			}
+		}
+	}
+}
+
+// prune deletes the records of the epochs before before.
+func (o *FeeOracle) prune(ctx context.Context, before abi.ChainEpoch) error {
+	if o.window <= 0 {
+		return nil
+	}
+
+	res, err := o.ds.Query(ctx, query.Query{KeysOnly: true})
+	if err != nil {
+		return err
+	}
+	defer res.Close() // nolint:errcheck
+
+	for r := range res.Next() {
+		if r.Error != nil {
+			return r.Error
+		}
+		epoch, err := strconv.ParseInt(datastore.NewKey(r.Key).BaseNamespace(), 10, 64)
+		if err != nil || abi.ChainEpoch(epoch) >= before {
+			continue
+		}
+		if err := o.ds.Delete(ctx, datastore.NewKey(r.Key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FeeHistory returns the fees of the last blocks tipsets executed up to newest, with their
+// premiums at percentiles.
+func (o *FeeOracle) FeeHistory(ctx context.Context, newest *types.TipSet, blocks int, percentiles []float64) (*types.FeeHistory, error) {
+	if blocks > MaxFeeHistoryBlocks {
+		return nil, fmt.Errorf("block count should be smaller than %d", MaxFeeHistoryBlocks)
+	}
+	if err := CheckFeePercentiles(percentiles); err != nil {
+		return nil, err
+	}
+
+	epochs := make([]types.FeeHistoryEpoch, 0, blocks)
+	ts := newest
+	for len(epochs) < blocks && ts.Height() > 0 {
+		r, err := o.Record(ctx, ts)
+		if err != nil {
+			return nil, err
+		}
+		epochs = append(epochs, types.FeeHistoryEpoch{
+			Epoch:        r.Epoch,
+			BaseFee:      r.BaseFee,
+			Messages:     len(r.Rewards),
+			GasUsed:      r.GasUsed(),
+			GasUsedRatio: r.GasUsedRatio(),
+			Premiums:     r.Premiums(percentiles),
+		})
+
+		parents := ts.Parents()
+		ts, err = o.api.LoadTipSet(ctx, parents)
+		if err != nil {
+			return nil, fmt.Errorf("loading tipset %s: %w", parents, err)
+		}
+	}
+
+	// collected newest first
+	for i, j := 0, len(epochs)-1; i < j; i, j = i+1, j-1 {
+		epochs[i], epochs[j] = epochs[j], epochs[i]
+	}
+
+	return &types.FeeHistory{
+		Percentiles: percentiles,
+		Epochs:      epochs,
+	}, nil
+}
@@ -0,0 +1,117 @@
+package messagepool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs/go-datastore"
+	"github.com/stretchr/testify/assert"
+
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+func TestFeePercentiles(t *testing.T) {
+	tf.UnitTest(t)
+
+	testcases := []struct {
+		percentiles []float64
+		rewards     []FeeReward
+		answer      []int64
+	}{
+		{
+			percentiles: []float64{25, 50, 75},
+			rewards:     []FeeReward{},
+			answer:      []int64{MinGasPremium, MinGasPremium, MinGasPremium},
+		},
+		{
+			percentiles: []float64{25, 50, 75, 100},
+			rewards: []FeeReward{
+				{GasUsed: int64(350), Premium: big.NewInt(100)},
+				{GasUsed: int64(100), Premium: big.NewInt(200)},
+				{GasUsed: int64(0), Premium: big.NewInt(300)},
+				{GasUsed: int64(500), Premium: big.NewInt(600)},
+				{GasUsed: int64(300), Premium: big.NewInt(700)},
+			},
+			answer: []int64{200, 700, 700, 700},
+		},
+	}
+	for _, tc := range testcases {
+		var ans []abi.TokenAmount
+		for _, p := range tc.answer {
+			ans = append(ans, big.NewInt(p))
+		}
+		assert.Equal(t, ans, FeePercentiles(tc.percentiles, tc.rewards))
+	}
+
+	assert.NoError(t, CheckFeePercentiles([]float64{0, 50, 50, 100}))
+	assert.Error(t, CheckFeePercentiles([]float64{50, 25}))
+	assert.Error(t, CheckFeePercentiles([]float64{101}))
+}
+
+func TestFeeOracle(t *testing.T) {
+	tf.UnitTest(t)
+
+	ctx := context.Background()
+	tma := newTestMpoolAPI()
+	ds := datastore.NewMapDatastore()
+	oracle := NewFeeOracle(tma, ds, 10)
+
+	w := newWallet(t)
+	a1, err := w.NewAddress(ctx, address.SECP256K1)
+	assert.NoError(t, err)
+	a2, err := w.NewAddress(ctx, address.SECP256K1)
+	assert.NoError(t, err)
+
+	// the effective premium of the test messages is their gas price
+	blk1 := tma.nextBlock()
+	tma.setBlockMessages(blk1,
+		makeTestMessage(w, a1, a2, 0, 100, 300),
+		makeTestMessage(w, a1, a2, 1, 300, 100),
+		makeTestMessage(w, a1, a2, 2, 100, 200),
+	)
+	blk2 := tma.nextBlock()
+	ts1, ts2 := mkTipSet(blk1), mkTipSet(blk2)
+
+	// the fees of a tipset are recorded once its child is applied
+	oracle.HeadChange(ctx, nil, []*types.TipSet{ts2})
+	has, err := ds.Has(ctx, feeHistoryDsPrefix.Child(feeRecordKey(blk1.Height)))
+	assert.NoError(t, err)
+	assert.True(t, has)
+
+	history, err := oracle.FeeHistory(ctx, ts2, 5, []float64{10, 50, 90})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{10, 50, 90}, history.Percentiles)
+	assert.Len(t, history.Epochs, 2)
+
+	e1 := history.Epochs[0]
+	assert.Equal(t, blk1.Height, e1.Epoch)
+	assert.Equal(t, blk1.ParentBaseFee, e1.BaseFee)
+	assert.Equal(t, 3, e1.Messages)
+	assert.Equal(t, int64(500), e1.GasUsed)
+	assert.Equal(t, []abi.TokenAmount{big.NewInt(200), big.NewInt(200), big.NewInt(300)}, e1.Premiums)
+
+	// a tipset without messages pays the minimum premium
+	e2 := history.Epochs[1]
+	assert.Equal(t, blk2.Height, e2.Epoch)
+	assert.Equal(t, 0, e2.Messages)
+	assert.Equal(t, []abi.TokenAmount{big.NewInt(MinGasPremium), big.NewInt(MinGasPremium), big.NewInt(MinGasPremium)}, e2.Premiums)
+
+	// the records survive restarts, and are pruned out of the window
+	reloaded := NewFeeOracle(tma, ds, 10)
+	r, err := reloaded.load(ctx, ts1)
+	assert.NoError(t, err)
+	assert.NotNil(t, r)
+	assert.Equal(t, ts1.Key(), r.TipSet)
+
+	assert.NoError(t, reloaded.prune(ctx, blk1.Height+1))
+	r, err = reloaded.load(ctx, ts1)
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+
+	_, err = oracle.FeeHistory(ctx, ts2, MaxFeeHistoryBlocks+1, nil)
+	assert.Error(t, err)
+}
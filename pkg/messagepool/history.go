@@ -0,0 +1,85 @@
+package messagepool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/venus/pkg/messagepool/journal"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// History returns the journaled events of the messages sent by addr, oldest first, or only of
+// its message with nonce when not nil. The journal must be queryable. The events are indexed by
+// the address the messages were sent from, so the id and key addresses of addr are looked up.
+func (mp *MessagePool) History(ctx context.Context, addr address.Address, nonce *uint64) ([]*types.MpoolJournalEntry, error) {
+	j, ok := mp.journal.(journal.Queryable)
+	if !ok {
+		return nil, fmt.Errorf("the message pool journal can't be queried, set mpool.journalType to sqlite")
+	}
+
+	senders := mp.journalSenders(ctx, addr)
+	keys := make([]string, 0, len(senders))
+	for sender := range senders {
+		if nonce != nil {
+			keys = append(keys, journalNonceKey(sender, *nonce))
+		} else {
+			keys = append(keys, journalAddrKey(sender))
+		}
+	}
+	evts, err := j.Query(ctx, keys, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*types.MpoolJournalEntry
+	for _, evt := range evts {
+		raw, ok := evt.Data.(json.RawMessage)
+		if !ok {
+			continue
+		}
+		// Error is left out, the interface it is stored as can't be decoded
+		var data struct {
+			Messages []MessagePoolEvtMessage
+			Reason   string
+		}
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, fmt.Errorf("decode %s journal event: %w", evt.EventType, err)
+		}
+
+		for _, m := range data.Messages {
+			if _, ok := senders[m.From]; !ok || (nonce != nil && m.Nonce != *nonce) {
+				continue
+			}
+			out = append(out, &types.MpoolJournalEntry{
+				Timestamp: evt.Timestamp,
+				Event:     evt.Event,
+				Reason:    data.Reason,
+				Cid:       m.CID,
+				Message:   m.Message,
+			})
+		}
+	}
+
+	return out, nil
+}
+
+// journalSenders returns the addresses the events of the messages sent by addr can be indexed
+// by: its id address and its key address, when known.
+func (mp *MessagePool) journalSenders(ctx context.Context, addr address.Address) map[address.Address]struct{} {
+	mp.curTSLk.RLock()
+	defer mp.curTSLk.RUnlock()
+
+	senders := map[address.Address]struct{}{addr: {}}
+	if mp.curTS != nil {
+		if id, err := mp.api.StateLookupID(ctx, addr, mp.curTS); err == nil {
+			senders[id] = struct{}{}
+		}
+	}
+	if ka, err := mp.resolveToKey(ctx, addr); err == nil {
+		senders[ka] = struct{}{}
+	}
+	return senders
+}
@@ -0,0 +1,118 @@
+package messagepool
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	builtin2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	"github.com/ipfs/go-datastore"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus/pkg/config"
+	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/pkg/messagepool/gasguess"
+	"github.com/filecoin-project/venus/pkg/messagepool/journal"
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+)
+
+// memJournal is a queryable journal keeping the events in memory.
+type memJournal struct {
+	journal.EventTypeRegistry
+
+	evts []*journal.Event
+	keys [][]string
+}
+
+func (j *memJournal) RecordEvent(evtType journal.EventType, supplier func() interface{}) {
+	if !evtType.Enabled() {
+		return
+	}
+	data := supplier()
+	raw, err := json.Marshal(data)
+	if err != nil {
+		panic(err)
+	}
+	var keys []string
+	if keyed, ok := data.(journal.Keyed); ok {
+		keys = keyed.JournalKeys()
+	}
+	j.evts = append(j.evts, &journal.Event{EventType: evtType, Timestamp: constants.Clock.Now(), Data: json.RawMessage(raw)})
+	j.keys = append(j.keys, keys)
+}
+
+func (j *memJournal) Query(_ context.Context, keys []string, _ int) ([]*journal.Event, error) {
+	var out []*journal.Event
+	for i, evt := range j.evts {
+	match:
+		for _, k := range j.keys[i] {
+			for _, key := range keys {
+				if k == key {
+					out = append(out, evt)
+					break match
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+func (j *memJournal) Close() error {
+	return nil
+}
+
+func TestHistory(t *testing.T) {
+	tf.UnitTest(t)
+
+	ctx := context.Background()
+	tma := newTestMpoolAPI()
+	j := &memJournal{EventTypeRegistry: journal.NewEventTypeRegistry(nil)}
+	mp, err := New(ctx, tma, nil, datastore.NewMapDatastore(), config.NewDefaultConfig().NetworkParams, config.DefaultMessagePoolParam, "test", j)
+	require.NoError(t, err)
+	defer mp.Close() // nolint
+
+	w1 := newWallet(t)
+	a1, err := w1.NewAddress(ctx, address.SECP256K1)
+	require.NoError(t, err)
+	w2 := newWallet(t)
+	a2, err := w2.NewAddress(ctx, address.SECP256K1)
+	require.NoError(t, err)
+	tma.setBalance(a1, 1)
+	gasLimit := gasguess.Costs[gasguess.CostKey{Code: builtin2.StorageMarketActorCodeID, M: 2}]
+
+	id, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+	tma.ids[a1] = id
+	m0 := makeTestMessage(w1, a1, a2, 0, gasLimit, 1)
+	c0, err := mp.Push(ctx, m0)
+	require.NoError(t, err)
+	m1 := makeTestMessage(w1, a1, a2, 1, gasLimit, 1)
+	c1, err := mp.Push(ctx, m1)
+	require.NoError(t, err)
+
+	// the events are indexed by the address the messages were sent from
+	require.Contains(t, j.keys[0], journalAddrKey(a1))
+
+	history, err := mp.History(ctx, a1, nil)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, c0, history[0].Cid)
+	require.Equal(t, c1, history[1].Cid)
+
+	// the id address of the sender is resolved when querying
+	history, err = mp.History(ctx, id, nil)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	nonce := uint64(0)
+	history, err = mp.History(ctx, a1, &nonce)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, c0, history[0].Cid)
+
+	// a journal which can't be queried
+	mp.journal = journal.NilJournal()
+	_, err = mp.History(ctx, a1, nil)
+	require.Error(t, err)
+}
@@ -9,11 +9,17 @@ import (
 const envDisabledEvents = "VENUS_JOURNAL_DISABLED_EVENTS"
 
 func EnvDisabledEvents() DisabledEvents {
+	return EnvDisabledEventsOr(DefaultDisabledEvents)
+}
+
+// EnvDisabledEventsOr returns the events disabled by the environment, or fallback when it
+// doesn't set them.
+func EnvDisabledEventsOr(fallback DisabledEvents) DisabledEvents {
 	if env, ok := os.LookupEnv(envDisabledEvents); ok {
 		if ret, err := ParseDisabledEvents(env); err == nil {
 			return ret
 		}
 	}
 	// fallback if env variable is not set, or if it failed to parse.
-	return DefaultDisabledEvents
+	return fallback
 }
@@ -0,0 +1,304 @@
+package journal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/pkg/repo"
+)
+
+// Keyed is implemented by the payloads of the events that can be looked up by key in a
+// Queryable journal.
+type Keyed interface {
+	JournalKeys() []string
+}
+
+// Queryable is a journal whose events can be looked up by the keys of their payload.
+type Queryable interface {
+	Journal
+
+	// Query returns the last limit events recorded with any of keys, oldest first, their
+	// Data is the json.RawMessage of the payload. A limit of zero returns every event.
+	Query(ctx context.Context, keys []string, limit int) ([]*Event, error)
+}
+
+var sqlitePragmas = []string{
+	"PRAGMA synchronous = normal",
+	"PRAGMA temp_store = memory",
+	"PRAGMA journal_mode = WAL",
+	"PRAGMA foreign_keys = ON",
+}
+
+var sqliteDDLs = []string{
+	`CREATE TABLE IF NOT EXISTS journal_event (
+		id INTEGER PRIMARY KEY,
+		system TEXT NOT NULL,
+		event TEXT NOT NULL,
+		timestamp INTEGER NOT NULL,
+		data BLOB NOT NULL
+	)`,
+
+	`CREATE INDEX IF NOT EXISTS journal_event_timestamp ON journal_event (timestamp)`,
+
+	`CREATE TABLE IF NOT EXISTS journal_key (
+		key TEXT NOT NULL,
+		event_id INTEGER NOT NULL REFERENCES journal_event(id) ON DELETE CASCADE,
+		UNIQUE (key, event_id)
+	)`,
+
+	`CREATE INDEX IF NOT EXISTS journal_key_event_id ON journal_key (event_id)`,
+}
+
+const (
+	insertJournalEvent = `INSERT INTO journal_event(system, event, timestamp, data) VALUES(?, ?, ?, ?)`
+	insertJournalKey   = `INSERT OR IGNORE INTO journal_key(key, event_id) VALUES(?, ?)`
+	deleteJournalEvent = `DELETE FROM journal_event WHERE timestamp < ?`
+	// selectJournalEvent is formatted with the placeholders of the keys
+	selectJournalEvent = `SELECT system, event, timestamp, data FROM (
+		SELECT DISTINCT e.id, e.system, e.event, e.timestamp, e.data FROM journal_key k JOIN journal_event e ON e.id = k.event_id
+		WHERE k.key IN (%s) ORDER BY e.id DESC LIMIT ?
+	) ORDER BY id ASC`
+)
+
+// sqliteJournal is a journal backed by a sqlite database, whose events are indexed by the
+// keys of their payload and are deleted once older than the retention.
+type sqliteJournal struct {
+	EventTypeRegistry
+
+	db        *sql.DB
+	retention time.Duration
+
+	// incoming blocks the callers while it is full, so that no event is lost, the loop writes
+	// the events queued in it in one transaction to keep up with bursts
+	incoming chan *Event
+
+	closing chan struct{}
+	closed  chan struct{}
+}
+
+var _ Queryable = (*sqliteJournal)(nil)
+
+// OpenSqliteJournal opens the sqlite journal stored in the repo, keeping the events for the
+// retention, or forever when it is zero.
+func OpenSqliteJournal(lr repo.Repo, disabled DisabledEvents, retention time.Duration) (Queryable, error) {
+	path, err := lr.Path()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(path, "journal")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to mk directory %s for sqlite journal: %w", dir, err)
+	}
+
+	return openSqliteJournal(filepath.Join(dir, "journal.sqlite"), disabled, retention)
+}
+
+func openSqliteJournal(path string, disabled DisabledEvents, retention time.Duration) (*sqliteJournal, error) {
+	db, err := sql.Open("sqlite3", path+"?mode=rwc")
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite3 database: %w", err)
+	}
+	// keep a single connection, so that the pragmas apply to every statement
+	db.SetMaxOpenConns(1)
+
+	for _, stmt := range append(sqlitePragmas, sqliteDDLs...) {
+		if _, err := db.Exec(stmt); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("exec %q: %w", stmt, err)
+		}
+	}
+
+	j := &sqliteJournal{
+		EventTypeRegistry: NewEventTypeRegistry(disabled),
+		db:                db,
+		retention:         retention,
+		incoming:          make(chan *Event, 256),
+		closing:           make(chan struct{}),
+		closed:            make(chan struct{}),
+	}
+	j.prune()
+
+	go j.runLoop()
+
+	return j, nil
+}
+
+func (j *sqliteJournal) RecordEvent(evtType EventType, supplier func() interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Warnf("recovered from panic while recording journal event; type=%s, err=%v", evtType, r)
+		}
+	}()
+
+	if !evtType.Enabled() {
+		return
+	}
+
+	je := &Event{
+		EventType: evtType,
+		Timestamp: constants.Clock.Now(),
+		Data:      supplier(),
+	}
+	select {
+	case j.incoming <- je:
+	case <-j.closing:
+		log.Warnw("journal closed but tried to log event", "event", je)
+	}
+}
+
+func (j *sqliteJournal) Query(ctx context.Context, keys []string, limit int) ([]*Event, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = -1
+	}
+
+	args := make([]interface{}, 0, len(keys)+1)
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	args = append(args, limit)
+	query := fmt.Sprintf(selectJournalEvent, strings.TrimSuffix(strings.Repeat("?, ", len(keys)), ", "))
+	rows, err := j.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query journal events of %v: %w", keys, err)
+	}
+	defer rows.Close() // nolint:errcheck
+
+	var evts []*Event
+	for rows.Next() {
+		var (
+			evt  Event
+			ts   int64
+			data []byte
+		)
+		if err := rows.Scan(&evt.System, &evt.Event, &ts, &data); err != nil {
+			return nil, fmt.Errorf("read journal event: %w", err)
+		}
+		evt.Timestamp = time.Unix(0, ts)
+		evt.Data = json.RawMessage(data)
+		evts = append(evts, &evt)
+	}
+
+	return evts, rows.Err()
+}
+
+func (j *sqliteJournal) Close() error {
+	close(j.closing)
+	<-j.closed
+	return nil
+}
+
+// putEvents writes the events in a single transaction.
+func (j *sqliteJournal) putEvents(evts []*Event) error {
+	tx, err := j.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, evt := range evts {
+		if err := putEvent(tx, evt); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func putEvent(tx *sql.Tx, evt *Event) error {
+	data, err := json.Marshal(evt.Data)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(insertJournalEvent, evt.System, evt.Event, evt.Timestamp.UnixNano(), data)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	if keyed, ok := evt.Data.(Keyed); ok {
+		for _, key := range keyed.JournalKeys() {
+			if _, err := tx.Exec(insertJournalKey, key, id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// prune deletes the events older than the retention.
+func (j *sqliteJournal) prune() {
+	if j.retention <= 0 {
+		return
+	}
+
+	before := constants.Clock.Now().Add(-j.retention)
+	res, err := j.db.Exec(deleteJournalEvent, before.UnixNano())
+	if err != nil {
+		log.Errorf("failed to prune journal events: %s", err)
+		return
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		log.Infof("pruned %d journal events older than %s", n, before)
+	}
+}
+
+func (j *sqliteJournal) runLoop() {
+	defer close(j.closed)
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	batch := make([]*Event, 0, cap(j.incoming))
+	// writeBatch writes je and the events queued after it
+	writeBatch := func(je *Event) {
+		batch = append(batch[:0], je)
+	queued:
+		for len(batch) < cap(batch) {
+			select {
+			case je := <-j.incoming:
+				batch = append(batch, je)
+			default:
+				break queued
+			}
+		}
+		if err := j.putEvents(batch); err != nil {
+			log.Errorw("failed to write out journal events", "count", len(batch), "err", err)
+		}
+	}
+
+	for {
+		select {
+		case je := <-j.incoming:
+			writeBatch(je)
+		case <-ticker.C:
+			j.prune()
+		case <-j.closing:
+			// write out the events recorded before closing
+			for {
+				select {
+				case je := <-j.incoming:
+					writeBatch(je)
+				default:
+					_ = j.db.Close()
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package journal
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+)
+
+type keyedEvt struct {
+	Name string
+	Keys []string
+}
+
+func (e keyedEvt) JournalKeys() []string { return e.Keys }
+
+func TestSqliteJournal(t *testing.T) {
+	tf.UnitTest(t)
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "journal.sqlite")
+
+	j, err := openSqliteJournal(path, DisabledEvents{{System: "test", Event: "disabled"}}, 0)
+	require.NoError(t, err)
+
+	evt := j.RegisterEventType("test", "evt")
+	disabled := j.RegisterEventType("test", "disabled")
+	j.RecordEvent(evt, func() interface{} { return keyedEvt{Name: "first", Keys: []string{"a", "a/1"}} })
+	j.RecordEvent(evt, func() interface{} { return keyedEvt{Name: "second", Keys: []string{"a", "a/2"}} })
+	j.RecordEvent(evt, func() interface{} { return keyedEvt{Name: "third", Keys: []string{"b", "b/1"}} })
+	j.RecordEvent(disabled, func() interface{} { return keyedEvt{Name: "disabled", Keys: []string{"a"}} })
+	// events whose payload has no keys are stored but can't be queried
+	j.RecordEvent(evt, func() interface{} { return "unkeyed" })
+	require.NoError(t, j.Close())
+
+	j, err = openSqliteJournal(path, nil, 0)
+	require.NoError(t, err)
+	defer j.Close() // nolint:errcheck
+
+	names := func(limit int, keys ...string) []string {
+		evts, err := j.Query(ctx, keys, limit)
+		require.NoError(t, err)
+
+		var out []string
+		for _, e := range evts {
+			require.Equal(t, "test", e.System)
+			require.Equal(t, "evt", e.Event)
+
+			var data keyedEvt
+			require.NoError(t, json.Unmarshal(e.Data.(json.RawMessage), &data))
+			out = append(out, data.Name)
+		}
+		return out
+	}
+
+	require.Equal(t, []string{"first", "second"}, names(0, "a"))
+	require.Equal(t, []string{"second"}, names(1, "a"))
+	require.Equal(t, []string{"second"}, names(0, "a/2"))
+	require.Equal(t, []string{"third"}, names(0, "b"))
+	require.Empty(t, names(0, "c"))
+	// the events of several keys are returned once, in the order they were recorded
+	require.Equal(t, []string{"first", "second", "third"}, names(0, "b", "a", "a/1"))
+	require.Equal(t, []string{"second", "third"}, names(2, "a", "b"))
+
+	// the events older than the retention are pruned
+	j.retention = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	j.prune()
+	require.Empty(t, names(0, "a"))
+}
+
+func TestSqliteJournalKeepsBursts(t *testing.T) {
+	tf.UnitTest(t)
+
+	ctx := context.Background()
+	j, err := openSqliteJournal(filepath.Join(t.TempDir(), "journal.sqlite"), nil, 0)
+	require.NoError(t, err)
+	defer j.Close() // nolint:errcheck
+
+	// many more events than the queue holds, e.g. the messages removed by a reorg
+	evt := j.RegisterEventType("test", "evt")
+	count := 4 * cap(j.incoming)
+	for i := 0; i < count; i++ {
+		j.RecordEvent(evt, func() interface{} { return keyedEvt{Name: "burst", Keys: []string{"a"}} })
+	}
+
+	require.Eventually(t, func() bool {
+		evts, err := j.Query(ctx, []string{"a"}, 0)
+		require.NoError(t, err)
+		return len(evts) == count
+	}, 10*time.Second, 10*time.Millisecond)
+}
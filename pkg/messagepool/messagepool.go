@@ -3,6 +3,7 @@ package messagepool
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -107,13 +108,34 @@ const (
 	evtTypeMpoolAdd = iota
 	evtTypeMpoolRemove
 	evtTypeMpoolRepub
+	evtTypeMpoolReplace
+	evtTypeMpoolPrune
 )
 
 // MessagePoolEvt is the journal entry for message pool events.
 type MessagePoolEvt struct { // nolint
 	Action   string
 	Messages []MessagePoolEvtMessage
-	Error    error `json:",omitempty"`
+	Reason   string `json:",omitempty"`
+	Error    error  `json:",omitempty"`
+}
+
+// JournalKeys indexes the event by the sender of its messages, and by their sender and nonce.
+// The sender is the address the messages were sent from, it is resolved when querying.
+func (evt MessagePoolEvt) JournalKeys() []string {
+	keys := make([]string, 0, 2*len(evt.Messages))
+	for _, m := range evt.Messages {
+		keys = append(keys, journalAddrKey(m.From), journalNonceKey(m.From, m.Nonce))
+	}
+	return keys
+}
+
+func journalAddrKey(addr address.Address) string {
+	return "mpool/" + addr.String()
+}
+
+func journalNonceKey(addr address.Address, nonce uint64) string {
+	return fmt.Sprintf("mpool/%s/%d", addr, nonce)
 }
 
 type MessagePoolEvtMessage struct { // nolint
@@ -122,6 +144,18 @@ type MessagePoolEvtMessage struct { // nolint
 	CID cid.Cid
 }
 
+// MarshalJSON keeps CID, the json encoding of the embedded message would replace it with the
+// cid of the message.
+func (m MessagePoolEvtMessage) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		*types.RawMessage
+		CID cid.Cid
+	}{
+		RawMessage: (*types.RawMessage)(&m.Message),
+		CID:        m.CID,
+	})
+}
+
 type MessagePool struct {
 	lk sync.RWMutex
 
@@ -143,6 +177,10 @@ type MessagePool struct {
 	// do NOT access this map directly, use getPendingMset, setPendingMset, deletePendingMset, forEachPending, and clearPending respectively
 	pending map[address.Address]*msgSet
 
+	// pendingCids indexes the messages of pending by cid, it is kept in sync by msgSet.add, remove,
+	// deletePendingMset and clearPending
+	pendingCids map[cid.Cid]struct{}
+
 	keyCache *lru.Cache[address.Address, address.Address]
 
 	curTSLk sync.RWMutex // DO NOT LOCK INSIDE lk
@@ -175,14 +213,18 @@ type MessagePool struct {
 
 	stateNonceCache *lru.Cache[stateNonceCacheKey, uint64]
 
-	evtTypes [3]journal.EventType
+	evtTypes [5]journal.EventType
 	journal  journal.Journal
 
+	// statuses remembers why the messages left the pool
+	statuses *lru.Cache[cid.Cid, types.MpoolMessageStatus]
+
 	forkParams       *config.ForkUpgradeConfig
 	gasPriceSchedule *gas.PricesSchedule
 
 	GetMaxFee  DefaultMaxFeeFunc
 	PriceCache *GasPriceCache
+	FeeOracle  *FeeOracle
 }
 
 type stateNonceCacheKey struct {
@@ -320,6 +362,10 @@ func (ms *msgSet) add(m *types.SignedMessage, mp *MessagePool, strict, untrusted
 
 	ms.nextNonce = nextNonce
 	ms.msgs[m.Message.Nonce] = m
+	if has {
+		delete(mp.pendingCids, exms.Cid())
+	}
+	mp.pendingCids[m.Cid()] = struct{}{}
 	ms.requiredFunds.Add(ms.requiredFunds, m.Message.RequiredFunds().Int)
 	// ms.requiredFunds.Add(ms.requiredFunds, m.Message.Value.Int)
 
@@ -400,6 +446,7 @@ func New(ctx context.Context,
 	verifcache, _ := lru.New2Q[string, struct{}](constants.VerifSigCacheSize)
 	keycache, _ := lru.New[address.Address, address.Address](1_000_000)
 	stateNonceCache, _ := lru.New[stateNonceCacheKey, uint64](32768) // 32k * ~200 bytes = 6MB
+	statuses, _ := lru.New[cid.Cid, types.MpoolMessageStatus](RemovedStatusCacheSize)
 
 	cfg, err := loadConfig(ctx, ds)
 	if err != nil {
@@ -420,6 +467,7 @@ func New(ctx context.Context,
 		repubTrigger:    make(chan struct{}, 1),
 		localAddrs:      make(map[address.Address]struct{}),
 		pending:         make(map[address.Address]*msgSet),
+		pendingCids:     make(map[cid.Cid]struct{}),
 		keyCache:        keycache,
 		minGasPrice:     big.NewInt(0),
 		pruneTrigger:    make(chan struct{}, 1),
@@ -427,6 +475,7 @@ func New(ctx context.Context,
 		blsSigCache:     cache,
 		sigValCache:     verifcache,
 		stateNonceCache: stateNonceCache,
+		statuses:        statuses,
 		changes:         lps.New(50),
 		localMsgs:       namespace.Wrap(ds, datastore.NewKey(localMsgsDs)),
 		api:             api,
@@ -434,15 +483,18 @@ func New(ctx context.Context,
 		netName:         netName,
 		cfg:             cfg,
 		evtTypes: [...]journal.EventType{
-			evtTypeMpoolAdd:    j.RegisterEventType("mpool", "add"),
-			evtTypeMpoolRemove: j.RegisterEventType("mpool", "remove"),
-			evtTypeMpoolRepub:  j.RegisterEventType("mpool", "repub"),
+			evtTypeMpoolAdd:     j.RegisterEventType("mpool", "add"),
+			evtTypeMpoolRemove:  j.RegisterEventType("mpool", "remove"),
+			evtTypeMpoolRepub:   j.RegisterEventType("mpool", "repub"),
+			evtTypeMpoolReplace: j.RegisterEventType("mpool", "replace"),
+			evtTypeMpoolPrune:   j.RegisterEventType("mpool", "prune"),
 		},
 		journal:          j,
 		forkParams:       networkParams.ForkUpgradeParam,
 		gasPriceSchedule: gas.NewPricesSchedule(networkParams.ForkUpgradeParam),
 		GetMaxFee:        newDefaultMaxFeeFunc(mpoolCfg.MaxFee),
 		PriceCache:       NewGasPriceCache(),
+		FeeOracle:        NewFeeOracle(api, ds, mpoolCfg.FeeHistoryWindow),
 	}
 
 	// enable initial prunes
@@ -456,6 +508,7 @@ func New(ctx context.Context,
 		if err != nil {
 			log.Errorf("mpool head notif handler error: %+v", err)
 		}
+		mp.FeeOracle.HeadChange(ctx, rev, app)
 		return err
 	})
 
@@ -543,6 +596,11 @@ func (mp *MessagePool) deletePendingMset(ctx context.Context, addr address.Addre
 		return err
 	}
 
+	if ms, ok := mp.pending[ra]; ok {
+		for _, m := range ms.msgs {
+			delete(mp.pendingCids, m.Cid())
+		}
+	}
 	delete(mp.pending, ra)
 
 	return nil
@@ -551,6 +609,7 @@ func (mp *MessagePool) deletePendingMset(ctx context.Context, addr address.Addre
 // This method isn't strictly necessary, since it doesn't resolve any addresses, but it's safer to have
 func (mp *MessagePool) clearPending() {
 	mp.pending = make(map[address.Address]*msgSet)
+	mp.pendingCids = make(map[cid.Cid]struct{})
 }
 
 func (mp *MessagePool) isLocal(ctx context.Context, addr address.Address) (bool, error) {
@@ -587,6 +646,12 @@ func (mp *MessagePool) DeleteByAdress(address address.Address) error {
 	defer mp.lk.Unlock()
 
 	if mp.pending != nil {
+		if mset, ok := mp.pending[address]; ok {
+			mp.removed(removal{evtType: evtTypeMpoolRemove, reason: types.MpoolRemoveDeleted}, mset.toSlice()...)
+			for _, m := range mset.msgs {
+				delete(mp.pendingCids, m.Cid())
+			}
+		}
 		delete(mp.pending, address)
 	}
 	return nil
@@ -938,7 +1003,13 @@ func (mp *MessagePool) checkBalance(ctx context.Context, m *types.SignedMessage,
 	return nil
 }
 
-func (mp *MessagePool) addTS(ctx context.Context, m *types.SignedMessage, curTS *types.TipSet, local, untrusted bool) (bool, error) {
+func (mp *MessagePool) addTS(ctx context.Context, m *types.SignedMessage, curTS *types.TipSet, local, untrusted bool) (_ bool, err error) {
+	defer func() {
+		if err != nil {
+			mp.rejected(m, err)
+		}
+	}()
+
 	snonce, err := mp.getStateNonce(ctx, m.Message.From, curTS)
 	if err != nil {
 		return false, fmt.Errorf("failed to look up actor state nonce: %s: %w", err, ErrSoftValidationFailure)
@@ -1064,12 +1135,22 @@ func (mp *MessagePool) addLocked(ctx context.Context, m *types.SignedMessage, st
 		}
 	}
 
+	replaced, hasReplaced := mset.msgs[m.Message.Nonce]
 	incr, err := mset.add(m, mp, strict, untrusted)
 	if err != nil {
 		log.Debug(err)
 		return err
 	}
 
+	if hasReplaced {
+		mp.removed(removal{
+			evtType: evtTypeMpoolReplace,
+			reason:  types.MpoolRemoveReplaced,
+			detail:  fmt.Sprintf("replaced by %s", m.Cid()),
+		}, replaced)
+	}
+	mp.statuses.Remove(m.Cid())
+
 	if incr {
 		mp.currentSize++
 		if mp.currentSize > mp.cfg.SizeLimitHigh {
@@ -1090,7 +1171,7 @@ func (mp *MessagePool) addLocked(ctx context.Context, m *types.SignedMessage, st
 		mc := m.Cid()
 		return MessagePoolEvt{
 			Action:   "add",
-			Messages: []MessagePoolEvtMessage{{Message: m.Message, CID: mc}},
+			Messages: []MessagePoolEvtMessage{MessagePoolEvtMessage{Message: m.Message, CID: mc}},
 		}
 	})
 
@@ -1242,10 +1323,15 @@ func (mp *MessagePool) Remove(ctx context.Context, from address.Address, nonce u
 	mp.lk.Lock()
 	defer mp.lk.Unlock()
 
-	mp.remove(ctx, from, nonce, applied)
+	r := removal{evtType: evtTypeMpoolRemove, reason: types.MpoolRemoveManual}
+	if applied {
+		r.reason = types.MpoolRemoveIncluded
+	}
+	mp.remove(ctx, from, nonce, applied, r)
 }
 
-func (mp *MessagePool) remove(ctx context.Context, from address.Address, nonce uint64, applied bool) {
+// remove deletes the message of from with nonce, recording the removal r.
+func (mp *MessagePool) remove(ctx context.Context, from address.Address, nonce uint64, applied bool, r removal) {
 	mset, ok, err := mp.getPendingMset(ctx, from)
 	if err != nil {
 		log.Debugf("mpoolremove failed to get mset: %s", err)
@@ -1262,12 +1348,8 @@ func (mp *MessagePool) remove(ctx context.Context, from address.Address, nonce u
 			Message: m,
 		}, localUpdates)
 
-		mp.journal.RecordEvent(mp.evtTypes[evtTypeMpoolRemove], func() interface{} {
-			return MessagePoolEvt{
-				Action:   "remove",
-				Messages: []MessagePoolEvtMessage{{Message: m.Message, CID: m.Cid()}},
-			}
-		})
+		mp.removed(r, m)
+		delete(mp.pendingCids, m.Cid())
 
 		mp.currentSize--
 	}
@@ -1340,19 +1422,23 @@ func (mp *MessagePool) HeadChange(ctx context.Context, revert []*types.TipSet, a
 		}
 		s[m.Message.Nonce] = m
 	}
-	rm := func(from address.Address, nonce uint64) {
+	rm := func(from address.Address, nonce uint64, included cid.Cid, epoch abi.ChainEpoch) {
 		s, ok := rmsgs[from]
-		if !ok {
-			mp.Remove(ctx, from, nonce, true)
-			return
-		}
-
-		if _, ok := s[nonce]; ok {
-			delete(s, nonce)
-			return
+		if ok {
+			if _, ok := s[nonce]; ok {
+				delete(s, nonce)
+				return
+			}
 		}
 
-		mp.Remove(ctx, from, nonce, true)
+		mp.lk.Lock()
+		mp.remove(ctx, from, nonce, true, removal{
+			evtType:  evtTypeMpoolRemove,
+			reason:   types.MpoolRemoveIncluded,
+			epoch:    epoch,
+			included: included,
+		})
+		mp.lk.Unlock()
 	}
 
 	maybeRepub := func(cid cid.Cid) {
@@ -1404,12 +1490,12 @@ func (mp *MessagePool) HeadChange(ctx context.Context, revert []*types.TipSet, a
 			}
 
 			for _, msg := range smsgs {
-				rm(msg.Message.From, msg.Message.Nonce)
+				rm(msg.Message.From, msg.Message.Nonce, msg.Cid(), ts.Height())
 				maybeRepub(msg.Cid())
 			}
 
 			for _, msg := range bmsgs {
-				rm(msg.From, msg.Nonce)
+				rm(msg.From, msg.Nonce, msg.Cid(), ts.Height())
 				maybeRepub(msg.Cid())
 			}
 		}
@@ -1696,6 +1782,9 @@ func (mp *MessagePool) Clear(ctx context.Context, local bool) {
 			}
 		})
 
+		mp.forEachPending(func(a address.Address, ms *msgSet) {
+			mp.removed(removal{evtType: evtTypeMpoolRemove, reason: types.MpoolRemoveCleared}, ms.toSlice()...)
+		})
 		mp.clearPending()
 		mp.republished = nil
 
@@ -1717,6 +1806,7 @@ func (mp *MessagePool) Clear(ctx context.Context, local bool) {
 			log.Warnf("errored while deleting mset: %w", err)
 			return
 		}
+		mp.removed(removal{evtType: evtTypeMpoolRemove, reason: types.MpoolRemoveCleared}, ms.toSlice()...)
 	})
 }
 
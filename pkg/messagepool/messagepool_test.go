@@ -44,6 +44,7 @@ type testMpoolAPI struct {
 	bmsgs      map[cid.Cid][]*types.SignedMessage
 	statenonce map[address.Address]uint64
 	balance    map[address.Address]tbig.Int
+	ids        map[address.Address]address.Address
 
 	tipsets []*types.TipSet
 
@@ -141,6 +142,7 @@ func newTestMpoolAPI() *testMpoolAPI {
 		bmsgs:      make(map[cid.Cid][]*types.SignedMessage),
 		statenonce: make(map[address.Address]uint64),
 		balance:    make(map[address.Address]tbig.Int),
+		ids:        make(map[address.Address]address.Address),
 		baseFee:    tbig.NewInt(100),
 	}
 	genesis := mkBlock(nil, 1, 1)
@@ -275,6 +277,11 @@ func (tma *testMpoolAPI) GetActorAfter(ctx context.Context, addr address.Address
 }
 
 func (tma *testMpoolAPI) StateAccountKeyAtFinality(ctx context.Context, addr address.Address, ts *types.TipSet) (address.Address, error) {
+	for ka, id := range tma.ids {
+		if id == addr {
+			return ka, nil
+		}
+	}
 	if addr.Protocol() != address.BLS && addr.Protocol() != address.SECP256K1 && addr.Protocol() != address.Delegated {
 		return address.Undef, fmt.Errorf("given address was not a key addr")
 	}
@@ -292,6 +299,16 @@ func (tma *testMpoolAPI) StateAccountKey(ctx context.Context, addr address.Addre
 	return addr, nil
 }
 
+func (tma *testMpoolAPI) StateLookupID(ctx context.Context, addr address.Address, ts *types.TipSet) (address.Address, error) {
+	if id, ok := tma.ids[addr]; ok {
+		return id, nil
+	}
+	if addr.Protocol() == address.ID {
+		return addr, nil
+	}
+	return address.Undef, types.ErrActorNotFound
+}
+
 func (tma *testMpoolAPI) MessagesForBlock(ctx context.Context, h *types.BlockHeader) ([]*types.Message, []*types.SignedMessage, error) {
 	return nil, tma.bmsgs[h.Cid()], nil
 }
@@ -332,6 +349,19 @@ func (tma *testMpoolAPI) ChainComputeBaseFee(ctx context.Context, ts *types.TipS
 	return tma.baseFee, nil
 }
 
+func (tma *testMpoolAPI) MessageReceipts(ctx context.Context, ts *types.TipSet) ([]types.MessageReceipt, error) {
+	msgs, err := tma.MessagesForTipset(ctx, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	rcpts := make([]types.MessageReceipt, 0, len(msgs))
+	for _, m := range msgs {
+		rcpts = append(rcpts, types.MessageReceipt{GasUsed: m.VMMessage().GasLimit})
+	}
+	return rcpts, nil
+}
+
 func assertNonce(t *testing.T, mp *MessagePool, addr address.Address, val uint64) {
 	tf.UnitTest(t)
 
@@ -958,3 +988,80 @@ func TestCapGasFee(t *testing.T) {
 		assert.Equal(t, msg.GasPremium.Int.Int64(), int64(100_000))
 	})
 }
+
+func TestMessageStatus(t *testing.T) {
+	tf.UnitTest(t)
+
+	ctx := context.Background()
+	mp, tma := makeTestMpool()
+	defer mp.Close() // nolint
+
+	w1 := newWallet(t)
+	a1, err := w1.NewAddress(ctx, address.SECP256K1)
+	assert.NoError(t, err)
+	w2 := newWallet(t)
+	a2, err := w2.NewAddress(ctx, address.SECP256K1)
+	assert.NoError(t, err)
+
+	tma.setBalance(a1, 1) // in FIL
+	tma.setBalance(a2, 0)
+	gasLimit := gasguess.Costs[gasguess.CostKey{Code: builtin2.StorageMarketActorCodeID, M: 2}]
+
+	status := func(m *types.SignedMessage) *types.MpoolMessageStatus {
+		s, err := mp.MessageStatus(ctx, m.Cid())
+		assert.NoError(t, err)
+		assert.Equal(t, m.Cid(), s.Cid)
+		return s
+	}
+
+	m0 := makeTestMessage(w1, a1, a2, 0, gasLimit, 1)
+	m1 := makeTestMessage(w1, a1, a2, 1, gasLimit, 1)
+	for _, m := range []*types.SignedMessage{m0, m1} {
+		_, err := mp.Push(ctx, m)
+		assert.NoError(t, err)
+		assert.True(t, status(m).Pending)
+	}
+
+	// replaced by fee
+	m0b := makeTestMessage(w1, a1, a2, 0, gasLimit, 10)
+	_, err = mp.Push(ctx, m0b)
+	assert.NoError(t, err)
+	assert.True(t, status(m0b).Pending)
+	assert.False(t, status(m0).Pending)
+	assert.Equal(t, types.MpoolRemoveReplaced, status(m0).Reason)
+	assert.Contains(t, status(m0).Detail, m0b.Cid().String())
+
+	// refused for a nonce gap, or because the sender can't pay
+	gapped := makeTestMessage(w1, a1, a2, 2+MaxNonceGap+1, gasLimit, 1)
+	assert.ErrorIs(t, mp.Add(ctx, gapped), ErrNonceGap)
+	assert.Equal(t, types.MpoolRemoveNonceGap, status(gapped).Reason)
+
+	broke := makeTestMessage(w2, a2, a1, 0, gasLimit, 1)
+	assert.ErrorIs(t, mp.Add(ctx, broke), ErrNotEnoughFunds)
+	assert.Equal(t, types.MpoolRemoveInsufficientBalance, status(broke).Reason)
+
+	// included, or another message used the nonce
+	m1b := makeTestMessage(w1, a1, a2, 1, gasLimit, 2)
+	blk := tma.nextBlock()
+	tma.setBlockMessages(blk, m0b, m1b)
+	tma.applyBlock(t, blk)
+
+	assert.Equal(t, types.MpoolRemoveIncluded, status(m0b).Reason)
+	assert.Equal(t, blk.Height, status(m0b).Epoch)
+	assert.Equal(t, types.MpoolRemoveNonceUsed, status(m1).Reason)
+	assert.Contains(t, status(m1).Detail, m1b.Cid().String())
+
+	// unknown messages
+	unknown := status(makeTestMessage(w1, a1, a2, 5, gasLimit, 1))
+	assert.False(t, unknown.Pending)
+	assert.Empty(t, unknown.Reason)
+
+	// cleared
+	m2 := makeTestMessage(w1, a1, a2, 2, gasLimit, 1)
+	_, err = mp.Push(ctx, m2)
+	assert.NoError(t, err)
+	assert.True(t, status(m2).Pending)
+	mp.Clear(ctx, true)
+	assert.False(t, status(m2).Pending)
+	assert.Equal(t, types.MpoolRemoveCleared, status(m2).Reason)
+}
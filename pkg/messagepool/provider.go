@@ -36,10 +36,13 @@ type Provider interface {
 	StateAccountKeyAtFinality(context.Context, address.Address, *types.TipSet) (address.Address, error)
 	StateNetworkVersion(context.Context, abi.ChainEpoch) network.Version
 	StateAccountKey(context.Context, address.Address, *types.TipSet) (address.Address, error)
+	StateLookupID(context.Context, address.Address, *types.TipSet) (address.Address, error)
 	MessagesForBlock(context.Context, *types.BlockHeader) ([]*types.Message, []*types.SignedMessage, error)
 	MessagesForTipset(context.Context, *types.TipSet) ([]types.ChainMsg, error)
 	LoadTipSet(context.Context, types.TipSetKey) (*types.TipSet, error)
 	ChainComputeBaseFee(ctx context.Context, ts *types.TipSet) (tbig.Int, error)
+	// MessageReceipts returns the receipts of the messages executed by ts, in the order of MessagesForTipset
+	MessageReceipts(ctx context.Context, ts *types.TipSet) ([]types.MessageReceipt, error)
 	IsLite() bool
 }
 
@@ -173,6 +176,18 @@ func (mpp *mpoolProvider) StateAccountKey(ctx context.Context, addr address.Addr
 	return mpp.stmgr.ResolveToDeterministicAddress(ctx, addr, ts)
 }
 
+func (mpp *mpoolProvider) StateLookupID(ctx context.Context, addr address.Address, ts *types.TipSet) (address.Address, error) {
+	if mpp.IsLite() {
+		return address.Undef, errors.New("looking up id addresses is not supported by a lite Provider")
+	}
+
+	st, err := mpp.stmgr.TipsetState(ctx, ts)
+	if err != nil {
+		return address.Undef, fmt.Errorf("computing tipset state for LookupID: %v", err)
+	}
+	return st.LookupID(addr)
+}
+
 func (mpp *mpoolProvider) MessagesForBlock(ctx context.Context, h *types.BlockHeader) ([]*types.Message, []*types.SignedMessage, error) {
 	secpMsgs, blsMsgs, err := mpp.cms.LoadMetaMessages(context.TODO(), h.Messages)
 	return blsMsgs, secpMsgs, err
@@ -193,3 +208,15 @@ func (mpp *mpoolProvider) ChainComputeBaseFee(ctx context.Context, ts *types.Tip
 	}
 	return baseFee, nil
 }
+
+func (mpp *mpoolProvider) MessageReceipts(ctx context.Context, ts *types.TipSet) ([]types.MessageReceipt, error) {
+	if mpp.IsLite() {
+		return nil, errors.New("message receipts are not available in lite mode")
+	}
+
+	_, rcptRoot, err := mpp.stmgr.RunStateTransition(ctx, ts, nil, false)
+	if err != nil {
+		return nil, fmt.Errorf("computing tipset state for receipts: %v", err)
+	}
+	return mpp.cms.LoadReceipts(ctx, rcptRoot)
+}
@@ -112,7 +112,7 @@ keepLoop:
 	// and remove all messages that are still in pruneMsgs after processing the chains
 	log.Infof("Pruning %d messages", len(pruneMsgs))
 	for _, m := range pruneMsgs {
-		mp.remove(ctx, m.Message.From, m.Message.Nonce, false)
+		mp.remove(ctx, m.Message.From, m.Message.Nonce, false, removal{evtType: evtTypeMpoolPrune, reason: types.MpoolRemovePruned})
 	}
 
 	return nil
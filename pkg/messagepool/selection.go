@@ -62,31 +62,18 @@ func (mp *MessagePool) SelectMessages(ctx context.Context, ts *types.TipSet, tq
 	if err != nil {
 		return nil, err
 	}
-	// if the ticket quality is high enough that the first block has higher probability
-	// than any other block, then we don't bother with optimal selection because the
-	// first block will always have higher effective performance
-	var sm *selectedMessages
-	if tq > 0.84 {
-		sm, err = mp.selectMessagesGreedy(ctx, mp.curTS, ts, pending)
-	} else {
-		sm, err = mp.selectMessagesOptimal(ctx, mp.curTS, ts, tq, pending)
-	}
-
+	msgs, err := mp.selectionStrategy().SelectMessages(ctx, mp, mp.curTS, ts, tq, pending)
 	if err != nil {
 		return nil, err
 	}
 
-	if sm == nil {
-		return nil, nil
-	}
-
 	// one last sanity check
-	if len(sm.msgs) > constants.BlockMessageLimit {
-		log.Errorf("message selection chose too many messages %d > %d", len(sm.msgs), constants.BlockMessageLimit)
-		sm.msgs = sm.msgs[:constants.BlockMessageLimit]
+	if len(msgs) > constants.BlockMessageLimit {
+		log.Errorf("message selection chose too many messages %d > %d", len(msgs), constants.BlockMessageLimit)
+		msgs = msgs[:constants.BlockMessageLimit]
 	}
 
-	return sm.msgs, nil
+	return msgs, nil
 }
 
 type selectedMessages struct {
@@ -213,7 +200,7 @@ func (sm *selectedMessages) trimChain(mc *msgChain, mp *MessagePool, baseFee typ
 	}
 }
 
-func (mp *MessagePool) selectMessagesOptimal(ctx context.Context, curTS, ts *types.TipSet, tq float64, pending map[address.Address]map[uint64]*types.SignedMessage) (*selectedMessages, error) {
+func (mp *MessagePool) selectMessagesOptimal(ctx context.Context, curTS, ts *types.TipSet, tq float64, pending map[address.Address]map[uint64]*types.SignedMessage, priority []address.Address) (*selectedMessages, error) {
 	start := time.Now()
 
 	baseFee, err := mp.api.ChainComputeBaseFee(context.TODO(), ts)
@@ -232,7 +219,7 @@ func (mp *MessagePool) selectMessagesOptimal(ctx context.Context, curTS, ts *typ
 
 	// 0b. Select all priority messages that fit in the block
 	minGas := int64(gasguess.MinGas)
-	result := mp.selectPriorityMessages(ctx, pending, baseFee, ts)
+	result := mp.selectPriorityMessages(ctx, pending, baseFee, ts, priority)
 
 	// have we filled the block?
 	if result.gasLimit < minGas || len(result.msgs) >= constants.BlockMessageLimit {
@@ -454,7 +441,7 @@ tailLoop:
 	return result, nil
 }
 
-func (mp *MessagePool) selectMessagesGreedy(ctx context.Context, curTS, ts *types.TipSet, pending map[address.Address]map[uint64]*types.SignedMessage) (*selectedMessages, error) {
+func (mp *MessagePool) selectMessagesGreedy(ctx context.Context, curTS, ts *types.TipSet, pending map[address.Address]map[uint64]*types.SignedMessage, priority []address.Address) (*selectedMessages, error) {
 	start := time.Now()
 
 	baseFee, err := mp.api.ChainComputeBaseFee(context.TODO(), ts)
@@ -473,7 +460,7 @@ func (mp *MessagePool) selectMessagesGreedy(ctx context.Context, curTS, ts *type
 
 	// 0b. Select all priority messages that fit in the block
 	minGas := int64(gasguess.MinGas)
-	result := mp.selectPriorityMessages(ctx, pending, baseFee, ts)
+	result := mp.selectPriorityMessages(ctx, pending, baseFee, ts, priority)
 
 	// have we filled the block?
 	if result.gasLimit < minGas || len(result.msgs) > constants.BlockMessageLimit {
@@ -581,7 +568,7 @@ tailLoop:
 	return result, nil
 }
 
-func (mp *MessagePool) selectPriorityMessages(ctx context.Context, pending map[address.Address]map[uint64]*types.SignedMessage, baseFee types.BigInt, ts *types.TipSet) *selectedMessages {
+func (mp *MessagePool) selectPriorityMessages(ctx context.Context, pending map[address.Address]map[uint64]*types.SignedMessage, baseFee types.BigInt, ts *types.TipSet, priority []address.Address) *selectedMessages {
 	start := time.Now()
 	defer func() {
 		if dt := time.Since(start); dt > time.Millisecond {
@@ -599,7 +586,6 @@ func (mp *MessagePool) selectPriorityMessages(ctx context.Context, pending map[a
 
 	// 1. Get priority actor chains
 	var chains []*msgChain
-	priority := mpCfg.PriorityAddrs
 	for _, actor := range priority {
 		pk, err := mp.resolveToKey(ctx, actor)
 		if err != nil {
@@ -1045,21 +1031,15 @@ func (mp *MessagePool) MultipleSelectMessages(ctx context.Context, ts *types.Tip
 			break
 		}
 
-		var selMsg *selectedMessages
-		if tq > 0.84 {
-			selMsg, err = mp.multiSelectMessagesGreedy(ctx, mp.curTS, ts, tq, pending)
-		} else {
-			selMsg, err = mp.multiSelectMessagesOptimal(ctx, mp.curTS, ts, tq, pending)
-		}
-
+		msgs, err := mp.selectionStrategy().SelectMessages(ctx, mp, mp.curTS, ts, tq, pending)
 		if err != nil {
 			return nil, err
 		}
 
-		msgss[idx] = selMsg.msgs
+		msgss[idx] = msgs
 
 		// delete the selected message from pending
-		pending = deleteSelectedMessages(pending, selMsg.msgs)
+		pending = deleteSelectedMessages(pending, msgs)
 	}
 
 	// if no message is selected for a block, msgss[0] is filled by default
@@ -1071,11 +1051,3 @@ func (mp *MessagePool) MultipleSelectMessages(ctx context.Context, ts *types.Tip
 
 	return msgss, nil
 }
-
-func (mp *MessagePool) multiSelectMessagesGreedy(ctx context.Context, curTS, ts *types.TipSet, tq float64, pending map[address.Address]map[uint64]*types.SignedMessage) (*selectedMessages, error) {
-	return mp.selectMessagesGreedy(ctx, curTS, ts, pending)
-}
-
-func (mp *MessagePool) multiSelectMessagesOptimal(ctx context.Context, curTS, ts *types.TipSet, tq float64, pending map[address.Address]map[uint64]*types.SignedMessage) (*selectedMessages, error) {
-	return mp.selectMessagesOptimal(ctx, curTS, ts, tq, pending)
-}
@@ -0,0 +1,177 @@
+package messagepool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/filecoin-project/go-address"
+
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// The message selection strategies available by default.
+const (
+	// SelectionOptimal is lotus' selection: the chains of messages with the best gas performance
+	// are packed greedily for the tickets of high quality, and by their expected reward in the
+	// blocks of the tipset otherwise. The messages of the priority addresses are selected first.
+	SelectionOptimal = "optimal"
+	// SelectionFee maximizes the fees, ignoring the priority addresses.
+	SelectionFee = "fee"
+	// SelectionLocal selects the messages of the local addresses first, along with the
+	// messages of the priority addresses.
+	SelectionLocal = "local"
+	// SelectionSenderGasCap is the optimal selection, limiting the gas of the messages selected
+	// for each sender to MaxSenderGasLimit.
+	SelectionSenderGasCap = "sender-gas-cap"
+
+	DefaultSelectionStrategy = SelectionOptimal
+)
+
+// SelectionStrategy chooses the messages included in the blocks produced by the node.
+type SelectionStrategy interface {
+	// SelectMessages returns the messages of pending to include in a block mined on top of ts
+	// with a ticket of quality tq, in their order of inclusion. curTS is the head of mp, whose
+	// locks are held, and pending holds the messages of each sender by nonce.
+	SelectMessages(ctx context.Context, mp *MessagePool, curTS, ts *types.TipSet, tq float64, pending map[address.Address]map[uint64]*types.SignedMessage) ([]*types.SignedMessage, error)
+}
+
+// SelectionStrategyFunc adapts a function to a SelectionStrategy.
+type SelectionStrategyFunc func(ctx context.Context, mp *MessagePool, curTS, ts *types.TipSet, tq float64, pending map[address.Address]map[uint64]*types.SignedMessage) ([]*types.SignedMessage, error)
+
+func (f SelectionStrategyFunc) SelectMessages(ctx context.Context, mp *MessagePool, curTS, ts *types.TipSet, tq float64, pending map[address.Address]map[uint64]*types.SignedMessage) ([]*types.SignedMessage, error) {
+	return f(ctx, mp, curTS, ts, tq, pending)
+}
+
+var (
+	selectionStrategiesLk sync.RWMutex
+	selectionStrategies   = map[string]SelectionStrategy{
+		SelectionOptimal:      SelectionStrategyFunc(selectOptimal),
+		SelectionFee:          SelectionStrategyFunc(selectFee),
+		SelectionLocal:        SelectionStrategyFunc(selectLocal),
+		SelectionSenderGasCap: SelectionStrategyFunc(selectSenderGasCap),
+	}
+)
+
+// RegisterSelectionStrategy makes a strategy available to the SelectionStrategy of the config
+// under name, replacing the strategy already registered under that name.
+func RegisterSelectionStrategy(name string, s SelectionStrategy) {
+	selectionStrategiesLk.Lock()
+	defer selectionStrategiesLk.Unlock()
+
+	selectionStrategies[name] = s
+}
+
+// SelectionStrategies returns the names of the registered selection strategies.
+func SelectionStrategies() []string {
+	selectionStrategiesLk.RLock()
+	defer selectionStrategiesLk.RUnlock()
+
+	names := make([]string, 0, len(selectionStrategies))
+	for name := range selectionStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func getSelectionStrategy(name string) (SelectionStrategy, error) {
+	if name == "" {
+		name = DefaultSelectionStrategy
+	}
+
+	selectionStrategiesLk.RLock()
+	defer selectionStrategiesLk.RUnlock()
+
+	s, ok := selectionStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown selection strategy %q, expected one of %v", name, SelectionStrategies())
+	}
+	return s, nil
+}
+
+// selectionStrategy returns the strategy selected by the config, or the default one when it is
+// no longer registered.
+func (mp *MessagePool) selectionStrategy() SelectionStrategy {
+	s, err := getSelectionStrategy(mp.cfg.SelectionStrategy)
+	if err != nil {
+		log.Errorf("falling back to the %s selection: %s", DefaultSelectionStrategy, err)
+		s, _ = getSelectionStrategy(DefaultSelectionStrategy)
+	}
+	return s
+}
+
+// selectWithPriority runs lotus' selection, selecting the messages of priority first.
+func selectWithPriority(ctx context.Context, mp *MessagePool, curTS, ts *types.TipSet, tq float64, pending map[address.Address]map[uint64]*types.SignedMessage, priority []address.Address) ([]*types.SignedMessage, error) {
+	// if the ticket quality is high enough that the first block has higher probability
+	// than any other block, then we don't bother with optimal selection because the
+	// first block will always have higher effective performance
+	var sm *selectedMessages
+	var err error
+	if tq > 0.84 {
+		sm, err = mp.selectMessagesGreedy(ctx, curTS, ts, pending, priority)
+	} else {
+		sm, err = mp.selectMessagesOptimal(ctx, curTS, ts, tq, pending, priority)
+	}
+	if err != nil || sm == nil {
+		return nil, err
+	}
+
+	return sm.msgs, nil
+}
+
+func selectOptimal(ctx context.Context, mp *MessagePool, curTS, ts *types.TipSet, tq float64, pending map[address.Address]map[uint64]*types.SignedMessage) ([]*types.SignedMessage, error) {
+	return selectWithPriority(ctx, mp, curTS, ts, tq, pending, mp.cfg.PriorityAddrs)
+}
+
+func selectFee(ctx context.Context, mp *MessagePool, curTS, ts *types.TipSet, tq float64, pending map[address.Address]map[uint64]*types.SignedMessage) ([]*types.SignedMessage, error) {
+	return selectWithPriority(ctx, mp, curTS, ts, tq, pending, nil)
+}
+
+func selectLocal(ctx context.Context, mp *MessagePool, curTS, ts *types.TipSet, tq float64, pending map[address.Address]map[uint64]*types.SignedMessage) ([]*types.SignedMessage, error) {
+	priority := make([]address.Address, 0, len(mp.cfg.PriorityAddrs)+len(mp.localAddrs))
+	priority = append(priority, mp.cfg.PriorityAddrs...)
+	for la := range mp.localAddrs {
+		priority = append(priority, la)
+	}
+
+	return selectWithPriority(ctx, mp, curTS, ts, tq, pending, priority)
+}
+
+func selectSenderGasCap(ctx context.Context, mp *MessagePool, curTS, ts *types.TipSet, tq float64, pending map[address.Address]map[uint64]*types.SignedMessage) ([]*types.SignedMessage, error) {
+	if limit := mp.cfg.MaxSenderGasLimit; limit > 0 {
+		pending = capSenderGasLimit(pending, limit)
+	}
+
+	return selectWithPriority(ctx, mp, curTS, ts, tq, pending, mp.cfg.PriorityAddrs)
+}
+
+// capSenderGasLimit keeps the messages of each sender, by increasing nonce, while their total gas
+// limit doesn't exceed limit.
+func capSenderGasLimit(pending map[address.Address]map[uint64]*types.SignedMessage, limit int64) map[address.Address]map[uint64]*types.SignedMessage {
+	capped := make(map[address.Address]map[uint64]*types.SignedMessage, len(pending))
+	for actor, mset := range pending {
+		nonces := make([]uint64, 0, len(mset))
+		for nonce := range mset {
+			nonces = append(nonces, nonce)
+		}
+		sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+
+		var gasLimit int64
+		kept := make(map[uint64]*types.SignedMessage, len(mset))
+		for _, nonce := range nonces {
+			m := mset[nonce]
+			if gasLimit+m.Message.GasLimit > limit {
+				break
+			}
+			gasLimit += m.Message.GasLimit
+			kept[nonce] = m
+		}
+		if len(kept) > 0 {
+			capped[actor] = kept
+		}
+	}
+
+	return capped
+}
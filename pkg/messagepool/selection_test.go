@@ -1228,7 +1228,7 @@ func testCompetitiveMessageSelection(t *testing.T, rng *rand.Rand, getPremium fu
 	pending, err := mp.getPendingMessages(context.TODO(), mp.curTS, ts)
 	require.NoError(t, err)
 	// 1. greedy selection
-	gm, err := mp.selectMessagesGreedy(context.Background(), ts, ts, pending)
+	gm, err := mp.selectMessagesGreedy(context.Background(), ts, ts, pending, mp.cfg.PriorityAddrs)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1604,3 +1604,73 @@ readLoop:
 		t.Fatalf("failed to pack with tq=0.01; packed %d, minimum packing: %d", gasLimit, minGasLimit)
 	}
 }
+
+func TestSelectionStrategies(t *testing.T) {
+	tf.UnitTest(t)
+
+	mp, tma := makeTestMpool()
+
+	// the actors
+	w1 := newWallet(t)
+	a1, err := w1.NewAddress(context.Background(), address.SECP256K1)
+	require.NoError(t, err)
+
+	w2 := newWallet(t)
+	a2, err := w2.NewAddress(context.Background(), address.SECP256K1)
+	require.NoError(t, err)
+
+	block := tma.nextBlock()
+	ts := mkTipSet(block)
+	tma.applyBlock(t, block)
+
+	gasLimit := gasguess.Costs[gasguess.CostKey{Code: builtin2.StorageMarketActorCodeID, M: 2}]
+
+	tma.setBalance(a1, 1) // in FIL
+	tma.setBalance(a2, 1) // in FIL
+
+	// a2 pays more than a1
+	for i := 0; i < 5; i++ {
+		mustAdd(t, mp, makeTestMessage(w1, a1, a2, uint64(i), gasLimit, 1))
+		mustAdd(t, mp, makeTestMessage(w2, a2, a1, uint64(i), gasLimit, 10))
+	}
+	mp.localAddrs[a1] = struct{}{}
+
+	selectWith := func(strategy string) []*types.SignedMessage {
+		cfg := mp.GetConfig()
+		cfg.SelectionStrategy = strategy
+		require.NoError(t, mp.SetConfig(context.Background(), cfg))
+
+		msgs, err := mp.SelectMessages(context.Background(), ts, 1.0)
+		require.NoError(t, err)
+		return msgs
+	}
+
+	msgs := selectWith(SelectionOptimal)
+	require.Len(t, msgs, 10)
+	require.Equal(t, a2, msgs[0].Message.From)
+
+	// the local addresses are selected first
+	msgs = selectWith(SelectionLocal)
+	require.Len(t, msgs, 10)
+	for i, m := range msgs {
+		require.Equal(t, i >= 5, m.Message.From == a2)
+	}
+
+	// the priority addresses are ignored when maximizing the fees
+	mp.cfg.PriorityAddrs = []address.Address{a1}
+	msgs = selectWith(SelectionFee)
+	require.Len(t, msgs, 10)
+	require.Equal(t, a2, msgs[0].Message.From)
+
+	// no more than two messages of each sender
+	mp.cfg.MaxSenderGasLimit = 2 * gasLimit
+	msgs = selectWith(SelectionSenderGasCap)
+	require.Len(t, msgs, 4)
+	for _, m := range msgs {
+		require.Less(t, m.Message.Nonce, uint64(2))
+	}
+
+	cfg := mp.GetConfig()
+	cfg.SelectionStrategy = "unknown"
+	require.Error(t, mp.SetConfig(context.Background(), cfg))
+}
@@ -0,0 +1,103 @@
+package messagepool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+
+	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// RemovedStatusCacheSize is the number of messages removed from the pool whose status is remembered.
+var RemovedStatusCacheSize = 100_000
+
+// removal describes why messages are removed from the pool.
+type removal struct {
+	// evtType is the journal event recording the removal
+	evtType int
+	reason  types.MpoolRemoveReason
+	detail  string
+	epoch   abi.ChainEpoch
+
+	// included is the message included on chain with the nonce of the removed message, the
+	// removed message is a different one when they don't match
+	included cid.Cid
+}
+
+// removed records that msgs left the pool, in the journal and in the statuses returned by MessageStatus.
+func (mp *MessagePool) removed(r removal, msgs ...*types.SignedMessage) {
+	if len(msgs) == 0 {
+		return
+	}
+
+	now := constants.Clock.Now()
+	for _, m := range msgs {
+		mc := m.Cid()
+		status := types.MpoolMessageStatus{
+			Cid:       mc,
+			Reason:    r.reason,
+			Detail:    r.detail,
+			Epoch:     r.epoch,
+			Timestamp: now,
+		}
+		if r.included.Defined() && r.included != mc {
+			status.Reason = types.MpoolRemoveNonceUsed
+			status.Detail = fmt.Sprintf("nonce used by %s", r.included)
+		}
+		mp.statuses.Add(mc, status)
+
+		mp.journal.RecordEvent(mp.evtTypes[r.evtType], func() interface{} {
+			reason := string(status.Reason)
+			if status.Detail != "" {
+				reason += ": " + status.Detail
+			}
+			return MessagePoolEvt{
+				Action:   mp.evtTypes[r.evtType].Event,
+				Messages: []MessagePoolEvtMessage{MessagePoolEvtMessage{Message: m.Message, CID: mc}},
+				Reason:   reason,
+			}
+		})
+	}
+}
+
+// rejected records why m was refused by the pool, when the sender can fix it.
+func (mp *MessagePool) rejected(m *types.SignedMessage, err error) {
+	var reason types.MpoolRemoveReason
+	switch {
+	case errors.Is(err, ErrNonceGap):
+		reason = types.MpoolRemoveNonceGap
+	case errors.Is(err, ErrNotEnoughFunds):
+		reason = types.MpoolRemoveInsufficientBalance
+	default:
+		return
+	}
+
+	mc := m.Cid()
+	mp.statuses.Add(mc, types.MpoolMessageStatus{
+		Cid:       mc,
+		Reason:    reason,
+		Detail:    err.Error(),
+		Timestamp: constants.Clock.Now(),
+	})
+}
+
+// MessageStatus returns whether the message c is in the pool, or why it left the pool. The reason
+// is only known for the last RemovedStatusCacheSize messages removed since the node started.
+func (mp *MessagePool) MessageStatus(ctx context.Context, c cid.Cid) (*types.MpoolMessageStatus, error) {
+	mp.lk.RLock()
+	defer mp.lk.RUnlock()
+
+	if _, pending := mp.pendingCids[c]; pending {
+		return &types.MpoolMessageStatus{Cid: c, Pending: true}, nil
+	}
+
+	if status, ok := mp.statuses.Get(c); ok {
+		return &status, nil
+	}
+
+	return &types.MpoolMessageStatus{Cid: c}, nil
+}
@@ -0,0 +1,158 @@
+package net
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/ipfs/go-datastore"
+	logging "github.com/ipfs/go-log/v2"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/net/conngater"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+var gaterLog = logging.Logger("conngater")
+
+// ConnGater rejects the connections of the peers, ip addresses and subnets blocked through the
+// api, which are persisted in a datastore, and of the peers graylisted by the gossipsub scores.
+type ConnGater struct {
+	*conngater.BasicConnectionGater
+
+	sk *ScoreKeeper
+}
+
+// NewConnGater loads the blocklist stored in ds.
+func NewConnGater(ds datastore.Datastore, sk *ScoreKeeper) (*ConnGater, error) {
+	cg, err := conngater.NewBasicConnectionGater(ds)
+	if err != nil {
+		return nil, fmt.Errorf("load connection gater: %w", err)
+	}
+
+	return &ConnGater{
+		BasicConnectionGater: cg,
+		sk:                   sk,
+	}, nil
+}
+
+func (cg *ConnGater) InterceptPeerDial(p peer.ID) bool {
+	return !cg.sk.Graylisted(p) && cg.BasicConnectionGater.InterceptPeerDial(p)
+}
+
+func (cg *ConnGater) InterceptAddrDial(p peer.ID, a ma.Multiaddr) bool {
+	return !cg.sk.Graylisted(p) && cg.BasicConnectionGater.InterceptAddrDial(p, a)
+}
+
+func (cg *ConnGater) InterceptSecured(dir network.Direction, p peer.ID, cma network.ConnMultiaddrs) bool {
+	return !cg.sk.Graylisted(p) && cg.BasicConnectionGater.InterceptSecured(dir, p, cma)
+}
+
+// BlockAdd blocks the peers, ip addresses and subnets of acl, and closes their connections.
+func (network *Network) BlockAdd(acl types.NetBlockList) error {
+	if network.gater == nil {
+		return fmt.Errorf("connection gater disabled")
+	}
+
+	for _, p := range acl.Peers {
+		if err := network.gater.BlockPeer(p); err != nil {
+			return fmt.Errorf("block peer %s: %w", p, err)
+		}
+		if err := network.host.Network().ClosePeer(p); err != nil {
+			return fmt.Errorf("close connections of peer %s: %w", p, err)
+		}
+	}
+
+	for _, addr := range acl.IPAddrs {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return fmt.Errorf("invalid ip address %s", addr)
+		}
+		if err := network.gater.BlockAddr(ip); err != nil {
+			return fmt.Errorf("block ip address %s: %w", addr, err)
+		}
+		network.closeConns(func(remote net.IP) bool { return ip.Equal(remote) })
+	}
+
+	for _, subnet := range acl.IPSubnets {
+		_, cidr, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return fmt.Errorf("invalid subnet %s: %w", subnet, err)
+		}
+		if err := network.gater.BlockSubnet(cidr); err != nil {
+			return fmt.Errorf("block subnet %s: %w", subnet, err)
+		}
+		network.closeConns(cidr.Contains)
+	}
+
+	return nil
+}
+
+// closeConns closes the connections whose remote ip address matches.
+func (network *Network) closeConns(match func(net.IP) bool) {
+	for _, conn := range network.host.Network().Conns() {
+		remote, err := manet.ToIP(conn.RemoteMultiaddr())
+		if err != nil || !match(remote) {
+			continue
+		}
+		if err := conn.Close(); err != nil {
+			gaterLog.Warnf("close connection to %s: %s", conn.RemotePeer(), err)
+		}
+	}
+}
+
+// BlockRemove unblocks the peers, ip addresses and subnets of acl.
+func (network *Network) BlockRemove(acl types.NetBlockList) error {
+	if network.gater == nil {
+		return fmt.Errorf("connection gater disabled")
+	}
+
+	for _, p := range acl.Peers {
+		if err := network.gater.UnblockPeer(p); err != nil {
+			return fmt.Errorf("unblock peer %s: %w", p, err)
+		}
+	}
+
+	for _, addr := range acl.IPAddrs {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return fmt.Errorf("invalid ip address %s", addr)
+		}
+		if err := network.gater.UnblockAddr(ip); err != nil {
+			return fmt.Errorf("unblock ip address %s: %w", addr, err)
+		}
+	}
+
+	for _, subnet := range acl.IPSubnets {
+		_, cidr, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return fmt.Errorf("invalid subnet %s: %w", subnet, err)
+		}
+		if err := network.gater.UnblockSubnet(cidr); err != nil {
+			return fmt.Errorf("unblock subnet %s: %w", subnet, err)
+		}
+	}
+
+	return nil
+}
+
+// BlockList returns the peers, ip addresses and subnets blocked through the api, the graylisted
+// peers are not included.
+func (network *Network) BlockList() (types.NetBlockList, error) {
+	var acl types.NetBlockList
+	if network.gater == nil {
+		return acl, nil
+	}
+
+	acl.Peers = network.gater.ListBlockedPeers()
+	for _, ip := range network.gater.ListBlockedAddrs() {
+		acl.IPAddrs = append(acl.IPAddrs, ip.String())
+	}
+	for _, subnet := range network.gater.ListBlockedSubnets() {
+		acl.IPSubnets = append(acl.IPSubnets, subnet.String())
+	}
+
+	return acl, nil
+}
@@ -0,0 +1,61 @@
+package net
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+func TestConnGater(t *testing.T) {
+	tf.UnitTest(t)
+
+	ds := dssync.MutexWrap(datastore.NewMapDatastore())
+	sk := NewScoreKeeper()
+	gater, err := NewConnGater(ds, sk)
+	require.NoError(t, err)
+	h, err := libp2p.New(libp2p.NoListenAddrs, libp2p.ConnectionGater(gater))
+	require.NoError(t, err)
+	defer h.Close() //nolint:errcheck
+	network := New(h, h, nil, nil, gater)
+
+	blocked := peer.ID("blocked")
+	graylisted := peer.ID("graylisted")
+	good := peer.ID("good")
+
+	require.NoError(t, network.BlockAdd(types.NetBlockList{
+		Peers:     []peer.ID{blocked},
+		IPAddrs:   []string{"1.2.3.4"},
+		IPSubnets: []string{"10.0.0.0/8"},
+	}))
+	sk.Update(map[peer.ID]*pubsub.PeerScoreSnapshot{
+		graylisted: {Score: GraylistScoreThreshold - 1},
+		good:       {Score: 10},
+	})
+
+	require.False(t, gater.InterceptPeerDial(blocked))
+	require.False(t, gater.InterceptPeerDial(graylisted))
+	require.True(t, gater.InterceptPeerDial(good))
+
+	require.NoError(t, network.BlockRemove(types.NetBlockList{IPAddrs: []string{"1.2.3.4"}}))
+	require.Error(t, network.BlockAdd(types.NetBlockList{IPAddrs: []string{"not an ip"}}))
+	require.Error(t, network.BlockAdd(types.NetBlockList{IPSubnets: []string{"10.0.0.1"}}))
+
+	// the blocklist is persisted, the graylist is not
+	reloaded, err := NewConnGater(ds, NewScoreKeeper())
+	require.NoError(t, err)
+	acl, err := New(h, h, nil, nil, reloaded).BlockList()
+	require.NoError(t, err)
+	require.Equal(t, types.NetBlockList{
+		Peers:     []peer.ID{blocked},
+		IPSubnets: []string{"10.0.0.0/8"},
+	}, acl)
+	require.True(t, reloaded.InterceptPeerDial(graylisted))
+}
@@ -22,6 +22,7 @@ import (
 type Network struct {
 	host    host.Host
 	rawHost types.RawHost
+	gater   *ConnGater
 	metrics.Reporter
 	*Router
 }
@@ -32,10 +33,12 @@ func New(
 	rawHost types.RawHost,
 	router *Router,
 	reporter metrics.Reporter,
+	gater *ConnGater,
 ) *Network {
 	return &Network{
 		host:     host,
 		rawHost:  rawHost,
+		gater:    gater,
 		Reporter: reporter,
 		Router:   router,
 	}
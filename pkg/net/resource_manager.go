@@ -0,0 +1,151 @@
+package net
+
+import (
+	"fmt"
+	"strings"
+
+	network2 "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	rcmgr "github.com/libp2p/go-libp2p/p2p/host/resource-manager"
+
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// The scopes of the resource manager are named `system`, `transient`, `svc:<service>`,
+// `proto:<protocol id>` or `peer:<peer id>`, `all` selects every scope in use.
+const (
+	ScopeAll       = "all"
+	ScopeSystem    = "system"
+	ScopeTransient = "transient"
+
+	ScopeServicePrefix  = "svc:"
+	ScopeProtocolPrefix = "proto:"
+	ScopePeerPrefix     = "peer:"
+)
+
+// ResourceLimits converts a limit to the limits of the resource manager, its zero fields
+// keep the default value.
+func ResourceLimits(l types.NetLimit) rcmgr.ResourceLimits {
+	return rcmgr.ResourceLimits{
+		Memory:          rcmgr.LimitVal64(l.Memory),
+		Streams:         rcmgr.LimitVal(l.Streams),
+		StreamsInbound:  rcmgr.LimitVal(l.StreamsInbound),
+		StreamsOutbound: rcmgr.LimitVal(l.StreamsOutbound),
+		Conns:           rcmgr.LimitVal(l.Conns),
+		ConnsInbound:    rcmgr.LimitVal(l.ConnsInbound),
+		ConnsOutbound:   rcmgr.LimitVal(l.ConnsOutbound),
+		FD:              rcmgr.LimitVal(l.FD),
+	}
+}
+
+func netLimit(l rcmgr.Limit) types.NetLimit {
+	return types.NetLimit{
+		Memory:          l.GetMemoryLimit(),
+		Streams:         l.GetStreamTotalLimit(),
+		StreamsInbound:  l.GetStreamLimit(network2.DirInbound),
+		StreamsOutbound: l.GetStreamLimit(network2.DirOutbound),
+		Conns:           l.GetConnTotalLimit(),
+		ConnsInbound:    l.GetConnLimit(network2.DirInbound),
+		ConnsOutbound:   l.GetConnLimit(network2.DirOutbound),
+		FD:              l.GetFDLimit(),
+	}
+}
+
+// viewScope calls f with the resource scope named scope.
+func (network *Network) viewScope(scope string, f func(network2.ResourceScope) error) error {
+	rm := network.host.Network().ResourceManager()
+	switch {
+	case scope == ScopeSystem:
+		return rm.ViewSystem(f)
+	case scope == ScopeTransient:
+		return rm.ViewTransient(f)
+	case strings.HasPrefix(scope, ScopeServicePrefix):
+		svc := strings.TrimPrefix(scope, ScopeServicePrefix)
+		return rm.ViewService(svc, func(s network2.ServiceScope) error { return f(s) })
+	case strings.HasPrefix(scope, ScopeProtocolPrefix):
+		proto := protocol.ID(strings.TrimPrefix(scope, ScopeProtocolPrefix))
+		return rm.ViewProtocol(proto, func(s network2.ProtocolScope) error { return f(s) })
+	case strings.HasPrefix(scope, ScopePeerPrefix):
+		p, err := peer.Decode(strings.TrimPrefix(scope, ScopePeerPrefix))
+		if err != nil {
+			return fmt.Errorf("invalid peer id: %w", err)
+		}
+		return rm.ViewPeer(p, func(s network2.PeerScope) error { return f(s) })
+	default:
+		return fmt.Errorf("invalid scope %q", scope)
+	}
+}
+
+// ResourceStat returns the resources used by a scope of the resource manager, or by all of them.
+func (network *Network) ResourceStat(scope string) (types.NetStat, error) {
+	var result types.NetStat
+	if scope == ScopeAll {
+		rm, ok := network.host.Network().ResourceManager().(rcmgr.ResourceManagerState)
+		if !ok {
+			return result, fmt.Errorf("the resource manager doesn't expose its state")
+		}
+
+		stat := rm.Stat()
+		result.System = &stat.System
+		result.Transient = &stat.Transient
+		result.Services = stat.Services
+		result.Protocols = make(map[string]network2.ScopeStat, len(stat.Protocols))
+		for proto, stat := range stat.Protocols {
+			result.Protocols[string(proto)] = stat
+		}
+		result.Peers = make(map[string]network2.ScopeStat, len(stat.Peers))
+		for p, stat := range stat.Peers {
+			result.Peers[p.String()] = stat
+		}
+		return result, nil
+	}
+
+	err := network.viewScope(scope, func(s network2.ResourceScope) error {
+		stat := s.Stat()
+		switch {
+		case scope == ScopeSystem:
+			result.System = &stat
+		case scope == ScopeTransient:
+			result.Transient = &stat
+		case strings.HasPrefix(scope, ScopeServicePrefix):
+			result.Services = map[string]network2.ScopeStat{strings.TrimPrefix(scope, ScopeServicePrefix): stat}
+		case strings.HasPrefix(scope, ScopeProtocolPrefix):
+			result.Protocols = map[string]network2.ScopeStat{strings.TrimPrefix(scope, ScopeProtocolPrefix): stat}
+		case strings.HasPrefix(scope, ScopePeerPrefix):
+			result.Peers = map[string]network2.ScopeStat{strings.TrimPrefix(scope, ScopePeerPrefix): stat}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// ResourceLimit returns the limit of a scope of the resource manager.
+func (network *Network) ResourceLimit(scope string) (types.NetLimit, error) {
+	var result types.NetLimit
+	err := network.viewScope(scope, func(s network2.ResourceScope) error {
+		limiter, ok := s.(rcmgr.ResourceScopeLimiter)
+		if !ok {
+			return fmt.Errorf("resource scope %s doesn't have limits", scope)
+		}
+		result = netLimit(limiter.Limit())
+		return nil
+	})
+	return result, err
+}
+
+// SetResourceLimit changes the limit of a scope of the resource manager, the zero fields of
+// limit keep their current value. The limit of a protocol or a peer is kept when it is no
+// longer in use.
+func (network *Network) SetResourceLimit(scope string, limit types.NetLimit) error {
+	return network.viewScope(scope, func(s network2.ResourceScope) error {
+		limiter, ok := s.(rcmgr.ResourceScopeLimiter)
+		if !ok {
+			return fmt.Errorf("resource scope %s doesn't have limits", scope)
+		}
+		rl := ResourceLimits(limit)
+		l := rl.Build(limiter.Limit())
+		limiter.SetLimit(&l)
+		return nil
+	})
+}
@@ -0,0 +1,50 @@
+package net
+
+import (
+	"testing"
+
+	"github.com/libp2p/go-libp2p"
+	rcmgr "github.com/libp2p/go-libp2p/p2p/host/resource-manager"
+	"github.com/stretchr/testify/require"
+
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+func TestResourceLimits(t *testing.T) {
+	tf.UnitTest(t)
+
+	rm, err := rcmgr.NewResourceManager(rcmgr.NewFixedLimiter(rcmgr.DefaultLimits.AutoScale()))
+	require.NoError(t, err)
+	h, err := libp2p.New(libp2p.NoListenAddrs, libp2p.ResourceManager(rm))
+	require.NoError(t, err)
+	defer h.Close() //nolint:errcheck
+
+	network := New(h, h, nil, nil, nil)
+
+	before, err := network.ResourceLimit(ScopeSystem)
+	require.NoError(t, err)
+
+	// the fields not set keep their value
+	require.NoError(t, network.SetResourceLimit(ScopeSystem, types.NetLimit{Streams: 10, FD: -1}))
+	after, err := network.ResourceLimit(ScopeSystem)
+	require.NoError(t, err)
+	require.Equal(t, 10, after.Streams)
+	require.Greater(t, after.FD, before.FD)
+	require.Equal(t, before.Memory, after.Memory)
+	require.Equal(t, before.Conns, after.Conns)
+
+	stat, err := network.ResourceStat(ScopeAll)
+	require.NoError(t, err)
+	require.NotNil(t, stat.System)
+	require.NotNil(t, stat.Transient)
+
+	stat, err = network.ResourceStat(ScopeProtocolPrefix + "/fil/hello/1.0.0")
+	require.NoError(t, err)
+	require.Contains(t, stat.Protocols, "/fil/hello/1.0.0")
+
+	_, err = network.ResourceLimit("unknown")
+	require.Error(t, err)
+	_, err = network.ResourceLimit(ScopePeerPrefix + "not-a-peer")
+	require.Error(t, err)
+}
@@ -29,3 +29,11 @@ func (sk *ScoreKeeper) Get() map[peer.ID]*pubsub.PeerScoreSnapshot {
 	defer sk.lk.Unlock()
 	return sk.scores
 }
+
+// Graylisted returns whether the gossipsub score of p is below the graylist threshold.
+func (sk *ScoreKeeper) Graylisted(p peer.ID) bool {
+	sk.lk.Lock()
+	defer sk.lk.Unlock()
+	score, ok := sk.scores[p]
+	return ok && score.Score < GraylistScoreThreshold
+}
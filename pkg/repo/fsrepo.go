@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/filecoin-project/venus/pkg/repo/fskeystore"
+	"github.com/filecoin-project/venus/pkg/splitstore"
 
 	blockstoreutil "github.com/filecoin-project/venus/venus-shared/blockstore"
 	bstore "github.com/ipfs/boxo/blockstore"
@@ -52,7 +53,7 @@ type FSRepo struct {
 	// lk protects the config file
 	lk sync.RWMutex
 
-	ds       *blockstoreutil.BadgerBlockstore
+	ds       closableBlockstore
 	keystore fskeystore.Keystore
 	walletDs Datastore
 	chainDs  Datastore
@@ -69,6 +70,11 @@ type FSRepo struct {
 
 var _ Repo = (*FSRepo)(nil)
 
+type closableBlockstore interface {
+	blockstoreutil.Blockstore
+	io.Closer
+}
+
 // InitFSRepo initializes a new repo at the target path with the provided configuration.
 // The successful result creates a symlink at targetPath pointing to a sibling directory
 // named with a timestamp and repo version number.
@@ -210,6 +216,11 @@ func (r *FSRepo) loadFromDisk() error {
 		return errors.Wrap(err, "failed to load config file")
 	}
 
+	// the splitstore keeps its compaction progress in the metadata datastore
+	if err := r.openMetaDatastore(); err != nil {
+		return errors.Wrap(err, "failed to open metadata datastore")
+	}
+
 	if err := r.openDatastore(); err != nil {
 		return errors.Wrap(err, "failed to open datastore")
 	}
@@ -226,10 +237,6 @@ func (r *FSRepo) loadFromDisk() error {
 		return errors.Wrap(err, "failed to open chain datastore")
 	}
 
-	if err := r.openMetaDatastore(); err != nil {
-		return errors.Wrap(err, "failed to open metadata datastore")
-	}
-
 	if err := r.openPaychDataStore(); err != nil {
 		return errors.Wrap(err, "failed to open paych datastore")
 	}
@@ -379,16 +386,31 @@ func (r *FSRepo) readVersion() (uint, error) {
 func (r *FSRepo) openDatastore() error {
 	switch Config.Datastore.Type {
 	case "badgerds":
-		path := filepath.Join(r.path, Config.Datastore.Path)
-		opts, err := blockstoreutil.BadgerBlockstoreOptions(path, false)
+		ds, err := openBadgerBlockstore(filepath.Join(r.path, Config.Datastore.Path))
 		if err != nil {
 			return err
 		}
-		opts.Prefix = bstore.BlockPrefix.String()
-		ds, err := blockstoreutil.Open(opts)
+		r.ds = ds
+	case "splitstore":
+		cfg := Config.Datastore.SplitStore
+		if cfg == nil {
+			return fmt.Errorf("missing splitstore config")
+		}
+		cold, err := openBadgerBlockstore(filepath.Join(r.path, Config.Datastore.Path))
 		if err != nil {
 			return err
 		}
+		hot, err := openBadgerBlockstore(filepath.Join(r.path, cfg.HotStorePath))
+		if err != nil {
+			_ = cold.Close()
+			return err
+		}
+		ds, err := splitstore.Open(hot, cold, r.metaDs, cfg)
+		if err != nil {
+			_ = hot.Close()
+			_ = cold.Close()
+			return err
+		}
 		r.ds = ds
 	default:
 		return fmt.Errorf("unknown datastore type in config: %s", Config.Datastore.Type)
@@ -397,6 +419,15 @@ func (r *FSRepo) openDatastore() error {
 	return nil
 }
 
+func openBadgerBlockstore(path string) (*blockstoreutil.BadgerBlockstore, error) {
+	opts, err := blockstoreutil.BadgerBlockstoreOptions(path, false)
+	if err != nil {
+		return nil, err
+	}
+	opts.Prefix = bstore.BlockPrefix.String()
+	return blockstoreutil.Open(opts)
+}
+
 func (r *FSRepo) openKeystore() error {
 	ksp := filepath.Join(r.path, "keystore")
 
@@ -0,0 +1,638 @@
+// Package splitstore implements a hot/cold blockstore for the chain.
+//
+// All writes go to the hot store. Reads go to the hot store first and fall back
+// to the cold store. Once the head has advanced far enough, a compaction walks
+// the chain from the head (see chain.Store.WalkSnapshot), marks the chain headers
+// and the state and messages of the last HotStoreFinalityRetention finalities,
+// as well as the state computed from the head and the prune roots of the chain,
+// and moves everything else from the hot store to the cold store, or deletes it
+// when the cold store type is "discard".
+//
+// Compaction runs online: objects written or found by Has while a compaction is
+// running are protected from being swept, and objects are copied to the cold
+// store before they are removed from the hot store, so readers never miss them.
+package splitstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	ipld "github.com/ipfs/go-ipld-format"
+	logging "github.com/ipfs/go-log/v2"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/go-state-types/abi"
+
+	"github.com/filecoin-project/venus/pkg/config"
+	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/venus-shared/actors/policy"
+	blockstoreutil "github.com/filecoin-project/venus/venus-shared/blockstore"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+var log = logging.Logger("splitstore")
+
+const (
+	// ColdStoreUniversal moves unreachable objects into the cold store.
+	ColdStoreUniversal = "universal"
+	// ColdStoreDiscard deletes unreachable objects from the hot store.
+	ColdStoreDiscard = "discard"
+)
+
+const (
+	phaseIdle  = "idle"
+	phaseMark  = "mark"
+	phaseSweep = "sweep"
+)
+
+// sweepBatchSize is the number of objects moved or deleted at once.
+var sweepBatchSize = 16 << 10
+
+// syncGap is how far the head may lag behind the wall clock before automatic
+// compaction is postponed, compacting while catching up only wastes work.
+var syncGap = time.Minute
+
+// baseEpochKey is the key in the metadata datastore at which the head height of
+// the last compaction is written.
+var baseEpochKey = datastore.NewKey("/splitstore/baseEpoch")
+
+// ErrCompacting is returned when a compaction is requested while one is running.
+var ErrCompacting = errors.New("splitstore compaction already in progress")
+
+// ErrNotStarted is returned when a compaction is requested before the chain is attached.
+var ErrNotStarted = errors.New("splitstore is not attached to a chain yet")
+
+// ChainWalker is the part of the chain store used to find live objects.
+type ChainWalker interface {
+	GetHead() *types.TipSet
+	GetTipSet(ctx context.Context, key types.TipSetKey) (*types.TipSet, error)
+	WalkSnapshot(ctx context.Context, ts *types.TipSet, inclRecentRoots abi.ChainEpoch, skipOldMsgs, skipMsgReceipts bool, cb func(cid.Cid) error) error
+	GetTipSetStateRoot(ctx context.Context, ts *types.TipSet) (cid.Cid, error)
+	GetTipSetReceiptsRoot(ctx context.Context, ts *types.TipSet) (cid.Cid, error)
+	LoadPruneRoots(ctx context.Context) ([]cid.Cid, error)
+}
+
+// HotStore is a blockstore whose keys can be enumerated, e.g. BadgerBlockstore.
+type HotStore interface {
+	blockstoreutil.Blockstore
+	io.Closer
+	ForEachKey(f func(cid.Cid) error) error
+}
+
+// ColdStore is the blockstore holding objects evicted from the hot store.
+type ColdStore interface {
+	blockstoreutil.Blockstore
+	io.Closer
+}
+
+// SplitStore is a blockstore made of a hot and a cold blockstore.
+type SplitStore struct {
+	hot  HotStore
+	cold ColdStore
+	ds   datastore.Datastore
+	cfg  config.SplitStoreConfig
+
+	chain ChainWalker
+
+	// protect holds the multihashes of the objects written or checked for
+	// existence while a compaction is running, it is nil otherwise.
+	protectLk sync.Mutex
+	protect   map[string]struct{}
+
+	compacting bool
+	baseEpoch  abi.ChainEpoch
+
+	statusLk sync.Mutex
+	status   types.SplitStoreInfo
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var (
+	_ blockstoreutil.Blockstore   = (*SplitStore)(nil)
+	_ blockstoreutil.BlockstoreGC = (*SplitStore)(nil)
+)
+
+// Open creates a splitstore on top of the given hot and cold stores, ds is used
+// to persist the compaction progress.
+func Open(hot HotStore, cold ColdStore, ds datastore.Datastore, cfg *config.SplitStoreConfig) (*SplitStore, error) {
+	switch cfg.ColdStoreType {
+	case ColdStoreUniversal, ColdStoreDiscard:
+	default:
+		return nil, fmt.Errorf("unknown splitstore cold store type: %s", cfg.ColdStoreType)
+	}
+	if cfg.HotStoreFinalityRetention == 0 {
+		return nil, fmt.Errorf("splitstore hot store retention must be at least one finality")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &SplitStore{
+		hot:    hot,
+		cold:   cold,
+		ds:     ds,
+		cfg:    *cfg,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	bs, err := ds.Get(ctx, baseEpochKey)
+	switch {
+	case err == nil:
+		epoch, _, err := cbg.CborReadHeader(bytes.NewReader(bs))
+		if err != nil {
+			return nil, fmt.Errorf("decoding splitstore base epoch: %w", err)
+		}
+		s.baseEpoch = abi.ChainEpoch(epoch)
+	case errors.Is(err, datastore.ErrNotFound):
+	default:
+		return nil, fmt.Errorf("loading splitstore base epoch: %w", err)
+	}
+
+	s.status = types.SplitStoreInfo{
+		ColdStoreType:     cfg.ColdStoreType,
+		HotStoreRetention: s.retention(),
+		BaseEpoch:         s.baseEpoch,
+		Phase:             phaseIdle,
+	}
+
+	return s, nil
+}
+
+// Start attaches the splitstore to the chain, automatic compaction is driven
+// by HeadChange from then on.
+func (s *SplitStore) Start(chain ChainWalker) {
+	s.statusLk.Lock()
+	defer s.statusLk.Unlock()
+	s.chain = chain
+}
+
+// HeadChange is a chain.ReorgNotifee which starts a compaction in the background
+// once the head has advanced CompactionFinalityInterval finalities since the last one.
+func (s *SplitStore) HeadChange(_, apply []*types.TipSet) error {
+	if len(apply) == 0 {
+		return nil
+	}
+
+	head := apply[len(apply)-1]
+	if constants.Clock.Since(time.Unix(int64(head.MinTimestamp()), 0)) > syncGap {
+		// still syncing
+		return nil
+	}
+
+	s.statusLk.Lock()
+	ready := s.chain != nil && !s.compacting && head.Height()-s.baseEpoch >= s.interval()
+	s.statusLk.Unlock()
+	if !ready {
+		return nil
+	}
+
+	if err := s.Compact(s.ctx); err != nil && !errors.Is(err, ErrCompacting) {
+		log.Warnf("failed to start splitstore compaction: %s", err)
+	}
+	return nil
+}
+
+// Compact starts a compaction at the current head in the background, it is cancelled when ctx is
+// done or the splitstore is closed.
+func (s *SplitStore) Compact(ctx context.Context) error {
+	s.statusLk.Lock()
+	defer s.statusLk.Unlock()
+
+	if s.chain == nil {
+		return ErrNotStarted
+	}
+	if s.compacting {
+		return ErrCompacting
+	}
+
+	head := s.chain.GetHead()
+	base := s.baseEpoch
+	s.compacting = true
+	s.status.Compacting = true
+	s.status.Phase = phaseMark
+	s.status.Marked, s.status.Moved, s.status.Purged = 0, 0, 0
+	s.status.LastCompactionStart = constants.Clock.Now()
+	s.status.LastError = ""
+
+	cctx, cancel := context.WithCancel(ctx)
+	s.wg.Add(1)
+	go func() {
+		defer cancel()
+		select {
+		case <-s.ctx.Done():
+		case <-cctx.Done():
+		}
+	}()
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+
+		start := constants.Clock.Now()
+		log.Infow("splitstore compaction started", "head", head.Height(), "base", base)
+		err := s.compact(cctx, head)
+
+		s.statusLk.Lock()
+		s.compacting = false
+		s.status.Compacting = false
+		s.status.Phase = phaseIdle
+		s.status.LastCompactionDuration = constants.Clock.Since(start)
+		if err != nil {
+			s.status.LastError = err.Error()
+		}
+		s.statusLk.Unlock()
+
+		if err != nil {
+			log.Errorf("splitstore compaction failed: %s", err)
+			return
+		}
+		log.Infow("splitstore compaction finished", "head", head.Height(), "duration", constants.Clock.Since(start))
+	}()
+
+	return nil
+}
+
+// Info returns the configuration and the compaction progress of the splitstore.
+func (s *SplitStore) Info() types.SplitStoreInfo {
+	s.statusLk.Lock()
+	defer s.statusLk.Unlock()
+	return s.status
+}
+
+func (s *SplitStore) compact(ctx context.Context, head *types.TipSet) error {
+	s.protectLk.Lock()
+	s.protect = make(map[string]struct{})
+	s.protectLk.Unlock()
+	defer func() {
+		s.protectLk.Lock()
+		s.protect = nil
+		s.protectLk.Unlock()
+	}()
+
+	// mark the chain headers and the recent state and messages reachable from head
+	marked := make(map[string]struct{})
+	mark := func(c cid.Cid) bool {
+		k := string(c.Hash())
+		if _, ok := marked[k]; ok {
+			return false
+		}
+		marked[k] = struct{}{}
+		s.updateStatus(func(st *types.SplitStoreInfo) { st.Marked++ })
+		return true
+	}
+	err := s.chain.WalkSnapshot(ctx, head, s.retention(), true, true, func(c cid.Cid) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		mark(c)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("marking live objects: %w", err)
+	}
+	// WalkSnapshot only includes the receipt roots, the receipts and their events
+	// are needed to search messages in the hot range.
+	if err := s.markReceipts(ctx, head, mark); err != nil {
+		return fmt.Errorf("marking receipts: %w", err)
+	}
+	if err := s.markRoots(ctx, head, mark); err != nil {
+		return err
+	}
+
+	s.updateStatus(func(st *types.SplitStoreInfo) { st.Phase = phaseSweep })
+
+	// sweep everything else out of the hot store
+	batch := make([]cid.Cid, 0, sweepBatchSize)
+	err = s.hot.ForEachKey(func(c cid.Cid) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, ok := marked[string(c.Hash())]; ok {
+			return nil
+		}
+
+		batch = append(batch, c)
+		if len(batch) < sweepBatchSize {
+			return nil
+		}
+		err := s.sweep(ctx, batch)
+		batch = batch[:0]
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("sweeping hot store: %w", err)
+	}
+	if err := s.sweep(ctx, batch); err != nil {
+		return fmt.Errorf("sweeping hot store: %w", err)
+	}
+
+	if err := s.setBaseEpoch(ctx, head.Height()); err != nil {
+		return err
+	}
+
+	return s.hot.Flush(ctx)
+}
+
+func (s *SplitStore) markReceipts(ctx context.Context, head *types.TipSet, mark func(cid.Cid) bool) error {
+	boundary := head.Height() - s.retention()
+	for ts := head; ts.Height() > boundary && ts.Height() > 0; {
+		if err := s.markLinks(ctx, ts.Blocks()[0].ParentMessageReceipts, mark); err != nil {
+			return err
+		}
+
+		parent, err := s.chain.GetTipSet(ctx, ts.Parents())
+		if err != nil {
+			return err
+		}
+		ts = parent
+	}
+	return nil
+}
+
+// markRoots marks the state and receipts computed from head, which only the chain
+// datastore references, and the prune roots of the chain, e.g. the messages pending
+// in the message pool.
+func (s *SplitStore) markRoots(ctx context.Context, head *types.TipSet, mark func(cid.Cid) bool) error {
+	var roots []cid.Cid
+	// head may not have been executed yet
+	if root, err := s.chain.GetTipSetStateRoot(ctx, head); err == nil {
+		roots = append(roots, root)
+	}
+	if root, err := s.chain.GetTipSetReceiptsRoot(ctx, head); err == nil {
+		roots = append(roots, root)
+	}
+	pruneRoots, err := s.chain.LoadPruneRoots(ctx)
+	if err != nil {
+		return fmt.Errorf("loading prune roots: %w", err)
+	}
+	roots = append(roots, pruneRoots...)
+
+	for _, root := range roots {
+		if err := s.markLinks(ctx, root, mark); err != nil {
+			return fmt.Errorf("marking %s: %w", root, err)
+		}
+	}
+	return nil
+}
+
+// markLinks marks root and every dag-cbor object reachable from it.
+func (s *SplitStore) markLinks(ctx context.Context, root cid.Cid, mark func(cid.Cid) bool) error {
+	stack := []cid.Cid{root}
+	for len(stack) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !mark(c) || c.Prefix().Codec != cid.DagCBOR {
+			continue
+		}
+
+		err := s.View(ctx, c, func(data []byte) error {
+			return cbg.ScanForLinks(bytes.NewReader(data), func(link cid.Cid) {
+				stack = append(stack, link)
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("scanning links of %s: %w", c, err)
+		}
+	}
+	return nil
+}
+
+// sweep moves the objects to the cold store, or discards them, and deletes them
+// from the hot store. Objects touched since the compaction started are skipped.
+func (s *SplitStore) sweep(ctx context.Context, cids []cid.Cid) error {
+	if len(cids) == 0 {
+		return nil
+	}
+
+	// holding protectLk until the objects are deleted ensures a concurrent Put
+	// either protects an object before it is swept or rewrites it afterwards.
+	s.protectLk.Lock()
+	defer s.protectLk.Unlock()
+
+	toDelete := make([]cid.Cid, 0, len(cids))
+	for _, c := range cids {
+		if _, ok := s.protect[string(c.Hash())]; !ok {
+			toDelete = append(toDelete, c)
+		}
+	}
+
+	if s.cfg.ColdStoreType == ColdStoreUniversal {
+		blks := make([]blocks.Block, 0, len(toDelete))
+		for _, c := range toDelete {
+			blk, err := s.hot.Get(ctx, c)
+			if err != nil {
+				if ipld.IsNotFound(err) {
+					continue
+				}
+				return fmt.Errorf("reading object %s from hot store: %w", c, err)
+			}
+			blks = append(blks, blk)
+		}
+		if err := s.cold.PutMany(ctx, blks); err != nil {
+			return fmt.Errorf("moving objects to cold store: %w", err)
+		}
+	}
+
+	if err := s.hot.DeleteMany(ctx, toDelete); err != nil {
+		return fmt.Errorf("deleting objects from hot store: %w", err)
+	}
+
+	s.updateStatus(func(st *types.SplitStoreInfo) {
+		if s.cfg.ColdStoreType == ColdStoreUniversal {
+			st.Moved += uint64(len(toDelete))
+		} else {
+			st.Purged += uint64(len(toDelete))
+		}
+	})
+
+	return nil
+}
+
+func (s *SplitStore) setBaseEpoch(ctx context.Context, epoch abi.ChainEpoch) error {
+	buf := new(bytes.Buffer)
+	if err := cbg.WriteMajorTypeHeader(buf, cbg.MajUnsignedInt, uint64(epoch)); err != nil {
+		return err
+	}
+	if err := s.ds.Put(ctx, baseEpochKey, buf.Bytes()); err != nil {
+		return fmt.Errorf("persisting splitstore base epoch: %w", err)
+	}
+
+	s.statusLk.Lock()
+	s.baseEpoch = epoch
+	s.status.BaseEpoch = epoch
+	s.statusLk.Unlock()
+	return nil
+}
+
+func (s *SplitStore) updateStatus(f func(*types.SplitStoreInfo)) {
+	s.statusLk.Lock()
+	f(&s.status)
+	s.statusLk.Unlock()
+}
+
+func (s *SplitStore) retention() abi.ChainEpoch {
+	return abi.ChainEpoch(s.cfg.HotStoreFinalityRetention) * policy.ChainFinality
+}
+
+func (s *SplitStore) interval() abi.ChainEpoch {
+	interval := abi.ChainEpoch(s.cfg.CompactionFinalityInterval) * policy.ChainFinality
+	if interval <= 0 {
+		interval = policy.ChainFinality
+	}
+	return interval
+}
+
+func (s *SplitStore) protectKey(c cid.Cid) {
+	s.protectLk.Lock()
+	if s.protect != nil {
+		s.protect[string(c.Hash())] = struct{}{}
+	}
+	s.protectLk.Unlock()
+}
+
+func (s *SplitStore) Has(ctx context.Context, c cid.Cid) (bool, error) {
+	has, err := s.hot.Has(ctx, c)
+	if err != nil {
+		return false, err
+	}
+	if has {
+		// the caller may skip writing this object, so it must survive a running compaction
+		s.protectKey(c)
+		return true, nil
+	}
+
+	return s.cold.Has(ctx, c)
+}
+
+func (s *SplitStore) Get(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	blk, err := s.hot.Get(ctx, c)
+	if err == nil || !ipld.IsNotFound(err) {
+		return blk, err
+	}
+
+	return s.cold.Get(ctx, c)
+}
+
+func (s *SplitStore) GetSize(ctx context.Context, c cid.Cid) (int, error) {
+	size, err := s.hot.GetSize(ctx, c)
+	if err == nil || !ipld.IsNotFound(err) {
+		return size, err
+	}
+
+	return s.cold.GetSize(ctx, c)
+}
+
+func (s *SplitStore) View(ctx context.Context, c cid.Cid, cb func([]byte) error) error {
+	err := s.hot.View(ctx, c, cb)
+	if err == nil || !ipld.IsNotFound(err) {
+		return err
+	}
+
+	return s.cold.View(ctx, c, cb)
+}
+
+func (s *SplitStore) Put(ctx context.Context, blk blocks.Block) error {
+	s.protectKey(blk.Cid())
+	return s.hot.Put(ctx, blk)
+}
+
+func (s *SplitStore) PutMany(ctx context.Context, blks []blocks.Block) error {
+	s.protectLk.Lock()
+	if s.protect != nil {
+		for _, blk := range blks {
+			s.protect[string(blk.Cid().Hash())] = struct{}{}
+		}
+	}
+	s.protectLk.Unlock()
+
+	return s.hot.PutMany(ctx, blks)
+}
+
+func (s *SplitStore) DeleteBlock(ctx context.Context, c cid.Cid) error {
+	if err := s.hot.DeleteBlock(ctx, c); err != nil {
+		return err
+	}
+	return s.cold.DeleteBlock(ctx, c)
+}
+
+func (s *SplitStore) DeleteMany(ctx context.Context, cids []cid.Cid) error {
+	if err := s.hot.DeleteMany(ctx, cids); err != nil {
+		return err
+	}
+	return s.cold.DeleteMany(ctx, cids)
+}
+
+// AllKeysChan returns the keys of the hot store followed by the keys of the cold store.
+func (s *SplitStore) AllKeysChan(ctx context.Context) (<-chan cid.Cid, error) {
+	hotCh, err := s.hot.AllKeysChan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	coldCh, err := s.cold.AllKeysChan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan cid.Cid)
+	go func() {
+		defer close(ch)
+		for _, in := range []<-chan cid.Cid{hotCh, coldCh} {
+			for c := range in {
+				select {
+				case ch <- c:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func (s *SplitStore) HashOnRead(enabled bool) {
+	s.hot.HashOnRead(enabled)
+	s.cold.HashOnRead(enabled)
+}
+
+func (s *SplitStore) Flush(ctx context.Context) error {
+	if err := s.hot.Flush(ctx); err != nil {
+		return err
+	}
+	return s.cold.Flush(ctx)
+}
+
+// CollectGarbage reclaims the space of the objects swept out of the hot store.
+func (s *SplitStore) CollectGarbage(options ...blockstoreutil.BlockstoreGCOption) error {
+	gc, ok := s.hot.(blockstoreutil.BlockstoreGC)
+	if !ok {
+		return fmt.Errorf("hot store does not support garbage collection")
+	}
+	return gc.CollectGarbage(options...)
+}
+
+// Close stops a running compaction and closes both stores.
+func (s *SplitStore) Close() error {
+	s.cancel()
+	s.wg.Wait()
+
+	if err := s.hot.Close(); err != nil {
+		return fmt.Errorf("closing hot store: %w", err)
+	}
+	if err := s.cold.Close(); err != nil {
+		return fmt.Errorf("closing cold store: %w", err)
+	}
+	return nil
+}
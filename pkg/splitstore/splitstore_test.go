@@ -0,0 +1,255 @@
+package splitstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus/pkg/config"
+	"github.com/filecoin-project/venus/pkg/state/tree"
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	blockstoreutil "github.com/filecoin-project/venus/venus-shared/blockstore"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+type closableMem struct {
+	blockstoreutil.MemBlockstore
+}
+
+func (closableMem) Close() error { return nil }
+
+type fakeChain struct {
+	head *types.TipSet
+	live []cid.Cid
+	// state and receipts are the roots computed from head
+	state, receipts cid.Cid
+	pruneRoots      []cid.Cid
+	// onWalk is called before the live objects are reported
+	onWalk func()
+}
+
+func (fc *fakeChain) GetHead() *types.TipSet { return fc.head }
+
+func (fc *fakeChain) GetTipSet(context.Context, types.TipSetKey) (*types.TipSet, error) {
+	return fc.head, nil
+}
+
+func (fc *fakeChain) WalkSnapshot(_ context.Context, _ *types.TipSet, _ abi.ChainEpoch, _, _ bool, cb func(cid.Cid) error) error {
+	if fc.onWalk != nil {
+		fc.onWalk()
+	}
+	for _, c := range fc.live {
+		if err := cb(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fc *fakeChain) GetTipSetStateRoot(context.Context, *types.TipSet) (cid.Cid, error) {
+	if !fc.state.Defined() {
+		return cid.Undef, errors.New("tipset not executed")
+	}
+	return fc.state, nil
+}
+
+func (fc *fakeChain) GetTipSetReceiptsRoot(context.Context, *types.TipSet) (cid.Cid, error) {
+	if !fc.receipts.Defined() {
+		return cid.Undef, errors.New("tipset not executed")
+	}
+	return fc.receipts, nil
+}
+
+func (fc *fakeChain) LoadPruneRoots(context.Context) ([]cid.Cid, error) {
+	return fc.pruneRoots, nil
+}
+
+func newBlock(data string) blocks.Block {
+	return blocks.NewBlock([]byte(data))
+}
+
+func newHead(t *testing.T) *types.TipSet {
+	miner, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+	c := newBlock("root").Cid()
+	ts, err := types.NewTipSet([]*types.BlockHeader{{
+		Miner:                 miner,
+		ParentStateRoot:       c,
+		ParentMessageReceipts: c,
+		Messages:              c,
+	}})
+	require.NoError(t, err)
+	return ts
+}
+
+func newSplitStore(t *testing.T, coldStoreType string) (*SplitStore, blockstoreutil.Blockstore, blockstoreutil.Blockstore) {
+	opts := blockstoreutil.DefaultOptions("")
+	opts.InMemory = true
+	hot, err := blockstoreutil.Open(opts)
+	require.NoError(t, err)
+	cold := closableMem{blockstoreutil.NewMemory()}
+
+	cfg := &config.SplitStoreConfig{
+		ColdStoreType:              coldStoreType,
+		HotStoreFinalityRetention:  1,
+		CompactionFinalityInterval: 1,
+	}
+	ss, err := Open(hot, cold, dssync.MutexWrap(datastore.NewMapDatastore()), cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, ss.Close()) })
+
+	return ss, hot, cold
+}
+
+func waitCompaction(t *testing.T, ss *SplitStore) {
+	ss.wg.Wait()
+	info := ss.Info()
+	require.False(t, info.Compacting)
+	require.Empty(t, info.LastError)
+}
+
+func TestCompactMovesUnreachableObjects(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	for _, coldStoreType := range []string{ColdStoreUniversal, ColdStoreDiscard} {
+		t.Run(coldStoreType, func(t *testing.T) {
+			ss, hot, cold := newSplitStore(t, coldStoreType)
+
+			live, dead := newBlock("live"), newBlock("dead")
+			require.NoError(t, ss.PutMany(ctx, []blocks.Block{live, dead}))
+
+			head := newHead(t)
+			ss.Start(&fakeChain{head: head, live: []cid.Cid{live.Cid()}})
+			require.NoError(t, ss.Compact(ctx))
+			waitCompaction(t, ss)
+
+			has, err := hot.Has(ctx, live.Cid())
+			require.NoError(t, err)
+			require.True(t, has)
+
+			has, err = hot.Has(ctx, dead.Cid())
+			require.NoError(t, err)
+			require.False(t, has)
+
+			has, err = cold.Has(ctx, dead.Cid())
+			require.NoError(t, err)
+			require.Equal(t, coldStoreType == ColdStoreUniversal, has)
+
+			has, err = ss.Has(ctx, dead.Cid())
+			require.NoError(t, err)
+			require.Equal(t, coldStoreType == ColdStoreUniversal, has)
+
+			info := ss.Info()
+			require.Equal(t, head.Height(), info.BaseEpoch)
+			require.EqualValues(t, 1, info.Marked)
+		})
+	}
+}
+
+func TestCompactKeepsHeadState(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	ss, _, _ := newSplitStore(t, ColdStoreDiscard)
+	cst := cbor.NewCborStore(ss)
+
+	st, err := tree.NewState(cst, tree.StateTreeVersion5)
+	require.NoError(t, err)
+	addr, err := address.NewIDAddress(100)
+	require.NoError(t, err)
+	require.NoError(t, st.SetActor(ctx, addr, &types.Actor{Code: newBlock("code").Cid(), Head: newBlock("head").Cid()}))
+	state, err := st.Flush(ctx)
+	require.NoError(t, err)
+	receipts, err := cst.Put(ctx, &types.MessageReceipt{})
+	require.NoError(t, err)
+	pending := newBlock("pending message")
+	require.NoError(t, ss.Put(ctx, pending))
+
+	head := newHead(t)
+	ss.Start(&fakeChain{head: head, state: state, receipts: receipts, pruneRoots: []cid.Cid{pending.Cid()}})
+	require.NoError(t, ss.Compact(ctx))
+	waitCompaction(t, ss)
+
+	st, err = tree.LoadState(ctx, cst, state)
+	require.NoError(t, err)
+	_, found, err := st.GetActor(ctx, addr)
+	require.NoError(t, err)
+	require.True(t, found)
+	for _, c := range []cid.Cid{receipts, pending.Cid()} {
+		has, err := ss.Has(ctx, c)
+		require.NoError(t, err)
+		require.True(t, has)
+	}
+}
+
+func TestCompactProtectsConcurrentWrites(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	ss, hot, _ := newSplitStore(t, ColdStoreDiscard)
+
+	written := newBlock("written during compaction")
+	chain := &fakeChain{head: newHead(t)}
+	chain.onWalk = func() {
+		require.NoError(t, ss.Put(ctx, written))
+	}
+	ss.Start(chain)
+
+	require.NoError(t, ss.Compact(ctx))
+	waitCompaction(t, ss)
+
+	has, err := hot.Has(ctx, written.Cid())
+	require.NoError(t, err)
+	require.True(t, has)
+}
+
+func TestCompactOnlyOnce(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	ss, _, _ := newSplitStore(t, ColdStoreUniversal)
+	require.ErrorIs(t, ss.Compact(ctx), ErrNotStarted)
+
+	block := make(chan struct{})
+	chain := &fakeChain{head: newHead(t), onWalk: func() { <-block }}
+	ss.Start(chain)
+
+	require.NoError(t, ss.Compact(ctx))
+	require.ErrorIs(t, ss.Compact(ctx), ErrCompacting)
+	close(block)
+	waitCompaction(t, ss)
+}
+
+func TestCompactCancelled(t *testing.T) {
+	tf.UnitTest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ss, hot, _ := newSplitStore(t, ColdStoreDiscard)
+
+	live, dead := newBlock("live"), newBlock("dead")
+	require.NoError(t, ss.PutMany(ctx, []blocks.Block{live, dead}))
+	ss.Start(&fakeChain{head: newHead(t), live: []cid.Cid{live.Cid()}, onWalk: cancel})
+
+	require.NoError(t, ss.Compact(ctx))
+	ss.wg.Wait()
+
+	info := ss.Info()
+	require.False(t, info.Compacting)
+	require.Contains(t, info.LastError, context.Canceled.Error())
+	require.Zero(t, info.BaseEpoch)
+
+	has, err := hot.Has(context.Background(), dead.Cid())
+	require.NoError(t, err)
+	require.True(t, has)
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 
 	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-hamt-ipld/v3"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/network"
 
@@ -605,10 +606,7 @@ func (st *State) At(root Root) error {
 func Diff(oldTree, newTree *State) (map[string]types.Actor, error) {
 	out := map[string]types.Actor{}
 
-	var (
-		ncval, ocval cbg.Deferred
-		buf          = bytes.NewReader(nil)
-	)
+	var ncval, ocval cbg.Deferred
 	if err := newTree.root.ForEach(&ncval, func(k string) error {
 		addr, err := address.NewFromBytes([]byte(k))
 		if err != nil {
@@ -623,35 +621,141 @@ func Diff(oldTree, newTree *State) (map[string]types.Actor, error) {
 		if found && bytes.Equal(ocval.Raw, ncval.Raw) {
 			return nil // not changed
 		}
-		if newTree.version <= StateTreeVersion4 {
-			var act types.ActorV4
+		act, err := decodeActor(newTree.version, ncval.Raw)
+		if err != nil {
+			return err
+		}
+		out[addr.String()] = *act
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
-			buf.Reset(ncval.Raw)
-			err = act.UnmarshalCBOR(buf)
-			buf.Reset(nil)
+// ActorChange is an actor whose entry differs between two state trees. From is nil when the
+// actor was added, and To when it was removed.
+type ActorChange struct {
+	Address address.Address
+	From    *types.Actor
+	To      *types.Actor
+}
 
-			if err != nil {
-				return err
+// DiffActors returns the actors added, modified and removed from oldTree to newTree. Since
+// actors v3, the state trees are HAMTs diffed node by node, skipping the subtrees shared by
+// both trees.
+func DiffActors(ctx context.Context, oldTree, newTree *State) ([]ActorChange, error) {
+	if oldTree.version < StateTreeVersion2 || newTree.version < StateTreeVersion2 {
+		return walkDiffActors(oldTree, newTree)
+	}
+
+	oldRoot, err := oldTree.root.Root()
+	if err != nil {
+		return nil, err
+	}
+	newRoot, err := newTree.root.Root()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := hamt.Diff(ctx, oldTree.Store, newTree.Store, oldRoot, newRoot, hamt.UseTreeBitWidth(builtintypes.DefaultHamtBitwidth))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]ActorChange, 0, len(changes))
+	for _, c := range changes {
+		addr, err := address.NewFromBytes([]byte(c.Key))
+		if err != nil {
+			return nil, fmt.Errorf("address in state tree was not valid: %v", err)
+		}
+
+		change := ActorChange{Address: addr}
+		if c.Before != nil {
+			if change.From, err = decodeActor(oldTree.version, c.Before.Raw); err != nil {
+				return nil, err
 			}
+		}
+		if c.After != nil {
+			if change.To, err = decodeActor(newTree.version, c.After.Raw); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, change)
+	}
+	return out, nil
+}
 
-			out[addr.String()] = *types.AsActorV5(&act)
-		} else {
-			var act types.Actor
+// walkDiffActors diffs the state trees by walking all their actors.
+func walkDiffActors(oldTree, newTree *State) ([]ActorChange, error) {
+	var out []ActorChange
+
+	var ncval, ocval cbg.Deferred
+	if err := newTree.root.ForEach(&ncval, func(k string) error {
+		addr, err := address.NewFromBytes([]byte(k))
+		if err != nil {
+			return fmt.Errorf("address in state tree was not valid: %v", err)
+		}
 
-			buf.Reset(ncval.Raw)
-			err = act.UnmarshalCBOR(buf)
-			buf.Reset(nil)
+		found, err := oldTree.root.Get(abi.AddrKey(addr), &ocval)
+		if err != nil {
+			return err
+		}
+		if found && bytes.Equal(ocval.Raw, ncval.Raw) {
+			return nil // not changed
+		}
 
-			if err != nil {
+		change := ActorChange{Address: addr}
+		if change.To, err = decodeActor(newTree.version, ncval.Raw); err != nil {
+			return err
+		}
+		if found {
+			if change.From, err = decodeActor(oldTree.version, ocval.Raw); err != nil {
 				return err
 			}
+		}
+		out = append(out, change)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
 
-			out[addr.String()] = act
+	if err := oldTree.root.ForEach(&ocval, func(k string) error {
+		addr, err := address.NewFromBytes([]byte(k))
+		if err != nil {
+			return fmt.Errorf("address in state tree was not valid: %v", err)
+		}
+
+		found, err := newTree.root.Get(abi.AddrKey(addr), &ncval)
+		if err != nil || found {
+			return err
 		}
 
+		change := ActorChange{Address: addr}
+		if change.From, err = decodeActor(oldTree.version, ocval.Raw); err != nil {
+			return err
+		}
+		out = append(out, change)
 		return nil
 	}); err != nil {
 		return nil, err
 	}
+
 	return out, nil
 }
+
+func decodeActor(version StateTreeVersion, raw []byte) (*types.Actor, error) {
+	if version <= StateTreeVersion4 {
+		var act types.ActorV4
+		if err := act.UnmarshalCBOR(bytes.NewReader(raw)); err != nil {
+			return nil, err
+		}
+		return types.AsActorV5(&act), nil
+	}
+
+	var act types.Actor
+	if err := act.UnmarshalCBOR(bytes.NewReader(raw)); err != nil {
+		return nil, err
+	}
+	return &act, nil
+}
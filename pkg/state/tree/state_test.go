@@ -3,6 +3,7 @@ package tree
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/filecoin-project/go-address"
@@ -234,3 +235,76 @@ func TestStateTreeConsistency(t *testing.T) {
 		t.Fatalf("state state Mismatch. Expected: bafy2bzaceamis23jp44ofm4fh6jwc4gkxlzhnvxrdw4zsn3v2fj6at6pf2m4y Actual: %s", root.String())
 	}
 }
+
+func TestDiffActors(t *testing.T) {
+	tf.UnitTest(t)
+
+	// the state trees before actors v3 are walked, the later ones are diffed as HAMTs
+	for _, version := range []StateTreeVersion{StateTreeVersion1, StateTreeVersion4, StateTreeVersion5} {
+		t.Run(fmt.Sprintf("version %d", version), func(t *testing.T) {
+			testDiffActors(t, version)
+		})
+	}
+}
+
+func testDiffActors(t *testing.T, version StateTreeVersion) {
+	ctx := context.Background()
+
+	bs := repo.NewInMemoryRepo().Datastore()
+	cst := cbor.NewCborStore(bs)
+	tree, err := NewStateAtVersionWithBuiltinActor(t, cst, version)
+	require.NoError(t, err)
+
+	addrGetter := testhelpers.NewForTestGetter()
+	addr1 := addrGetter()
+	addr2 := addrGetter()
+	addr3 := addrGetter()
+	AddAccount(t, tree, cst, addr1)
+	AddAccount(t, tree, cst, addr2)
+	oldRoot, err := tree.Flush(ctx)
+	require.NoError(t, err)
+
+	UpdateAccount(t, tree, addr1, func(act *types.Actor) {
+		act.IncrementSeqNum()
+	})
+	require.NoError(t, tree.DeleteActor(ctx, addr2))
+	AddAccount(t, tree, cst, addr3)
+	newRoot, err := tree.Flush(ctx)
+	require.NoError(t, err)
+
+	oldTree, err := LoadState(ctx, cst, oldRoot)
+	require.NoError(t, err)
+	newTree, err := LoadState(ctx, cst, newRoot)
+	require.NoError(t, err)
+
+	changes, err := DiffActors(ctx, oldTree, newTree)
+	require.NoError(t, err)
+
+	byAddr := make(map[address.Address]ActorChange)
+	for _, c := range changes {
+		byAddr[c.Address] = c
+	}
+	// the init actor changes too, as it registers the new account
+	idOf := func(st *State, addr address.Address) address.Address {
+		id, err := st.LookupID(addr)
+		require.NoError(t, err)
+		return id
+	}
+	modified := byAddr[idOf(newTree, addr1)]
+	require.NotNil(t, modified.From)
+	require.NotNil(t, modified.To)
+	assert.Equal(t, modified.From.Nonce+1, modified.To.Nonce)
+
+	removed := byAddr[idOf(oldTree, addr2)]
+	assert.NotNil(t, removed.From)
+	assert.Nil(t, removed.To)
+
+	added := byAddr[idOf(newTree, addr3)]
+	assert.Nil(t, added.From)
+	assert.NotNil(t, added.To)
+
+	// no change between identical trees
+	changes, err = DiffActors(ctx, newTree, newTree)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
@@ -35,8 +35,13 @@ func NewFromString(t *testing.T, s string, store cbor.IpldStore) *State {
 }
 
 func NewStateWithBuiltinActor(t *testing.T, store cbor.IpldStore, ver StateTreeVersion) (*State, error) {
+	return NewStateAtVersionWithBuiltinActor(t, store, StateTreeVersion0)
+}
+
+// NewStateAtVersionWithBuiltinActor returns a state tree of version ver holding the init actor.
+func NewStateAtVersionWithBuiltinActor(t *testing.T, store cbor.IpldStore, ver StateTreeVersion) (*State, error) {
 	ctx := context.TODO()
-	tree, err := NewState(store, StateTreeVersion0)
+	tree, err := NewState(store, ver)
 	require.NoError(t, err)
 	adtStore := &AdtStore{store}
 
@@ -217,32 +217,9 @@ func (s *Stmgr) callInternal(ctx context.Context, msg *types.Message, priorMsgs
 	var ret *vm.Ret
 	var gasInfo types.MsgGasCost
 	if checkGas {
-		fromKey, err := s.ResolveToDeterministicAddress(ctx, msg.From, ts)
+		msgApply, err := s.withFakeSignature(ctx, msg, ts)
 		if err != nil {
-			return nil, fmt.Errorf("could not resolve key: %w", err)
-		}
-
-		var msgApply types.ChainMsg
-
-		switch fromKey.Protocol() {
-		case address.BLS:
-			msgApply = msg
-		case address.SECP256K1:
-			msgApply = &types.SignedMessage{
-				Message: *msg,
-				Signature: crypto.Signature{
-					Type: crypto.SigTypeSecp256k1,
-					Data: make([]byte, 65),
-				},
-			}
-		case address.Delegated:
-			msgApply = &types.SignedMessage{
-				Message: *msg,
-				Signature: crypto.Signature{
-					Type: crypto.SigTypeDelegated,
-					Data: make([]byte, 65),
-				},
-			}
+			return nil, err
 		}
 
 		ret, err = vmi.ApplyMessage(ctx, msgApply)
@@ -272,3 +249,38 @@ func (s *Stmgr) callInternal(ctx context.Context, msg *types.Message, priorMsgs
 		Duration:       ret.Duration,
 	}, err
 }
+
+// withFakeSignature wraps msg with an empty signature of the type of its sender, so that applying
+// it charges the gas of the signature.
+func (s *Stmgr) withFakeSignature(ctx context.Context, msg *types.Message, ts *types.TipSet) (types.ChainMsg, error) {
+	fromKey, err := s.ResolveToDeterministicAddress(ctx, msg.From, ts)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve key: %w", err)
+	}
+	return fakeSigned(msg, fromKey), nil
+}
+
+// fakeSigned wraps msg in a signed message with an empty signature matching the type of the
+// key fromKey of its sender, when its sender is not a BLS account.
+func fakeSigned(msg *types.Message, fromKey address.Address) types.ChainMsg {
+	switch fromKey.Protocol() {
+	case address.SECP256K1:
+		return &types.SignedMessage{
+			Message: *msg,
+			Signature: crypto.Signature{
+				Type: crypto.SigTypeSecp256k1,
+				Data: make([]byte, 65),
+			},
+		}
+	case address.Delegated:
+		return &types.SignedMessage{
+			Message: *msg,
+			Signature: crypto.Signature{
+				Type: crypto.SigTypeDelegated,
+				Data: make([]byte, 65),
+			},
+		}
+	default:
+		return msg
+	}
+}
@@ -0,0 +1,180 @@
+package statemanger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"go.opencensus.io/trace"
+
+	"github.com/filecoin-project/venus/pkg/chain"
+	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/pkg/fvm"
+	"github.com/filecoin-project/venus/pkg/state/tree"
+	"github.com/filecoin-project/venus/pkg/vm"
+	"github.com/filecoin-project/venus/pkg/vm/vmcontext"
+	blockstoreutil "github.com/filecoin-project/venus/venus-shared/blockstore"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// SimulateBundle applies the unsigned msgs in order on top of the state computed by ts, as a
+// block mined on ts at the next height would, without persisting anything. The nonce of each
+// message is read from the state of its sender as the bundle is applied, the missing gas limits
+// default to the block gas limit and the missing fee caps to the base fee of the next height.
+func (s *Stmgr) SimulateBundle(ctx context.Context, msgs []*types.Message, ts *types.TipSet, opts types.SimulateBundleOpts) (*types.SimulateBundleResult, error) {
+	ctx, span := trace.StartSpan(ctx, "statemanager.SimulateBundle")
+	defer span.End()
+
+	if ts == nil {
+		ts = s.cs.GetHead()
+	}
+
+	base, _, err := s.RunStateTransition(ctx, ts, nil, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute base state: %w", err)
+	}
+	// Technically, the tipset we're passing in here should be ts+1, but that may not exist.
+	base, err = s.fork.HandleStateForks(ctx, base, ts.Height(), ts)
+	if err != nil {
+		return nil, fmt.Errorf("error handling state forks: %w", err)
+	}
+
+	height := ts.Height() + 1
+	baseFee, err := s.ms.ComputeBaseFee(ctx, ts, s.fork.GetForkUpgrade())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute base fee: %w", err)
+	}
+	timestamp, err := s.nextTimestamp(ctx, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	random := chain.NewChainRandomnessSource(s.cs, ts.Key(), s.beacon, s.GetNetworkVersion)
+	buffStore := blockstoreutil.NewTieredBstore(s.cs.Blockstore(), blockstoreutil.NewTemporarySync())
+	vmopt := vm.VmOption{
+		CircSupplyCalculator: func(ctx context.Context, epoch abi.ChainEpoch, tree tree.Tree) (abi.TokenAmount, error) {
+			cs, err := s.cs.GetCirculatingSupplyDetailed(ctx, epoch, tree)
+			if err != nil {
+				return abi.TokenAmount{}, err
+			}
+			return cs.FilCirculating, nil
+		},
+		PRoot:               base,
+		Epoch:               height,
+		Timestamp:           timestamp,
+		Rnd:                 random,
+		Bsstore:             buffStore,
+		SysCallsImpl:        s.syscallsImpl,
+		GasPriceSchedule:    s.gasSchedule,
+		NetworkVersion:      s.GetNetworkVersion(ctx, height),
+		BaseFee:             baseFee,
+		Fork:                s.fork,
+		LookbackStateGetter: vmcontext.LookbackStateGetterForTipset(ctx, s.cs, s.fork, ts),
+		TipSetGetter:        vmcontext.TipSetGetterForTipset(s.cs.GetTipSetByHeight, ts),
+		Tracing:             true,
+		ReturnEvents:        true,
+		ActorDebugging:      s.actorDebugging,
+	}
+	vmi, err := fvm.NewVM(ctx, vmopt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up vm: %w", err)
+	}
+
+	return simulateBundle(ctx, vmi, buffStore, msgs, baseFee, opts)
+}
+
+// simulateBundle applies msgs in order with vmi, whose state is held by bs, reading the nonce
+// and the key of the sender of each message from the state left by the previous ones.
+func simulateBundle(ctx context.Context, vmi vm.Interface, bs blockstoreutil.Blockstore, msgs []*types.Message, baseFee abi.TokenAmount, opts types.SimulateBundleOpts) (*types.SimulateBundleResult, error) {
+	cst := cbor.NewCborStore(bs)
+	res := &types.SimulateBundleResult{
+		Results: make([]*types.SimulatedMessage, 0, len(msgs)),
+	}
+	for i, m := range msgs {
+		// Copy the message as we fill its nonce and gas.
+		msg := *m
+		if msg.GasLimit == 0 {
+			msg.GasLimit = constants.BlockGasLimit
+		}
+		if msg.GasFeeCap == types.EmptyInt {
+			msg.GasFeeCap = baseFee
+		}
+		if msg.GasPremium == types.EmptyInt {
+			msg.GasPremium = types.NewInt(0)
+		}
+		if msg.Value == types.EmptyInt {
+			msg.Value = types.NewInt(0)
+		}
+
+		// We flush to get the VM's view of the state tree after applying the previous messages,
+		// the sender may have been created or its nonce bumped by them.
+		root, err := vmi.Flush(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("flushing vm: %w", err)
+		}
+		st, err := tree.LoadState(ctx, cst, root)
+		if err != nil {
+			return nil, fmt.Errorf("loading state: %w", err)
+		}
+		act, found, err := st.GetActor(ctx, msg.From)
+		if err != nil {
+			return nil, fmt.Errorf("loading the sender of message %d: %w", i, err)
+		}
+		if !found {
+			return nil, fmt.Errorf("sender %s of message %d not found", msg.From, i)
+		}
+		msg.Nonce = act.Nonce
+
+		fromKey, err := vmcontext.ResolveToDeterministicAddress(ctx, st, msg.From, cst)
+		if err != nil {
+			return nil, fmt.Errorf("resolving the key of the sender of message %d: %w", i, err)
+		}
+		ret, err := vmi.ApplyMessage(ctx, fakeSigned(&msg, fromKey))
+		if err != nil {
+			return nil, fmt.Errorf("applying message %d: %w", i, err)
+		}
+
+		sm := &types.SimulatedMessage{
+			InvocResult: types.InvocResult{
+				MsgCid:         msg.Cid(),
+				Msg:            &msg,
+				MsgRct:         &ret.Receipt,
+				ExecutionTrace: ret.GasTracker.ExecutionTrace,
+				Duration:       ret.Duration,
+			},
+			Events: ret.Events,
+		}
+		if ret.ActorErr != nil {
+			sm.Error = ret.ActorErr.Error()
+		}
+		if !ret.OutPuts.Refund.Nil() {
+			sm.GasCost = MakeMsgGasCost(&msg, ret)
+		}
+		res.Results = append(res.Results, sm)
+	}
+
+	if opts.ReturnState {
+		root, err := vmi.Flush(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("flushing vm: %w", err)
+		}
+		res.Root = root
+	}
+
+	return res, nil
+}
+
+// nextTimestamp returns the timestamp of the blocks mined on ts at the next height, the blocks
+// being mined at a fixed delay from the genesis.
+func (s *Stmgr) nextTimestamp(ctx context.Context, ts *types.TipSet) (uint64, error) {
+	genesis, err := s.cs.GetGenesisBlock(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get genesis block: %w", err)
+	}
+	if ts.Height() == 0 {
+		return ts.MinTimestamp(), nil
+	}
+	blockDelay := (ts.MinTimestamp() - genesis.Timestamp) / uint64(ts.Height())
+	return ts.MinTimestamp() + blockDelay, nil
+}
@@ -0,0 +1,123 @@
+package statemanger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	actorstypes "github.com/filecoin-project/go-state-types/actors"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus/pkg/chain"
+	"github.com/filecoin-project/venus/pkg/state/tree"
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	"github.com/filecoin-project/venus/pkg/vm"
+	"github.com/filecoin-project/venus/pkg/vm/gas"
+	"github.com/filecoin-project/venus/venus-shared/actors/adt"
+	"github.com/filecoin-project/venus/venus-shared/actors/builtin"
+	accountactor "github.com/filecoin-project/venus/venus-shared/actors/builtin/account"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// fakeBundleVM applies the messages to a state tree: a message is refused when its nonce is not
+// the one of its sender, otherwise it bumps the nonce of its sender and creates its recipient
+// when it doesn't exist yet.
+type fakeBundleVM struct {
+	t       *testing.T
+	st      *tree.State
+	applied []types.ChainMsg
+}
+
+func (vmi *fakeBundleVM) ApplyMessage(ctx context.Context, cmsg types.ChainMsg) (*vm.Ret, error) {
+	vmi.applied = append(vmi.applied, cmsg)
+	msg := cmsg.VMMessage()
+	ret := &vm.Ret{GasTracker: gas.NewGasTracker(msg.GasLimit)}
+
+	from, found, err := vmi.st.GetActor(ctx, msg.From)
+	require.NoError(vmi.t, err)
+	if !found || from.Nonce != msg.Nonce {
+		ret.Receipt.ExitCode = exitcode.SysErrSenderStateInvalid
+		return ret, nil
+	}
+	from.Nonce++
+	require.NoError(vmi.t, vmi.st.SetActor(ctx, msg.From, from))
+
+	if _, found, err := vmi.st.GetActor(ctx, msg.To); err != nil || !found {
+		id, err := vmi.st.RegisterNewAddress(msg.To)
+		require.NoError(vmi.t, err)
+		require.NoError(vmi.t, vmi.st.SetActor(ctx, id, &types.Actor{Code: from.Code, Head: from.Head, Balance: msg.Value}))
+	}
+	return ret, nil
+}
+
+func (vmi *fakeBundleVM) ApplyImplicitMessage(ctx context.Context, msg types.ChainMsg) (*vm.Ret, error) {
+	return vmi.ApplyMessage(ctx, msg)
+}
+
+func (vmi *fakeBundleVM) Flush(ctx context.Context) (cid.Cid, error) {
+	return vmi.st.Flush(ctx)
+}
+
+func TestSimulateBundleNonces(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	builder := chain.NewBuilder(t, address.Undef)
+	st, err := tree.LoadState(ctx, builder.Cstore(), builder.Genesis().At(0).ParentStateRoot)
+	require.NoError(t, err)
+	account, found, err := st.GetActor(ctx, builtin.BurntFundsActorAddr)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	newAddr := func(seed string) address.Address {
+		addr, err := address.NewSecp256k1Address([]byte(seed))
+		require.NoError(t, err)
+		return addr
+	}
+	alice, bob, carol := newAddr("alice"), newAddr("bob"), newAddr("carol")
+	aliceID, err := st.RegisterNewAddress(alice)
+	require.NoError(t, err)
+	aliceState, err := accountactor.MakeState(adt.WrapStore(ctx, builder.Cstore()), actorstypes.Version0, alice)
+	require.NoError(t, err)
+	aliceHead, err := builder.Cstore().Put(ctx, aliceState)
+	require.NoError(t, err)
+	require.NoError(t, st.SetActor(ctx, aliceID, &types.Actor{Code: account.Code, Head: aliceHead, Balance: abi.NewTokenAmount(100), Nonce: 5}))
+
+	send := func(from, to address.Address) *types.Message {
+		return &types.Message{From: from, To: to, Method: 0, GasLimit: 0, Value: types.EmptyInt, GasFeeCap: types.EmptyInt, GasPremium: types.EmptyInt}
+	}
+	vmi := &fakeBundleVM{t: t, st: st}
+	res, err := simulateBundle(ctx, vmi, builder.BlockStore(), []*types.Message{
+		send(alice, bob),
+		// bob only exists once the first message is applied
+		send(bob, carol),
+		send(alice, carol),
+		// alice again, through her id address
+		send(aliceID, bob),
+	}, abi.NewTokenAmount(1), types.SimulateBundleOpts{ReturnState: true})
+	require.NoError(t, err)
+
+	require.Len(t, res.Results, 4)
+	for i, expected := range []uint64{5, 0, 6, 7} {
+		require.Equal(t, expected, res.Results[i].Msg.Nonce, "message %d", i)
+		require.Equal(t, exitcode.Ok, res.Results[i].MsgRct.ExitCode, "message %d", i)
+		require.Equal(t, abi.NewTokenAmount(1), res.Results[i].Msg.GasFeeCap)
+		// the messages of the secp senders are applied with a fake signature
+		_, signed := vmi.applied[i].(*types.SignedMessage)
+		require.True(t, signed, "message %d", i)
+	}
+
+	after, err := tree.LoadState(ctx, builder.Cstore(), res.Root)
+	require.NoError(t, err)
+	act, found, err := after.GetActor(ctx, alice)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, uint64(8), act.Nonce)
+
+	// a sender which is not created by the bundle doesn't exist
+	_, err = simulateBundle(ctx, vmi, builder.BlockStore(), []*types.Message{send(newAddr("dave"), alice)}, abi.NewTokenAmount(1), types.SimulateBundleOpts{})
+	require.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,54 @@
+package statemanger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// VerifyStateRoots recomputes the states of the count tipsets below head through RunStateTransition,
+// and checks them against the parent states and receipts their children hold, as they are taken on
+// trust when a snapshot is imported. The verification resumes after the first verified tipsets,
+// checked by a previous run. progress is called with the number of states verified so far and the
+// last tipset.
+func (s *Stmgr) VerifyStateRoots(ctx context.Context, head *types.TipSet, verified, count int, progress func(verified int, ts *types.TipSet)) error {
+	child := head
+	for skipped := 0; skipped < verified && child.Height() > 0; skipped++ {
+		ts, err := s.cs.GetTipSet(ctx, child.Parents())
+		if err != nil {
+			return fmt.Errorf("loading tipset %s: %w", child.Parents(), err)
+		}
+		child = ts
+	}
+
+	for verified < count && child.Height() > 0 {
+		ts, err := s.cs.GetTipSet(ctx, child.Parents())
+		if err != nil {
+			return fmt.Errorf("loading tipset %s: %w", child.Parents(), err)
+		}
+
+		// drop the state recorded by the import, so that it is computed
+		if err := s.cs.DeleteTipSetMetadata(ctx, ts); err != nil {
+			return fmt.Errorf("deleting the state of %d: %w", ts.Height(), err)
+		}
+		root, receipts, err := s.RunStateTransition(ctx, ts, nil, false)
+		if err != nil {
+			return fmt.Errorf("computing the state of %d: %w", ts.Height(), err)
+		}
+		if root != child.ParentState() {
+			return fmt.Errorf("state of %d is %s but %d has parent state %s", ts.Height(), root, child.Height(), child.ParentState())
+		}
+		if receipts != child.Blocks()[0].ParentMessageReceipts {
+			return fmt.Errorf("receipts of %d are %s but %d has parent receipts %s", ts.Height(), receipts, child.Height(), child.Blocks()[0].ParentMessageReceipts)
+		}
+
+		verified++
+		if progress != nil {
+			progress(verified, ts)
+		}
+		child = ts
+	}
+
+	return nil
+}
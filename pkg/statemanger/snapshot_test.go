@@ -0,0 +1,59 @@
+package statemanger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/venus/pkg/chain"
+	"github.com/filecoin-project/venus/pkg/consensus"
+	tf "github.com/filecoin-project/venus/pkg/testhelpers/testflags"
+	"github.com/filecoin-project/venus/pkg/vm"
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+// recordingTransformer records the heights of the tipsets it computes the state of.
+type recordingTransformer struct {
+	consensus.StateTransformer
+	heights []abi.ChainEpoch
+}
+
+func (rt *recordingTransformer) RunStateTransition(ctx context.Context, ts *types.TipSet, cb vm.ExecCallBack, vmTracing bool) (cid.Cid, cid.Cid, error) {
+	rt.heights = append(rt.heights, ts.Height())
+	return rt.StateTransformer.RunStateTransition(ctx, ts, cb, vmTracing)
+}
+
+func TestVerifyStateRootsResumes(t *testing.T) {
+	tf.UnitTest(t)
+	ctx := context.Background()
+
+	builder := chain.NewBuilder(t, address.Undef)
+	head := builder.AppendManyOn(ctx, 6, builder.Genesis())
+	rt := &recordingTransformer{StateTransformer: builder.FakeStateEvaluator()}
+	stmgr, err := NewStateManager(builder.Store(), builder.MessageStore(), rt, nil, nil, nil, nil, false)
+	require.NoError(t, err)
+
+	var progress []int
+	record := func(verified int, ts *types.TipSet) {
+		progress = append(progress, verified)
+	}
+	require.NoError(t, stmgr.VerifyStateRoots(ctx, head, 0, 2, record))
+	require.Equal(t, []int{1, 2}, progress)
+	require.Equal(t, []abi.ChainEpoch{5, 4}, rt.heights)
+
+	// the tipsets verified by the previous run are not computed again
+	progress, rt.heights = nil, nil
+	require.NoError(t, stmgr.VerifyStateRoots(ctx, head, 2, 4, record))
+	require.Equal(t, []int{3, 4}, progress)
+	require.Equal(t, []abi.ChainEpoch{3, 2}, rt.heights)
+
+	// the verification stops at the genesis, whose state is not computed
+	progress, rt.heights = nil, nil
+	require.NoError(t, stmgr.VerifyStateRoots(ctx, head, 4, 10, record))
+	require.Equal(t, []int{5, 6}, progress)
+	require.Equal(t, []abi.ChainEpoch{1}, rt.heights)
+}
@@ -484,6 +484,104 @@ func (s *Stmgr) Replay(ctx context.Context, ts *types.TipSet, msgCID cid.Cid) (*
 	return outm, outr, nil
 }
 
+// MessageStates is the execution of a message replayed by ReplayStates.
+type MessageStates struct {
+	Msg *types.Message
+	Ret *vm.Ret
+	// PreStateRoot and PostStateRoot are the state roots right before and right after
+	// the execution of the message, both are held by Store.
+	PreStateRoot  cid.Cid
+	PostStateRoot cid.Cid
+	Store         blockstoreutil.Blockstore
+}
+
+// ReplayStates applies in order the messages of ts up to the message msgCID on top of the
+// parent state of ts, and returns the state before and after the execution of the message.
+// As with CallWithGas, the block rewards paid between the blocks of ts and the cron of the
+// null rounds preceding ts are not applied, which only affects the miner actors.
+func (s *Stmgr) ReplayStates(ctx context.Context, ts *types.TipSet, msgCID cid.Cid) (*MessageStates, error) {
+	tsMsgs, err := s.ms.MessagesForTipset(ts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup messages for tipset: %w", err)
+	}
+
+	// Technically, the tipset we're passing in here should be ts+1, but that may not exist.
+	stateCid, err := s.fork.HandleStateForks(ctx, ts.ParentState(), ts.Height(), ts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to handle fork: %w", err)
+	}
+
+	random := chain.NewChainRandomnessSource(s.cs, ts.Key(), s.beacon, s.GetNetworkVersion)
+	buffStore := blockstoreutil.NewTieredBstore(s.cs.Blockstore(), blockstoreutil.NewTemporarySync())
+	vmopt := vm.VmOption{
+		CircSupplyCalculator: func(ctx context.Context, epoch abi.ChainEpoch, tree tree.Tree) (abi.TokenAmount, error) {
+			cs, err := s.cs.GetCirculatingSupplyDetailed(ctx, epoch, tree)
+			if err != nil {
+				return abi.TokenAmount{}, err
+			}
+			return cs.FilCirculating, nil
+		},
+		PRoot:               stateCid,
+		Epoch:               ts.Height(),
+		Timestamp:           ts.MinTimestamp(),
+		Rnd:                 random,
+		Bsstore:             buffStore,
+		SysCallsImpl:        s.syscallsImpl,
+		GasPriceSchedule:    s.gasSchedule,
+		NetworkVersion:      s.GetNetworkVersion(ctx, ts.Height()),
+		BaseFee:             ts.Blocks()[0].ParentBaseFee,
+		Fork:                s.fork,
+		LookbackStateGetter: vmcontext.LookbackStateGetterForTipset(ctx, s.cs, s.fork, ts),
+		TipSetGetter:        vmcontext.TipSetGetterForTipset(s.cs.GetTipSetByHeight, ts),
+		Tracing:             true,
+		ActorDebugging:      s.actorDebugging,
+	}
+	vmi, err := fvm.NewVM(ctx, vmopt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up vm: %w", err)
+	}
+
+	// the same message could have been included by more than one miner
+	seenMsgs := make(map[cid.Cid]struct{})
+	for _, m := range tsMsgs {
+		mcid := m.VMMessage().Cid()
+		if _, found := seenMsgs[mcid]; found {
+			continue
+		}
+		seenMsgs[mcid] = struct{}{}
+
+		if !msgCID.Equals(m.Cid()) && !msgCID.Equals(mcid) {
+			if _, err := vmi.ApplyMessage(ctx, m); err != nil {
+				return nil, fmt.Errorf("applying message %s: %w", m.Cid(), err)
+			}
+			continue
+		}
+
+		pre, err := vmi.Flush(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("flushing vm: %w", err)
+		}
+		ret, err := vmi.ApplyMessage(ctx, m)
+		if err != nil {
+			return nil, fmt.Errorf("applying message %s: %w", m.Cid(), err)
+		}
+		post, err := vmi.Flush(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("flushing vm: %w", err)
+		}
+
+		return &MessageStates{
+			Msg:           m.VMMessage(),
+			Ret:           ret,
+			PreStateRoot:  pre,
+			PostStateRoot: post,
+			Store:         buffStore,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("given message not found in tipset")
+}
+
 func (s *Stmgr) ExecutionTrace(ctx context.Context, ts *types.TipSet) (cid.Cid, []*types.InvocResult, error) {
 
 	tsKey := ts.Key()
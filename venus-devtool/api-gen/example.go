@@ -123,6 +123,7 @@ func init() {
 	addExample(abi.SectorNumber(9))
 	addExample(abi.SectorSize(32 * 1024 * 1024 * 1024))
 	addExample(types.MpoolChange(0))
+	addExample(types.MpoolRemoveReplaced)
 	addExample(network.Connected)
 	addExample(types.NetworkName("mainnet"))
 	addExample(types.SyncStateStage(1))
@@ -221,6 +222,8 @@ func init() {
 	addExample(types.CheckStatusCode(0))
 	addExample(map[string]interface{}{"abc": 123})
 	addExample(types.HCApply)
+	addExample(types.SnapshotImporting)
+	addExample(types.DisputeSucceeded)
 
 	// messager
 	i64 := int64(10000)
@@ -90,4 +90,5 @@ type State interface {
 	GetBytecode() ([]byte, error)
 	GetBytecodeCID() (cid.Cid, error)
 	GetBytecodeHash() ([32]byte, error)
+	GetContractStateCID() (cid.Cid, error)
 }
@@ -59,6 +59,10 @@ func (s *state10) GetBytecodeHash() ([32]byte, error) {
 	return s.State.BytecodeHash, nil
 }
 
+func (s *state10) GetContractStateCID() (cid.Cid, error) {
+	return s.State.ContractState, nil
+}
+
 func (s *state10) GetBytecode() ([]byte, error) {
 	bc, err := s.GetBytecodeCID()
 	if err != nil {
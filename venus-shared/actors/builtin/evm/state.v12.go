@@ -59,6 +59,10 @@ func (s *state12) GetBytecodeHash() ([32]byte, error) {
 	return s.State.BytecodeHash, nil
 }
 
+func (s *state12) GetContractStateCID() (cid.Cid, error) {
+	return s.State.ContractState, nil
+}
+
 func (s *state12) GetBytecode() ([]byte, error) {
 	bc, err := s.GetBytecodeCID()
 	if err != nil {
@@ -1054,6 +1054,55 @@ type EthTraceBlock struct {
 	TransactionPosition int     `json:"transactionPosition"`
 }
 
+type EthTraceTransaction struct {
+	*EthTrace
+	BlockHash           EthHash `json:"blockHash"`
+	BlockNumber         int64   `json:"blockNumber"`
+	TransactionHash     EthHash `json:"transactionHash"`
+	TransactionPosition int     `json:"transactionPosition"`
+}
+
+type EthTraceFilterResult struct {
+	*EthTrace
+	BlockHash           EthHash `json:"blockHash"`
+	BlockNumber         int64   `json:"blockNumber"`
+	TransactionHash     EthHash `json:"transactionHash"`
+	TransactionPosition int     `json:"transactionPosition"`
+}
+
+// EthTraceFilterCriteria defines the criteria for filtering traces.
+type EthTraceFilterCriteria struct {
+	// Interpreted as an epoch (in hex) or one of "latest" for last mined block, "pending" for not yet committed messages.
+	// Optional, default: "latest".
+	// Note: "earliest" is not a permitted value.
+	FromBlock *string `json:"fromBlock,omitempty"`
+
+	// Interpreted as an epoch (in hex) or one of "latest" for last mined block, "pending" for not yet committed messages.
+	// Optional, default: "latest".
+	// Note: "earliest" is not a permitted value.
+	ToBlock *string `json:"toBlock,omitempty"`
+
+	// Actor address or a list of addresses from which traces should originate.
+	// Optional, default: nil.
+	// The JSON decoding must treat a string as equivalent to an array with one value, for example
+	// "0x8888f1f195afa192cfee86069858" must be decoded as [ "0x8888f1f195afa192cfee86069858" ]
+	FromAddress EthAddressList `json:"fromAddress,omitempty"`
+
+	// Actor address or a list of addresses to which traces should go.
+	// Optional, default: nil.
+	// The JSON decoding must treat a string as equivalent to an array with one value, for example
+	// "0x8888f1f195afa192cfee86069858" must be decoded as [ "0x8888f1f195afa192cfee86069858" ]
+	ToAddress EthAddressList `json:"toAddress,omitempty"`
+
+	// After specifies the offset for pagination of trace results. The number of traces to skip before returning results.
+	// Optional, default: nil.
+	After *EthUint64 `json:"after,omitempty"`
+
+	// Limits the number of traces returned.
+	// Optional, default: all traces.
+	Count *EthUint64 `json:"count,omitempty"`
+}
+
 type EthTraceReplayBlockTransaction struct {
 	Output          EthBytes    `json:"output"`
 	StateDiff       *string     `json:"stateDiff"`
@@ -1088,3 +1137,51 @@ type EthCreateTraceResult struct {
 	GasUsed EthUint64   `json:"gasUsed"`
 	Code    EthBytes    `json:"code"`
 }
+
+// EthTraceConfig selects the tracer used by debug_traceTransaction and debug_traceCall.
+type EthTraceConfig struct {
+	// Tracer is one of "callTracer" or "prestateTracer".
+	Tracer       string          `json:"tracer"`
+	TracerConfig EthTracerConfig `json:"tracerConfig"`
+}
+
+type EthTracerConfig struct {
+	// OnlyTopCall makes the callTracer skip the sub calls.
+	OnlyTopCall bool `json:"onlyTopCall,omitempty"`
+	// DiffMode makes the prestateTracer return the accounts modified by the
+	// transaction before and after its execution.
+	DiffMode bool `json:"diffMode,omitempty"`
+}
+
+// EthCallFrame is a call frame of the callTracer.
+type EthCallFrame struct {
+	Type         string          `json:"type"`
+	From         EthAddress      `json:"from"`
+	To           *EthAddress     `json:"to,omitempty"`
+	Value        EthBigInt       `json:"value"`
+	Gas          EthUint64       `json:"gas"`
+	GasUsed      EthUint64       `json:"gasUsed"`
+	Input        EthBytes        `json:"input"`
+	Output       EthBytes        `json:"output,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revertReason,omitempty"`
+	Calls        []*EthCallFrame `json:"calls,omitempty"`
+}
+
+// EthPrestateAccount is an account reported by the prestateTracer. As FVM
+// execution traces don't record the slots read by a contract, Storage only
+// holds the slots written by the traced transaction, keyed by their hex
+// encoded position.
+type EthPrestateAccount struct {
+	Balance *EthBigInt         `json:"balance,omitempty"`
+	Nonce   uint64             `json:"nonce,omitempty"`
+	Code    EthBytes           `json:"code,omitempty"`
+	Storage map[string]EthHash `json:"storage,omitempty"`
+}
+
+// EthPrestateDiff is the result of the prestateTracer in diff mode, the
+// accounts are keyed by their hex encoded address.
+type EthPrestateDiff struct {
+	Pre  map[string]*EthPrestateAccount `json:"pre"`
+	Post map[string]*EthPrestateAccount `json:"post"`
+}
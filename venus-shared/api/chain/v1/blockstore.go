@@ -15,4 +15,9 @@ type IBlockStore interface {
 	ChainStatObj(ctx context.Context, obj cid.Cid, base cid.Cid) (types.ObjStat, error) //perm:read
 	// ChainPutObj puts a given object into the block store
 	ChainPutObj(context.Context, blocks.Block) error //perm:admin
+	// ChainSplitStoreInfo returns the state of the hot/cold splitstore and the progress of its compaction,
+	// it fails if the datastore type is not splitstore
+	ChainSplitStoreInfo(ctx context.Context) (*types.SplitStoreInfo, error) //perm:read
+	// ChainSplitStoreCompact starts a splitstore compaction at the current head in the background
+	ChainSplitStoreCompact(ctx context.Context) error //perm:admin
 }
@@ -70,6 +70,14 @@ type IChainInfo interface {
 	ProtocolParameters(ctx context.Context) (*types.ProtocolParams, error)                                         //perm:read
 	ResolveToKeyAddr(ctx context.Context, addr address.Address, ts *types.TipSet) (address.Address, error)         //perm:read
 	StateNetworkName(ctx context.Context) (types.NetworkName, error)                                               //perm:read
+	// ChainNotifySince returns a channel with the path from the tipset tsk to the head, made of
+	// reverts and then applies, or a single 'current' event when tsk is the head, followed by the
+	// live head changes. It lets a client that reconnects resume from the last tipset it has seen,
+	// which must not fork off the chain more than finality behind the head.
+	ChainNotifySince(ctx context.Context, tsk types.TipSetKey) (<-chan []*types.HeadChange, error) //perm:read
+	// ChainGetTipSetState returns the state root and the receipts root after the execution of the
+	// tipset tsk, it fails when the node has not executed the tipset.
+	ChainGetTipSetState(ctx context.Context, tsk types.TipSetKey) (*types.TipSetState, error) //perm:read
 	// StateSearchMsg looks back up to limit epochs in the chain for a message, and returns its receipt and the tipset where it was executed
 	//
 	// NOTE: If a replacing message is found on chain, this method will return
@@ -106,10 +114,30 @@ type IChainInfo interface {
 	// different signature, but with all other parameters matching (source/destination,
 	// nonce, params, etc.)
 	StateWaitMsg(ctx context.Context, cid cid.Cid, confidence uint64, limit abi.ChainEpoch, allowReplaced bool) (*types.MsgLookup, error) //perm:read
-	StateNetworkVersion(ctx context.Context, tsk types.TipSetKey) (network.Version, error)                                                //perm:read
-	VerifyEntry(parent, child *types.BeaconEntry, height abi.ChainEpoch) bool                                                             //perm:read
-	ChainExport(context.Context, abi.ChainEpoch, bool, types.TipSetKey) (<-chan []byte, error)                                            //perm:read
-	ChainGetPath(ctx context.Context, from types.TipSetKey, to types.TipSetKey) ([]*types.HeadChange, error)                              //perm:read
+	// StateSearchMsgFinality is StateSearchMsg, but only returns the message once the probability that
+	// the tipset where it was executed is reorged out of the chain of from is at most reorgProbability,
+	// as given by ChainGetFinality. A zero reorgProbability uses the default of 1e-6.
+	StateSearchMsgFinality(ctx context.Context, from types.TipSetKey, msg cid.Cid, reorgProbability float64, limit abi.ChainEpoch, allowReplaced bool) (*types.MsgLookup, error) //perm:read
+	// StateWaitMsgFinality is StateWaitMsg, but waits until the probability that the tipset where the
+	// message was executed is reorged out is at most reorgProbability instead of a confidence depth.
+	// A zero reorgProbability uses the default of 1e-6.
+	StateWaitMsgFinality(ctx context.Context, cid cid.Cid, reorgProbability float64, limit abi.ChainEpoch, allowReplaced bool) (*types.MsgLookup, error) //perm:read
+	StateNetworkVersion(ctx context.Context, tsk types.TipSetKey) (network.Version, error)                                                               //perm:read
+	VerifyEntry(parent, child *types.BeaconEntry, height abi.ChainEpoch) bool                                                                            //perm:read
+	ChainExport(context.Context, abi.ChainEpoch, bool, types.TipSetKey) (<-chan []byte, error)                                                           //perm:read
+	// ChainExportRange streams a car of the tipsets from head down to the height tail, with the
+	// messages, receipts and states selected by opts. Each tipset is written after the objects it
+	// adds, so an interrupted export can be resumed from the parents of its last complete tipset
+	// with opts.SkipHeader set, and the two cars concatenated.
+	ChainExportRange(ctx context.Context, head types.TipSetKey, tail abi.ChainEpoch, opts types.ChainExportRangeOpts) (<-chan []byte, error) //perm:read
+	// ChainSnapshotImportStatus returns the progress and the verification of the last snapshot
+	// imported in the repo.
+	ChainSnapshotImportStatus(ctx context.Context) (*types.SnapshotImportStatus, error)                      //perm:read
+	ChainGetPath(ctx context.Context, from types.TipSetKey, to types.TipSetKey) ([]*types.HeadChange, error) //perm:read
+	// ChainGetFinality returns an upper bound of the probability that the tipset at the given height
+	// is reorged out of the current chain, computed from the blocks observed at each epoch, and the
+	// highest height whose reorg probability is at most threshold. A zero threshold uses the default of 1e-6.
+	ChainGetFinality(ctx context.Context, height abi.ChainEpoch, threshold float64) (*types.ChainFinalityStatus, error) //perm:read
 	// StateGetNetworkParams return current network params
 	StateGetNetworkParams(ctx context.Context) (*types.NetworkParams, error) //perm:read
 	// StateActorCodeCIDs returns the CIDs of all the builtin actors for the given network version
@@ -155,6 +183,25 @@ type IChainInfo interface {
 	// Messages in the `apply` parameter must have the correct nonces, and gas
 	// values set.
 	StateCompute(context.Context, abi.ChainEpoch, []*types.Message, types.TipSetKey) (*types.ComputeStateOutput, error) //perm:read
+	// StateSimulateBundle applies the given unsigned messages, in order, on top of the state computed
+	// by the given tipset, or the current head if not provided, as a block mined on it at the next
+	// height would, and returns the receipt, gas cost, events and execution trace of each message,
+	// without persisting anything.
+	//
+	// The nonce of each message is read from the state of its sender as the bundle is applied, so
+	// that the messages of a sender, including the ones sent by actors created earlier in the
+	// bundle, are applied in sequence. A gas limit left to zero defaults to the block gas limit, and
+	// a missing gas fee cap to the base fee of the next height.
+	//
+	// The state root after the bundle is only returned with the ReturnState option.
+	StateSimulateBundle(ctx context.Context, msgs []*types.Message, tsk types.TipSetKey, opts types.SimulateBundleOpts) (*types.SimulateBundleResult, error) //perm:read
+	// ChainPrune deletes from the blockstore every object that is not reachable from the current head,
+	// keeping all chain headers and the state, messages and receipts of the last opts.RetainState epochs.
+	// With opts.DryRun nothing is deleted and the result reports what would be freed.
+	ChainPrune(ctx context.Context, opts types.ChainPruneOpts) (*types.ChainPruneResult, error) //perm:admin
+	// ChainHotGC runs the badger value log garbage collection on the blockstore,
+	// it reclaims the disk space of the objects deleted by ChainPrune or by splitstore compaction.
+	ChainHotGC(ctx context.Context, opts types.HotGCOpts) error //perm:admin
 }
 
 type IMinerState interface {
@@ -210,13 +257,17 @@ type IMinerState interface {
 	StateMinerActiveSectors(ctx context.Context, maddr address.Address, tsk types.TipSetKey) ([]*types.SectorOnChainInfo, error)                                  //perm:read
 	StateLookupID(ctx context.Context, addr address.Address, tsk types.TipSetKey) (address.Address, error)                                                        //perm:read
 	// StateLookupRobustAddress returns the public key address of the given ID address for non-account addresses (multisig, miners etc)
-	StateLookupRobustAddress(context.Context, address.Address, types.TipSetKey) (address.Address, error)                                                     //perm:read
-	StateListMiners(ctx context.Context, tsk types.TipSetKey) ([]address.Address, error)                                                                     //perm:read
-	StateListActors(ctx context.Context, tsk types.TipSetKey) ([]address.Address, error)                                                                     //perm:read
-	StateMinerPower(ctx context.Context, addr address.Address, tsk types.TipSetKey) (*types.MinerPower, error)                                               //perm:read
-	StateMinerAvailableBalance(ctx context.Context, maddr address.Address, tsk types.TipSetKey) (big.Int, error)                                             //perm:read
-	StateSectorExpiration(ctx context.Context, maddr address.Address, sectorNumber abi.SectorNumber, tsk types.TipSetKey) (*lminer.SectorExpiration, error)  //perm:read
-	StateChangedActors(context.Context, cid.Cid, cid.Cid) (map[string]types.Actor, error)                                                                    //perm:read
+	StateLookupRobustAddress(context.Context, address.Address, types.TipSetKey) (address.Address, error)                                                    //perm:read
+	StateListMiners(ctx context.Context, tsk types.TipSetKey) ([]address.Address, error)                                                                    //perm:read
+	StateListActors(ctx context.Context, tsk types.TipSetKey) ([]address.Address, error)                                                                    //perm:read
+	StateMinerPower(ctx context.Context, addr address.Address, tsk types.TipSetKey) (*types.MinerPower, error)                                              //perm:read
+	StateMinerAvailableBalance(ctx context.Context, maddr address.Address, tsk types.TipSetKey) (big.Int, error)                                            //perm:read
+	StateSectorExpiration(ctx context.Context, maddr address.Address, sectorNumber abi.SectorNumber, tsk types.TipSetKey) (*lminer.SectorExpiration, error) //perm:read
+	StateChangedActors(context.Context, cid.Cid, cid.Cid) (map[string]types.Actor, error)                                                                   //perm:read
+	// StateDiff returns the actors added, removed and modified between the parent states of the
+	// tipsets from and to, with their balance and nonce deltas, and optionally the decoded changes
+	// of the states of the miner, market and power actors.
+	StateDiff(ctx context.Context, from, to types.TipSetKey, opts types.StateDiffOpts) (*types.StateDiff, error)                                             //perm:read
 	StateMinerSectorCount(ctx context.Context, addr address.Address, tsk types.TipSetKey) (types.MinerSectors, error)                                        //perm:read
 	StateMarketBalance(ctx context.Context, addr address.Address, tsk types.TipSetKey) (types.MarketBalance, error)                                          //perm:read
 	StateDealProviderCollateralBounds(ctx context.Context, size abi.PaddedPieceSize, verified bool, tsk types.TipSetKey) (types.DealCollateralBounds, error) //perm:read
@@ -0,0 +1,20 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/filecoin-project/venus/venus-shared/types"
+)
+
+type IDisputer interface {
+	// DisputerStart starts the window post disputer with the disputer config of the node, it keeps
+	// running across restarts of the node until it is stopped.
+	DisputerStart(ctx context.Context) error //perm:admin
+	// DisputerStop stops the window post disputer.
+	DisputerStop(ctx context.Context) error //perm:admin
+	// DisputerStatus returns the progress of the window post disputer, with the disputes it sent and
+	// the rewards it earned.
+	DisputerStatus(ctx context.Context) (*types.DisputerStatus, error) //perm:read
+	// DisputerHistory returns the DisputeWindowedPoSt messages sent by the disputer, oldest first.
+	DisputerHistory(ctx context.Context) ([]*types.DisputeRecord, error) //perm:read
+}
@@ -40,8 +40,13 @@ type IETH interface {
 	EthGetTransactionCount(ctx context.Context, sender types.EthAddress, blkParam types.EthBlockNumberOrHash) (types.EthUint64, error) //perm:read
 	EthGetTransactionReceipt(ctx context.Context, txHash types.EthHash) (*types.EthTxReceipt, error)                                   //perm:read
 	EthGetTransactionReceiptLimited(ctx context.Context, txHash types.EthHash, limit abi.ChainEpoch) (*types.EthTxReceipt, error)      //perm:read
-	EthGetTransactionByBlockHashAndIndex(ctx context.Context, blkHash types.EthHash, txIndex types.EthUint64) (types.EthTx, error)     //perm:read
-	EthGetTransactionByBlockNumberAndIndex(ctx context.Context, blkNum types.EthUint64, txIndex types.EthUint64) (types.EthTx, error)  //perm:read
+	// EthGetBlockReceipts returns a list of receipts for all transactions in a block (identified by number or hash),
+	// implementing `eth_getBlockReceipts`.
+	EthGetBlockReceipts(ctx context.Context, blkParam types.EthBlockNumberOrHash) ([]*types.EthTxReceipt, error) //perm:read
+	// EthGetBlockReceiptsLimited is EthGetBlockReceipts refusing blocks more than limit epochs behind the head.
+	EthGetBlockReceiptsLimited(ctx context.Context, blkParam types.EthBlockNumberOrHash, limit abi.ChainEpoch) ([]*types.EthTxReceipt, error) //perm:read
+	EthGetTransactionByBlockHashAndIndex(ctx context.Context, blkHash types.EthHash, txIndex types.EthUint64) (types.EthTx, error)            //perm:read
+	EthGetTransactionByBlockNumberAndIndex(ctx context.Context, blkNum types.EthUint64, txIndex types.EthUint64) (types.EthTx, error)         //perm:read
 
 	EthGetCode(ctx context.Context, address types.EthAddress, blkParam types.EthBlockNumberOrHash) (types.EthBytes, error)                               //perm:read
 	EthGetStorageAt(ctx context.Context, address types.EthAddress, position types.EthBytes, blkParam types.EthBlockNumberOrHash) (types.EthBytes, error) //perm:read
@@ -85,6 +90,14 @@ type IETH interface {
 	EthTraceBlock(ctx context.Context, blkNum string) ([]*types.EthTraceBlock, error) //perm:read
 	// Replays all transactions in a block returning the requested traces for each transaction
 	EthTraceReplayBlockTransactions(ctx context.Context, blkNum string, traceTypes []string) ([]*types.EthTraceReplayBlockTransaction, error) //perm:read
+	// Returns the traces of the given transaction (implementing `trace_transaction`)
+	EthTraceTransaction(ctx context.Context, txHash string) ([]*types.EthTraceTransaction, error) //perm:read
+	// Returns the traces matching the given filter criteria (implementing `trace_filter`)
+	EthTraceFilter(ctx context.Context, filter types.EthTraceFilterCriteria) ([]*types.EthTraceFilterResult, error) //perm:read
+	// Returns the trace of a transaction produced by the callTracer or the prestateTracer (implementing `debug_traceTransaction`)
+	EthDebugTraceTransaction(ctx context.Context, txHash string, config types.EthTraceConfig) (interface{}, error) //perm:read
+	// Returns the trace of a call executed on top of the given block (implementing `debug_traceCall`)
+	EthDebugTraceCall(ctx context.Context, tx types.EthCall, blkParam types.EthBlockNumberOrHash, config types.EthTraceConfig) (interface{}, error) //perm:read
 }
 
 type IETHEvent interface {
@@ -122,6 +135,14 @@ type IETHEvent interface {
 
 	// Unsubscribe from a websocket subscription
 	EthUnsubscribe(ctx context.Context, id types.EthSubscriptionID) (bool, error) //perm:read
+
+	// EthIndexBackfill indexes the actor events and the eth transaction hashes of the tipsets between
+	// from and to (inclusive), reading the events from the receipts of the chain. Entries already
+	// indexed are kept.
+	EthIndexBackfill(ctx context.Context, from, to abi.ChainEpoch) (*types.EthIndexBackfillResult, error) //perm:admin
+	// EthIndexValidate checks the actor events and the eth transaction hashes indexed for the tipsets
+	// between from and to (inclusive) against the chain, and repairs the inconsistencies when fix is set.
+	EthIndexValidate(ctx context.Context, from, to abi.ChainEpoch, fix bool) (*types.EthIndexValidateResult, error) //perm:admin
 }
 
 // reverse interface to the client, called after EthSubscribe
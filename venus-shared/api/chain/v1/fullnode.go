@@ -6,6 +6,7 @@ type FullNode interface {
 	IMarket
 	IMining
 	IMessagePool
+	IMultiSig
 	INetwork
 	IPaychan
 	ISyncer
@@ -13,4 +14,5 @@ type FullNode interface {
 	ICommon
 	FullETH
 	IActorEvent
+	IDisputer
 }
@@ -99,6 +99,21 @@ func (mr *MockFullNodeMockRecorder) ChainExport(arg0, arg1, arg2, arg3 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainExport", reflect.TypeOf((*MockFullNode)(nil).ChainExport), arg0, arg1, arg2, arg3)
 }
 
+// ChainExportRange mocks base method.
+func (m *MockFullNode) ChainExportRange(arg0 context.Context, arg1 types0.TipSetKey, arg2 abi.ChainEpoch, arg3 types0.ChainExportRangeOpts) (<-chan []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainExportRange", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(<-chan []byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChainExportRange indicates an expected call of ChainExportRange.
+func (mr *MockFullNodeMockRecorder) ChainExportRange(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainExportRange", reflect.TypeOf((*MockFullNode)(nil).ChainExportRange), arg0, arg1, arg2, arg3)
+}
+
 // ChainGetBlock mocks base method.
 func (m *MockFullNode) ChainGetBlock(arg0 context.Context, arg1 cid.Cid) (*types0.BlockHeader, error) {
 	m.ctrl.T.Helper()
@@ -144,6 +159,21 @@ func (mr *MockFullNodeMockRecorder) ChainGetEvents(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainGetEvents", reflect.TypeOf((*MockFullNode)(nil).ChainGetEvents), arg0, arg1)
 }
 
+// ChainGetFinality mocks base method.
+func (m *MockFullNode) ChainGetFinality(arg0 context.Context, arg1 abi.ChainEpoch, arg2 float64) (*types0.ChainFinalityStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainGetFinality", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*types0.ChainFinalityStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChainGetFinality indicates an expected call of ChainGetFinality.
+func (mr *MockFullNodeMockRecorder) ChainGetFinality(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainGetFinality", reflect.TypeOf((*MockFullNode)(nil).ChainGetFinality), arg0, arg1, arg2)
+}
+
 // ChainGetGenesis mocks base method.
 func (m *MockFullNode) ChainGetGenesis(arg0 context.Context) (*types0.TipSet, error) {
 	m.ctrl.T.Helper()
@@ -294,6 +324,21 @@ func (mr *MockFullNodeMockRecorder) ChainGetTipSetByHeight(arg0, arg1, arg2 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainGetTipSetByHeight", reflect.TypeOf((*MockFullNode)(nil).ChainGetTipSetByHeight), arg0, arg1, arg2)
 }
 
+// ChainGetTipSetState mocks base method.
+func (m *MockFullNode) ChainGetTipSetState(arg0 context.Context, arg1 types0.TipSetKey) (*types0.TipSetState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainGetTipSetState", arg0, arg1)
+	ret0, _ := ret[0].(*types0.TipSetState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChainGetTipSetState indicates an expected call of ChainGetTipSetState.
+func (mr *MockFullNodeMockRecorder) ChainGetTipSetState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainGetTipSetState", reflect.TypeOf((*MockFullNode)(nil).ChainGetTipSetState), arg0, arg1)
+}
+
 // ChainHasObj mocks base method.
 func (m *MockFullNode) ChainHasObj(arg0 context.Context, arg1 cid.Cid) (bool, error) {
 	m.ctrl.T.Helper()
@@ -324,6 +369,20 @@ func (mr *MockFullNodeMockRecorder) ChainHead(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainHead", reflect.TypeOf((*MockFullNode)(nil).ChainHead), arg0)
 }
 
+// ChainHotGC mocks base method.
+func (m *MockFullNode) ChainHotGC(arg0 context.Context, arg1 types0.HotGCOpts) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainHotGC", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChainHotGC indicates an expected call of ChainHotGC.
+func (mr *MockFullNodeMockRecorder) ChainHotGC(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainHotGC", reflect.TypeOf((*MockFullNode)(nil).ChainHotGC), arg0, arg1)
+}
+
 // ChainList mocks base method.
 func (m *MockFullNode) ChainList(arg0 context.Context, arg1 types0.TipSetKey, arg2 int) ([]types0.TipSetKey, error) {
 	m.ctrl.T.Helper()
@@ -354,6 +413,36 @@ func (mr *MockFullNodeMockRecorder) ChainNotify(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainNotify", reflect.TypeOf((*MockFullNode)(nil).ChainNotify), arg0)
 }
 
+// ChainNotifySince mocks base method.
+func (m *MockFullNode) ChainNotifySince(arg0 context.Context, arg1 types0.TipSetKey) (<-chan []*types0.HeadChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainNotifySince", arg0, arg1)
+	ret0, _ := ret[0].(<-chan []*types0.HeadChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChainNotifySince indicates an expected call of ChainNotifySince.
+func (mr *MockFullNodeMockRecorder) ChainNotifySince(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainNotifySince", reflect.TypeOf((*MockFullNode)(nil).ChainNotifySince), arg0, arg1)
+}
+
+// ChainPrune mocks base method.
+func (m *MockFullNode) ChainPrune(arg0 context.Context, arg1 types0.ChainPruneOpts) (*types0.ChainPruneResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainPrune", arg0, arg1)
+	ret0, _ := ret[0].(*types0.ChainPruneResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChainPrune indicates an expected call of ChainPrune.
+func (mr *MockFullNodeMockRecorder) ChainPrune(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainPrune", reflect.TypeOf((*MockFullNode)(nil).ChainPrune), arg0, arg1)
+}
+
 // ChainPutObj mocks base method.
 func (m *MockFullNode) ChainPutObj(arg0 context.Context, arg1 blocks.Block) error {
 	m.ctrl.T.Helper()
@@ -397,6 +486,50 @@ func (mr *MockFullNodeMockRecorder) ChainSetHead(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainSetHead", reflect.TypeOf((*MockFullNode)(nil).ChainSetHead), arg0, arg1)
 }
 
+// ChainSnapshotImportStatus mocks base method.
+func (m *MockFullNode) ChainSnapshotImportStatus(arg0 context.Context) (*types0.SnapshotImportStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainSnapshotImportStatus", arg0)
+	ret0, _ := ret[0].(*types0.SnapshotImportStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChainSnapshotImportStatus indicates an expected call of ChainSnapshotImportStatus.
+func (mr *MockFullNodeMockRecorder) ChainSnapshotImportStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainSnapshotImportStatus", reflect.TypeOf((*MockFullNode)(nil).ChainSnapshotImportStatus), arg0)
+}
+
+// ChainSplitStoreCompact mocks base method.
+func (m *MockFullNode) ChainSplitStoreCompact(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainSplitStoreCompact", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChainSplitStoreCompact indicates an expected call of ChainSplitStoreCompact.
+func (mr *MockFullNodeMockRecorder) ChainSplitStoreCompact(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainSplitStoreCompact", reflect.TypeOf((*MockFullNode)(nil).ChainSplitStoreCompact), arg0)
+}
+
+// ChainSplitStoreInfo mocks base method.
+func (m *MockFullNode) ChainSplitStoreInfo(arg0 context.Context) (*types0.SplitStoreInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainSplitStoreInfo", arg0)
+	ret0, _ := ret[0].(*types0.SplitStoreInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChainSplitStoreInfo indicates an expected call of ChainSplitStoreInfo.
+func (mr *MockFullNodeMockRecorder) ChainSplitStoreInfo(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainSplitStoreInfo", reflect.TypeOf((*MockFullNode)(nil).ChainSplitStoreInfo), arg0)
+}
+
 // ChainStatObj mocks base method.
 func (m *MockFullNode) ChainStatObj(arg0 context.Context, arg1, arg2 cid.Cid) (types0.ObjStat, error) {
 	m.ctrl.T.Helper()
@@ -455,6 +588,64 @@ func (mr *MockFullNodeMockRecorder) Concurrent(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Concurrent", reflect.TypeOf((*MockFullNode)(nil).Concurrent), arg0)
 }
 
+// DisputerHistory mocks base method.
+func (m *MockFullNode) DisputerHistory(arg0 context.Context) ([]*types0.DisputeRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisputerHistory", arg0)
+	ret0, _ := ret[0].([]*types0.DisputeRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisputerHistory indicates an expected call of DisputerHistory.
+func (mr *MockFullNodeMockRecorder) DisputerHistory(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisputerHistory", reflect.TypeOf((*MockFullNode)(nil).DisputerHistory), arg0)
+}
+
+// DisputerStart mocks base method.
+func (m *MockFullNode) DisputerStart(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisputerStart", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisputerStart indicates an expected call of DisputerStart.
+func (mr *MockFullNodeMockRecorder) DisputerStart(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisputerStart", reflect.TypeOf((*MockFullNode)(nil).DisputerStart), arg0)
+}
+
+// DisputerStatus mocks base method.
+func (m *MockFullNode) DisputerStatus(arg0 context.Context) (*types0.DisputerStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisputerStatus", arg0)
+	ret0, _ := ret[0].(*types0.DisputerStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisputerStatus indicates an expected call of DisputerStatus.
+func (mr *MockFullNodeMockRecorder) DisputerStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisputerStatus", reflect.TypeOf((*MockFullNode)(nil).DisputerStatus), arg0)
+}
+
+// DisputerStop mocks base method.
+func (m *MockFullNode) DisputerStop(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisputerStop", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisputerStop indicates an expected call of DisputerStop.
+func (mr *MockFullNodeMockRecorder) DisputerStop(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisputerStop", reflect.TypeOf((*MockFullNode)(nil).DisputerStop), arg0)
+}
+
 // EthAccounts mocks base method.
 func (m *MockFullNode) EthAccounts(arg0 context.Context) ([]types.EthAddress, error) {
 	m.ctrl.T.Helper()
@@ -530,6 +721,36 @@ func (mr *MockFullNodeMockRecorder) EthChainId(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EthChainId", reflect.TypeOf((*MockFullNode)(nil).EthChainId), arg0)
 }
 
+// EthDebugTraceCall mocks base method.
+func (m *MockFullNode) EthDebugTraceCall(arg0 context.Context, arg1 types.EthCall, arg2 types.EthBlockNumberOrHash, arg3 types.EthTraceConfig) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EthDebugTraceCall", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EthDebugTraceCall indicates an expected call of EthDebugTraceCall.
+func (mr *MockFullNodeMockRecorder) EthDebugTraceCall(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EthDebugTraceCall", reflect.TypeOf((*MockFullNode)(nil).EthDebugTraceCall), arg0, arg1, arg2, arg3)
+}
+
+// EthDebugTraceTransaction mocks base method.
+func (m *MockFullNode) EthDebugTraceTransaction(arg0 context.Context, arg1 string, arg2 types.EthTraceConfig) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EthDebugTraceTransaction", arg0, arg1, arg2)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EthDebugTraceTransaction indicates an expected call of EthDebugTraceTransaction.
+func (mr *MockFullNodeMockRecorder) EthDebugTraceTransaction(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EthDebugTraceTransaction", reflect.TypeOf((*MockFullNode)(nil).EthDebugTraceTransaction), arg0, arg1, arg2)
+}
+
 // EthEstimateGas mocks base method.
 func (m *MockFullNode) EthEstimateGas(arg0 context.Context, arg1 jsonrpc.RawParams) (types.EthUint64, error) {
 	m.ctrl.T.Helper()
@@ -620,6 +841,36 @@ func (mr *MockFullNodeMockRecorder) EthGetBlockByNumber(arg0, arg1, arg2 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EthGetBlockByNumber", reflect.TypeOf((*MockFullNode)(nil).EthGetBlockByNumber), arg0, arg1, arg2)
 }
 
+// EthGetBlockReceipts mocks base method.
+func (m *MockFullNode) EthGetBlockReceipts(arg0 context.Context, arg1 types.EthBlockNumberOrHash) ([]*types.EthTxReceipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EthGetBlockReceipts", arg0, arg1)
+	ret0, _ := ret[0].([]*types.EthTxReceipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EthGetBlockReceipts indicates an expected call of EthGetBlockReceipts.
+func (mr *MockFullNodeMockRecorder) EthGetBlockReceipts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EthGetBlockReceipts", reflect.TypeOf((*MockFullNode)(nil).EthGetBlockReceipts), arg0, arg1)
+}
+
+// EthGetBlockReceiptsLimited mocks base method.
+func (m *MockFullNode) EthGetBlockReceiptsLimited(arg0 context.Context, arg1 types.EthBlockNumberOrHash, arg2 abi.ChainEpoch) ([]*types.EthTxReceipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EthGetBlockReceiptsLimited", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*types.EthTxReceipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EthGetBlockReceiptsLimited indicates an expected call of EthGetBlockReceiptsLimited.
+func (mr *MockFullNodeMockRecorder) EthGetBlockReceiptsLimited(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EthGetBlockReceiptsLimited", reflect.TypeOf((*MockFullNode)(nil).EthGetBlockReceiptsLimited), arg0, arg1, arg2)
+}
+
 // EthGetBlockTransactionCountByHash mocks base method.
 func (m *MockFullNode) EthGetBlockTransactionCountByHash(arg0 context.Context, arg1 types.EthHash) (types.EthUint64, error) {
 	m.ctrl.T.Helper()
@@ -860,6 +1111,36 @@ func (mr *MockFullNodeMockRecorder) EthGetTransactionReceiptLimited(arg0, arg1,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EthGetTransactionReceiptLimited", reflect.TypeOf((*MockFullNode)(nil).EthGetTransactionReceiptLimited), arg0, arg1, arg2)
 }
 
+// EthIndexBackfill mocks base method.
+func (m *MockFullNode) EthIndexBackfill(arg0 context.Context, arg1, arg2 abi.ChainEpoch) (*types0.EthIndexBackfillResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EthIndexBackfill", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*types0.EthIndexBackfillResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EthIndexBackfill indicates an expected call of EthIndexBackfill.
+func (mr *MockFullNodeMockRecorder) EthIndexBackfill(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EthIndexBackfill", reflect.TypeOf((*MockFullNode)(nil).EthIndexBackfill), arg0, arg1, arg2)
+}
+
+// EthIndexValidate mocks base method.
+func (m *MockFullNode) EthIndexValidate(arg0 context.Context, arg1, arg2 abi.ChainEpoch, arg3 bool) (*types0.EthIndexValidateResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EthIndexValidate", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*types0.EthIndexValidateResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EthIndexValidate indicates an expected call of EthIndexValidate.
+func (mr *MockFullNodeMockRecorder) EthIndexValidate(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EthIndexValidate", reflect.TypeOf((*MockFullNode)(nil).EthIndexValidate), arg0, arg1, arg2, arg3)
+}
+
 // EthMaxPriorityFeePerGas mocks base method.
 func (m *MockFullNode) EthMaxPriorityFeePerGas(arg0 context.Context) (types.EthBigInt, error) {
 	m.ctrl.T.Helper()
@@ -995,6 +1276,21 @@ func (mr *MockFullNodeMockRecorder) EthTraceBlock(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EthTraceBlock", reflect.TypeOf((*MockFullNode)(nil).EthTraceBlock), arg0, arg1)
 }
 
+// EthTraceFilter mocks base method.
+func (m *MockFullNode) EthTraceFilter(arg0 context.Context, arg1 types.EthTraceFilterCriteria) ([]*types.EthTraceFilterResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EthTraceFilter", arg0, arg1)
+	ret0, _ := ret[0].([]*types.EthTraceFilterResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EthTraceFilter indicates an expected call of EthTraceFilter.
+func (mr *MockFullNodeMockRecorder) EthTraceFilter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EthTraceFilter", reflect.TypeOf((*MockFullNode)(nil).EthTraceFilter), arg0, arg1)
+}
+
 // EthTraceReplayBlockTransactions mocks base method.
 func (m *MockFullNode) EthTraceReplayBlockTransactions(arg0 context.Context, arg1 string, arg2 []string) ([]*types.EthTraceReplayBlockTransaction, error) {
 	m.ctrl.T.Helper()
@@ -1010,6 +1306,21 @@ func (mr *MockFullNodeMockRecorder) EthTraceReplayBlockTransactions(arg0, arg1,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EthTraceReplayBlockTransactions", reflect.TypeOf((*MockFullNode)(nil).EthTraceReplayBlockTransactions), arg0, arg1, arg2)
 }
 
+// EthTraceTransaction mocks base method.
+func (m *MockFullNode) EthTraceTransaction(arg0 context.Context, arg1 string) ([]*types.EthTraceTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EthTraceTransaction", arg0, arg1)
+	ret0, _ := ret[0].([]*types.EthTraceTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EthTraceTransaction indicates an expected call of EthTraceTransaction.
+func (mr *MockFullNodeMockRecorder) EthTraceTransaction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EthTraceTransaction", reflect.TypeOf((*MockFullNode)(nil).EthTraceTransaction), arg0, arg1)
+}
+
 // EthUninstallFilter mocks base method.
 func (m *MockFullNode) EthUninstallFilter(arg0 context.Context, arg1 types.EthFilterID) (bool, error) {
 	m.ctrl.T.Helper()
@@ -1085,6 +1396,21 @@ func (mr *MockFullNodeMockRecorder) GasEstimateFeeCap(arg0, arg1, arg2, arg3 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GasEstimateFeeCap", reflect.TypeOf((*MockFullNode)(nil).GasEstimateFeeCap), arg0, arg1, arg2, arg3)
 }
 
+// GasEstimateFeeHistory mocks base method.
+func (m *MockFullNode) GasEstimateFeeHistory(arg0 context.Context, arg1 uint64, arg2 []float64, arg3 types0.TipSetKey) (*types0.FeeHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GasEstimateFeeHistory", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*types0.FeeHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GasEstimateFeeHistory indicates an expected call of GasEstimateFeeHistory.
+func (mr *MockFullNodeMockRecorder) GasEstimateFeeHistory(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GasEstimateFeeHistory", reflect.TypeOf((*MockFullNode)(nil).GasEstimateFeeHistory), arg0, arg1, arg2, arg3)
+}
+
 // GasEstimateGasLimit mocks base method.
 func (m *MockFullNode) GasEstimateGasLimit(arg0 context.Context, arg1 *types.Message, arg2 types0.TipSetKey) (int64, error) {
 	m.ctrl.T.Helper()
@@ -1426,6 +1752,21 @@ func (mr *MockFullNodeMockRecorder) MpoolGetConfig(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolGetConfig", reflect.TypeOf((*MockFullNode)(nil).MpoolGetConfig), arg0)
 }
 
+// MpoolGetMessageStatus mocks base method.
+func (m *MockFullNode) MpoolGetMessageStatus(arg0 context.Context, arg1 cid.Cid) (*types0.MpoolMessageStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MpoolGetMessageStatus", arg0, arg1)
+	ret0, _ := ret[0].(*types0.MpoolMessageStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MpoolGetMessageStatus indicates an expected call of MpoolGetMessageStatus.
+func (mr *MockFullNodeMockRecorder) MpoolGetMessageStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolGetMessageStatus", reflect.TypeOf((*MockFullNode)(nil).MpoolGetMessageStatus), arg0, arg1)
+}
+
 // MpoolGetNonce mocks base method.
 func (m *MockFullNode) MpoolGetNonce(arg0 context.Context, arg1 address.Address) (uint64, error) {
 	m.ctrl.T.Helper()
@@ -1441,6 +1782,21 @@ func (mr *MockFullNodeMockRecorder) MpoolGetNonce(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolGetNonce", reflect.TypeOf((*MockFullNode)(nil).MpoolGetNonce), arg0, arg1)
 }
 
+// MpoolHistory mocks base method.
+func (m *MockFullNode) MpoolHistory(arg0 context.Context, arg1 address.Address, arg2 *uint64) ([]*types0.MpoolJournalEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MpoolHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*types0.MpoolJournalEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MpoolHistory indicates an expected call of MpoolHistory.
+func (mr *MockFullNodeMockRecorder) MpoolHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolHistory", reflect.TypeOf((*MockFullNode)(nil).MpoolHistory), arg0, arg1, arg2)
+}
+
 // MpoolPending mocks base method.
 func (m *MockFullNode) MpoolPending(arg0 context.Context, arg1 types0.TipSetKey) ([]*types.SignedMessage, error) {
 	m.ctrl.T.Helper()
@@ -1588,6 +1944,261 @@ func (mr *MockFullNodeMockRecorder) MpoolSub(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MpoolSub", reflect.TypeOf((*MockFullNode)(nil).MpoolSub), arg0)
 }
 
+// MsigAddApprove mocks base method.
+func (m *MockFullNode) MsigAddApprove(arg0 context.Context, arg1, arg2 address.Address, arg3 uint64, arg4, arg5 address.Address, arg6 bool) (*types0.MessagePrototype, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigAddApprove", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret0, _ := ret[0].(*types0.MessagePrototype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigAddApprove indicates an expected call of MsigAddApprove.
+func (mr *MockFullNodeMockRecorder) MsigAddApprove(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigAddApprove", reflect.TypeOf((*MockFullNode)(nil).MsigAddApprove), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// MsigAddCancel mocks base method.
+func (m *MockFullNode) MsigAddCancel(arg0 context.Context, arg1, arg2 address.Address, arg3 uint64, arg4 address.Address, arg5 bool) (*types0.MessagePrototype, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigAddCancel", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(*types0.MessagePrototype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigAddCancel indicates an expected call of MsigAddCancel.
+func (mr *MockFullNodeMockRecorder) MsigAddCancel(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigAddCancel", reflect.TypeOf((*MockFullNode)(nil).MsigAddCancel), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// MsigAddPropose mocks base method.
+func (m *MockFullNode) MsigAddPropose(arg0 context.Context, arg1, arg2, arg3 address.Address, arg4 bool) (*types0.MessagePrototype, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigAddPropose", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*types0.MessagePrototype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigAddPropose indicates an expected call of MsigAddPropose.
+func (mr *MockFullNodeMockRecorder) MsigAddPropose(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigAddPropose", reflect.TypeOf((*MockFullNode)(nil).MsigAddPropose), arg0, arg1, arg2, arg3, arg4)
+}
+
+// MsigApprove mocks base method.
+func (m *MockFullNode) MsigApprove(arg0 context.Context, arg1 address.Address, arg2 uint64, arg3 address.Address) (*types0.MessagePrototype, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigApprove", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*types0.MessagePrototype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigApprove indicates an expected call of MsigApprove.
+func (mr *MockFullNodeMockRecorder) MsigApprove(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigApprove", reflect.TypeOf((*MockFullNode)(nil).MsigApprove), arg0, arg1, arg2, arg3)
+}
+
+// MsigApproveTxnHash mocks base method.
+func (m *MockFullNode) MsigApproveTxnHash(arg0 context.Context, arg1 address.Address, arg2 uint64, arg3, arg4 address.Address, arg5 big.Int, arg6 address.Address, arg7 uint64, arg8 []byte) (*types0.MessagePrototype, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigApproveTxnHash", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
+	ret0, _ := ret[0].(*types0.MessagePrototype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigApproveTxnHash indicates an expected call of MsigApproveTxnHash.
+func (mr *MockFullNodeMockRecorder) MsigApproveTxnHash(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigApproveTxnHash", reflect.TypeOf((*MockFullNode)(nil).MsigApproveTxnHash), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
+}
+
+// MsigCancel mocks base method.
+func (m *MockFullNode) MsigCancel(arg0 context.Context, arg1 address.Address, arg2 uint64, arg3 address.Address) (*types0.MessagePrototype, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigCancel", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*types0.MessagePrototype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigCancel indicates an expected call of MsigCancel.
+func (mr *MockFullNodeMockRecorder) MsigCancel(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigCancel", reflect.TypeOf((*MockFullNode)(nil).MsigCancel), arg0, arg1, arg2, arg3)
+}
+
+// MsigCancelTxnHash mocks base method.
+func (m *MockFullNode) MsigCancelTxnHash(arg0 context.Context, arg1 address.Address, arg2 uint64, arg3 address.Address, arg4 big.Int, arg5 address.Address, arg6 uint64, arg7 []byte) (*types0.MessagePrototype, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigCancelTxnHash", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+	ret0, _ := ret[0].(*types0.MessagePrototype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigCancelTxnHash indicates an expected call of MsigCancelTxnHash.
+func (mr *MockFullNodeMockRecorder) MsigCancelTxnHash(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigCancelTxnHash", reflect.TypeOf((*MockFullNode)(nil).MsigCancelTxnHash), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+}
+
+// MsigCreate mocks base method.
+func (m *MockFullNode) MsigCreate(arg0 context.Context, arg1 uint64, arg2 []address.Address, arg3 abi.ChainEpoch, arg4 big.Int, arg5 address.Address, arg6 big.Int) (*types0.MessagePrototype, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigCreate", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret0, _ := ret[0].(*types0.MessagePrototype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigCreate indicates an expected call of MsigCreate.
+func (mr *MockFullNodeMockRecorder) MsigCreate(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigCreate", reflect.TypeOf((*MockFullNode)(nil).MsigCreate), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// MsigGetAvailableBalance mocks base method.
+func (m *MockFullNode) MsigGetAvailableBalance(arg0 context.Context, arg1 address.Address, arg2 types0.TipSetKey) (big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigGetAvailableBalance", arg0, arg1, arg2)
+	ret0, _ := ret[0].(big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigGetAvailableBalance indicates an expected call of MsigGetAvailableBalance.
+func (mr *MockFullNodeMockRecorder) MsigGetAvailableBalance(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigGetAvailableBalance", reflect.TypeOf((*MockFullNode)(nil).MsigGetAvailableBalance), arg0, arg1, arg2)
+}
+
+// MsigGetPending mocks base method.
+func (m *MockFullNode) MsigGetPending(arg0 context.Context, arg1 address.Address, arg2 types0.TipSetKey) ([]*types0.MsigTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigGetPending", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*types0.MsigTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigGetPending indicates an expected call of MsigGetPending.
+func (mr *MockFullNodeMockRecorder) MsigGetPending(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigGetPending", reflect.TypeOf((*MockFullNode)(nil).MsigGetPending), arg0, arg1, arg2)
+}
+
+// MsigGetVested mocks base method.
+func (m *MockFullNode) MsigGetVested(arg0 context.Context, arg1 address.Address, arg2, arg3 types0.TipSetKey) (big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigGetVested", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigGetVested indicates an expected call of MsigGetVested.
+func (mr *MockFullNodeMockRecorder) MsigGetVested(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigGetVested", reflect.TypeOf((*MockFullNode)(nil).MsigGetVested), arg0, arg1, arg2, arg3)
+}
+
+// MsigGetVestingSchedule mocks base method.
+func (m *MockFullNode) MsigGetVestingSchedule(arg0 context.Context, arg1 address.Address, arg2 types0.TipSetKey) (types0.MsigVesting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigGetVestingSchedule", arg0, arg1, arg2)
+	ret0, _ := ret[0].(types0.MsigVesting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigGetVestingSchedule indicates an expected call of MsigGetVestingSchedule.
+func (mr *MockFullNodeMockRecorder) MsigGetVestingSchedule(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigGetVestingSchedule", reflect.TypeOf((*MockFullNode)(nil).MsigGetVestingSchedule), arg0, arg1, arg2)
+}
+
+// MsigPropose mocks base method.
+func (m *MockFullNode) MsigPropose(arg0 context.Context, arg1, arg2 address.Address, arg3 big.Int, arg4 address.Address, arg5 uint64, arg6 []byte) (*types0.MessagePrototype, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigPropose", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret0, _ := ret[0].(*types0.MessagePrototype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigPropose indicates an expected call of MsigPropose.
+func (mr *MockFullNodeMockRecorder) MsigPropose(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigPropose", reflect.TypeOf((*MockFullNode)(nil).MsigPropose), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// MsigRemoveSigner mocks base method.
+func (m *MockFullNode) MsigRemoveSigner(arg0 context.Context, arg1, arg2, arg3 address.Address, arg4 bool) (*types0.MessagePrototype, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigRemoveSigner", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*types0.MessagePrototype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigRemoveSigner indicates an expected call of MsigRemoveSigner.
+func (mr *MockFullNodeMockRecorder) MsigRemoveSigner(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigRemoveSigner", reflect.TypeOf((*MockFullNode)(nil).MsigRemoveSigner), arg0, arg1, arg2, arg3, arg4)
+}
+
+// MsigSwapApprove mocks base method.
+func (m *MockFullNode) MsigSwapApprove(arg0 context.Context, arg1, arg2 address.Address, arg3 uint64, arg4, arg5, arg6 address.Address) (*types0.MessagePrototype, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigSwapApprove", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret0, _ := ret[0].(*types0.MessagePrototype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigSwapApprove indicates an expected call of MsigSwapApprove.
+func (mr *MockFullNodeMockRecorder) MsigSwapApprove(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigSwapApprove", reflect.TypeOf((*MockFullNode)(nil).MsigSwapApprove), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// MsigSwapCancel mocks base method.
+func (m *MockFullNode) MsigSwapCancel(arg0 context.Context, arg1, arg2 address.Address, arg3 uint64, arg4, arg5 address.Address) (*types0.MessagePrototype, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigSwapCancel", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(*types0.MessagePrototype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigSwapCancel indicates an expected call of MsigSwapCancel.
+func (mr *MockFullNodeMockRecorder) MsigSwapCancel(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigSwapCancel", reflect.TypeOf((*MockFullNode)(nil).MsigSwapCancel), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// MsigSwapPropose mocks base method.
+func (m *MockFullNode) MsigSwapPropose(arg0 context.Context, arg1, arg2, arg3, arg4 address.Address) (*types0.MessagePrototype, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MsigSwapPropose", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*types0.MessagePrototype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MsigSwapPropose indicates an expected call of MsigSwapPropose.
+func (mr *MockFullNodeMockRecorder) MsigSwapPropose(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MsigSwapPropose", reflect.TypeOf((*MockFullNode)(nil).MsigSwapPropose), arg0, arg1, arg2, arg3, arg4)
+}
+
 // NetAddrsListen mocks base method.
 func (m *MockFullNode) NetAddrsListen(arg0 context.Context) (peer.AddrInfo, error) {
 	m.ctrl.T.Helper()
@@ -1678,6 +2289,49 @@ func (mr *MockFullNodeMockRecorder) NetBandwidthStatsByProtocol(arg0 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetBandwidthStatsByProtocol", reflect.TypeOf((*MockFullNode)(nil).NetBandwidthStatsByProtocol), arg0)
 }
 
+// NetBlockAdd mocks base method.
+func (m *MockFullNode) NetBlockAdd(arg0 context.Context, arg1 types0.NetBlockList) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetBlockAdd", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NetBlockAdd indicates an expected call of NetBlockAdd.
+func (mr *MockFullNodeMockRecorder) NetBlockAdd(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetBlockAdd", reflect.TypeOf((*MockFullNode)(nil).NetBlockAdd), arg0, arg1)
+}
+
+// NetBlockList mocks base method.
+func (m *MockFullNode) NetBlockList(arg0 context.Context) (types0.NetBlockList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetBlockList", arg0)
+	ret0, _ := ret[0].(types0.NetBlockList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NetBlockList indicates an expected call of NetBlockList.
+func (mr *MockFullNodeMockRecorder) NetBlockList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetBlockList", reflect.TypeOf((*MockFullNode)(nil).NetBlockList), arg0)
+}
+
+// NetBlockRemove mocks base method.
+func (m *MockFullNode) NetBlockRemove(arg0 context.Context, arg1 types0.NetBlockList) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetBlockRemove", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NetBlockRemove indicates an expected call of NetBlockRemove.
+func (mr *MockFullNodeMockRecorder) NetBlockRemove(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetBlockRemove", reflect.TypeOf((*MockFullNode)(nil).NetBlockRemove), arg0, arg1)
+}
+
 // NetConnect mocks base method.
 func (m *MockFullNode) NetConnect(arg0 context.Context, arg1 peer.AddrInfo) error {
 	m.ctrl.T.Helper()
@@ -1765,6 +2419,21 @@ func (mr *MockFullNodeMockRecorder) NetGetClosestPeers(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetGetClosestPeers", reflect.TypeOf((*MockFullNode)(nil).NetGetClosestPeers), arg0, arg1)
 }
 
+// NetLimit mocks base method.
+func (m *MockFullNode) NetLimit(arg0 context.Context, arg1 string) (types0.NetLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetLimit", arg0, arg1)
+	ret0, _ := ret[0].(types0.NetLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NetLimit indicates an expected call of NetLimit.
+func (mr *MockFullNodeMockRecorder) NetLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetLimit", reflect.TypeOf((*MockFullNode)(nil).NetLimit), arg0, arg1)
+}
+
 // NetListening mocks base method.
 func (m *MockFullNode) NetListening(arg0 context.Context) (bool, error) {
 	m.ctrl.T.Helper()
@@ -1883,6 +2552,35 @@ func (mr *MockFullNodeMockRecorder) NetPubsubScores(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetPubsubScores", reflect.TypeOf((*MockFullNode)(nil).NetPubsubScores), arg0)
 }
 
+// NetSetLimit mocks base method.
+func (m *MockFullNode) NetSetLimit(arg0 context.Context, arg1 string, arg2 types0.NetLimit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetSetLimit", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NetSetLimit indicates an expected call of NetSetLimit.
+func (mr *MockFullNodeMockRecorder) NetSetLimit(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetSetLimit", reflect.TypeOf((*MockFullNode)(nil).NetSetLimit), arg0, arg1, arg2)
+}
+
+// NetStat mocks base method.
+func (m *MockFullNode) NetStat(arg0 context.Context, arg1 string) (types0.NetStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetStat", arg0, arg1)
+	ret0, _ := ret[0].(types0.NetStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NetStat indicates an expected call of NetStat.
+func (mr *MockFullNodeMockRecorder) NetStat(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetStat", reflect.TypeOf((*MockFullNode)(nil).NetStat), arg0, arg1)
+}
+
 // NetVersion mocks base method.
 func (m *MockFullNode) NetVersion(arg0 context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -2405,6 +3103,21 @@ func (mr *MockFullNodeMockRecorder) StateDecodeParams(arg0, arg1, arg2, arg3, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateDecodeParams", reflect.TypeOf((*MockFullNode)(nil).StateDecodeParams), arg0, arg1, arg2, arg3, arg4)
 }
 
+// StateDiff mocks base method.
+func (m *MockFullNode) StateDiff(arg0 context.Context, arg1, arg2 types0.TipSetKey, arg3 types0.StateDiffOpts) (*types0.StateDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StateDiff", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*types0.StateDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateDiff indicates an expected call of StateDiff.
+func (mr *MockFullNodeMockRecorder) StateDiff(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateDiff", reflect.TypeOf((*MockFullNode)(nil).StateDiff), arg0, arg1, arg2, arg3)
+}
+
 // StateEncodeParams mocks base method.
 func (m *MockFullNode) StateEncodeParams(arg0 context.Context, arg1 cid.Cid, arg2 abi.MethodNum, arg3 json.RawMessage) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -3110,6 +3823,21 @@ func (mr *MockFullNodeMockRecorder) StateSearchMsg(arg0, arg1, arg2, arg3, arg4
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateSearchMsg", reflect.TypeOf((*MockFullNode)(nil).StateSearchMsg), arg0, arg1, arg2, arg3, arg4)
 }
 
+// StateSearchMsgFinality mocks base method.
+func (m *MockFullNode) StateSearchMsgFinality(arg0 context.Context, arg1 types0.TipSetKey, arg2 cid.Cid, arg3 float64, arg4 abi.ChainEpoch, arg5 bool) (*types0.MsgLookup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StateSearchMsgFinality", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(*types0.MsgLookup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateSearchMsgFinality indicates an expected call of StateSearchMsgFinality.
+func (mr *MockFullNodeMockRecorder) StateSearchMsgFinality(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateSearchMsgFinality", reflect.TypeOf((*MockFullNode)(nil).StateSearchMsgFinality), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
 // StateSectorExpiration mocks base method.
 func (m *MockFullNode) StateSectorExpiration(arg0 context.Context, arg1 address.Address, arg2 abi.SectorNumber, arg3 types0.TipSetKey) (*miner0.SectorExpiration, error) {
 	m.ctrl.T.Helper()
@@ -3170,6 +3898,21 @@ func (mr *MockFullNodeMockRecorder) StateSectorPreCommitInfo(arg0, arg1, arg2, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateSectorPreCommitInfo", reflect.TypeOf((*MockFullNode)(nil).StateSectorPreCommitInfo), arg0, arg1, arg2, arg3)
 }
 
+// StateSimulateBundle mocks base method.
+func (m *MockFullNode) StateSimulateBundle(arg0 context.Context, arg1 []*types.Message, arg2 types0.TipSetKey, arg3 types0.SimulateBundleOpts) (*types0.SimulateBundleResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StateSimulateBundle", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*types0.SimulateBundleResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateSimulateBundle indicates an expected call of StateSimulateBundle.
+func (mr *MockFullNodeMockRecorder) StateSimulateBundle(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateSimulateBundle", reflect.TypeOf((*MockFullNode)(nil).StateSimulateBundle), arg0, arg1, arg2, arg3)
+}
+
 // StateVMCirculatingSupplyInternal mocks base method.
 func (m *MockFullNode) StateVMCirculatingSupplyInternal(arg0 context.Context, arg1 types0.TipSetKey) (types0.CirculatingSupply, error) {
 	m.ctrl.T.Helper()
@@ -3245,6 +3988,21 @@ func (mr *MockFullNodeMockRecorder) StateWaitMsg(arg0, arg1, arg2, arg3, arg4 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateWaitMsg", reflect.TypeOf((*MockFullNode)(nil).StateWaitMsg), arg0, arg1, arg2, arg3, arg4)
 }
 
+// StateWaitMsgFinality mocks base method.
+func (m *MockFullNode) StateWaitMsgFinality(arg0 context.Context, arg1 cid.Cid, arg2 float64, arg3 abi.ChainEpoch, arg4 bool) (*types0.MsgLookup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StateWaitMsgFinality", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*types0.MsgLookup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateWaitMsgFinality indicates an expected call of StateWaitMsgFinality.
+func (mr *MockFullNodeMockRecorder) StateWaitMsgFinality(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateWaitMsgFinality", reflect.TypeOf((*MockFullNode)(nil).StateWaitMsgFinality), arg0, arg1, arg2, arg3, arg4)
+}
+
 // SubscribeActorEvents mocks base method.
 func (m *MockFullNode) SubscribeActorEvents(arg0 context.Context, arg1 *types0.ActorEventFilter) (<-chan *types0.ActorEvent, error) {
 	m.ctrl.T.Helper()
@@ -3260,6 +4018,35 @@ func (mr *MockFullNodeMockRecorder) SubscribeActorEvents(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeActorEvents", reflect.TypeOf((*MockFullNode)(nil).SubscribeActorEvents), arg0, arg1)
 }
 
+// SyncCheckBad mocks base method.
+func (m *MockFullNode) SyncCheckBad(arg0 context.Context, arg1 cid.Cid) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncCheckBad", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncCheckBad indicates an expected call of SyncCheckBad.
+func (mr *MockFullNodeMockRecorder) SyncCheckBad(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncCheckBad", reflect.TypeOf((*MockFullNode)(nil).SyncCheckBad), arg0, arg1)
+}
+
+// SyncCheckpoint mocks base method.
+func (m *MockFullNode) SyncCheckpoint(arg0 context.Context, arg1 types0.TipSetKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncCheckpoint", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncCheckpoint indicates an expected call of SyncCheckpoint.
+func (mr *MockFullNodeMockRecorder) SyncCheckpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncCheckpoint", reflect.TypeOf((*MockFullNode)(nil).SyncCheckpoint), arg0, arg1)
+}
+
 // SyncIncomingBlocks mocks base method.
 func (m *MockFullNode) SyncIncomingBlocks(arg0 context.Context) (<-chan *types0.BlockHeader, error) {
 	m.ctrl.T.Helper()
@@ -3275,6 +4062,20 @@ func (mr *MockFullNodeMockRecorder) SyncIncomingBlocks(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncIncomingBlocks", reflect.TypeOf((*MockFullNode)(nil).SyncIncomingBlocks), arg0)
 }
 
+// SyncMarkBad mocks base method.
+func (m *MockFullNode) SyncMarkBad(arg0 context.Context, arg1 cid.Cid) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncMarkBad", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncMarkBad indicates an expected call of SyncMarkBad.
+func (mr *MockFullNodeMockRecorder) SyncMarkBad(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncMarkBad", reflect.TypeOf((*MockFullNode)(nil).SyncMarkBad), arg0, arg1)
+}
+
 // SyncState mocks base method.
 func (m *MockFullNode) SyncState(arg0 context.Context) (*types0.SyncState, error) {
 	m.ctrl.T.Helper()
@@ -3304,6 +4105,34 @@ func (mr *MockFullNodeMockRecorder) SyncSubmitBlock(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncSubmitBlock", reflect.TypeOf((*MockFullNode)(nil).SyncSubmitBlock), arg0, arg1)
 }
 
+// SyncUnmarkAllBad mocks base method.
+func (m *MockFullNode) SyncUnmarkAllBad(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncUnmarkAllBad", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncUnmarkAllBad indicates an expected call of SyncUnmarkAllBad.
+func (mr *MockFullNodeMockRecorder) SyncUnmarkAllBad(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncUnmarkAllBad", reflect.TypeOf((*MockFullNode)(nil).SyncUnmarkAllBad), arg0)
+}
+
+// SyncUnmarkBad mocks base method.
+func (m *MockFullNode) SyncUnmarkBad(arg0 context.Context, arg1 cid.Cid) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncUnmarkBad", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncUnmarkBad indicates an expected call of SyncUnmarkBad.
+func (mr *MockFullNodeMockRecorder) SyncUnmarkBad(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncUnmarkBad", reflect.TypeOf((*MockFullNode)(nil).SyncUnmarkBad), arg0, arg1)
+}
+
 // SyncerTracker mocks base method.
 func (m *MockFullNode) SyncerTracker(arg0 context.Context) *types0.TargetTracker {
 	m.ctrl.T.Helper()
@@ -39,4 +39,14 @@ type IMessagePool interface {
 	MpoolCheckPendingMessages(ctx context.Context, addr address.Address) ([][]types.MessageCheckStatus, error) //perm:read
 	// MpoolCheckReplaceMessages performs logical checks on pending messages with replacement
 	MpoolCheckReplaceMessages(ctx context.Context, msg []*types.Message) ([][]types.MessageCheckStatus, error) //perm:read
+	// MpoolHistory returns the journaled events of the messages sent by addr, oldest first, or only
+	// of its message with nonce when not nil. It requires the sqlite message pool journal.
+	MpoolHistory(ctx context.Context, addr address.Address, nonce *uint64) ([]*types.MpoolJournalEntry, error) //perm:read
+	// MpoolGetMessageStatus returns whether the message is in the message pool, or why it was removed
+	// from it or refused by it, which is remembered for the messages removed since the node started.
+	MpoolGetMessageStatus(ctx context.Context, c cid.Cid) (*types.MpoolMessageStatus, error) //perm:read
+	// GasEstimateFeeHistory returns the base fees and the premiums paid at the percentiles of the
+	// gas used by the messages of the last blocks tipsets executed up to tsk, which defaults to
+	// the parent of the head.
+	GasEstimateFeeHistory(ctx context.Context, blocks uint64, percentiles []float64, tsk types.TipSetKey) (*types.FeeHistory, error) //perm:read
 }
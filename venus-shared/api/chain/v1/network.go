@@ -44,4 +44,22 @@ type INetwork interface {
 	NetProtectAdd(ctx context.Context, acl []peer.ID) error    //perm:admin
 	NetProtectRemove(ctx context.Context, acl []peer.ID) error //perm:admin
 	NetProtectList(ctx context.Context) ([]peer.ID, error)     //perm:read
+
+	// NetBlockAdd blocks the connections of the given peers, ip addresses and subnets, the
+	// blocklist is persisted across restarts.
+	NetBlockAdd(ctx context.Context, acl types.NetBlockList) error //perm:admin
+	// NetBlockRemove unblocks the given peers, ip addresses and subnets.
+	NetBlockRemove(ctx context.Context, acl types.NetBlockList) error //perm:admin
+	// NetBlockList returns the peers, ip addresses and subnets that are blocked. The peers
+	// rejected because of their gossipsub score are not listed.
+	NetBlockList(ctx context.Context) (types.NetBlockList, error) //perm:read
+
+	// NetStat returns the resources used by a scope of the libp2p resource manager: `system`,
+	// `transient`, `svc:<service>`, `proto:<protocol id>`, `peer:<peer id>` or `all`.
+	NetStat(ctx context.Context, scope string) (types.NetStat, error) //perm:read
+	// NetLimit returns the limit of a scope of the libp2p resource manager.
+	NetLimit(ctx context.Context, scope string) (types.NetLimit, error) //perm:read
+	// NetSetLimit changes the limit of a scope of the libp2p resource manager, the zero fields
+	// of limit keep their current value.
+	NetSetLimit(ctx context.Context, scope string, limit types.NetLimit) error //perm:admin
 }
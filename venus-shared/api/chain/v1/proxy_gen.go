@@ -28,11 +28,13 @@ import (
 
 type IBlockStoreStruct struct {
 	Internal struct {
-		ChainDeleteObj func(ctx context.Context, obj cid.Cid) error                                `perm:"admin"`
-		ChainHasObj    func(ctx context.Context, obj cid.Cid) (bool, error)                        `perm:"read"`
-		ChainPutObj    func(context.Context, blocks.Block) error                                   `perm:"admin"`
-		ChainReadObj   func(ctx context.Context, cid cid.Cid) ([]byte, error)                      `perm:"read"`
-		ChainStatObj   func(ctx context.Context, obj cid.Cid, base cid.Cid) (types.ObjStat, error) `perm:"read"`
+		ChainDeleteObj         func(ctx context.Context, obj cid.Cid) error                                `perm:"admin"`
+		ChainHasObj            func(ctx context.Context, obj cid.Cid) (bool, error)                        `perm:"read"`
+		ChainPutObj            func(context.Context, blocks.Block) error                                   `perm:"admin"`
+		ChainReadObj           func(ctx context.Context, cid cid.Cid) ([]byte, error)                      `perm:"read"`
+		ChainSplitStoreCompact func(ctx context.Context) error                                             `perm:"admin"`
+		ChainSplitStoreInfo    func(ctx context.Context) (*types.SplitStoreInfo, error)                    `perm:"read"`
+		ChainStatObj           func(ctx context.Context, obj cid.Cid, base cid.Cid) (types.ObjStat, error) `perm:"read"`
 	}
 }
 
@@ -48,6 +50,12 @@ func (s *IBlockStoreStruct) ChainPutObj(p0 context.Context, p1 blocks.Block) err
 func (s *IBlockStoreStruct) ChainReadObj(p0 context.Context, p1 cid.Cid) ([]byte, error) {
 	return s.Internal.ChainReadObj(p0, p1)
 }
+func (s *IBlockStoreStruct) ChainSplitStoreCompact(p0 context.Context) error {
+	return s.Internal.ChainSplitStoreCompact(p0)
+}
+func (s *IBlockStoreStruct) ChainSplitStoreInfo(p0 context.Context) (*types.SplitStoreInfo, error) {
+	return s.Internal.ChainSplitStoreInfo(p0)
+}
 func (s *IBlockStoreStruct) ChainStatObj(p0 context.Context, p1 cid.Cid, p2 cid.Cid) (types.ObjStat, error) {
 	return s.Internal.ChainStatObj(p0, p1, p2)
 }
@@ -84,6 +92,7 @@ type IMinerStateStruct struct {
 		StateComputeDataCID                func(ctx context.Context, maddr address.Address, sectorType abi.RegisteredSealProof, deals []abi.DealID, tsk types.TipSetKey) (cid.Cid, error) `perm:"read"`
 		StateDealProviderCollateralBounds  func(ctx context.Context, size abi.PaddedPieceSize, verified bool, tsk types.TipSetKey) (types.DealCollateralBounds, error)                    `perm:"read"`
 		StateDecodeParams                  func(ctx context.Context, toAddr address.Address, method abi.MethodNum, params []byte, tsk types.TipSetKey) (interface{}, error)               `perm:"read"`
+		StateDiff                          func(ctx context.Context, from, to types.TipSetKey, opts types.StateDiffOpts) (*types.StateDiff, error)                                        `perm:"read"`
 		StateEncodeParams                  func(ctx context.Context, toActCode cid.Cid, method abi.MethodNum, params json.RawMessage) ([]byte, error)                                     `perm:"read"`
 		StateGetAllAllocations             func(ctx context.Context, tsk types.TipSetKey) (map[types.AllocationId]types.Allocation, error)                                                `perm:"read"`
 		StateGetAllClaims                  func(ctx context.Context, tsk types.TipSetKey) (map[types.ClaimId]types.Claim, error)                                                          `perm:"read"`
@@ -146,6 +155,9 @@ func (s *IMinerStateStruct) StateDealProviderCollateralBounds(p0 context.Context
 func (s *IMinerStateStruct) StateDecodeParams(p0 context.Context, p1 address.Address, p2 abi.MethodNum, p3 []byte, p4 types.TipSetKey) (interface{}, error) {
 	return s.Internal.StateDecodeParams(p0, p1, p2, p3, p4)
 }
+func (s *IMinerStateStruct) StateDiff(p0 context.Context, p1, p2 types.TipSetKey, p3 types.StateDiffOpts) (*types.StateDiff, error) {
+	return s.Internal.StateDiff(p0, p1, p2, p3)
+}
 func (s *IMinerStateStruct) StateEncodeParams(p0 context.Context, p1 cid.Cid, p2 abi.MethodNum, p3 json.RawMessage) ([]byte, error) {
 	return s.Internal.StateEncodeParams(p0, p1, p2, p3)
 }
@@ -274,9 +286,11 @@ type IChainInfoStruct struct {
 	Internal struct {
 		BlockTime                           func(ctx context.Context) time.Duration                                                                                                                      `perm:"read"`
 		ChainExport                         func(context.Context, abi.ChainEpoch, bool, types.TipSetKey) (<-chan []byte, error)                                                                          `perm:"read"`
+		ChainExportRange                    func(ctx context.Context, head types.TipSetKey, tail abi.ChainEpoch, opts types.ChainExportRangeOpts) (<-chan []byte, error)                                 `perm:"read"`
 		ChainGetBlock                       func(ctx context.Context, id cid.Cid) (*types.BlockHeader, error)                                                                                            `perm:"read"`
 		ChainGetBlockMessages               func(ctx context.Context, bid cid.Cid) (*types.BlockMessages, error)                                                                                         `perm:"read"`
 		ChainGetEvents                      func(context.Context, cid.Cid) ([]types.Event, error)                                                                                                        `perm:"read"`
+		ChainGetFinality                    func(ctx context.Context, height abi.ChainEpoch, threshold float64) (*types.ChainFinalityStatus, error)                                                      `perm:"read"`
 		ChainGetGenesis                     func(context.Context) (*types.TipSet, error)                                                                                                                 `perm:"read"`
 		ChainGetMessage                     func(ctx context.Context, msgID cid.Cid) (*types.Message, error)                                                                                             `perm:"read"`
 		ChainGetMessagesInTipset            func(ctx context.Context, key types.TipSetKey) ([]types.MessageCID, error)                                                                                   `perm:"read"`
@@ -287,10 +301,15 @@ type IChainInfoStruct struct {
 		ChainGetTipSet                      func(ctx context.Context, key types.TipSetKey) (*types.TipSet, error)                                                                                        `perm:"read"`
 		ChainGetTipSetAfterHeight           func(ctx context.Context, height abi.ChainEpoch, tsk types.TipSetKey) (*types.TipSet, error)                                                                 `perm:"read"`
 		ChainGetTipSetByHeight              func(ctx context.Context, height abi.ChainEpoch, tsk types.TipSetKey) (*types.TipSet, error)                                                                 `perm:"read"`
+		ChainGetTipSetState                 func(ctx context.Context, tsk types.TipSetKey) (*types.TipSetState, error)                                                                                   `perm:"read"`
 		ChainHead                           func(ctx context.Context) (*types.TipSet, error)                                                                                                             `perm:"read"`
+		ChainHotGC                          func(ctx context.Context, opts types.HotGCOpts) error                                                                                                        `perm:"admin"`
 		ChainList                           func(ctx context.Context, tsKey types.TipSetKey, count int) ([]types.TipSetKey, error)                                                                       `perm:"read"`
 		ChainNotify                         func(ctx context.Context) (<-chan []*types.HeadChange, error)                                                                                                `perm:"read"`
+		ChainNotifySince                    func(ctx context.Context, tsk types.TipSetKey) (<-chan []*types.HeadChange, error)                                                                           `perm:"read"`
+		ChainPrune                          func(ctx context.Context, opts types.ChainPruneOpts) (*types.ChainPruneResult, error)                                                                        `perm:"admin"`
 		ChainSetHead                        func(ctx context.Context, key types.TipSetKey) error                                                                                                         `perm:"admin"`
+		ChainSnapshotImportStatus           func(ctx context.Context) (*types.SnapshotImportStatus, error)                                                                                               `perm:"read"`
 		GetActor                            func(ctx context.Context, addr address.Address) (*types.Actor, error)                                                                                        `perm:"read"`
 		GetEntry                            func(ctx context.Context, height abi.ChainEpoch, round uint64) (*types.BeaconEntry, error)                                                                   `perm:"read"`
 		GetFullBlock                        func(ctx context.Context, id cid.Cid) (*types.FullBlock, error)                                                                                              `perm:"read"`
@@ -311,9 +330,12 @@ type IChainInfoStruct struct {
 		StateNetworkVersion                 func(ctx context.Context, tsk types.TipSetKey) (network.Version, error)                                                                                      `perm:"read"`
 		StateReplay                         func(context.Context, types.TipSetKey, cid.Cid) (*types.InvocResult, error)                                                                                  `perm:"read"`
 		StateSearchMsg                      func(ctx context.Context, from types.TipSetKey, msg cid.Cid, limit abi.ChainEpoch, allowReplaced bool) (*types.MsgLookup, error)                             `perm:"read"`
+		StateSearchMsgFinality              func(ctx context.Context, from types.TipSetKey, msg cid.Cid, reorgProbability float64, limit abi.ChainEpoch, allowReplaced bool) (*types.MsgLookup, error)   `perm:"read"`
+		StateSimulateBundle                 func(ctx context.Context, msgs []*types.Message, tsk types.TipSetKey, opts types.SimulateBundleOpts) (*types.SimulateBundleResult, error)                    `perm:"read"`
 		StateVerifiedRegistryRootKey        func(ctx context.Context, tsk types.TipSetKey) (address.Address, error)                                                                                      `perm:"read"`
 		StateVerifierStatus                 func(ctx context.Context, addr address.Address, tsk types.TipSetKey) (*abi.StoragePower, error)                                                              `perm:"read"`
 		StateWaitMsg                        func(ctx context.Context, cid cid.Cid, confidence uint64, limit abi.ChainEpoch, allowReplaced bool) (*types.MsgLookup, error)                                `perm:"read"`
+		StateWaitMsgFinality                func(ctx context.Context, cid cid.Cid, reorgProbability float64, limit abi.ChainEpoch, allowReplaced bool) (*types.MsgLookup, error)                         `perm:"read"`
 		VerifyEntry                         func(parent, child *types.BeaconEntry, height abi.ChainEpoch) bool                                                                                           `perm:"read"`
 	}
 }
@@ -324,6 +346,9 @@ func (s *IChainInfoStruct) BlockTime(p0 context.Context) time.Duration {
 func (s *IChainInfoStruct) ChainExport(p0 context.Context, p1 abi.ChainEpoch, p2 bool, p3 types.TipSetKey) (<-chan []byte, error) {
 	return s.Internal.ChainExport(p0, p1, p2, p3)
 }
+func (s *IChainInfoStruct) ChainExportRange(p0 context.Context, p1 types.TipSetKey, p2 abi.ChainEpoch, p3 types.ChainExportRangeOpts) (<-chan []byte, error) {
+	return s.Internal.ChainExportRange(p0, p1, p2, p3)
+}
 func (s *IChainInfoStruct) ChainGetBlock(p0 context.Context, p1 cid.Cid) (*types.BlockHeader, error) {
 	return s.Internal.ChainGetBlock(p0, p1)
 }
@@ -333,6 +358,9 @@ func (s *IChainInfoStruct) ChainGetBlockMessages(p0 context.Context, p1 cid.Cid)
 func (s *IChainInfoStruct) ChainGetEvents(p0 context.Context, p1 cid.Cid) ([]types.Event, error) {
 	return s.Internal.ChainGetEvents(p0, p1)
 }
+func (s *IChainInfoStruct) ChainGetFinality(p0 context.Context, p1 abi.ChainEpoch, p2 float64) (*types.ChainFinalityStatus, error) {
+	return s.Internal.ChainGetFinality(p0, p1, p2)
+}
 func (s *IChainInfoStruct) ChainGetGenesis(p0 context.Context) (*types.TipSet, error) {
 	return s.Internal.ChainGetGenesis(p0)
 }
@@ -363,18 +391,33 @@ func (s *IChainInfoStruct) ChainGetTipSetAfterHeight(p0 context.Context, p1 abi.
 func (s *IChainInfoStruct) ChainGetTipSetByHeight(p0 context.Context, p1 abi.ChainEpoch, p2 types.TipSetKey) (*types.TipSet, error) {
 	return s.Internal.ChainGetTipSetByHeight(p0, p1, p2)
 }
+func (s *IChainInfoStruct) ChainGetTipSetState(p0 context.Context, p1 types.TipSetKey) (*types.TipSetState, error) {
+	return s.Internal.ChainGetTipSetState(p0, p1)
+}
 func (s *IChainInfoStruct) ChainHead(p0 context.Context) (*types.TipSet, error) {
 	return s.Internal.ChainHead(p0)
 }
+func (s *IChainInfoStruct) ChainHotGC(p0 context.Context, p1 types.HotGCOpts) error {
+	return s.Internal.ChainHotGC(p0, p1)
+}
 func (s *IChainInfoStruct) ChainList(p0 context.Context, p1 types.TipSetKey, p2 int) ([]types.TipSetKey, error) {
 	return s.Internal.ChainList(p0, p1, p2)
 }
 func (s *IChainInfoStruct) ChainNotify(p0 context.Context) (<-chan []*types.HeadChange, error) {
 	return s.Internal.ChainNotify(p0)
 }
+func (s *IChainInfoStruct) ChainNotifySince(p0 context.Context, p1 types.TipSetKey) (<-chan []*types.HeadChange, error) {
+	return s.Internal.ChainNotifySince(p0, p1)
+}
+func (s *IChainInfoStruct) ChainPrune(p0 context.Context, p1 types.ChainPruneOpts) (*types.ChainPruneResult, error) {
+	return s.Internal.ChainPrune(p0, p1)
+}
 func (s *IChainInfoStruct) ChainSetHead(p0 context.Context, p1 types.TipSetKey) error {
 	return s.Internal.ChainSetHead(p0, p1)
 }
+func (s *IChainInfoStruct) ChainSnapshotImportStatus(p0 context.Context) (*types.SnapshotImportStatus, error) {
+	return s.Internal.ChainSnapshotImportStatus(p0)
+}
 func (s *IChainInfoStruct) GetActor(p0 context.Context, p1 address.Address) (*types.Actor, error) {
 	return s.Internal.GetActor(p0, p1)
 }
@@ -435,6 +478,12 @@ func (s *IChainInfoStruct) StateReplay(p0 context.Context, p1 types.TipSetKey, p
 func (s *IChainInfoStruct) StateSearchMsg(p0 context.Context, p1 types.TipSetKey, p2 cid.Cid, p3 abi.ChainEpoch, p4 bool) (*types.MsgLookup, error) {
 	return s.Internal.StateSearchMsg(p0, p1, p2, p3, p4)
 }
+func (s *IChainInfoStruct) StateSearchMsgFinality(p0 context.Context, p1 types.TipSetKey, p2 cid.Cid, p3 float64, p4 abi.ChainEpoch, p5 bool) (*types.MsgLookup, error) {
+	return s.Internal.StateSearchMsgFinality(p0, p1, p2, p3, p4, p5)
+}
+func (s *IChainInfoStruct) StateSimulateBundle(p0 context.Context, p1 []*types.Message, p2 types.TipSetKey, p3 types.SimulateBundleOpts) (*types.SimulateBundleResult, error) {
+	return s.Internal.StateSimulateBundle(p0, p1, p2, p3)
+}
 func (s *IChainInfoStruct) StateVerifiedRegistryRootKey(p0 context.Context, p1 types.TipSetKey) (address.Address, error) {
 	return s.Internal.StateVerifiedRegistryRootKey(p0, p1)
 }
@@ -444,6 +493,9 @@ func (s *IChainInfoStruct) StateVerifierStatus(p0 context.Context, p1 address.Ad
 func (s *IChainInfoStruct) StateWaitMsg(p0 context.Context, p1 cid.Cid, p2 uint64, p3 abi.ChainEpoch, p4 bool) (*types.MsgLookup, error) {
 	return s.Internal.StateWaitMsg(p0, p1, p2, p3, p4)
 }
+func (s *IChainInfoStruct) StateWaitMsgFinality(p0 context.Context, p1 cid.Cid, p2 float64, p3 abi.ChainEpoch, p4 bool) (*types.MsgLookup, error) {
+	return s.Internal.StateWaitMsgFinality(p0, p1, p2, p3, p4)
+}
 func (s *IChainInfoStruct) VerifyEntry(p0, p1 *types.BeaconEntry, p2 abi.ChainEpoch) bool {
 	return s.Internal.VerifyEntry(p0, p1, p2)
 }
@@ -483,6 +535,7 @@ type IMessagePoolStruct struct {
 	Internal struct {
 		GasBatchEstimateMessageGas func(ctx context.Context, estimateMessages []*types.EstimateMessage, fromNonce uint64, tsk types.TipSetKey) ([]*types.EstimateResult, error) `perm:"read"`
 		GasEstimateFeeCap          func(ctx context.Context, msg *types.Message, maxqueueblks int64, tsk types.TipSetKey) (big.Int, error)                                      `perm:"read"`
+		GasEstimateFeeHistory      func(ctx context.Context, blocks uint64, percentiles []float64, tsk types.TipSetKey) (*types.FeeHistory, error)                              `perm:"read"`
 		GasEstimateGasLimit        func(ctx context.Context, msgIn *types.Message, tsk types.TipSetKey) (int64, error)                                                          `perm:"read"`
 		GasEstimateGasPremium      func(ctx context.Context, nblocksincl uint64, sender address.Address, gaslimit int64, tsk types.TipSetKey) (big.Int, error)                  `perm:"read"`
 		GasEstimateMessageGas      func(ctx context.Context, msg *types.Message, spec *types.MessageSendSpec, tsk types.TipSetKey) (*types.Message, error)                      `perm:"read"`
@@ -495,7 +548,9 @@ type IMessagePoolStruct struct {
 		MpoolClear                 func(ctx context.Context, local bool) error                                                                                                  `perm:"write"`
 		MpoolDeleteByAdress        func(ctx context.Context, addr address.Address) error                                                                                        `perm:"admin"`
 		MpoolGetConfig             func(context.Context) (*types.MpoolConfig, error)                                                                                            `perm:"read"`
+		MpoolGetMessageStatus      func(ctx context.Context, c cid.Cid) (*types.MpoolMessageStatus, error)                                                                      `perm:"read"`
 		MpoolGetNonce              func(ctx context.Context, addr address.Address) (uint64, error)                                                                              `perm:"read"`
+		MpoolHistory               func(ctx context.Context, addr address.Address, nonce *uint64) ([]*types.MpoolJournalEntry, error)                                           `perm:"read"`
 		MpoolPending               func(ctx context.Context, tsk types.TipSetKey) ([]*types.SignedMessage, error)                                                               `perm:"read"`
 		MpoolPublishByAddr         func(context.Context, address.Address) error                                                                                                 `perm:"write"`
 		MpoolPublishMessage        func(ctx context.Context, smsg *types.SignedMessage) error                                                                                   `perm:"write"`
@@ -515,6 +570,9 @@ func (s *IMessagePoolStruct) GasBatchEstimateMessageGas(p0 context.Context, p1 [
 func (s *IMessagePoolStruct) GasEstimateFeeCap(p0 context.Context, p1 *types.Message, p2 int64, p3 types.TipSetKey) (big.Int, error) {
 	return s.Internal.GasEstimateFeeCap(p0, p1, p2, p3)
 }
+func (s *IMessagePoolStruct) GasEstimateFeeHistory(p0 context.Context, p1 uint64, p2 []float64, p3 types.TipSetKey) (*types.FeeHistory, error) {
+	return s.Internal.GasEstimateFeeHistory(p0, p1, p2, p3)
+}
 func (s *IMessagePoolStruct) GasEstimateGasLimit(p0 context.Context, p1 *types.Message, p2 types.TipSetKey) (int64, error) {
 	return s.Internal.GasEstimateGasLimit(p0, p1, p2)
 }
@@ -551,9 +609,15 @@ func (s *IMessagePoolStruct) MpoolDeleteByAdress(p0 context.Context, p1 address.
 func (s *IMessagePoolStruct) MpoolGetConfig(p0 context.Context) (*types.MpoolConfig, error) {
 	return s.Internal.MpoolGetConfig(p0)
 }
+func (s *IMessagePoolStruct) MpoolGetMessageStatus(p0 context.Context, p1 cid.Cid) (*types.MpoolMessageStatus, error) {
+	return s.Internal.MpoolGetMessageStatus(p0, p1)
+}
 func (s *IMessagePoolStruct) MpoolGetNonce(p0 context.Context, p1 address.Address) (uint64, error) {
 	return s.Internal.MpoolGetNonce(p0, p1)
 }
+func (s *IMessagePoolStruct) MpoolHistory(p0 context.Context, p1 address.Address, p2 *uint64) ([]*types.MpoolJournalEntry, error) {
+	return s.Internal.MpoolHistory(p0, p1, p2)
+}
 func (s *IMessagePoolStruct) MpoolPending(p0 context.Context, p1 types.TipSetKey) ([]*types.SignedMessage, error) {
 	return s.Internal.MpoolPending(p0, p1)
 }
@@ -585,6 +649,80 @@ func (s *IMessagePoolStruct) MpoolSub(p0 context.Context) (<-chan types.MpoolUpd
 	return s.Internal.MpoolSub(p0)
 }
 
+type IMultiSigStruct struct {
+	Internal struct {
+		MsigAddApprove          func(ctx context.Context, msig address.Address, src address.Address, txID uint64, proposer address.Address, newAdd address.Address, inc bool) (*types.MessagePrototype, error)                                   `perm:"sign"`
+		MsigAddCancel           func(ctx context.Context, msig address.Address, src address.Address, txID uint64, newAdd address.Address, inc bool) (*types.MessagePrototype, error)                                                             `perm:"sign"`
+		MsigAddPropose          func(ctx context.Context, msig address.Address, src address.Address, newAdd address.Address, inc bool) (*types.MessagePrototype, error)                                                                          `perm:"sign"`
+		MsigApprove             func(ctx context.Context, msig address.Address, txID uint64, src address.Address) (*types.MessagePrototype, error)                                                                                               `perm:"sign"`
+		MsigApproveTxnHash      func(ctx context.Context, msig address.Address, txID uint64, proposer address.Address, to address.Address, amt types.BigInt, src address.Address, method uint64, params []byte) (*types.MessagePrototype, error) `perm:"sign"`
+		MsigCancel              func(ctx context.Context, msig address.Address, txID uint64, src address.Address) (*types.MessagePrototype, error)                                                                                               `perm:"sign"`
+		MsigCancelTxnHash       func(ctx context.Context, msig address.Address, txID uint64, to address.Address, amt types.BigInt, src address.Address, method uint64, params []byte) (*types.MessagePrototype, error)                           `perm:"sign"`
+		MsigCreate              func(ctx context.Context, req uint64, addrs []address.Address, duration abi.ChainEpoch, val types.BigInt, src address.Address, gp types.BigInt) (*types.MessagePrototype, error)                                 `perm:"sign"`
+		MsigGetAvailableBalance func(ctx context.Context, addr address.Address, tsk types.TipSetKey) (types.BigInt, error)                                                                                                                       `perm:"read"`
+		MsigGetPending          func(ctx context.Context, addr address.Address, tsk types.TipSetKey) ([]*types.MsigTransaction, error)                                                                                                           `perm:"read"`
+		MsigGetVested           func(ctx context.Context, addr address.Address, start types.TipSetKey, end types.TipSetKey) (types.BigInt, error)                                                                                                `perm:"read"`
+		MsigGetVestingSchedule  func(ctx context.Context, addr address.Address, tsk types.TipSetKey) (types.MsigVesting, error)                                                                                                                  `perm:"read"`
+		MsigPropose             func(ctx context.Context, msig address.Address, to address.Address, amt types.BigInt, src address.Address, method uint64, params []byte) (*types.MessagePrototype, error)                                        `perm:"sign"`
+		MsigRemoveSigner        func(ctx context.Context, msig address.Address, proposer address.Address, toRemove address.Address, decrease bool) (*types.MessagePrototype, error)                                                              `perm:"sign"`
+		MsigSwapApprove         func(ctx context.Context, msig address.Address, src address.Address, txID uint64, proposer address.Address, oldAdd address.Address, newAdd address.Address) (*types.MessagePrototype, error)                     `perm:"sign"`
+		MsigSwapCancel          func(ctx context.Context, msig address.Address, src address.Address, txID uint64, oldAdd address.Address, newAdd address.Address) (*types.MessagePrototype, error)                                               `perm:"sign"`
+		MsigSwapPropose         func(ctx context.Context, msig address.Address, src address.Address, oldAdd address.Address, newAdd address.Address) (*types.MessagePrototype, error)                                                            `perm:"sign"`
+	}
+}
+
+func (s *IMultiSigStruct) MsigAddApprove(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 address.Address, p6 bool) (*types.MessagePrototype, error) {
+	return s.Internal.MsigAddApprove(p0, p1, p2, p3, p4, p5, p6)
+}
+func (s *IMultiSigStruct) MsigAddCancel(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 bool) (*types.MessagePrototype, error) {
+	return s.Internal.MsigAddCancel(p0, p1, p2, p3, p4, p5)
+}
+func (s *IMultiSigStruct) MsigAddPropose(p0 context.Context, p1 address.Address, p2 address.Address, p3 address.Address, p4 bool) (*types.MessagePrototype, error) {
+	return s.Internal.MsigAddPropose(p0, p1, p2, p3, p4)
+}
+func (s *IMultiSigStruct) MsigApprove(p0 context.Context, p1 address.Address, p2 uint64, p3 address.Address) (*types.MessagePrototype, error) {
+	return s.Internal.MsigApprove(p0, p1, p2, p3)
+}
+func (s *IMultiSigStruct) MsigApproveTxnHash(p0 context.Context, p1 address.Address, p2 uint64, p3 address.Address, p4 address.Address, p5 types.BigInt, p6 address.Address, p7 uint64, p8 []byte) (*types.MessagePrototype, error) {
+	return s.Internal.MsigApproveTxnHash(p0, p1, p2, p3, p4, p5, p6, p7, p8)
+}
+func (s *IMultiSigStruct) MsigCancel(p0 context.Context, p1 address.Address, p2 uint64, p3 address.Address) (*types.MessagePrototype, error) {
+	return s.Internal.MsigCancel(p0, p1, p2, p3)
+}
+func (s *IMultiSigStruct) MsigCancelTxnHash(p0 context.Context, p1 address.Address, p2 uint64, p3 address.Address, p4 types.BigInt, p5 address.Address, p6 uint64, p7 []byte) (*types.MessagePrototype, error) {
+	return s.Internal.MsigCancelTxnHash(p0, p1, p2, p3, p4, p5, p6, p7)
+}
+func (s *IMultiSigStruct) MsigCreate(p0 context.Context, p1 uint64, p2 []address.Address, p3 abi.ChainEpoch, p4 types.BigInt, p5 address.Address, p6 types.BigInt) (*types.MessagePrototype, error) {
+	return s.Internal.MsigCreate(p0, p1, p2, p3, p4, p5, p6)
+}
+func (s *IMultiSigStruct) MsigGetAvailableBalance(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (types.BigInt, error) {
+	return s.Internal.MsigGetAvailableBalance(p0, p1, p2)
+}
+func (s *IMultiSigStruct) MsigGetPending(p0 context.Context, p1 address.Address, p2 types.TipSetKey) ([]*types.MsigTransaction, error) {
+	return s.Internal.MsigGetPending(p0, p1, p2)
+}
+func (s *IMultiSigStruct) MsigGetVested(p0 context.Context, p1 address.Address, p2 types.TipSetKey, p3 types.TipSetKey) (types.BigInt, error) {
+	return s.Internal.MsigGetVested(p0, p1, p2, p3)
+}
+func (s *IMultiSigStruct) MsigGetVestingSchedule(p0 context.Context, p1 address.Address, p2 types.TipSetKey) (types.MsigVesting, error) {
+	return s.Internal.MsigGetVestingSchedule(p0, p1, p2)
+}
+func (s *IMultiSigStruct) MsigPropose(p0 context.Context, p1 address.Address, p2 address.Address, p3 types.BigInt, p4 address.Address, p5 uint64, p6 []byte) (*types.MessagePrototype, error) {
+	return s.Internal.MsigPropose(p0, p1, p2, p3, p4, p5, p6)
+}
+func (s *IMultiSigStruct) MsigRemoveSigner(p0 context.Context, p1 address.Address, p2 address.Address, p3 address.Address, p4 bool) (*types.MessagePrototype, error) {
+	return s.Internal.MsigRemoveSigner(p0, p1, p2, p3, p4)
+}
+func (s *IMultiSigStruct) MsigSwapApprove(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 address.Address, p6 address.Address) (*types.MessagePrototype, error) {
+	return s.Internal.MsigSwapApprove(p0, p1, p2, p3, p4, p5, p6)
+}
+func (s *IMultiSigStruct) MsigSwapCancel(p0 context.Context, p1 address.Address, p2 address.Address, p3 uint64, p4 address.Address, p5 address.Address) (*types.MessagePrototype, error) {
+	return s.Internal.MsigSwapCancel(p0, p1, p2, p3, p4, p5)
+}
+func (s *IMultiSigStruct) MsigSwapPropose(p0 context.Context, p1 address.Address, p2 address.Address, p3 address.Address, p4 address.Address) (*types.MessagePrototype, error) {
+	return s.Internal.MsigSwapPropose(p0, p1, p2, p3, p4)
+}
+
 type INetworkStruct struct {
 	Internal struct {
 		ID                          func(ctx context.Context) (peer.ID, error)                             `perm:"read"`
@@ -594,12 +732,16 @@ type INetworkStruct struct {
 		NetBandwidthStats           func(ctx context.Context) (metrics.Stats, error)                       `perm:"read"`
 		NetBandwidthStatsByPeer     func(ctx context.Context) (map[string]metrics.Stats, error)            `perm:"read"`
 		NetBandwidthStatsByProtocol func(ctx context.Context) (map[protocol.ID]metrics.Stats, error)       `perm:"read"`
+		NetBlockAdd                 func(ctx context.Context, acl types.NetBlockList) error                `perm:"admin"`
+		NetBlockList                func(ctx context.Context) (types.NetBlockList, error)                  `perm:"read"`
+		NetBlockRemove              func(ctx context.Context, acl types.NetBlockList) error                `perm:"admin"`
 		NetConnect                  func(ctx context.Context, pi peer.AddrInfo) error                      `perm:"admin"`
 		NetConnectedness            func(context.Context, peer.ID) (network2.Connectedness, error)         `perm:"read"`
 		NetDisconnect               func(ctx context.Context, p peer.ID) error                             `perm:"admin"`
 		NetFindPeer                 func(ctx context.Context, p peer.ID) (peer.AddrInfo, error)            `perm:"read"`
 		NetFindProvidersAsync       func(ctx context.Context, key cid.Cid, count int) <-chan peer.AddrInfo `perm:"read"`
 		NetGetClosestPeers          func(ctx context.Context, key string) ([]peer.ID, error)               `perm:"read"`
+		NetLimit                    func(ctx context.Context, scope string) (types.NetLimit, error)        `perm:"read"`
 		NetPeerInfo                 func(ctx context.Context, p peer.ID) (*types.ExtendedPeerInfo, error)  `perm:"read"`
 		NetPeers                    func(ctx context.Context) ([]peer.AddrInfo, error)                     `perm:"read"`
 		NetPing                     func(ctx context.Context, p peer.ID) (time.Duration, error)            `perm:"read"`
@@ -607,6 +749,8 @@ type INetworkStruct struct {
 		NetProtectList              func(ctx context.Context) ([]peer.ID, error)                           `perm:"read"`
 		NetProtectRemove            func(ctx context.Context, acl []peer.ID) error                         `perm:"admin"`
 		NetPubsubScores             func(context.Context) ([]types.PubsubScore, error)                     `perm:"read"`
+		NetSetLimit                 func(ctx context.Context, scope string, limit types.NetLimit) error    `perm:"admin"`
+		NetStat                     func(ctx context.Context, scope string) (types.NetStat, error)         `perm:"read"`
 	}
 }
 
@@ -629,6 +773,15 @@ func (s *INetworkStruct) NetBandwidthStatsByPeer(p0 context.Context) (map[string
 func (s *INetworkStruct) NetBandwidthStatsByProtocol(p0 context.Context) (map[protocol.ID]metrics.Stats, error) {
 	return s.Internal.NetBandwidthStatsByProtocol(p0)
 }
+func (s *INetworkStruct) NetBlockAdd(p0 context.Context, p1 types.NetBlockList) error {
+	return s.Internal.NetBlockAdd(p0, p1)
+}
+func (s *INetworkStruct) NetBlockList(p0 context.Context) (types.NetBlockList, error) {
+	return s.Internal.NetBlockList(p0)
+}
+func (s *INetworkStruct) NetBlockRemove(p0 context.Context, p1 types.NetBlockList) error {
+	return s.Internal.NetBlockRemove(p0, p1)
+}
 func (s *INetworkStruct) NetConnect(p0 context.Context, p1 peer.AddrInfo) error {
 	return s.Internal.NetConnect(p0, p1)
 }
@@ -647,6 +800,9 @@ func (s *INetworkStruct) NetFindProvidersAsync(p0 context.Context, p1 cid.Cid, p
 func (s *INetworkStruct) NetGetClosestPeers(p0 context.Context, p1 string) ([]peer.ID, error) {
 	return s.Internal.NetGetClosestPeers(p0, p1)
 }
+func (s *INetworkStruct) NetLimit(p0 context.Context, p1 string) (types.NetLimit, error) {
+	return s.Internal.NetLimit(p0, p1)
+}
 func (s *INetworkStruct) NetPeerInfo(p0 context.Context, p1 peer.ID) (*types.ExtendedPeerInfo, error) {
 	return s.Internal.NetPeerInfo(p0, p1)
 }
@@ -668,6 +824,12 @@ func (s *INetworkStruct) NetProtectRemove(p0 context.Context, p1 []peer.ID) erro
 func (s *INetworkStruct) NetPubsubScores(p0 context.Context) ([]types.PubsubScore, error) {
 	return s.Internal.NetPubsubScores(p0)
 }
+func (s *INetworkStruct) NetSetLimit(p0 context.Context, p1 string, p2 types.NetLimit) error {
+	return s.Internal.NetSetLimit(p0, p1, p2)
+}
+func (s *INetworkStruct) NetStat(p0 context.Context, p1 string) (types.NetStat, error) {
+	return s.Internal.NetStat(p0, p1)
+}
 
 type IPaychanStruct struct {
 	Internal struct {
@@ -749,9 +911,14 @@ type ISyncerStruct struct {
 		ChainTipSetWeight        func(ctx context.Context, tsk types.TipSetKey) (big.Int, error) `perm:"read"`
 		Concurrent               func(ctx context.Context) int64                                 `perm:"read"`
 		SetConcurrent            func(ctx context.Context, concurrent int64) error               `perm:"admin"`
+		SyncCheckBad             func(ctx context.Context, bcid cid.Cid) (string, error)         `perm:"read"`
+		SyncCheckpoint           func(ctx context.Context, tsk types.TipSetKey) error            `perm:"admin"`
 		SyncIncomingBlocks       func(ctx context.Context) (<-chan *types.BlockHeader, error)    `perm:"read"`
+		SyncMarkBad              func(ctx context.Context, bcid cid.Cid) error                   `perm:"admin"`
 		SyncState                func(ctx context.Context) (*types.SyncState, error)             `perm:"read"`
 		SyncSubmitBlock          func(ctx context.Context, blk *types.BlockMsg) error            `perm:"write"`
+		SyncUnmarkAllBad         func(ctx context.Context) error                                 `perm:"admin"`
+		SyncUnmarkBad            func(ctx context.Context, bcid cid.Cid) error                   `perm:"admin"`
 		SyncerTracker            func(ctx context.Context) *types.TargetTracker                  `perm:"read"`
 	}
 }
@@ -766,15 +933,30 @@ func (s *ISyncerStruct) Concurrent(p0 context.Context) int64 { return s.Internal
 func (s *ISyncerStruct) SetConcurrent(p0 context.Context, p1 int64) error {
 	return s.Internal.SetConcurrent(p0, p1)
 }
+func (s *ISyncerStruct) SyncCheckBad(p0 context.Context, p1 cid.Cid) (string, error) {
+	return s.Internal.SyncCheckBad(p0, p1)
+}
+func (s *ISyncerStruct) SyncCheckpoint(p0 context.Context, p1 types.TipSetKey) error {
+	return s.Internal.SyncCheckpoint(p0, p1)
+}
 func (s *ISyncerStruct) SyncIncomingBlocks(p0 context.Context) (<-chan *types.BlockHeader, error) {
 	return s.Internal.SyncIncomingBlocks(p0)
 }
+func (s *ISyncerStruct) SyncMarkBad(p0 context.Context, p1 cid.Cid) error {
+	return s.Internal.SyncMarkBad(p0, p1)
+}
 func (s *ISyncerStruct) SyncState(p0 context.Context) (*types.SyncState, error) {
 	return s.Internal.SyncState(p0)
 }
 func (s *ISyncerStruct) SyncSubmitBlock(p0 context.Context, p1 *types.BlockMsg) error {
 	return s.Internal.SyncSubmitBlock(p0, p1)
 }
+func (s *ISyncerStruct) SyncUnmarkAllBad(p0 context.Context) error {
+	return s.Internal.SyncUnmarkAllBad(p0)
+}
+func (s *ISyncerStruct) SyncUnmarkBad(p0 context.Context, p1 cid.Cid) error {
+	return s.Internal.SyncUnmarkBad(p0, p1)
+}
 func (s *ISyncerStruct) SyncerTracker(p0 context.Context) *types.TargetTracker {
 	return s.Internal.SyncerTracker(p0)
 }
@@ -868,12 +1050,16 @@ type IETHStruct struct {
 		EthBlockNumber                         func(ctx context.Context) (types.EthUint64, error)                                                                                        `perm:"read"`
 		EthCall                                func(ctx context.Context, tx types.EthCall, blkParam types.EthBlockNumberOrHash) (types.EthBytes, error)                                  `perm:"read"`
 		EthChainId                             func(ctx context.Context) (types.EthUint64, error)                                                                                        `perm:"read"`
+		EthDebugTraceCall                      func(ctx context.Context, tx types.EthCall, blkParam types.EthBlockNumberOrHash, config types.EthTraceConfig) (interface{}, error)        `perm:"read"`
+		EthDebugTraceTransaction               func(ctx context.Context, txHash string, config types.EthTraceConfig) (interface{}, error)                                                `perm:"read"`
 		EthEstimateGas                         func(ctx context.Context, p jsonrpc.RawParams) (types.EthUint64, error)                                                                   `perm:"read"`
 		EthFeeHistory                          func(ctx context.Context, p jsonrpc.RawParams) (types.EthFeeHistory, error)                                                               `perm:"read"`
 		EthGasPrice                            func(ctx context.Context) (types.EthBigInt, error)                                                                                        `perm:"read"`
 		EthGetBalance                          func(ctx context.Context, address types.EthAddress, blkParam types.EthBlockNumberOrHash) (types.EthBigInt, error)                         `perm:"read"`
 		EthGetBlockByHash                      func(ctx context.Context, blkHash types.EthHash, fullTxInfo bool) (types.EthBlock, error)                                                 `perm:"read"`
 		EthGetBlockByNumber                    func(ctx context.Context, blkNum string, fullTxInfo bool) (types.EthBlock, error)                                                         `perm:"read"`
+		EthGetBlockReceipts                    func(ctx context.Context, blkParam types.EthBlockNumberOrHash) ([]*types.EthTxReceipt, error)                                             `perm:"read"`
+		EthGetBlockReceiptsLimited             func(ctx context.Context, blkParam types.EthBlockNumberOrHash, limit abi.ChainEpoch) ([]*types.EthTxReceipt, error)                       `perm:"read"`
 		EthGetBlockTransactionCountByHash      func(ctx context.Context, blkHash types.EthHash) (types.EthUint64, error)                                                                 `perm:"read"`
 		EthGetBlockTransactionCountByNumber    func(ctx context.Context, blkNum types.EthUint64) (types.EthUint64, error)                                                                `perm:"read"`
 		EthGetCode                             func(ctx context.Context, address types.EthAddress, blkParam types.EthBlockNumberOrHash) (types.EthBytes, error)                          `perm:"read"`
@@ -892,7 +1078,9 @@ type IETHStruct struct {
 		EthSendRawTransaction                  func(ctx context.Context, rawTx types.EthBytes) (types.EthHash, error)                                                                    `perm:"read"`
 		EthSyncing                             func(ctx context.Context) (types.EthSyncingResult, error)                                                                                 `perm:"read"`
 		EthTraceBlock                          func(ctx context.Context, blkNum string) ([]*types.EthTraceBlock, error)                                                                  `perm:"read"`
+		EthTraceFilter                         func(ctx context.Context, filter types.EthTraceFilterCriteria) ([]*types.EthTraceFilterResult, error)                                     `perm:"read"`
 		EthTraceReplayBlockTransactions        func(ctx context.Context, blkNum string, traceTypes []string) ([]*types.EthTraceReplayBlockTransaction, error)                            `perm:"read"`
+		EthTraceTransaction                    func(ctx context.Context, txHash string) ([]*types.EthTraceTransaction, error)                                                            `perm:"read"`
 		FilecoinAddressToEthAddress            func(ctx context.Context, filecoinAddress address.Address) (types.EthAddress, error)                                                      `perm:"read"`
 		NetListening                           func(ctx context.Context) (bool, error)                                                                                                   `perm:"read"`
 		NetVersion                             func(ctx context.Context) (string, error)                                                                                                 `perm:"read"`
@@ -915,6 +1103,12 @@ func (s *IETHStruct) EthCall(p0 context.Context, p1 types.EthCall, p2 types.EthB
 func (s *IETHStruct) EthChainId(p0 context.Context) (types.EthUint64, error) {
 	return s.Internal.EthChainId(p0)
 }
+func (s *IETHStruct) EthDebugTraceCall(p0 context.Context, p1 types.EthCall, p2 types.EthBlockNumberOrHash, p3 types.EthTraceConfig) (interface{}, error) {
+	return s.Internal.EthDebugTraceCall(p0, p1, p2, p3)
+}
+func (s *IETHStruct) EthDebugTraceTransaction(p0 context.Context, p1 string, p2 types.EthTraceConfig) (interface{}, error) {
+	return s.Internal.EthDebugTraceTransaction(p0, p1, p2)
+}
 func (s *IETHStruct) EthEstimateGas(p0 context.Context, p1 jsonrpc.RawParams) (types.EthUint64, error) {
 	return s.Internal.EthEstimateGas(p0, p1)
 }
@@ -933,6 +1127,12 @@ func (s *IETHStruct) EthGetBlockByHash(p0 context.Context, p1 types.EthHash, p2
 func (s *IETHStruct) EthGetBlockByNumber(p0 context.Context, p1 string, p2 bool) (types.EthBlock, error) {
 	return s.Internal.EthGetBlockByNumber(p0, p1, p2)
 }
+func (s *IETHStruct) EthGetBlockReceipts(p0 context.Context, p1 types.EthBlockNumberOrHash) ([]*types.EthTxReceipt, error) {
+	return s.Internal.EthGetBlockReceipts(p0, p1)
+}
+func (s *IETHStruct) EthGetBlockReceiptsLimited(p0 context.Context, p1 types.EthBlockNumberOrHash, p2 abi.ChainEpoch) ([]*types.EthTxReceipt, error) {
+	return s.Internal.EthGetBlockReceiptsLimited(p0, p1, p2)
+}
 func (s *IETHStruct) EthGetBlockTransactionCountByHash(p0 context.Context, p1 types.EthHash) (types.EthUint64, error) {
 	return s.Internal.EthGetBlockTransactionCountByHash(p0, p1)
 }
@@ -987,9 +1187,15 @@ func (s *IETHStruct) EthSyncing(p0 context.Context) (types.EthSyncingResult, err
 func (s *IETHStruct) EthTraceBlock(p0 context.Context, p1 string) ([]*types.EthTraceBlock, error) {
 	return s.Internal.EthTraceBlock(p0, p1)
 }
+func (s *IETHStruct) EthTraceFilter(p0 context.Context, p1 types.EthTraceFilterCriteria) ([]*types.EthTraceFilterResult, error) {
+	return s.Internal.EthTraceFilter(p0, p1)
+}
 func (s *IETHStruct) EthTraceReplayBlockTransactions(p0 context.Context, p1 string, p2 []string) ([]*types.EthTraceReplayBlockTransaction, error) {
 	return s.Internal.EthTraceReplayBlockTransactions(p0, p1, p2)
 }
+func (s *IETHStruct) EthTraceTransaction(p0 context.Context, p1 string) ([]*types.EthTraceTransaction, error) {
+	return s.Internal.EthTraceTransaction(p0, p1)
+}
 func (s *IETHStruct) FilecoinAddressToEthAddress(p0 context.Context, p1 address.Address) (types.EthAddress, error) {
 	return s.Internal.FilecoinAddressToEthAddress(p0, p1)
 }
@@ -1003,15 +1209,17 @@ func (s *IETHStruct) Web3ClientVersion(p0 context.Context) (string, error) {
 
 type IETHEventStruct struct {
 	Internal struct {
-		EthGetFilterChanges            func(ctx context.Context, id types.EthFilterID) (*types.EthFilterResult, error)        `perm:"read"`
-		EthGetFilterLogs               func(ctx context.Context, id types.EthFilterID) (*types.EthFilterResult, error)        `perm:"read"`
-		EthGetLogs                     func(ctx context.Context, filter *types.EthFilterSpec) (*types.EthFilterResult, error) `perm:"read"`
-		EthNewBlockFilter              func(ctx context.Context) (types.EthFilterID, error)                                   `perm:"read"`
-		EthNewFilter                   func(ctx context.Context, filter *types.EthFilterSpec) (types.EthFilterID, error)      `perm:"read"`
-		EthNewPendingTransactionFilter func(ctx context.Context) (types.EthFilterID, error)                                   `perm:"read"`
-		EthSubscribe                   func(ctx context.Context, params jsonrpc.RawParams) (types.EthSubscriptionID, error)   `perm:"read"`
-		EthUninstallFilter             func(ctx context.Context, id types.EthFilterID) (bool, error)                          `perm:"read"`
-		EthUnsubscribe                 func(ctx context.Context, id types.EthSubscriptionID) (bool, error)                    `perm:"read"`
+		EthGetFilterChanges            func(ctx context.Context, id types.EthFilterID) (*types.EthFilterResult, error)                     `perm:"read"`
+		EthGetFilterLogs               func(ctx context.Context, id types.EthFilterID) (*types.EthFilterResult, error)                     `perm:"read"`
+		EthGetLogs                     func(ctx context.Context, filter *types.EthFilterSpec) (*types.EthFilterResult, error)              `perm:"read"`
+		EthIndexBackfill               func(ctx context.Context, from, to abi.ChainEpoch) (*types.EthIndexBackfillResult, error)           `perm:"admin"`
+		EthIndexValidate               func(ctx context.Context, from, to abi.ChainEpoch, fix bool) (*types.EthIndexValidateResult, error) `perm:"admin"`
+		EthNewBlockFilter              func(ctx context.Context) (types.EthFilterID, error)                                                `perm:"read"`
+		EthNewFilter                   func(ctx context.Context, filter *types.EthFilterSpec) (types.EthFilterID, error)                   `perm:"read"`
+		EthNewPendingTransactionFilter func(ctx context.Context) (types.EthFilterID, error)                                                `perm:"read"`
+		EthSubscribe                   func(ctx context.Context, params jsonrpc.RawParams) (types.EthSubscriptionID, error)                `perm:"read"`
+		EthUninstallFilter             func(ctx context.Context, id types.EthFilterID) (bool, error)                                       `perm:"read"`
+		EthUnsubscribe                 func(ctx context.Context, id types.EthSubscriptionID) (bool, error)                                 `perm:"read"`
 	}
 }
 
@@ -1024,6 +1232,12 @@ func (s *IETHEventStruct) EthGetFilterLogs(p0 context.Context, p1 types.EthFilte
 func (s *IETHEventStruct) EthGetLogs(p0 context.Context, p1 *types.EthFilterSpec) (*types.EthFilterResult, error) {
 	return s.Internal.EthGetLogs(p0, p1)
 }
+func (s *IETHEventStruct) EthIndexBackfill(p0 context.Context, p1, p2 abi.ChainEpoch) (*types.EthIndexBackfillResult, error) {
+	return s.Internal.EthIndexBackfill(p0, p1, p2)
+}
+func (s *IETHEventStruct) EthIndexValidate(p0 context.Context, p1, p2 abi.ChainEpoch, p3 bool) (*types.EthIndexValidateResult, error) {
+	return s.Internal.EthIndexValidate(p0, p1, p2, p3)
+}
 func (s *IETHEventStruct) EthNewBlockFilter(p0 context.Context) (types.EthFilterID, error) {
 	return s.Internal.EthNewBlockFilter(p0)
 }
@@ -1062,12 +1276,33 @@ func (s *IActorEventStruct) SubscribeActorEvents(p0 context.Context, p1 *types.A
 	return s.Internal.SubscribeActorEvents(p0, p1)
 }
 
+type IDisputerStruct struct {
+	Internal struct {
+		DisputerHistory func(ctx context.Context) ([]*types.DisputeRecord, error) `perm:"read"`
+		DisputerStart   func(ctx context.Context) error                           `perm:"admin"`
+		DisputerStatus  func(ctx context.Context) (*types.DisputerStatus, error)  `perm:"read"`
+		DisputerStop    func(ctx context.Context) error                           `perm:"admin"`
+	}
+}
+
+func (s *IDisputerStruct) DisputerHistory(p0 context.Context) ([]*types.DisputeRecord, error) {
+	return s.Internal.DisputerHistory(p0)
+}
+func (s *IDisputerStruct) DisputerStart(p0 context.Context) error {
+	return s.Internal.DisputerStart(p0)
+}
+func (s *IDisputerStruct) DisputerStatus(p0 context.Context) (*types.DisputerStatus, error) {
+	return s.Internal.DisputerStatus(p0)
+}
+func (s *IDisputerStruct) DisputerStop(p0 context.Context) error { return s.Internal.DisputerStop(p0) }
+
 type FullNodeStruct struct {
 	IBlockStoreStruct
 	IChainStruct
 	IMarketStruct
 	IMiningStruct
 	IMessagePoolStruct
+	IMultiSigStruct
 	INetworkStruct
 	IPaychanStruct
 	ISyncerStruct
@@ -1075,4 +1310,5 @@ type FullNodeStruct struct {
 	ICommonStruct
 	FullETHStruct
 	IActorEventStruct
+	IDisputerStruct
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sync"
 	"sync/atomic"
 
 	"github.com/dgraph-io/badger/v2"
@@ -126,6 +127,10 @@ type BadgerBlockstore struct {
 	keyTransform *keytransform.PrefixTransform
 
 	cache IBlockCache
+
+	observersLk  sync.RWMutex
+	observers    map[int]func(cid.Cid)
+	nextObserver int
 }
 
 var (
@@ -204,11 +209,44 @@ func (b *BadgerBlockstore) Flush(context.Context) error {
 	return b.DB.Sync()
 }
 
+// ObserveWrites calls f with the cid of every object written to the blockstore, or checked for
+// existence, until the returned function is called. f is called before the object is looked up,
+// so a write skipped because the object exists is observed too, and a write waits for f.
+func (b *BadgerBlockstore) ObserveWrites(f func(cid.Cid)) func() {
+	b.observersLk.Lock()
+	defer b.observersLk.Unlock()
+
+	if b.observers == nil {
+		b.observers = make(map[int]func(cid.Cid))
+	}
+	id := b.nextObserver
+	b.nextObserver++
+	b.observers[id] = f
+
+	return func() {
+		b.observersLk.Lock()
+		defer b.observersLk.Unlock()
+		delete(b.observers, id)
+	}
+}
+
+func (b *BadgerBlockstore) observe(cids ...cid.Cid) {
+	b.observersLk.RLock()
+	defer b.observersLk.RUnlock()
+
+	for _, f := range b.observers {
+		for _, c := range cids {
+			f(c)
+		}
+	}
+}
+
 // Has implements blockstore.Has.
 func (b *BadgerBlockstore) Has(ctx context.Context, cid cid.Cid) (bool, error) {
 	if atomic.LoadInt64(&b.state) != stateOpen {
 		return false, ErrBlockstoreClosed
 	}
+	b.observe(cid)
 
 	key := b.ConvertKey(cid)
 	if b.cache != nil {
@@ -311,6 +349,7 @@ func (b *BadgerBlockstore) Put(ctx context.Context, block blocks.Block) error {
 	if atomic.LoadInt64(&b.state) != stateOpen {
 		return ErrBlockstoreClosed
 	}
+	b.observe(block.Cid())
 
 	key := b.ConvertKey(block.Cid())
 	if _, ok := b.cache.Get(key.String()); ok {
@@ -349,6 +388,13 @@ func (b *BadgerBlockstore) PutMany(ctx context.Context, blks []blocks.Block) err
 	if atomic.LoadInt64(&b.state) != stateOpen {
 		return ErrBlockstoreClosed
 	}
+	if len(blks) > 0 {
+		cids := make([]cid.Cid, 0, len(blks))
+		for _, blk := range blks {
+			cids = append(cids, blk.Cid())
+		}
+		b.observe(cids...)
+	}
 
 	batch := b.DB.NewWriteBatch()
 	defer batch.Cancel()
@@ -472,6 +518,87 @@ func (b *BadgerBlockstore) AllKeysChan(ctx context.Context) (<-chan cid.Cid, err
 	return ch, nil
 }
 
+// discard ratios used by CollectGarbage, a full GC rewrites almost every value
+// log that has anything to discard.
+const (
+	defaultGCThreshold = 0.125
+	fullGCThreshold    = 0.01
+)
+
+var _ BlockstoreGC = (*BadgerBlockstore)(nil)
+
+// CollectGarbage rewrites the value log files until badger finds nothing more
+// to reclaim, this is what frees disk space after blocks were deleted.
+func (b *BadgerBlockstore) CollectGarbage(options ...BlockstoreGCOption) error {
+	if atomic.LoadInt64(&b.state) != stateOpen {
+		return ErrBlockstoreClosed
+	}
+
+	var opts BlockstoreGCOptions
+	for _, opt := range options {
+		if err := opt(&opts); err != nil {
+			return err
+		}
+	}
+
+	threshold := opts.Threshold
+	if threshold <= 0 {
+		threshold = defaultGCThreshold
+		if opts.FullGC {
+			threshold = fullGCThreshold
+		}
+	}
+
+	for {
+		if atomic.LoadInt64(&b.state) != stateOpen {
+			return ErrBlockstoreClosed
+		}
+		switch err := b.DB.RunValueLogGC(threshold); err {
+		case nil:
+		case badger.ErrNoRewrite:
+			return nil
+		default:
+			return fmt.Errorf("badger value log gc: %w", err)
+		}
+	}
+}
+
+// ForEachKey iterates over all the keys in the blockstore without prefetching
+// values, and calls f with a raw cid for every one of them. Iteration stops at
+// the first error returned by f.
+func (b *BadgerBlockstore) ForEachKey(f func(cid.Cid) error) error {
+	if atomic.LoadInt64(&b.state) != stateOpen {
+		return ErrBlockstoreClosed
+	}
+
+	return b.DB.View(func(txn *badger.Txn) error {
+		opts := badger.IteratorOptions{
+			PrefetchValues: false,
+			Prefix:         b.keyTransform.Prefix.Bytes(),
+		}
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		for iter.Rewind(); iter.Valid(); iter.Next() {
+			if atomic.LoadInt64(&b.state) != stateOpen {
+				return ErrBlockstoreClosed
+			}
+
+			dsKey := b.keyTransform.InvertKey(datastore.RawKey(string(iter.Item().Key())))
+			bk, err := dshelp.BinaryFromDsKey(dsKey)
+			if err != nil {
+				log.Warnf("error parsing key from binary: %s", err)
+				continue
+			}
+
+			if err := f(cid.NewCidV1(cid.Raw, bk)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // HashOnRead implements blockstore.HashOnRead. It is not supported by this
 // blockstore.
 func (b *BadgerBlockstore) HashOnRead(_ bool) {
@@ -137,6 +137,8 @@ type BlockstoreGCOption = func(*BlockstoreGCOptions) error // nolint
 // BlockstoreGCOptions is a struct with GC options
 type BlockstoreGCOptions struct { // nolint
 	FullGC bool
+	// Threshold is the ratio of discardable data above which a value log file is rewritten.
+	Threshold float64
 }
 
 func WithFullGC(fullgc bool) BlockstoreGCOption {
@@ -145,3 +147,10 @@ func WithFullGC(fullgc bool) BlockstoreGCOption {
 		return nil
 	}
 }
+
+func WithThreshold(threshold float64) BlockstoreGCOption {
+	return func(opts *BlockstoreGCOptions) error {
+		opts.Threshold = threshold
+		return nil
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/filecoin-project/go-bitfield"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/ipfs/go-cid"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -22,6 +23,127 @@ type ComputeStateOutput struct {
 	Trace []*InvocResult
 }
 
+// ChainExportRangeOpts are the options of a range export of the chain.
+type ChainExportRangeOpts struct {
+	// Workers is the number of tipsets walked in parallel, 1 when zero
+	Workers int
+	// Messages includes the messages of the blocks
+	Messages bool
+	// Receipts includes the receipts of the parent messages of the blocks
+	Receipts bool
+	// StateRoots includes the parent states of the blocks
+	StateRoots bool
+	// SkipHeader omits the car header, to append the export to the one it resumes
+	SkipHeader bool
+}
+
+// SnapshotImportState is the stage of the import of a snapshot.
+type SnapshotImportState string
+
+const (
+	SnapshotImporting       SnapshotImportState = "importing"
+	SnapshotVerifyingLinks  SnapshotImportState = "verifying-links"
+	SnapshotVerifyingStates SnapshotImportState = "verifying-states"
+	SnapshotImported        SnapshotImportState = "imported"
+	SnapshotImportFailed    SnapshotImportState = "failed"
+)
+
+// SnapshotImportStatus reports the import of a snapshot and its verification.
+type SnapshotImportStatus struct {
+	Source    string
+	State     SnapshotImportState
+	StartedAt time.Time
+	UpdatedAt time.Time
+
+	// TotalBytes is the size of the snapshot, zero when unknown
+	TotalBytes      uint64
+	Bytes           uint64
+	Blocks          uint64
+	BytesPerSecond  float64
+	BlocksPerSecond float64
+	// ETA is the estimated time left to read the snapshot
+	ETA time.Duration
+
+	Head   TipSetKey
+	Height abi.ChainEpoch
+	// LinksVerified is the number of tipsets whose links to their parents were checked
+	LinksVerified uint64
+	// StateRoots is the number of state roots to recompute, StateRootsVerified the ones that matched
+	StateRoots         int
+	StateRootsVerified int
+
+	Error string `json:",omitempty"`
+}
+
+// TipSetState is the result of the execution of a tipset.
+type TipSetState struct {
+	StateRoot cid.Cid
+	Receipts  cid.Cid
+}
+
+// DisputeState is the state of a DisputeWindowedPoSt message sent by the disputer.
+type DisputeState string
+
+const (
+	DisputePending   DisputeState = "pending"
+	DisputeSucceeded DisputeState = "succeeded"
+	DisputeFailed    DisputeState = "failed"
+)
+
+// DisputeRecord is a DisputeWindowedPoSt message sent by the disputer.
+type DisputeRecord struct {
+	Miner     address.Address
+	Deadline  uint64
+	PoStIndex uint64
+	Message   cid.Cid
+	// SentAt is the epoch the message was sent at
+	SentAt abi.ChainEpoch
+	State  DisputeState
+	// ExitCode is the exit code of the message once it landed on chain
+	ExitCode exitcode.ExitCode
+	// Reward is what the disputer received for a successful dispute
+	Reward BigInt
+}
+
+// DisputerStatus reports the window post disputer.
+type DisputerStatus struct {
+	Running bool
+	From    address.Address
+	// NextEpoch is the next epoch whose closed deadlines are checked
+	NextEpoch abi.ChainEpoch
+	// Miners is the number of miners whose deadlines are watched
+	Miners int
+
+	ProofsChecked     uint64
+	DisputesSent      uint64
+	DisputesSucceeded uint64
+	DisputesFailed    uint64
+	RewardsEarned     BigInt
+
+	Error string `json:",omitempty"`
+}
+
+// SimulateBundleOpts are the options of a bundle simulation.
+type SimulateBundleOpts struct {
+	// ReturnState flushes the state after the bundle and returns its root
+	ReturnState bool
+}
+
+// SimulateBundleResult is the outcome of the messages of a bundle simulation.
+type SimulateBundleResult struct {
+	// Root is the state after the bundle, it is only set when requested
+	Root cid.Cid `json:",omitempty"`
+	// Results are the outcome of each message, in the order of the bundle
+	Results []*SimulatedMessage
+}
+
+// SimulatedMessage is the outcome of a message of a bundle simulation.
+type SimulatedMessage struct {
+	InvocResult
+	// Events are the events emitted by the message
+	Events []Event
+}
+
 type HeadChangeType string
 
 // HeadChangeTopic is the topic used to publish new heads.
@@ -493,3 +615,42 @@ type NodeChainStatus struct {
 	BlocksPerTipsetLast100      float64
 	BlocksPerTipsetLastFinality float64
 }
+
+// ChainFinalityStatus describes how final a height of the chain is relative to the chain head.
+type ChainFinalityStatus struct {
+	Head   abi.ChainEpoch
+	Height abi.ChainEpoch
+	// ReorgProbability is an upper bound of the probability that the tipset at Height is reorged out
+	ReorgProbability float64
+	Threshold        float64
+	// SafeHeight is the highest height whose reorg probability is at most Threshold
+	SafeHeight abi.ChainEpoch
+	// SafeDepth is the number of epochs between SafeHeight and Head
+	SafeDepth abi.ChainEpoch
+}
+
+// EthIndexBackfillResult reports what was indexed by a backfill.
+type EthIndexBackfillResult struct {
+	From    abi.ChainEpoch
+	To      abi.ChainEpoch
+	TipSets int
+	Events  int
+	// TxHashes is the number of eth transaction hash mappings added
+	TxHashes int
+}
+
+// EthIndexValidateResult lists the inconsistencies found between the indexes and the chain.
+type EthIndexValidateResult struct {
+	From    abi.ChainEpoch
+	To      abi.ChainEpoch
+	TipSets int
+	// MissingEvents holds the heights of the tipsets whose events are not all indexed
+	MissingEvents []abi.ChainEpoch
+	// UnrevertedEvents holds the heights of the tipsets no longer on the chain whose events
+	// are not marked as reverted
+	UnrevertedEvents []abi.ChainEpoch
+	// MissingTxHashes holds the eth transactions without a tx hash mapping
+	MissingTxHashes []cid.Cid
+	// Fixed is set when the inconsistencies were repaired
+	Fixed bool
+}
@@ -0,0 +1,49 @@
+package types
+
+import (
+	"time"
+
+	"github.com/filecoin-project/go-state-types/abi"
+)
+
+// SplitStoreInfo describes the state of the hot/cold splitstore and the
+// progress of the running (or last) compaction.
+type SplitStoreInfo struct {
+	ColdStoreType          string
+	HotStoreRetention      abi.ChainEpoch
+	BaseEpoch              abi.ChainEpoch
+	Compacting             bool
+	Phase                  string
+	Marked                 uint64
+	Moved                  uint64
+	Purged                 uint64
+	LastCompactionStart    time.Time
+	LastCompactionDuration time.Duration
+	LastError              string
+}
+
+// ChainPruneOpts controls which objects ChainPrune keeps.
+type ChainPruneOpts struct {
+	// RetainState is the number of epochs below the head whose state, messages
+	// and receipts are kept, chain headers are always kept.
+	RetainState abi.ChainEpoch
+	// DryRun only counts the objects that would be deleted.
+	DryRun bool
+}
+
+// ChainPruneResult reports what ChainPrune marked and deleted.
+type ChainPruneResult struct {
+	Head        abi.ChainEpoch
+	DryRun      bool
+	Marked      uint64
+	Pruned      uint64
+	PrunedBytes uint64
+	Duration    time.Duration
+}
+
+// HotGCOpts controls the garbage collection of the badger value logs.
+type HotGCOpts struct {
+	// Threshold is the ratio of discardable data above which a value log file is
+	// rewritten, 0 uses the default.
+	Threshold float64
+}
@@ -31,6 +31,7 @@ type (
 	EthBlockNumberOrHash           = types.EthBlockNumberOrHash
 	EthBytes                       = types.EthBytes
 	EthCall                        = types.EthCall
+	EthCallFrame                   = types.EthCallFrame
 	EthCallTraceAction             = types.EthCallTraceAction
 	EthCallTraceResult             = types.EthCallTraceResult
 	EthCreateTraceAction           = types.EthCreateTraceAction
@@ -45,6 +46,8 @@ type (
 	EthHashList                    = types.EthHashList
 	EthLog                         = types.EthLog
 	EthNonce                       = types.EthNonce
+	EthPrestateAccount             = types.EthPrestateAccount
+	EthPrestateDiff                = types.EthPrestateDiff
 	EthSubscribeParams             = types.EthSubscribeParams
 	EthSubscriptionID              = types.EthSubscriptionID
 	EthSubscriptionParams          = types.EthSubscriptionParams
@@ -53,7 +56,12 @@ type (
 	EthTopicSpec                   = types.EthTopicSpec
 	EthTrace                       = types.EthTrace
 	EthTraceBlock                  = types.EthTraceBlock
+	EthTraceConfig                 = types.EthTraceConfig
+	EthTraceFilterCriteria         = types.EthTraceFilterCriteria
+	EthTraceFilterResult           = types.EthTraceFilterResult
 	EthTraceReplayBlockTransaction = types.EthTraceReplayBlockTransaction
+	EthTraceTransaction            = types.EthTraceTransaction
+	EthTracerConfig                = types.EthTracerConfig
 	EthTxReceipt                   = types.EthTxReceipt
 	EthUint64                      = types.EthUint64
 )
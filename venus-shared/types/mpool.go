@@ -1,6 +1,9 @@
 package types
 
 import (
+	"time"
+
+	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/ipfs/go-cid"
 )
 
@@ -52,3 +55,76 @@ type MpoolUpdate struct {
 	Type    MpoolChange
 	Message *SignedMessage
 }
+
+// MpoolJournalEntry is an event of the message pool about a message, as recorded by its journal.
+type MpoolJournalEntry struct {
+	Timestamp time.Time
+	// Event is one of add, remove, replace, prune or repub
+	Event  string
+	Reason string `json:",omitempty"`
+
+	Cid     cid.Cid
+	Message Message
+}
+
+// MpoolRemoveReason is why a message left the message pool, or was refused by it.
+type MpoolRemoveReason string
+
+const (
+	// MpoolRemoveIncluded means the message was included on chain
+	MpoolRemoveIncluded MpoolRemoveReason = "included"
+	// MpoolRemoveNonceUsed means another message of the sender with the same nonce was included on chain
+	MpoolRemoveNonceUsed MpoolRemoveReason = "nonce used"
+	// MpoolRemoveReplaced means the message was replaced by a message paying a higher fee
+	MpoolRemoveReplaced MpoolRemoveReason = "replaced"
+	// MpoolRemovePruned means the message was evicted while the message pool was above its size limit
+	MpoolRemovePruned MpoolRemoveReason = "pruned"
+	// MpoolRemoveNonceGap means the message was refused because its nonce is too far ahead of the sender nonce
+	MpoolRemoveNonceGap MpoolRemoveReason = "nonce gap"
+	// MpoolRemoveInsufficientBalance means the message was refused because the sender can't pay for it
+	MpoolRemoveInsufficientBalance MpoolRemoveReason = "insufficient balance"
+	// MpoolRemoveCleared means the message pool was cleared
+	MpoolRemoveCleared MpoolRemoveReason = "cleared"
+	// MpoolRemoveDeleted means the messages of the sender were deleted
+	MpoolRemoveDeleted MpoolRemoveReason = "deleted"
+	// MpoolRemoveManual means the message was removed through the api
+	MpoolRemoveManual MpoolRemoveReason = "removed"
+)
+
+// MpoolMessageStatus tells whether a message is in the message pool, or why it left it.
+type MpoolMessageStatus struct {
+	Cid cid.Cid
+	// Pending is set while the message is in the message pool
+	Pending bool
+	// Reason is why the message left the message pool, it is empty while the message is
+	// pending or when the message pool doesn't remember the message
+	Reason MpoolRemoveReason `json:",omitempty"`
+	// Detail describes the reason, such as the message replacing this one
+	Detail string `json:",omitempty"`
+	// Epoch is the height of the tipset including the message, or another message using its nonce
+	Epoch abi.ChainEpoch `json:",omitempty"`
+	// Timestamp is when the message left the message pool
+	Timestamp time.Time
+}
+
+// FeeHistory is the history of the fees paid by the messages included in a range of tipsets.
+type FeeHistory struct {
+	// Percentiles of the gas used at which the premiums of each epoch are reported
+	Percentiles []float64
+	// Epochs are the tipsets of the range, oldest first, without the null rounds
+	Epochs []FeeHistoryEpoch
+}
+
+// FeeHistoryEpoch holds the fees paid by the messages of a tipset.
+type FeeHistoryEpoch struct {
+	Epoch abi.ChainEpoch
+	// BaseFee is the base fee burnt by the messages of the tipset
+	BaseFee abi.TokenAmount
+	// Messages is the number of messages executed by the tipset
+	Messages int
+	GasUsed  int64
+	// GasUsedRatio is the gas used over the gas limit of the blocks of the tipset
+	GasUsedRatio float64
+	// Premiums are the effective gas premiums paid at the percentiles of the gas used
+	Premiums []abi.TokenAmount
+}
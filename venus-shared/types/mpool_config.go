@@ -13,4 +13,9 @@ type MpoolConfig struct {
 	ReplaceByFeeRatio      Percent
 	PruneCooldown          time.Duration
 	GasLimitOverestimation float64
+	// SelectionStrategy is the name of the strategy selecting the messages of the blocks
+	SelectionStrategy string
+	// MaxSenderGasLimit is the total gas limit of the messages of a sender selected in a block
+	// by the sender-gas-cap strategy
+	MaxSenderGasLimit int64
 }
@@ -29,3 +29,34 @@ type NatInfo struct {
 	Reachability network.Reachability
 	PublicAddrs  []string
 }
+
+// NetStat is the resource usage of the scopes of the libp2p resource manager
+type NetStat struct {
+	System    *network.ScopeStat           `json:",omitempty"`
+	Transient *network.ScopeStat           `json:",omitempty"`
+	Services  map[string]network.ScopeStat `json:",omitempty"`
+	Protocols map[string]network.ScopeStat `json:",omitempty"`
+	Peers     map[string]network.ScopeStat `json:",omitempty"`
+}
+
+// NetLimit is the resource limit of a scope of the libp2p resource manager
+type NetLimit struct {
+	Memory int64 `json:",omitempty"`
+
+	Streams         int `json:",omitempty"`
+	StreamsInbound  int `json:",omitempty"`
+	StreamsOutbound int `json:",omitempty"`
+
+	Conns         int `json:",omitempty"`
+	ConnsInbound  int `json:",omitempty"`
+	ConnsOutbound int `json:",omitempty"`
+
+	FD int `json:",omitempty"`
+}
+
+// NetBlockList holds the peers, ip addresses and subnets (in CIDR notation) blocked by the node
+type NetBlockList struct {
+	Peers     []peer.ID
+	IPAddrs   []string
+	IPSubnets []string
+}
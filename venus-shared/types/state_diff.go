@@ -0,0 +1,103 @@
+package types
+
+import (
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs/go-cid"
+
+	"github.com/filecoin-project/venus/venus-shared/actors/builtin/market"
+	"github.com/filecoin-project/venus/venus-shared/actors/builtin/miner"
+	"github.com/filecoin-project/venus/venus-shared/actors/builtin/power"
+)
+
+// StateDiffOpts are the options of a state diff.
+type StateDiffOpts struct {
+	// DecodeStates adds the decoded changes of the states of the modified miner, market and
+	// power actors
+	DecodeStates bool
+}
+
+// StateDiff holds the actors that differ between the parent states of two tipsets.
+type StateDiff struct {
+	// From and To are the compared state roots
+	From cid.Cid
+	To   cid.Cid
+
+	Added    []*ActorDiff
+	Removed  []*ActorDiff
+	Modified []*ActorDiff
+}
+
+// ActorDiff is an actor that differs between two states.
+type ActorDiff struct {
+	Address address.Address
+	// From is the actor in the first state, nil when the actor was added
+	From *Actor `json:",omitempty"`
+	// To is the actor in the second state, nil when the actor was removed
+	To *Actor `json:",omitempty"`
+	// BalanceDelta is the balance in the second state minus the balance in the first, a missing
+	// actor having none
+	BalanceDelta big.Int
+	// NonceDelta is the nonce in the second state minus the nonce in the first
+	NonceDelta int64
+
+	// the decoded changes of the state of the actor, when requested
+	Miner  *MinerStateDiff  `json:",omitempty"`
+	Market *MarketStateDiff `json:",omitempty"`
+	Power  *PowerStateDiff  `json:",omitempty"`
+}
+
+// MinerStateDiff holds the changes of the state of a miner actor.
+type MinerStateDiff struct {
+	Sectors    *miner.SectorChanges
+	PreCommits *miner.PreCommitChanges
+	// Deadlines are the changes of the partitions, by deadline and partition index
+	Deadlines miner.DeadlinesDiff
+}
+
+// MarketStateDiff holds the changes of the state of the market actor.
+type MarketStateDiff struct {
+	Proposals *market.DealProposalChanges
+	States    *MarketDealStateChanges
+}
+
+// MarketDealStateChanges holds the changes of the states of the deals.
+type MarketDealStateChanges struct {
+	Added    []MarketDealIDState
+	Modified []MarketDealStateChange
+	Removed  []MarketDealIDState
+}
+
+// MarketDealIDState is the state of a deal.
+type MarketDealIDState struct {
+	ID    abi.DealID
+	State MarketDealState
+}
+
+// MarketDealStateChange is the change of the state of a deal.
+type MarketDealStateChange struct {
+	ID   abi.DealID
+	From MarketDealState
+	To   MarketDealState
+}
+
+// MakeMarketDealStateChanges converts the deal state changes of the market actor.
+func MakeMarketDealStateChanges(changes *market.DealStateChanges) *MarketDealStateChanges {
+	out := &MarketDealStateChanges{}
+	for _, ds := range changes.Added {
+		out.Added = append(out.Added, MarketDealIDState{ID: ds.ID, State: MakeDealState(ds.Deal)})
+	}
+	for _, ds := range changes.Modified {
+		out.Modified = append(out.Modified, MarketDealStateChange{ID: ds.ID, From: MakeDealState(ds.From), To: MakeDealState(ds.To)})
+	}
+	for _, ds := range changes.Removed {
+		out.Removed = append(out.Removed, MarketDealIDState{ID: ds.ID, State: MakeDealState(ds.Deal)})
+	}
+	return out
+}
+
+// PowerStateDiff holds the changes of the state of the power actor.
+type PowerStateDiff struct {
+	Claims *power.ClaimChanges
+}